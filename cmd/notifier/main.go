@@ -5,11 +5,13 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"example.com/avalanche/internal/clients"
 	"example.com/avalanche/internal/models"
 	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -27,8 +29,6 @@ func main() {
 		log.Fatalf("failed to connect to db: %v", err)
 	}
 
-	emailClient := notifier.NewSendGridEmailClient()
-
 	pollIntervalStr := os.Getenv("NOTIFIER_POLL_INTERVAL")
 	if pollIntervalStr == "" {
 		pollIntervalStr = "12h"
@@ -39,6 +39,19 @@ func main() {
 	}
 
 	repo := notifier.NewGormRepository(db)
+	// Zones and centers to check even without subscribers, e.g. for dashboards
+	// following the event stream.
+	for _, id := range strings.Split(os.Getenv("NOTIFIER_WATCH"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			repo.Watch(strings.ToUpper(id))
+		}
+	}
+
+	sender, err := notifier.NewEmailSenderFromEnv(repo)
+	if err != nil {
+		log.Fatalf("failed to configure email sender: %v", err)
+	}
+	emailClient := notifier.NewSuppressingEmailSender(sender, repo)
 
 	baseURL := os.Getenv("AVY_API_BASE_URL")
 	if baseURL == "" {
@@ -50,7 +63,56 @@ func main() {
 	fetcher := notifier.MakeFetchFromSubscriptions(repo, forecastSourceAdapter{apiClient})
 	service := notifier.NewService(repo, emailClient, pollInterval, fetcher)
 
+	forecastService := services.NewForecast(apiClient)
+	details := func(ctx context.Context, centerID string) ([]models.ZoneForecast, error) {
+		return forecastService.GetForecastsForCenters([]string{centerID}, time.Now().UTC())
+	}
+	service.SetForecastDetails(details)
+
+	chat := notifier.NewChatWebhookSender()
+	for _, ch := range []string{models.ChannelSlack, models.ChannelDiscord, models.ChannelMattermost} {
+		service.RegisterChannel(ch, chat)
+	}
+	if sms, err := notifier.NewHTTPSMSGatewayFromEnv(); err != nil {
+		log.Printf("sms notifications disabled: %v", err)
+	} else {
+		service.RegisterChannel(models.ChannelSMS, notifier.NewSMSChannel(sms))
+	}
+	if bot, err := notifier.NewSlackBotClientFromEnv(); err != nil {
+		log.Printf("slack channel notifications disabled: %v", err)
+	} else {
+		service.RegisterChannel(models.ChannelSlackApp, bot)
+	}
+	if vapid, err := notifier.VAPIDKeysFromEnv(); err != nil {
+		log.Printf("web push notifications disabled: %v", err)
+	} else {
+		service.RegisterChannel(models.ChannelWebPush, notifier.NewWebPushSender(vapid, repo))
+	}
+	service.AddEventSink(notifier.NewWebhookDispatcher(repo))
+	service.AddEventSink(notifier.NewEventLog(repo))
+	service.AddEventSink(notifier.NewForecastArchive(repo))
+	if replies := notifier.ReplyAddresserFromEnv(); replies != nil {
+		service.SetReplyAddresser(replies)
+	}
+
 	ctx := context.Background()
+	// Trip plan countdowns are due at fixed local times, so they are checked
+	// far more often than forecasts are polled.
+	tripIntervalStr := os.Getenv("NOTIFIER_TRIP_INTERVAL")
+	if tripIntervalStr == "" {
+		tripIntervalStr = "10m"
+	}
+	tripInterval, err := time.ParseDuration(tripIntervalStr)
+	if err != nil {
+		log.Fatalf("invalid NOTIFIER_TRIP_INTERVAL: %v", err)
+	}
+	trips := notifier.NewTripNotifier(repo, emailClient, details, notifier.TripLinksFromEnv(), tripInterval)
+	go func() {
+		if err := trips.Run(ctx); err != nil {
+			log.Printf("trip notifier stopped: %v", err)
+		}
+	}()
+
 	log.Printf("email notifier started; polling every %s", pollInterval)
 	if err := service.Run(ctx); err != nil {
 		log.Fatalf("notifier stopped: %v", err)
@@ -67,19 +129,27 @@ func (a forecastSourceAdapter) FetchForecasts(centerID string) ([]notifier.Forec
 	out := make([]notifier.ForecastSource, 0, len(forecasts))
 	for i := range forecasts {
 		f := forecasts[i]
-		out = append(out, forecastSource{F: &f})
+		out = append(out, forecastSource{F: &f, centerID: centerID})
 	}
 	return out, nil
 }
 
-type forecastSource struct{ F *models.Forecast }
+type forecastSource struct {
+	F        *models.Forecast
+	centerID string
+}
 
+// ZoneIDs returns center-prefixed zone IDs ("NWAC_10") to match subscriptions.
 func (fs forecastSource) ZoneIDs() []string {
 	ids := make([]string, 0, len(fs.F.ForecastZone))
 	for _, z := range fs.F.ForecastZone {
-		ids = append(ids, z.ZoneID)
+		ids = append(ids, fs.centerID+"_"+z.ZoneID)
 	}
 	return ids
 }
 
 func (fs forecastSource) PublishedAt() time.Time { return fs.F.PublishedTime }
+
+func (fs forecastSource) ProductID() int { return fs.F.ID }
+
+func (fs forecastSource) ProductType() string { return fs.F.ProductType }
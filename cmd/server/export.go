@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"example.com/avalanche/internal/app"
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/services"
+)
+
+// runExport implements "server export", writing archived forecast history
+// to stdout or a file:
+//
+//	server export -format ndjson -centers NWAC -from 2024-11-01 -to 2025-04-30 -o nwac.ndjson
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", services.ExportCSV, "output format: csv or ndjson")
+	centers := fs.String("centers", "", "comma-separated center IDs")
+	zones := fs.String("zones", "", "comma-separated zone IDs, e.g. NWAC_10")
+	from := fs.String("from", "", "first day, YYYY-MM-DD")
+	to := fs.String("to", "", "last day, YYYY-MM-DD")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *format != services.ExportCSV && *format != services.ExportNDJSON {
+		return services.ErrUnknownExportFormat
+	}
+
+	filter, err := services.ParseExportFilter(*centers, *zones, *from, *to)
+	if err != nil {
+		return err
+	}
+	dbConn, err := app.OpenDB()
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	buf := bufio.NewWriter(w)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := services.NewExportService(db.NewArchiveRepository(dbConn)).Export(ctx, buf, *format, filter); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
@@ -2,11 +2,19 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"example.com/avalanche/internal/app"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatalf("export failed: %v", err)
+		}
+		return
+	}
+
 	a, err := app.New()
 	if err != nil {
 		log.Fatalf("failed to initialize app: %v", err)
@@ -0,0 +1,19 @@
+// Command vapidkeys prints a new VAPID key pair for Web Push in environment
+// variable form.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"example.com/avalanche/internal/notifier"
+)
+
+func main() {
+	private, public, err := notifier.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatalf("failed to generate keys: %v", err)
+	}
+	fmt.Printf("VAPID_PRIVATE_KEY=%s\n", private)
+	fmt.Printf("# public key, served at /api/push/vapid-public-key\n# %s\n", public)
+}
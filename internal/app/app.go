@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"log"
 	"net/http"
 	"os"
@@ -8,6 +10,7 @@ import (
 
 	"example.com/avalanche/internal/clients"
 	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/geo"
 	"example.com/avalanche/internal/handlers"
 	"example.com/avalanche/internal/models"
 	"example.com/avalanche/internal/notifier"
@@ -25,13 +28,17 @@ type App struct {
 	Router  *http.ServeMux
 }
 
-func New() (*App, error) {
+// OpenDB connects to DATABASE_URL, defaulting to the docker compose database.
+func OpenDB() (*gorm.DB, error) {
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
 		dsn = "postgres://postgres:postgres@db:5432/avalanche?sslmode=disable"
 	}
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+}
 
-	dbConn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func New() (*App, error) {
+	dbConn, err := OpenDB()
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +50,20 @@ func New() (*App, error) {
 			&models.Forecast{},
 			&models.Subscription{},
 			&models.ForecastCache{},
+			&models.EmailDeliveryAttempt{},
+			&models.EmailSuppression{},
+			&models.WebhookEndpoint{},
+			&models.WebhookDelivery{},
+			&models.PhoneVerification{},
+			&models.ForecastEvent{},
+			&models.ArchivedForecast{},
+			&models.ZoneElevationBands{},
+			&models.TripPlan{},
+			&models.Organization{},
+			&models.OrganizationMember{},
+			&models.OrganizationAuditEntry{},
+			&models.ZoneGroup{},
+			&models.ZoneAlias{},
 		); err != nil {
 			return nil, err
 		}
@@ -59,17 +80,143 @@ func New() (*App, error) {
 	service := services.NewForecast(apiClient)
 
 	handler := handlers.NewForecastHandlerWithRepo(service, repo)
+	shapes := geo.NewShapeCache(apiClient, 0)
+	handler.SetZoneShapes(shapes)
 
 	subRepo := db.NewSubscriptionRepository(dbConn)
 
+	notifierRepo := notifier.NewGormRepository(dbConn)
+
 	// Email sender for subscriptions
-	emailSender := notifier.NewSendGridEmailClient()
+	emailSender, err := notifier.NewEmailSenderFromEnv(notifierRepo)
+	if err != nil {
+		log.Printf("email sending disabled: %v", err)
+		emailSender = notifier.LogEmailSender{}
+	}
+	emailSender = notifier.NewSuppressingEmailSender(emailSender, notifierRepo)
 
 	// Create SubscriptionService with all dependencies
 	subService := services.NewSubscriptionService(subRepo, repo, service, emailSender)
+	chat := notifier.NewChatWebhookSender()
+	for _, ch := range []string{models.ChannelSlack, models.ChannelDiscord, models.ChannelMattermost} {
+		subService.RegisterChannel(ch, chat)
+	}
+
+	// SMS subscriptions; numbers are verified by code before alerts are sent
+	var smsSender notifier.SMSSender = notifier.LogSMSSender{}
+	if gateway, err := notifier.NewHTTPSMSGatewayFromEnv(); err != nil {
+		log.Printf("sms sending disabled: %v", err)
+	} else {
+		smsSender = gateway
+	}
+	phoneRepo := db.NewPhoneVerificationRepository(dbConn)
+	subService.EnableSMS(smsSender, phoneRepo)
+
+	// Zone input by name, slug or alias, resolved against the zones in the
+	// active centers' forecasts
+	zoneResolver := services.NewZoneResolver(apiClient, repo, db.NewZoneAliasRepository(dbConn), 0)
+	zoneHandler := handlers.NewZoneHandler(zoneResolver)
 
 	// Create SubscriptionHandler with the service
 	subHandler := handlers.NewSubscriptionHandler(subService)
+	subHandler.SetZoneResolver(zoneResolver)
+
+	// Bounce/complaint processing
+	suppressionService := services.NewSuppressionService(db.NewSuppressionRepository(dbConn), subRepo)
+	var webhookKey *ecdsa.PublicKey
+	if raw := os.Getenv("SENDGRID_WEBHOOK_PUBLIC_KEY"); raw != "" {
+		if webhookKey, err = notifier.ParseSendGridPublicKey(raw); err != nil {
+			return nil, err
+		}
+	}
+	suppressionHandler := handlers.NewSuppressionHandler(suppressionService, webhookKey)
+
+	// Commands sent by replying to forecast emails
+	replies := notifier.ReplyAddresserFromEnv()
+	if replies != nil {
+		subService.SetReplyAddresser(replies)
+	}
+	inboundEmailHandler := handlers.NewInboundEmailHandler(subService, replies, os.Getenv("INBOUND_EMAIL_TOKEN"))
+
+	// Forecast queries texted in from phones and satellite messengers
+	smsQueryConfig, err := services.SMSQueryConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	smsQueries := services.NewSMSQueryService(service, subRepo, phoneRepo, smsSender, smsQueryConfig)
+	smsQueries.SetZoneResolver(zoneResolver)
+	inboundSMSHandler := handlers.NewInboundSMSHandler(smsQueries, os.Getenv("SMS_INBOUND_TOKEN"))
+
+	// Slack app: /avy slash command, subscribe buttons and channel delivery by bot
+	slackBot, err := notifier.NewSlackBotClientFromEnv()
+	if err != nil {
+		log.Printf("slack channel subscriptions disabled: %v", err)
+	} else {
+		subService.RegisterChannel(models.ChannelSlackApp, slackBot)
+	}
+	slackCommands := services.NewSlackCommandService(service, repo, subService, subRepo, slackBot)
+	slackCommands.SetZoneResolver(zoneResolver)
+	slackHandler := handlers.NewSlackHandler(slackCommands, os.Getenv("SLACK_SIGNING_SECRET"))
+
+	// Web Push for browser subscribers
+	var vapidPublicKey string
+	if vapid, err := notifier.VAPIDKeysFromEnv(); err != nil {
+		log.Printf("web push disabled: %v", err)
+	} else {
+		subService.RegisterChannel(models.ChannelWebPush, notifier.NewWebPushSender(vapid, notifierRepo))
+		vapidPublicKey = vapid.PublicKey()
+	}
+	pushHandler := handlers.NewPushHandler(subService, vapidPublicKey)
+	pushHandler.SetZoneResolver(zoneResolver)
+
+	// Live forecast events for dashboards, read from the notifier's event log
+	streamService := services.NewStreamService(db.NewEventRepository(dbConn), 0)
+	go streamService.Run(context.Background())
+	streamHandler := handlers.NewStreamHandler(streamService)
+	streamHandler.SetZoneResolver(zoneResolver)
+
+	// CAP alerts for emergency management partners
+	alertConfig, err := services.AlertConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	capHandler := handlers.NewCAPHandler(services.NewAlertService(apiClient, repo, shapes, alertConfig), os.Getenv("PUBLIC_BASE_URL"))
+
+	// Atom feeds of forecasts archived by the notifier
+	archiveRepo := db.NewArchiveRepository(dbConn)
+	feedHandler := handlers.NewFeedHandler(services.NewFeedService(archiveRepo), os.Getenv("PUBLIC_BASE_URL"))
+	feedHandler.SetZoneResolver(zoneResolver)
+	exportHandler := handlers.NewExportHandler(services.NewExportService(archiveRepo))
+	exportHandler.SetZoneResolver(zoneResolver)
+	badgeHandler := handlers.NewBadgeHandler(services.NewBadgeService(service, archiveRepo))
+	badgeHandler.SetZoneResolver(zoneResolver)
+
+	// Danger along uploaded GPX routes
+	routeHandler := handlers.NewRouteHandler(services.NewRouteService(service, apiClient, repo, shapes,
+		db.NewElevationBandRepository(dbConn)))
+
+	// Trip plans shared by signed links; the notifier sends their countdown
+	tripLinks := notifier.TripLinksFromEnv()
+	if tripLinks == nil {
+		log.Printf("trip plans disabled: TRIP_SHARE_SECRET is not set")
+	}
+	tripMailer, _ := emailSender.(notifier.MessageSender)
+	tripHandler := handlers.NewTripHandler(services.NewTripPlanService(db.NewTripPlanRepository(dbConn), tripLinks, tripMailer))
+
+	// Organizations whose members share a set of zone subscriptions
+	orgRepo := db.NewOrganizationRepository(dbConn)
+	orgHandler := handlers.NewOrganizationHandler(services.NewOrganizationService(orgRepo))
+
+	// Named zone groups, possibly spanning centers, queried and subscribed
+	// to like a single zone
+	groupRepo := db.NewZoneGroupRepository(dbConn)
+	groupService := services.NewZoneGroupService(groupRepo, orgRepo, service, os.Getenv("ZONE_GROUP_SECRET"))
+	subService.SetZoneGroups(groupRepo)
+	handler.SetZoneGroups(groupService)
+	groupHandler := handlers.NewZoneGroupHandler(groupService)
+
+	// Partner webhook endpoints; deliveries are made by the notifier
+	webhookHandler := handlers.NewWebhookHandler(services.NewWebhookService(db.NewWebhookRepository(dbConn)))
 
 	app := &App{
 		DB:      dbConn,
@@ -79,29 +226,179 @@ func New() (*App, error) {
 		Router:  http.NewServeMux(),
 	}
 
-	app.setupRoutes(subHandler)
+	app.setupRoutes(routeHandlers{
+		subscriptions: subHandler,
+		suppressions:  suppressionHandler,
+		webhooks:      webhookHandler,
+		inboundSMS:    inboundSMSHandler,
+		inboundEmail:  inboundEmailHandler,
+		slack:         slackHandler,
+		push:          pushHandler,
+		stream:        streamHandler,
+		cap:           capHandler,
+		feeds:         feedHandler,
+		export:        exportHandler,
+		badges:        badgeHandler,
+		routes:        routeHandler,
+		trips:         tripHandler,
+		organizations: orgHandler,
+		groups:        groupHandler,
+		zones:         zoneHandler,
+		adminToken:    os.Getenv("ADMIN_API_TOKEN"),
+	})
 
 	return app, nil
 }
 
-// New method
-func (a *App) setupRoutes(subHandler *handlers.SubscriptionHandler) {
+// routeHandlers groups the handlers mounted by setupRoutes.
+type routeHandlers struct {
+	subscriptions *handlers.SubscriptionHandler
+	suppressions  *handlers.SuppressionHandler
+	webhooks      *handlers.WebhookHandler
+	inboundSMS    *handlers.InboundSMSHandler
+	inboundEmail  *handlers.InboundEmailHandler
+	slack         *handlers.SlackHandler
+	push          *handlers.PushHandler
+	stream        *handlers.StreamHandler
+	cap           *handlers.CAPHandler
+	feeds         *handlers.FeedHandler
+	export        *handlers.ExportHandler
+	badges        *handlers.BadgeHandler
+	routes        *handlers.RouteHandler
+	trips         *handlers.TripHandler
+	organizations *handlers.OrganizationHandler
+	groups        *handlers.ZoneGroupHandler
+	zones         *handlers.ZoneHandler
+	adminToken    string
+}
+
+func (a *App) setupRoutes(h routeHandlers) {
 	// Subscription routes
 	a.Router.HandleFunc("/api/subscriptions", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			subHandler.GetSubscriptions(w, r)
+			h.subscriptions.GetSubscriptions(w, r)
 		case http.MethodPost:
-			subHandler.CreateSubscription(w, r)
+			h.subscriptions.CreateSubscription(w, r)
 		case http.MethodDelete:
-			subHandler.DeleteSubscription(w, r)
+			h.subscriptions.DeleteSubscription(w, r)
 		default:
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
 
+	a.Router.HandleFunc("/api/subscriptions/verify", h.subscriptions.VerifyPhone)
+
+	// Browser push subscriptions
+	a.Router.HandleFunc("/api/push/vapid-public-key", h.push.PublicKey)
+	a.Router.HandleFunc("/api/push/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			h.push.Subscribe(w, r)
+		case http.MethodDelete:
+			h.push.Unsubscribe(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Provider event webhooks
+	a.Router.HandleFunc("/api/webhooks/sendgrid/events", h.suppressions.HandleSendGridEvents)
+	a.Router.HandleFunc("/api/webhooks/sms/inbound", h.inboundSMS.HandleInbound)
+	a.Router.HandleFunc("/api/webhooks/email/inbound", h.inboundEmail.HandleInbound)
+
+	// Slack app request URLs
+	a.Router.HandleFunc("/api/slack/commands", h.slack.HandleCommand)
+	a.Router.HandleFunc("/api/slack/interactions", h.slack.HandleInteraction)
+
+	// Admin routes
+	a.Router.HandleFunc("/api/admin/suppressions", handlers.RequireAdmin(h.adminToken, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.suppressions.ListSuppressions(w, r)
+		case http.MethodDelete:
+			h.suppressions.ClearSuppression(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	a.Router.HandleFunc("/api/admin/webhooks", handlers.RequireAdmin(h.adminToken, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.webhooks.ListWebhooks(w, r)
+		case http.MethodPost:
+			h.webhooks.CreateWebhook(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+	a.Router.HandleFunc("DELETE /api/admin/webhooks/{id}", handlers.RequireAdmin(h.adminToken, h.webhooks.DeleteWebhook))
+	a.Router.HandleFunc("GET /api/admin/webhooks/{id}/deliveries", handlers.RequireAdmin(h.adminToken, h.webhooks.ListDeliveries))
+	a.Router.HandleFunc("POST /api/admin/webhooks/{id}/enable", handlers.RequireAdmin(h.adminToken, h.webhooks.EnableWebhook))
+
+	a.Router.HandleFunc("GET /api/admin/elevation-bands", handlers.RequireAdmin(h.adminToken, h.routes.ListElevationBands))
+	a.Router.HandleFunc("PUT /api/admin/elevation-bands/{zoneID}", handlers.RequireAdmin(h.adminToken, h.routes.SetElevationBands))
+
+	a.Router.HandleFunc("GET /api/admin/organizations", handlers.RequireAdmin(h.adminToken, h.organizations.ListOrganizations))
+	a.Router.HandleFunc("POST /api/admin/organizations", handlers.RequireAdmin(h.adminToken, h.organizations.CreateOrganization))
+	a.Router.HandleFunc("GET /api/admin/organizations/{id}", handlers.RequireAdmin(h.adminToken, h.organizations.GetOrganization))
+	a.Router.HandleFunc("DELETE /api/admin/organizations/{id}", handlers.RequireAdmin(h.adminToken, h.organizations.DeleteOrganization))
+	a.Router.HandleFunc("PUT /api/admin/organizations/{id}/zones", handlers.RequireAdmin(h.adminToken, h.organizations.SetZones))
+	a.Router.HandleFunc("POST /api/admin/organizations/{id}/members", handlers.RequireAdmin(h.adminToken, h.organizations.AddMembers))
+	a.Router.HandleFunc("DELETE /api/admin/organizations/{id}/members", handlers.RequireAdmin(h.adminToken, h.organizations.RemoveMembers))
+	a.Router.HandleFunc("GET /api/admin/organizations/{id}/audit", handlers.RequireAdmin(h.adminToken, h.organizations.Audit))
+	a.Router.HandleFunc("GET /api/admin/organizations/{id}/groups", handlers.RequireAdmin(h.adminToken, h.groups.ListOrganizationGroups))
+	a.Router.HandleFunc("POST /api/admin/organizations/{id}/groups", handlers.RequireAdmin(h.adminToken, h.groups.CreateOrganizationGroup))
+	a.Router.HandleFunc("PUT /api/admin/groups/{slug}", handlers.RequireAdmin(h.adminToken, h.groups.AdminUpdateGroup))
+	a.Router.HandleFunc("DELETE /api/admin/groups/{slug}", handlers.RequireAdmin(h.adminToken, h.groups.AdminDeleteGroup))
+
+	a.Router.HandleFunc("GET /api/admin/zone-aliases", handlers.RequireAdmin(h.adminToken, h.zones.ListAliases))
+	a.Router.HandleFunc("PUT /api/admin/zone-aliases/{alias}", handlers.RequireAdmin(h.adminToken, h.zones.SetAlias))
+	a.Router.HandleFunc("DELETE /api/admin/zone-aliases/{alias}", handlers.RequireAdmin(h.adminToken, h.zones.DeleteAlias))
+	a.Router.HandleFunc("POST /api/admin/zones/renumber", handlers.RequireAdmin(h.adminToken, h.zones.Renumber))
+
 	// Forecast routes
 	a.Router.HandleFunc("/api/forecast", a.Handler.GetForecast)
+	a.Router.HandleFunc("GET /api/forecast.kml", a.Handler.GetForecastKML)
+	a.Router.HandleFunc("GET /api/forecast.kmz", a.Handler.GetForecastKML)
+	a.Router.HandleFunc("GET /api/centers/{id}/map.svg", a.Handler.GetCenterMap)
+	a.Router.HandleFunc("/api/stream", h.stream.Stream)
+
+	// CAP alerts
+	a.Router.HandleFunc("GET /api/cap/alerts.atom", h.cap.Index)
+	a.Router.HandleFunc("GET /api/cap/alerts/{id}", h.cap.GetAlert)
+
+	// Atom feeds
+	a.Router.HandleFunc("GET /feeds/zones/{file}", h.feeds.ZoneFeed)
+	a.Router.HandleFunc("GET /feeds/centers/{file}", h.feeds.CenterFeed)
+
+	// Bulk export of forecast history
+	a.Router.HandleFunc("GET /api/export/forecasts", h.export.ExportForecasts)
+
+	// Route planning
+	a.Router.HandleFunc("POST /api/route-assessment", h.routes.AssessRoute)
+	a.Router.HandleFunc("POST /api/trips", h.trips.CreateTrip)
+	a.Router.HandleFunc("GET /api/trips/{token}", h.trips.GetTrip)
+	a.Router.HandleFunc("DELETE /api/trips/{token}", h.trips.CancelTrip)
+	a.Router.HandleFunc("POST /api/trips/{token}/participants", h.trips.JoinTrip)
+	a.Router.HandleFunc("GET /api/trips/{token}/confirm", h.trips.ConfirmTrip)
+	a.Router.HandleFunc("GET /api/trips/{token}/leave", h.trips.LeaveTrip)
+
+	// Zone groups
+	a.Router.HandleFunc("GET /api/groups", h.groups.ListGroups)
+	a.Router.HandleFunc("POST /api/groups", h.groups.CreateGroup)
+	a.Router.HandleFunc("GET /api/groups/{slug}", h.groups.GetGroup)
+	a.Router.HandleFunc("PUT /api/groups/{slug}", h.groups.UpdateGroup)
+	a.Router.HandleFunc("DELETE /api/groups/{slug}", h.groups.DeleteGroup)
+
+	// Zone catalog
+	a.Router.HandleFunc("GET /api/zones", h.zones.ListZones)
+	a.Router.HandleFunc("GET /api/zones/resolve", h.zones.ResolveZone)
+
+	// Embeddable danger images
+	a.Router.HandleFunc("GET /api/zones/{zoneID}/badge.svg", h.badges.Badge)
+	a.Router.HandleFunc("GET /api/zones/{zoneID}/history.svg", h.badges.History)
 
 	// Health check
 	a.Router.HandleFunc("/api/health", func(w http.ResponseWriter, _ *http.Request) {
@@ -0,0 +1,89 @@
+// Package atom writes Atom 1.0 (RFC 4287) feeds.
+package atom
+
+import (
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ContentType is the Atom feed media type.
+const ContentType = "application/atom+xml"
+
+// Feed is an Atom feed document.
+type Feed struct {
+	XMLName  xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string   `xml:"id"`
+	Title    string   `xml:"title"`
+	Subtitle string   `xml:"subtitle,omitempty"`
+	Updated  string   `xml:"updated"`
+	Author   *Person  `xml:"author,omitempty"`
+	Links    []Link   `xml:"link"`
+	Entries  []Entry  `xml:"entry"`
+}
+
+// Entry is a single feed entry.
+type Entry struct {
+	ID        string  `xml:"id"`
+	Title     string  `xml:"title"`
+	Updated   string  `xml:"updated"`
+	Published string  `xml:"published,omitempty"`
+	Author    *Person `xml:"author,omitempty"`
+	Links     []Link  `xml:"link"`
+	Summary   *Text   `xml:"summary,omitempty"`
+	Content   *Text   `xml:"content,omitempty"`
+}
+
+// Person names an author.
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// Link is an Atom link; Rel defaults to "alternate" when empty.
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// Text is a text construct, either "text" or "html".
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// Time formats t as an RFC 3339 timestamp in UTC.
+func Time(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Write encodes f with an XML declaration.
+func Write(w io.Writer, f Feed) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("failed to encode atom feed: %w", err)
+	}
+	return enc.Close()
+}
+
+// uuidNamespaceURL is the RFC 4122 namespace for URL names.
+var uuidNamespaceURL = [16]byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+// NameID returns a stable "urn:uuid:" identifier derived from name (a
+// version 5 UUID), so feed and entry IDs survive host or path changes.
+func NameID(name string) string {
+	h := sha1.New()
+	h.Write(uuidNamespaceURL[:])
+	h.Write([]byte(name))
+	u := h.Sum(nil)[:16]
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
@@ -0,0 +1,147 @@
+// Package badge renders forecast danger as small SVG images for embedding in
+// web pages and emails.
+package badge
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+)
+
+// ContentType is the media type of the rendered images.
+const ContentType = "image/svg+xml"
+
+// Band is one elevation band's danger rating.
+type Band struct {
+	Name  string
+	Level domain.DangerLevel
+}
+
+// Day is a zone's highest danger rating on one day. Level is 0 for days
+// without a forecast.
+type Day struct {
+	Date  string
+	Level domain.DangerLevel
+}
+
+const (
+	zoneWidth   = 220
+	rowHeight   = 22
+	titleLength = 32
+
+	historyWidth  = 240
+	historyHeight = 40
+	historyPad    = 4
+)
+
+// Zone renders a card with a title bar and one row per elevation band: the
+// band name, the danger scale icon and the level name on the scale color.
+func Zone(title string, bands []Band) []byte {
+	height := rowHeight * (len(bands) + 1)
+	var desc []string
+	for _, b := range bands {
+		desc = append(desc, b.Name+": "+b.Level.Name())
+	}
+
+	var b strings.Builder
+	writeHeader(&b, zoneWidth, height, title+". "+strings.Join(desc, ", "))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" rx="3" fill="#fff" stroke="#999"/>`, zoneWidth, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" rx="3" fill="#333"/>`, zoneWidth, rowHeight)
+	fmt.Fprintf(&b, `<text x="8" y="15" font-weight="bold" fill="#fff">%s</text>`, escape(truncate(title, titleLength)))
+	for i, band := range bands {
+		y := rowHeight * (i + 1)
+		fmt.Fprintf(&b, `<text x="8" y="%d" fill="#222">%s</text>`, y+15, escape(band.Name))
+		writeIcon(&b, 122, y+rowHeight/2, band.Level)
+		fmt.Fprintf(&b, `<rect x="136" y="%d" width="%d" height="%d" fill="%s"/>`, y, zoneWidth-136, rowHeight, band.Level.Color())
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle" fill="%s">%s</text>`,
+			(136+zoneWidth)/2, y+15, textColor(band.Level), escape(band.Level.Name()))
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// History renders a sparkline of daily danger, oldest day first. Rated days
+// are dots in the scale color joined by a line; unrated days leave a gap.
+func History(title string, days []Day) []byte {
+	var b strings.Builder
+	writeHeader(&b, historyWidth, historyHeight, title)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`, historyWidth, historyHeight)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#ddd"/>`,
+		historyPad, historyHeight-historyPad, historyWidth-historyPad, historyHeight-historyPad)
+
+	x := func(i int) float64 {
+		if len(days) < 2 {
+			return historyWidth / 2
+		}
+		return historyPad + float64(i)*float64(historyWidth-2*historyPad)/float64(len(days)-1)
+	}
+	y := func(level domain.DangerLevel) float64 {
+		return historyHeight - historyPad - float64(level)*float64(historyHeight-2*historyPad)/5
+	}
+
+	var path strings.Builder
+	drawing := false
+	for i, d := range days {
+		if !d.Level.IsRated() {
+			drawing = false
+			continue
+		}
+		cmd := "L"
+		if !drawing {
+			cmd = "M"
+		}
+		fmt.Fprintf(&path, "%s%.1f %.1f", cmd, x(i), y(d.Level))
+		drawing = true
+	}
+	if path.Len() > 0 {
+		fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="#555" stroke-width="1.5"/>`, path.String())
+	}
+	for i, d := range days {
+		if d.Level.IsRated() {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s" stroke="#222" stroke-width="0.5"><title>%s: %s</title></circle>`,
+				x(i), y(d.Level), d.Level.Color(), escape(d.Date), d.Level.Name())
+		}
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+func writeHeader(b *strings.Builder, width, height int, title string) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s" font-family="Arial,Helvetica,sans-serif" font-size="11">`,
+		width, height, width, height, escape(title))
+	fmt.Fprintf(b, `<title>%s</title>`, escape(title))
+}
+
+// writeIcon draws the danger scale's diamond icon centered on cx, cy, with
+// the level number inside. Unrated bands get an empty grey diamond.
+func writeIcon(b *strings.Builder, cx, cy int, level domain.DangerLevel) {
+	const r = 9
+	fmt.Fprintf(b, `<polygon points="%d,%d %d,%d %d,%d %d,%d" fill="%s" stroke="#222"/>`,
+		cx, cy-r, cx+r, cy, cx, cy+r, cx-r, cy, level.Color())
+	if level.IsRated() {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-size="10" font-weight="bold" fill="%s">%d</text>`,
+			cx, cy+4, textColor(level), level)
+	}
+}
+
+// textColor keeps labels readable on the darker scale colors.
+func textColor(level domain.DangerLevel) string {
+	if level >= domain.DangerHigh {
+		return "#fff"
+	}
+	return "#222"
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
+
+func truncate(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(r[:limit-1])) + "…"
+}
@@ -0,0 +1,73 @@
+package badge_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/badge"
+)
+
+func wellFormed(t *testing.T, svg []byte) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(string(svg)))
+	for {
+		_, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			t.Fatalf("malformed SVG: %v\n%s", err, svg)
+		}
+	}
+}
+
+func TestZone(t *testing.T) {
+	svg := badge.Zone("Snoqualmie Pass & Alpental", []badge.Band{
+		{Name: "Above treeline", Level: 4},
+		{Name: "Near treeline", Level: 3},
+		{Name: "Below treeline", Level: 0},
+	})
+	wellFormed(t, svg)
+	out := string(svg)
+	for _, want := range []string{
+		`height="88"`,
+		"Snoqualmie Pass &amp; Alpental",
+		`fill="#ED1C24"`, ">High<",
+		`fill="#F7941E"`, ">Considerable<",
+		`fill="#CCCCCC"`, ">No Rating<",
+		"Above treeline: High, Near treeline: Considerable, Below treeline: No Rating",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("badge missing %q", want)
+		}
+	}
+	if got := string(badge.Zone("Snoqualmie Pass & Alpental", nil)); got == out {
+		t.Error("expected different bands to change the badge")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	svg := badge.History("Snoqualmie Pass", []badge.Day{
+		{Date: "2025-01-01", Level: 2},
+		{Date: "2025-01-02", Level: 3},
+		{Date: "2025-01-03", Level: 0},
+		{Date: "2025-01-04", Level: 5},
+	})
+	wellFormed(t, svg)
+	out := string(svg)
+	if n := strings.Count(out, "<circle"); n != 3 {
+		t.Errorf("expected a dot per rated day, got %d", n)
+	}
+	// The unrated day breaks the line into two segments.
+	_, path, _ := strings.Cut(out, `<path d="`)
+	path, _, _ = strings.Cut(path, `"`)
+	if n := strings.Count(path, "M"); n != 2 {
+		t.Errorf("expected two line segments, got path %q", path)
+	}
+	if !strings.Contains(out, "2025-01-04: Extreme") {
+		t.Error("expected per-day tooltips")
+	}
+}
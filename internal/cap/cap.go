@@ -0,0 +1,125 @@
+// Package cap writes Common Alerting Protocol 1.2 alert messages.
+package cap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the CAP media type.
+const ContentType = "application/cap+xml"
+
+// Values used by the alerts this service publishes. See the CAP 1.2
+// specification for the full code lists.
+const (
+	StatusActual = "Actual"
+	MsgTypeAlert = "Alert"
+	ScopePublic  = "Public"
+
+	CategoryGeo    = "Geo"
+	CategorySafety = "Safety"
+
+	ResponseAvoid   = "Avoid"
+	ResponseMonitor = "Monitor"
+
+	UrgencyImmediate = "Immediate"
+	UrgencyExpected  = "Expected"
+	UrgencyFuture    = "Future"
+
+	SeverityExtreme  = "Extreme"
+	SeveritySevere   = "Severe"
+	SeverityModerate = "Moderate"
+	SeverityMinor    = "Minor"
+
+	CertaintyLikely = "Likely"
+)
+
+// Alert is a CAP alert message. Field order follows the schema, which
+// requires elements in sequence.
+type Alert struct {
+	XMLName    xml.Name `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+	Identifier string   `xml:"identifier"`
+	Sender     string   `xml:"sender"`
+	Sent       string   `xml:"sent"`
+	Status     string   `xml:"status"`
+	MsgType    string   `xml:"msgType"`
+	Scope      string   `xml:"scope"`
+	Info       []Info   `xml:"info"`
+}
+
+// Info describes the hazard for one language.
+type Info struct {
+	Language     string      `xml:"language"`
+	Category     []string    `xml:"category"`
+	Event        string      `xml:"event"`
+	ResponseType []string    `xml:"responseType,omitempty"`
+	Urgency      string      `xml:"urgency"`
+	Severity     string      `xml:"severity"`
+	Certainty    string      `xml:"certainty"`
+	Effective    string      `xml:"effective,omitempty"`
+	Expires      string      `xml:"expires,omitempty"`
+	SenderName   string      `xml:"senderName,omitempty"`
+	Headline     string      `xml:"headline,omitempty"`
+	Description  string      `xml:"description,omitempty"`
+	Instruction  string      `xml:"instruction,omitempty"`
+	Web          string      `xml:"web,omitempty"`
+	Parameters   []Parameter `xml:"parameter"`
+	Areas        []Area      `xml:"area"`
+}
+
+// Parameter is a system-specific name/value pair.
+type Parameter struct {
+	ValueName string `xml:"valueName"`
+	Value     string `xml:"value"`
+}
+
+// Area is the affected area; each polygon is a closed ring of "lat,lon" pairs.
+type Area struct {
+	AreaDesc string      `xml:"areaDesc"`
+	Polygons []string    `xml:"polygon"`
+	Geocodes []Parameter `xml:"geocode"`
+}
+
+// Time formats t as CAP requires: second precision with a numeric offset,
+// where UTC is written "-00:00".
+func Time(t time.Time) string {
+	s := t.UTC().Format("2006-01-02T15:04:05-07:00")
+	return strings.TrimSuffix(s, "+00:00") + "-00:00"
+}
+
+// Polygon formats a GeoJSON ring of [longitude, latitude] positions as a CAP
+// polygon. It returns "" for rings with fewer than four positions. An open
+// ring is closed.
+func Polygon(ring [][]float64) string {
+	parts := make([]string, 0, len(ring)+1)
+	for _, pos := range ring {
+		if len(pos) < 2 {
+			continue
+		}
+		parts = append(parts, strconv.FormatFloat(pos[1], 'f', -1, 64)+","+strconv.FormatFloat(pos[0], 'f', -1, 64))
+	}
+	if len(parts) > 0 && parts[0] != parts[len(parts)-1] {
+		parts = append(parts, parts[0])
+	}
+	if len(parts) < 4 {
+		return ""
+	}
+	return strings.Join(parts, " ")
+}
+
+// Write encodes a with an XML declaration.
+func Write(w io.Writer, a Alert) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(a); err != nil {
+		return fmt.Errorf("failed to encode cap alert: %w", err)
+	}
+	return enc.Close()
+}
@@ -0,0 +1,46 @@
+package cap_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/cap"
+)
+
+func TestTime(t *testing.T) {
+	at := time.Date(2025, 1, 15, 7, 30, 0, 0, time.FixedZone("PST", -8*3600))
+	if got := cap.Time(at); got != "2025-01-15T15:30:00-00:00" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	open := [][]float64{{-121.5, 47.4}, {-121.3, 47.4}, {-121.3, 47.6}}
+	if got := cap.Polygon(open); got != "47.4,-121.5 47.4,-121.3 47.6,-121.3 47.4,-121.5" {
+		t.Errorf("got %q", got)
+	}
+	if got := cap.Polygon(open[:2]); got != "" {
+		t.Errorf("expected no polygon for a degenerate ring, got %q", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := cap.Write(&buf, cap.Alert{
+		Identifier: "NWAC-1-1",
+		Sender:     "alerts@example.com",
+		Status:     cap.StatusActual,
+		Info:       []cap.Info{{Event: "Avalanche Warning", Areas: []cap.Area{{AreaDesc: "Snoqualmie Pass"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{`<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">`, "<identifier>NWAC-1-1</identifier>", "<areaDesc>Snoqualmie Pass</areaDesc>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
     "net/http"
     "net/url"
 
+    "example.com/avalanche/internal/geo"
     "example.com/avalanche/internal/models"
 )
 
@@ -53,3 +54,67 @@ func (a *AvalancheAPIClient) FetchForecasts(centerID string) ([]models.Forecast,
     }
     return data, nil
 }
+
+// mapLayer is the subset of the map-layer FeatureCollection used for zone shapes.
+type mapLayer struct {
+    Features []struct {
+        ID         json.Number   `json:"id"`
+        Geometry   *geo.Geometry `json:"geometry"`
+        Properties struct {
+            CenterID string `json:"center_id"`
+        } `json:"properties"`
+    } `json:"features"`
+}
+
+// FetchZoneShapes loads a center's zone polygons from the map-layer endpoint,
+// keyed by zone ID (e.g. "NWAC_10").
+func (a *AvalancheAPIClient) FetchZoneShapes(centerID string) (map[string]*geo.Geometry, error) {
+    resp, err := a.HTTPClient.Get(a.BaseURL + "/products/map-layer/" + url.PathEscape(centerID))
+    if err != nil {
+        return nil, fmt.Errorf("map layer fetch failed for %s: %w", centerID, err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+        return nil, fmt.Errorf("bad response: %s", string(body))
+    }
+
+    var layer mapLayer
+    if err := json.NewDecoder(resp.Body).Decode(&layer); err != nil {
+        return nil, fmt.Errorf("decode error: %w", err)
+    }
+    shapes := make(map[string]*geo.Geometry, len(layer.Features))
+    for _, f := range layer.Features {
+        if f.Geometry == nil || f.ID == "" {
+            continue
+        }
+        center := f.Properties.CenterID
+        if center == "" {
+            center = centerID
+        }
+        shapes[center+"_"+f.ID.String()] = f.Geometry
+    }
+    return shapes, nil
+}
+
+// FetchProduct loads a single forecast product, which unlike the product list
+// includes its avalanche problems.
+func (a *AvalancheAPIClient) FetchProduct(productID int) (*models.Forecast, error) {
+    resp, err := a.HTTPClient.Get(fmt.Sprintf("%s/product/%d", a.BaseURL, productID))
+    if err != nil {
+        return nil, fmt.Errorf("product fetch failed for %d: %w", productID, err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+        return nil, fmt.Errorf("bad response: %s", string(body))
+    }
+
+    var product models.Forecast
+    if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+        return nil, fmt.Errorf("decode error: %w", err)
+    }
+    return &product, nil
+}
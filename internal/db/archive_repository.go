@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+)
+
+// ArchiveRepository reads the forecast archive written by the notifier.
+type ArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) *ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// ListByZone returns up to limit archived forecasts for a zone, most recently
+// amended first.
+func (r *ArchiveRepository) ListByZone(zoneID string, limit int) ([]models.ArchivedForecast, error) {
+	var out []models.ArchivedForecast
+	err := r.db.Where("zone_id = ?", zoneID).Order("amended_at DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+// ListByCenter returns up to limit archived forecasts across a center's zones,
+// most recently amended first.
+func (r *ArchiveRepository) ListByCenter(centerID string, limit int) ([]models.ArchivedForecast, error) {
+	var out []models.ArchivedForecast
+	err := r.db.Where("center_id = ?", centerID).Order("amended_at DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+// ArchiveFilter selects archived forecasts for export. Empty fields match
+// everything; From and To are inclusive YYYY-MM-DD dates.
+type ArchiveFilter struct {
+	CenterIDs []string
+	ZoneIDs   []string
+	From, To  string
+}
+
+// EachForecast calls fn for every archived forecast (not warning) matching
+// filter, ordered by day and zone with the latest amendment of each zone's
+// day first. Rows are read one at a time, so memory use does not grow with
+// the result size. Iteration stops at the first error from fn.
+func (r *ArchiveRepository) EachForecast(ctx context.Context, filter ArchiveFilter, fn func(models.ArchivedForecast) error) error {
+	q := r.db.WithContext(ctx).Model(&models.ArchivedForecast{}).Where("product_type = ?", "forecast")
+	if len(filter.CenterIDs) > 0 {
+		q = q.Where("center_id IN ?", filter.CenterIDs)
+	}
+	if len(filter.ZoneIDs) > 0 {
+		q = q.Where("zone_id IN ?", filter.ZoneIDs)
+	}
+	if filter.From != "" {
+		q = q.Where("valid_date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		q = q.Where("valid_date <= ?", filter.To)
+	}
+	rows, err := q.Order("valid_date ASC, zone_id ASC, amended_at DESC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f models.ArchivedForecast
+		if err := r.db.ScanRows(rows, &f); err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ElevationBandRepository stores the elevations separating each zone's danger
+// rating bands.
+type ElevationBandRepository struct {
+	db *gorm.DB
+}
+
+func NewElevationBandRepository(db *gorm.DB) *ElevationBandRepository {
+	return &ElevationBandRepository{db: db}
+}
+
+// Upsert inserts or replaces a zone's band elevations.
+func (r *ElevationBandRepository) Upsert(b *models.ZoneElevationBands) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "zone_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"near_treeline_m", "above_treeline_m", "updated_at"}),
+	}).Create(b).Error
+}
+
+// ForZones returns the band elevations configured for zoneIDs, keyed by zone
+// ID. Zones without any are absent.
+func (r *ElevationBandRepository) ForZones(zoneIDs []string) (map[string]models.ZoneElevationBands, error) {
+	out := make(map[string]models.ZoneElevationBands)
+	if len(zoneIDs) == 0 {
+		return out, nil
+	}
+	var rows []models.ZoneElevationBands
+	if err := r.db.Where("zone_id IN ?", zoneIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range rows {
+		out[b.ZoneID] = b
+	}
+	return out, nil
+}
+
+func (r *ElevationBandRepository) List() ([]models.ZoneElevationBands, error) {
+	var out []models.ZoneElevationBands
+	err := r.db.Order("zone_id").Find(&out).Error
+	return out, err
+}
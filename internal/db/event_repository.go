@@ -0,0 +1,29 @@
+package db
+
+import (
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+)
+
+// EventRepository reads the forecast event log written by the notifier.
+type EventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// ListAfter returns up to limit events with Seq greater than after, oldest first.
+func (r *EventRepository) ListAfter(after uint64, limit int) ([]models.ForecastEvent, error) {
+	var events []models.ForecastEvent
+	err := r.db.Where("seq > ?", after).Order("seq ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// LatestSeq returns the highest Seq in the log, or 0 when it is empty.
+func (r *EventRepository) LatestSeq() (uint64, error) {
+	var seq uint64
+	err := r.db.Model(&models.ForecastEvent{}).Select("COALESCE(MAX(seq), 0)").Scan(&seq).Error
+	return seq, err
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"errors"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// Transaction runs fn with a repository bound to a single transaction.
+func (r *OrganizationRepository) Transaction(fn func(tx *OrganizationRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&OrganizationRepository{db: tx})
+	})
+}
+
+func (r *OrganizationRepository) Create(org *models.Organization) error {
+	return r.db.Create(org).Error
+}
+
+func (r *OrganizationRepository) Get(id uint) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.First(&org, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepository) List() ([]models.Organization, error) {
+	var out []models.Organization
+	err := r.db.Order("id").Find(&out).Error
+	return out, err
+}
+
+func (r *OrganizationRepository) SetZones(id uint, zoneIDs []string) error {
+	return r.db.Model(&models.Organization{ID: id}).Select("zone_ids", "updated_at").
+		Updates(&models.Organization{ZoneIDs: zoneIDs}).Error
+}
+
+// Delete removes an organization with its members, zone groups and the
+// subscriptions it manages. The audit log is kept.
+func (r *OrganizationRepository) Delete(id uint) error {
+	if err := r.db.Where("organization_id = ?", id).Delete(&models.Subscription{}).Error; err != nil {
+		return err
+	}
+	var groups []models.ZoneGroup
+	if err := r.db.Where("organization_id = ?", id).Find(&groups).Error; err != nil {
+		return err
+	}
+	for i := range groups {
+		if err := r.db.Where("zone_id = ?", groups[i].Ref()).Delete(&models.Subscription{}).Error; err != nil {
+			return err
+		}
+		if err := r.db.Delete(&models.ZoneGroup{}, groups[i].ID).Error; err != nil {
+			return err
+		}
+	}
+	if err := r.db.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.Organization{}, id).Error
+}
+
+func (r *OrganizationRepository) Members(orgID uint) ([]models.OrganizationMember, error) {
+	var out []models.OrganizationMember
+	err := r.db.Where("organization_id = ?", orgID).Order("email").Find(&out).Error
+	return out, err
+}
+
+// SaveMember inserts a member or updates its role.
+func (r *OrganizationRepository) SaveMember(m *models.OrganizationMember) error {
+	return r.db.Save(m).Error
+}
+
+func (r *OrganizationRepository) DeleteMember(id uint) error {
+	return r.db.Delete(&models.OrganizationMember{}, id).Error
+}
+
+// MemberSubscriptions returns the subscriptions an organization manages for email.
+func (r *OrganizationRepository) MemberSubscriptions(orgID uint, email string) ([]models.Subscription, error) {
+	var out []models.Subscription
+	err := r.db.Where("organization_id = ? AND email = ?", orgID, email).Find(&out).Error
+	return out, err
+}
+
+// HasEmailSubscription reports whether email already gets zoneID's forecasts
+// by email, whether personally or through any organization.
+func (r *OrganizationRepository) HasEmailSubscription(email, zoneID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Subscription{}).
+		Where("email = ? AND zone_id = ? AND channel = ?", email, zoneID, models.ChannelEmail).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *OrganizationRepository) CreateSubscription(sub *models.Subscription) error {
+	return r.db.Create(sub).Error
+}
+
+func (r *OrganizationRepository) DeleteSubscription(id uint) error {
+	return r.db.Delete(&models.Subscription{}, id).Error
+}
+
+func (r *OrganizationRepository) AppendAudit(entry *models.OrganizationAuditEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// Audit returns an organization's most recent audit entries, newest first.
+func (r *OrganizationRepository) Audit(orgID uint, limit int) ([]models.OrganizationAuditEntry, error) {
+	var out []models.OrganizationAuditEntry
+	err := r.db.Where("organization_id = ?", orgID).Order("id DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PhoneVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewPhoneVerificationRepository(db *gorm.DB) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{db: db}
+}
+
+func (r *PhoneVerificationRepository) Get(phone string) (*models.PhoneVerification, error) {
+	var v models.PhoneVerification
+	err := r.db.First(&v, "phone = ?", phone).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// StartVerification stores a new pending code for phone, resetting attempts.
+func (r *PhoneVerificationRepository) StartVerification(phone, codeHash string, expiresAt time.Time) error {
+	v := models.PhoneVerification{Phone: phone, CodeHash: codeHash, ExpiresAt: expiresAt}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "phone"}},
+		DoUpdates: clause.AssignmentColumns([]string{"code_hash", "expires_at", "attempts", "updated_at"}),
+	}).Create(&v).Error
+}
+
+func (r *PhoneVerificationRepository) IncrementAttempts(phone string) error {
+	return r.db.Model(&models.PhoneVerification{}).
+		Where("phone = ?", phone).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (r *PhoneVerificationRepository) MarkVerified(phone string, at time.Time) error {
+	return r.db.Model(&models.PhoneVerification{}).
+		Where("phone = ?", phone).
+		Update("verified_at", at).Error
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"time"
 
 	"example.com/avalanche/internal/models"
@@ -23,6 +24,15 @@ func (r *SubscriptionRepository) Delete(email, zoneID string) error {
 	return r.db.Where("email = ? AND zone_id = ?", email, zoneID).Delete(&models.Subscription{}).Error
 }
 
+func (r *SubscriptionRepository) DeleteByTarget(target, zoneID string) error {
+	return r.db.Where("target = ? AND zone_id = ?", target, zoneID).Delete(&models.Subscription{}).Error
+}
+
+// DeleteAllByTarget removes every subscription addressed to target.
+func (r *SubscriptionRepository) DeleteAllByTarget(target string) error {
+	return r.db.Where("target = ?", target).Delete(&models.Subscription{}).Error
+}
+
 func (r *SubscriptionRepository) GetByZone(zoneID string) ([]models.Subscription, error) {
 	var subs []models.Subscription
 	err := r.db.Where("zone_id = ?", zoneID).Find(&subs).Error
@@ -38,3 +48,74 @@ func (r *SubscriptionRepository) GetByEmail(email string) ([]models.Subscription
 func (r *SubscriptionRepository) UpdateLastNotified(subID int, t time.Time) error {
 	return r.db.Model(&models.Subscription{}).Where("id = ?", subID).Update("last_notified", t).Error
 }
+
+// PauseByEmail pauses every active subscription for email and returns how many were paused.
+func (r *SubscriptionRepository) PauseByEmail(email, reason string, at time.Time) (int64, error) {
+	res := r.db.Model(&models.Subscription{}).
+		Where("email = ? AND paused_at IS NULL", email).
+		Updates(map[string]any{"paused_at": at, "pause_reason": reason})
+	return res.RowsAffected, res.Error
+}
+
+// ResumeByEmail un-pauses subscriptions for email that were paused for reason.
+func (r *SubscriptionRepository) ResumeByEmail(email, reason string) (int64, error) {
+	res := r.db.Model(&models.Subscription{}).
+		Where("email = ? AND pause_reason = ?", email, reason).
+		Updates(map[string]any{"paused_at": nil, "pause_reason": ""})
+	return res.RowsAffected, res.Error
+}
+
+// GetByTarget returns the subscriptions addressed to a non-email target.
+func (r *SubscriptionRepository) GetByTarget(target string) ([]models.Subscription, error) {
+	var subs []models.Subscription
+	err := r.db.Where("target = ?", target).Find(&subs).Error
+	return subs, err
+}
+
+// ResumeByTarget un-pauses subscriptions for target that were paused for reason.
+func (r *SubscriptionRepository) ResumeByTarget(target, reason string) (int64, error) {
+	res := r.db.Model(&models.Subscription{}).
+		Where("target = ? AND pause_reason = ?", target, reason).
+		Updates(map[string]any{"paused_at": nil, "pause_reason": ""})
+	return res.RowsAffected, res.Error
+}
+
+// PauseByTarget pauses every active subscription for target and returns how many were paused.
+func (r *SubscriptionRepository) PauseByTarget(target, reason string, at time.Time) (int64, error) {
+	res := r.db.Model(&models.Subscription{}).
+		Where("target = ? AND paused_at IS NULL", target).
+		Updates(map[string]any{"paused_at": at, "pause_reason": reason})
+	return res.RowsAffected, res.Error
+}
+
+func (r *SubscriptionRepository) GetByID(id uint) (*models.Subscription, error) {
+	var sub models.Subscription
+	err := r.db.First(&sub, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *SubscriptionRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.Subscription{}, id).Error
+}
+
+// Pause pauses a subscription until the given time; a nil until pauses it indefinitely.
+func (r *SubscriptionRepository) Pause(id uint, reason string, at time.Time, until *time.Time) error {
+	return r.db.Model(&models.Subscription{}).Where("id = ?", id).
+		Updates(map[string]any{"paused_at": at, "paused_until": until, "pause_reason": reason}).Error
+}
+
+// Resume clears any pause on a subscription.
+func (r *SubscriptionRepository) Resume(id uint) error {
+	return r.db.Model(&models.Subscription{}).Where("id = ?", id).
+		Updates(map[string]any{"paused_at": nil, "paused_until": nil, "pause_reason": ""}).Error
+}
+
+func (r *SubscriptionRepository) SetDigest(id uint, digest bool) error {
+	return r.db.Model(&models.Subscription{}).Where("id = ?", id).Update("digest", digest).Error
+}
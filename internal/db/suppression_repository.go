@@ -0,0 +1,49 @@
+package db
+
+import (
+	"errors"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SuppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuppressionRepository(db *gorm.DB) *SuppressionRepository {
+	return &SuppressionRepository{db: db}
+}
+
+// Upsert inserts a suppression or refreshes the reason of an existing one.
+func (r *SuppressionRepository) Upsert(s *models.EmailSuppression) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "detail", "source", "updated_at"}),
+	}).Create(s).Error
+}
+
+func (r *SuppressionRepository) Get(email string) (*models.EmailSuppression, error) {
+	var s models.EmailSuppression
+	err := r.db.First(&s, "email = ?", email).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SuppressionRepository) List() ([]models.EmailSuppression, error) {
+	var out []models.EmailSuppression
+	err := r.db.Order("updated_at DESC").Find(&out).Error
+	return out, err
+}
+
+// Delete removes a suppression and reports whether one existed.
+func (r *SuppressionRepository) Delete(email string) (bool, error) {
+	res := r.db.Where("email = ?", email).Delete(&models.EmailSuppression{})
+	return res.RowsAffected > 0, res.Error
+}
@@ -0,0 +1,61 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSuppressionRepository_UpsertAndPauseSubscriptions(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.EmailSuppression{}, &models.Subscription{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	supps := db.NewSuppressionRepository(gdb)
+	subs := db.NewSubscriptionRepository(gdb)
+
+	for _, zone := range []string{"NWAC_10", "NWAC_2"} {
+		if err := subs.Create(&models.Subscription{ZoneID: zone, Email: "gone@example.com"}); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	if err := supps.Upsert(&models.EmailSuppression{Email: "gone@example.com", Reason: models.SuppressionBounce}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if err := supps.Upsert(&models.EmailSuppression{Email: "gone@example.com", Reason: models.SuppressionSpamReport}); err != nil {
+		t.Fatalf("second upsert: %v", err)
+	}
+	got, err := supps.Get("gone@example.com")
+	if err != nil || got == nil || got.Reason != models.SuppressionSpamReport {
+		t.Fatalf("expected refreshed reason, got %+v (%v)", got, err)
+	}
+
+	paused, err := subs.PauseByEmail("gone@example.com", models.PauseReasonSuppressed, time.Now())
+	if err != nil || paused != 2 {
+		t.Fatalf("expected 2 paused, got %d (%v)", paused, err)
+	}
+	list, _ := subs.GetByEmail("gone@example.com")
+	for _, s := range list {
+		if !s.IsPaused() {
+			t.Fatalf("expected subscription %d to be paused", s.ID)
+		}
+	}
+
+	found, err := supps.Delete("gone@example.com")
+	if err != nil || !found {
+		t.Fatalf("expected delete to find suppression: %v", err)
+	}
+	resumed, err := subs.ResumeByEmail("gone@example.com", models.PauseReasonSuppressed)
+	if err != nil || resumed != 2 {
+		t.Fatalf("expected 2 resumed, got %d (%v)", resumed, err)
+	}
+}
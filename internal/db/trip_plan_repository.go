@@ -0,0 +1,44 @@
+package db
+
+import (
+	"errors"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+)
+
+type TripPlanRepository struct {
+	db *gorm.DB
+}
+
+func NewTripPlanRepository(db *gorm.DB) *TripPlanRepository {
+	return &TripPlanRepository{db: db}
+}
+
+func (r *TripPlanRepository) Create(p *models.TripPlan) error {
+	return r.db.Create(p).Error
+}
+
+func (r *TripPlanRepository) Get(id uint) (*models.TripPlan, error) {
+	var p models.TripPlan
+	err := r.db.First(&p, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SetParticipants replaces the confirmed and pending participants of a plan.
+func (r *TripPlanRepository) SetParticipants(id uint, participants, pending []string) error {
+	return r.db.Model(&models.TripPlan{ID: id}).Select("participants", "pending", "updated_at").
+		Updates(&models.TripPlan{Participants: participants, Pending: pending}).Error
+}
+
+// Delete removes a plan, reporting whether it existed.
+func (r *TripPlanRepository) Delete(id uint) (bool, error) {
+	res := r.db.Delete(&models.TripPlan{}, id)
+	return res.RowsAffected > 0, res.Error
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(ep *models.WebhookEndpoint) error {
+	return r.db.Create(ep).Error
+}
+
+func (r *WebhookRepository) Get(id uint) (*models.WebhookEndpoint, error) {
+	var ep models.WebhookEndpoint
+	err := r.db.First(&ep, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+func (r *WebhookRepository) List() ([]models.WebhookEndpoint, error) {
+	var out []models.WebhookEndpoint
+	err := r.db.Order("id").Find(&out).Error
+	return out, err
+}
+
+// Delete removes an endpoint and its delivery log, reporting whether it existed.
+func (r *WebhookRepository) Delete(id uint) (bool, error) {
+	var found bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&models.WebhookEndpoint{}, id)
+		found = res.RowsAffected > 0
+		return res.Error
+	})
+	return found, err
+}
+
+// Enable reactivates an endpoint and clears its failure state.
+func (r *WebhookRepository) Enable(id uint) (bool, error) {
+	res := r.db.Model(&models.WebhookEndpoint{}).Where("id = ?", id).Updates(map[string]any{
+		"active":               true,
+		"consecutive_failures": 0,
+		"disabled_at":          nil,
+		"disabled_reason":      "",
+		"updated_at":           time.Now(),
+	})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ListDeliveries returns the most recent delivery attempts for an endpoint.
+func (r *WebhookRepository) ListDeliveries(endpointID uint, limit int) ([]models.WebhookDelivery, error) {
+	var out []models.WebhookDelivery
+	err := r.db.Where("endpoint_id = ?", endpointID).Order("id DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
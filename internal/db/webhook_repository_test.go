@@ -0,0 +1,54 @@
+package db_test
+
+import (
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestWebhookRepository_EnableAndDelete(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repo := db.NewWebhookRepository(gdb)
+
+	ep := &models.WebhookEndpoint{URL: "https://partner.example/hook", Secret: "s", ZoneID: "NWAC", Events: []string{models.EventWarningIssued}, Active: true}
+	if err := repo.Create(ep); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := gdb.Model(ep).Updates(map[string]any{"active": false, "consecutive_failures": 10, "disabled_reason": "failing"}).Error; err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if err := gdb.Create(&models.WebhookDelivery{EndpointID: ep.ID, EventID: "evt_1", EventType: models.EventWarningIssued, Attempt: 1}).Error; err != nil {
+		t.Fatalf("seed delivery: %v", err)
+	}
+
+	if found, err := repo.Enable(ep.ID); err != nil || !found {
+		t.Fatalf("enable: found=%v err=%v", found, err)
+	}
+	got, err := repo.Get(ep.ID)
+	if err != nil || got == nil {
+		t.Fatalf("get: %+v %v", got, err)
+	}
+	if !got.Active || got.ConsecutiveFailures != 0 || got.DisabledReason != "" {
+		t.Fatalf("expected endpoint reset, got %+v", got)
+	}
+	if len(got.Events) != 1 || got.Events[0] != models.EventWarningIssued {
+		t.Fatalf("events not round-tripped: %v", got.Events)
+	}
+
+	if found, err := repo.Delete(ep.ID); err != nil || !found {
+		t.Fatalf("delete: found=%v err=%v", found, err)
+	}
+	deliveries, err := repo.ListDeliveries(ep.ID, 10)
+	if err != nil || len(deliveries) != 0 {
+		t.Fatalf("expected deliveries removed, got %d (%v)", len(deliveries), err)
+	}
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"errors"
+	"slices"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ZoneAliasRepository struct {
+	db *gorm.DB
+}
+
+func NewZoneAliasRepository(db *gorm.DB) *ZoneAliasRepository {
+	return &ZoneAliasRepository{db: db}
+}
+
+// Upsert inserts an alias or points an existing one at a new zone.
+func (r *ZoneAliasRepository) Upsert(a *models.ZoneAlias) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "alias"}},
+		DoUpdates: clause.AssignmentColumns([]string{"zone_id", "renumbered", "updated_at"}),
+	}).Create(a).Error
+}
+
+func (r *ZoneAliasRepository) Get(alias string) (*models.ZoneAlias, error) {
+	var a models.ZoneAlias
+	err := r.db.Where("alias = ?", alias).First(&a).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *ZoneAliasRepository) List() ([]models.ZoneAlias, error) {
+	var out []models.ZoneAlias
+	err := r.db.Order("alias").Find(&out).Error
+	return out, err
+}
+
+// Delete removes an alias and reports whether it existed.
+func (r *ZoneAliasRepository) Delete(alias string) (bool, error) {
+	res := r.db.Where("alias = ?", alias).Delete(&models.ZoneAlias{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Renumber records that zone from is now zone to: alias records the old ID,
+// aliases of the old zone are repointed, and subscriptions, webhook endpoints,
+// zone groups, organizations and trip plans move to the new ID. It returns
+// the number of subscriptions moved.
+func (r *ZoneAliasRepository) Renumber(alias *models.ZoneAlias, from string) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		to := alias.ZoneID
+		if err := NewZoneAliasRepository(tx).Upsert(alias); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ZoneAlias{}).Where("zone_id = ?", from).Update("zone_id", to).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&models.Subscription{}).Where("zone_id = ?", from).Update("zone_id", to)
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = res.RowsAffected
+		if err := tx.Model(&models.WebhookEndpoint{}).Where("zone_id = ?", from).Update("zone_id", to).Error; err != nil {
+			return err
+		}
+		if err := renumberZoneLists[models.ZoneGroup](tx, from, to, func(g *models.ZoneGroup) *[]string { return &g.ZoneIDs }); err != nil {
+			return err
+		}
+		if err := renumberZoneLists[models.Organization](tx, from, to, func(o *models.Organization) *[]string { return &o.ZoneIDs }); err != nil {
+			return err
+		}
+		return renumberZoneLists[models.TripPlan](tx, from, to, func(p *models.TripPlan) *[]string { return &p.ZoneIDs })
+	})
+	return moved, err
+}
+
+// renumberZoneLists replaces from with to in the JSON zone_ids column of
+// table T, dropping the old ID where the list already holds the new one.
+func renumberZoneLists[T any](tx *gorm.DB, from, to string, zoneIDs func(*T) *[]string) error {
+	var rows []T
+	if err := tx.Where("zone_ids LIKE ?", `%"`+from+`"%`).Find(&rows).Error; err != nil {
+		return err
+	}
+	for i := range rows {
+		zones := zoneIDs(&rows[i])
+		out := make([]string, 0, len(*zones))
+		for _, z := range *zones {
+			if z == from {
+				z = to
+			}
+			if !slices.Contains(out, z) {
+				out = append(out, z)
+			}
+		}
+		if slices.Equal(out, *zones) {
+			continue
+		}
+		*zones = out
+		if err := tx.Model(&rows[i]).Select("zone_ids").Updates(&rows[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"errors"
+
+	"example.com/avalanche/internal/models"
+	"gorm.io/gorm"
+)
+
+type ZoneGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewZoneGroupRepository(db *gorm.DB) *ZoneGroupRepository {
+	return &ZoneGroupRepository{db: db}
+}
+
+func (r *ZoneGroupRepository) Create(g *models.ZoneGroup) error {
+	return r.db.Create(g).Error
+}
+
+func (r *ZoneGroupRepository) Get(slug string) (*models.ZoneGroup, error) {
+	var g models.ZoneGroup
+	err := r.db.Where("slug = ?", slug).First(&g).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *ZoneGroupRepository) ListByOwner(email string) ([]models.ZoneGroup, error) {
+	var out []models.ZoneGroup
+	err := r.db.Where("owner_email = ?", email).Order("slug").Find(&out).Error
+	return out, err
+}
+
+func (r *ZoneGroupRepository) ListByOrganization(orgID uint) ([]models.ZoneGroup, error) {
+	var out []models.ZoneGroup
+	err := r.db.Where("organization_id = ?", orgID).Order("slug").Find(&out).Error
+	return out, err
+}
+
+// Update saves the name and zones of a group.
+func (r *ZoneGroupRepository) Update(g *models.ZoneGroup) error {
+	return r.db.Model(g).Select("name", "zone_ids", "updated_at").Updates(g).Error
+}
+
+// Delete removes a group together with the subscriptions following it.
+func (r *ZoneGroupRepository) Delete(g *models.ZoneGroup) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("zone_id = ?", g.Ref()).Delete(&models.Subscription{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ZoneGroup{}, g.ID).Error
+	})
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DangerLevel is a rating on the North American Public Avalanche Danger
+// Scale, from DangerLow (1) to DangerExtreme (5). DangerNoRating (0) covers
+// zones that are not rated, including upstream's -1.
+type DangerLevel int
+
+const (
+	DangerNoRating DangerLevel = iota
+	DangerLow
+	DangerModerate
+	DangerConsiderable
+	DangerHigh
+	DangerExtreme
+)
+
+// ErrInvalidDangerLevel is returned for values outside the danger scale.
+var ErrInvalidDangerLevel = errors.New("danger level must be 1 (Low) to 5 (Extreme), or -1/0 for no rating")
+
+type dangerScaleEntry struct {
+	name   string
+	color  string
+	icon   string
+	advice string
+}
+
+// dangerScale holds the display metadata of each level. Colors are the
+// scale's standard colors; icons are emoji approximating them for clients
+// without color support.
+var dangerScale = map[DangerLevel]dangerScaleEntry{
+	DangerNoRating: {"No Rating", "#CCCCCC", "⬜",
+		"Watch for signs of unstable snow such as recent avalanches, cracking in the snow, and audible collapsing. Avoid traveling on or under similar slopes."},
+	DangerLow: {"Low", "#50B848", "🟩",
+		"Generally safe avalanche conditions. Watch for unstable snow on isolated terrain features."},
+	DangerModerate: {"Moderate", "#FFF200", "🟨",
+		"Heightened avalanche conditions on specific terrain features. Evaluate snow and terrain carefully; identify features of concern."},
+	DangerConsiderable: {"Considerable", "#F7941E", "🟧",
+		"Dangerous avalanche conditions. Careful snowpack evaluation, cautious route-finding and conservative decision-making essential."},
+	DangerHigh: {"High", "#ED1C24", "🟥",
+		"Very dangerous avalanche conditions. Travel in avalanche terrain not recommended."},
+	DangerExtreme: {"Extreme", "#231F20", "⬛",
+		"Avoid all avalanche terrain."},
+}
+
+// NewDangerLevel validates a numeric rating, mapping upstream's -1 to
+// DangerNoRating.
+func NewDangerLevel(n int) (DangerLevel, error) {
+	if n == -1 {
+		return DangerNoRating, nil
+	}
+	if n < int(DangerNoRating) || n > int(DangerExtreme) {
+		return DangerNoRating, fmt.Errorf("%w: got %d", ErrInvalidDangerLevel, n)
+	}
+	return DangerLevel(n), nil
+}
+
+// ParseDangerLevel accepts a number ("4") or a scale name ("high").
+func ParseDangerLevel(raw string) (DangerLevel, error) {
+	raw = strings.TrimSpace(raw)
+	if n, err := strconv.Atoi(raw); err == nil {
+		return NewDangerLevel(n)
+	}
+	for level, e := range dangerScale {
+		if strings.EqualFold(raw, e.name) {
+			return level, nil
+		}
+	}
+	return DangerNoRating, fmt.Errorf("%w: got %q", ErrInvalidDangerLevel, raw)
+}
+
+// IsRated reports whether the level is on the scale rather than unrated.
+func (d DangerLevel) IsRated() bool { return d >= DangerLow && d <= DangerExtreme }
+
+func (d DangerLevel) entry() dangerScaleEntry {
+	if e, ok := dangerScale[d]; ok {
+		return e
+	}
+	return dangerScale[DangerNoRating]
+}
+
+// Name returns the scale name, e.g. "Considerable", or "No Rating".
+func (d DangerLevel) Name() string { return d.entry().name }
+
+// Color returns the scale color as "#RRGGBB"; unrated levels are grey.
+func (d DangerLevel) Color() string { return d.entry().color }
+
+// Icon returns a colored square emoji for the level.
+func (d DangerLevel) Icon() string { return d.entry().icon }
+
+// TravelAdvice returns the scale's standard travel advice for the level.
+func (d DangerLevel) TravelAdvice() string { return d.entry().advice }
+
+// Label renders the level as "🟧 3 - Considerable", or "⬜ No Rating".
+func (d DangerLevel) Label() string {
+	if !d.IsRated() {
+		return d.Icon() + " " + d.Name()
+	}
+	return fmt.Sprintf("%s %d - %s", d.Icon(), int(d), d.Name())
+}
+
+// String returns the scale name.
+func (d DangerLevel) String() string { return d.Name() }
+
+// UnmarshalJSON validates numeric ratings from upstream and API input.
+func (d *DangerLevel) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDangerLevel, b)
+	}
+	level, err := NewDangerLevel(n)
+	if err != nil {
+		return err
+	}
+	*d = level
+	return nil
+}
+
+// MaxDanger returns the highest of levels, or DangerNoRating for none.
+func MaxDanger(levels ...DangerLevel) DangerLevel {
+	out := DangerNoRating
+	for _, l := range levels {
+		out = max(out, l)
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+)
+
+func TestNewDangerLevel(t *testing.T) {
+	cases := []struct {
+		in   int
+		ok   bool
+		want domain.DangerLevel
+	}{
+		{-1, true, domain.DangerNoRating},
+		{0, true, domain.DangerNoRating},
+		{3, true, domain.DangerConsiderable},
+		{5, true, domain.DangerExtreme},
+		{6, false, 0},
+		{-2, false, 0},
+	}
+	for _, c := range cases {
+		got, err := domain.NewDangerLevel(c.in)
+		if c.ok != (err == nil) || got != c.want {
+			t.Errorf("NewDangerLevel(%d) = %v, %v", c.in, got, err)
+		}
+		if err != nil && !errors.Is(err, domain.ErrInvalidDangerLevel) {
+			t.Errorf("expected ErrInvalidDangerLevel, got %v", err)
+		}
+	}
+}
+
+func TestParseDangerLevel(t *testing.T) {
+	for raw, want := range map[string]domain.DangerLevel{
+		"4": domain.DangerHigh, " high ": domain.DangerHigh, "No Rating": domain.DangerNoRating, "-1": domain.DangerNoRating,
+	} {
+		if got, err := domain.ParseDangerLevel(raw); err != nil || got != want {
+			t.Errorf("ParseDangerLevel(%q) = %v, %v", raw, got, err)
+		}
+	}
+	if _, err := domain.ParseDangerLevel("severe"); !errors.Is(err, domain.ErrInvalidDangerLevel) {
+		t.Errorf("expected ErrInvalidDangerLevel, got %v", err)
+	}
+}
+
+func TestDangerLevel_Display(t *testing.T) {
+	if l := domain.DangerConsiderable; l.Name() != "Considerable" || l.Color() != "#F7941E" || l.Label() != "🟧 3 - Considerable" || !l.IsRated() {
+		t.Errorf("unexpected considerable display %q %q %q", l.Name(), l.Color(), l.Label())
+	}
+	if l := domain.DangerLevel(9); l.IsRated() || l.Name() != "No Rating" || l.Label() != "⬜ No Rating" {
+		t.Errorf("expected out-of-scale levels to display as unrated, got %q", l.Label())
+	}
+	if domain.DangerHigh.TravelAdvice() == domain.DangerLow.TravelAdvice() {
+		t.Error("expected travel advice per level")
+	}
+	if got := domain.MaxDanger(domain.DangerLow, domain.DangerHigh, domain.DangerNoRating); got != domain.DangerHigh {
+		t.Errorf("MaxDanger = %v", got)
+	}
+}
+
+func TestDangerLevel_UnmarshalJSON(t *testing.T) {
+	var v struct{ Upper, Lower domain.DangerLevel }
+	if err := json.Unmarshal([]byte(`{"upper":4,"lower":-1}`), &v); err != nil || v.Upper != domain.DangerHigh || v.Lower != domain.DangerNoRating {
+		t.Errorf("unexpected %+v %v", v, err)
+	}
+	if err := json.Unmarshal([]byte(`{"upper":7}`), &v); !errors.Is(err, domain.ErrInvalidDangerLevel) {
+		t.Errorf("expected ErrInvalidDangerLevel, got %v", err)
+	}
+}
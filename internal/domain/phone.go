@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// phoneSeparators are formatting characters accepted in input and stripped
+// before validation, e.g. "+1 (206) 555-0100".
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+
+// PhoneNumber represents a validated E.164 phone number value object.
+type PhoneNumber struct {
+	value string
+}
+
+// NewPhoneNumber validates and constructs a PhoneNumber. The number must
+// include the country code; a leading "00" is accepted in place of "+".
+func NewPhoneNumber(raw string) (*PhoneNumber, error) {
+	raw = phoneSeparators.Replace(strings.TrimSpace(raw))
+	if raw == "" {
+		return nil, errors.New("phone number cannot be empty")
+	}
+	if strings.HasPrefix(raw, "00") {
+		raw = "+" + raw[2:]
+	}
+	if !e164Regex.MatchString(raw) {
+		return nil, errors.New("phone number must be in E.164 format, e.g. +12065550100")
+	}
+	return &PhoneNumber{value: raw}, nil
+}
+
+// String returns the number in E.164 form.
+func (p PhoneNumber) String() string { return p.value }
+
+// Value returns the underlying value.
+func (p PhoneNumber) Value() string { return p.value }
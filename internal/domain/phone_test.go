@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+)
+
+func TestNewPhoneNumber_ValidatesE164(t *testing.T) {
+	cases := []struct {
+		in  string
+		ok  bool
+		out string
+	}{
+		{"+12065550100", true, "+12065550100"},
+		{" +1 (206) 555-0100 ", true, "+12065550100"},
+		{"0041 44 668 18 00", true, "+41446681800"},
+		{"2065550100", false, ""},
+		{"+0123456789", false, ""},
+		{"+1206555010012345", false, ""},
+		{"+1206abc0100", false, ""},
+		{"", false, ""},
+	}
+	for _, c := range cases {
+		p, err := domain.NewPhoneNumber(c.in)
+		if c.ok && err != nil {
+			t.Fatalf("expected ok for %q: %v", c.in, err)
+		}
+		if !c.ok && err == nil {
+			t.Fatalf("expected error for %q", c.in)
+		}
+		if c.ok && p.String() != c.out {
+			t.Fatalf("expected %q, got %q", c.out, p.String())
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// PushSubscription represents a validated browser PushSubscription: the push
+// service endpoint and the keys used to encrypt messages for it.
+type PushSubscription struct {
+	Endpoint *WebhookURL
+	P256DH   string
+	Auth     string
+}
+
+// NewPushSubscription validates the fields of PushSubscription.toJSON().
+// p256dh must be an uncompressed P-256 point and auth a 16-byte secret, both
+// base64url encoded.
+func NewPushSubscription(endpoint, p256dh, auth string) (*PushSubscription, error) {
+	ep, err := NewWebhookURL(endpoint)
+	if err != nil {
+		return nil, errors.New("invalid push endpoint")
+	}
+	p256dh, auth = strings.TrimRight(strings.TrimSpace(p256dh), "="), strings.TrimRight(strings.TrimSpace(auth), "=")
+	if key, err := base64.RawURLEncoding.DecodeString(p256dh); err != nil || len(key) != 65 || key[0] != 0x04 {
+		return nil, errors.New("invalid p256dh key")
+	}
+	if secret, err := base64.RawURLEncoding.DecodeString(auth); err != nil || len(secret) != 16 {
+		return nil, errors.New("invalid auth secret")
+	}
+	return &PushSubscription{Endpoint: ep, P256DH: p256dh, Auth: auth}, nil
+}
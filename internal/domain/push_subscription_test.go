@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+)
+
+func TestNewPushSubscription(t *testing.T) {
+	p256dh := base64.RawURLEncoding.EncodeToString(append([]byte{0x04}, make([]byte, 64)...))
+	auth := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+	endpoint := "https://fcm.googleapis.com/fcm/send/abc123"
+
+	sub, err := domain.NewPushSubscription(endpoint, p256dh, auth+"==")
+	if err != nil {
+		t.Fatalf("expected valid subscription: %v", err)
+	}
+	if sub.Endpoint.String() != endpoint || sub.Auth != auth {
+		t.Fatalf("unexpected subscription: %+v", sub)
+	}
+
+	cases := map[string][3]string{
+		"http endpoint":   {"http://push.example.com/x", p256dh, auth},
+		"short p256dh":    {endpoint, p256dh[:20], auth},
+		"compressed key":  {endpoint, base64.RawURLEncoding.EncodeToString(append([]byte{0x02}, make([]byte, 64)...)), auth},
+		"long auth":       {endpoint, p256dh, auth + "AAAA"},
+		"non-base64 auth": {endpoint, p256dh, strings.Repeat("*", 22)},
+	}
+	for name, c := range cases {
+		if _, err := domain.NewPushSubscription(c[0], c[1], c[2]); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
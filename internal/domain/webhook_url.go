@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// AllowLoopbackWebhooks accepts webhook URLs on loopback hosts, over plain
+// http too, and lets webhook clients connect to them. It is meant for local
+// development and tests and is set with WEBHOOK_ALLOW_LOOPBACK=true.
+var AllowLoopbackWebhooks = os.Getenv("WEBHOOK_ALLOW_LOOPBACK") == "true"
+
+// ErrPrivateWebhookHost is returned for webhook URLs and addresses that point
+// at loopback, private or link-local networks.
+var ErrPrivateWebhookHost = errors.New("webhook URL must point at a public host")
+
+// reservedNets are ranges not covered by the net.IP predicates that must not
+// be reachable through user-supplied URLs.
+var reservedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "64:ff9b::/96"} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// WebhookURL represents a validated outbound webhook endpoint.
+// HTTPS and a public host are required; loopback hosts are only accepted,
+// over http too, when AllowLoopbackWebhooks is set.
+type WebhookURL struct {
+	value string
+}
+
+// NewWebhookURL validates and constructs a WebhookURL. Host names are checked
+// again when the notifier connects, against the addresses they resolve to.
+func NewWebhookURL(raw string) (*WebhookURL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("webhook URL cannot be empty")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, errors.New("invalid webhook URL")
+	}
+	host := u.Hostname()
+	switch {
+	case isLoopback(host):
+		if !AllowLoopbackWebhooks {
+			return nil, ErrPrivateWebhookHost
+		}
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return nil, errors.New("webhook URL must use https")
+		}
+	case u.Scheme != "https":
+		return nil, errors.New("webhook URL must use https")
+	default:
+		if ip := net.ParseIP(host); ip != nil {
+			if err := CheckWebhookIP(ip); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if u.User != nil {
+		return nil, errors.New("webhook URL must not contain credentials")
+	}
+	return &WebhookURL{value: u.String()}, nil
+}
+
+// CheckWebhookIP returns ErrPrivateWebhookHost unless ip is a public unicast
+// address, or a loopback address while AllowLoopbackWebhooks is set.
+func CheckWebhookIP(ip net.IP) error {
+	switch {
+	case ip == nil:
+		return ErrPrivateWebhookHost
+	case ip.IsLoopback():
+		if AllowLoopbackWebhooks {
+			return nil
+		}
+		return ErrPrivateWebhookHost
+	case !ip.IsGlobalUnicast(), ip.IsPrivate():
+		return ErrPrivateWebhookHost
+	}
+	for _, n := range reservedNets {
+		if n.Contains(ip) {
+			return ErrPrivateWebhookHost
+		}
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// String returns the webhook URL.
+func (w WebhookURL) String() string { return w.value }
@@ -2,14 +2,23 @@ package domain
 
 import (
 	"errors"
+	"regexp"
 	"strings"
 )
 
+// GroupPrefix starts the canonical form of zone group references.
+const GroupPrefix = "group:"
+
+var groupSlugRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,47}$`)
+
 // ZoneID represents a validated zone identifier in the format "CENTER_ZONE" or just "CENTER".
 // Examples: "NWAC_164" (specific zone), "NWAC" (center-level subscription)
+// A ZoneID may instead reference a named zone group ("group:home-range"),
+// which is only built by NewGroupZoneID.
 type ZoneID struct {
 	center string
 	zone   string
+	group  string
 }
 
 // ParseZoneID parses and validates a raw zone identifier.
@@ -30,6 +39,26 @@ func ParseZoneID(raw string) (*ZoneID, error) {
 	return z, nil
 }
 
+// NewGroupZoneID validates a zone group slug: 2 to 48 lowercase letters,
+// digits and dashes, starting with a letter or digit.
+func NewGroupZoneID(slug string) (*ZoneID, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	slug = strings.TrimPrefix(slug, GroupPrefix)
+	if !groupSlugRegex.MatchString(slug) {
+		return nil, errors.New("group must be 2-48 lowercase letters, digits and dashes")
+	}
+	return &ZoneID{group: slug}, nil
+}
+
+// ParseZoneRef parses either a zone identifier or a zone group reference
+// ("group:<slug>"), the forms a subscription can follow.
+func ParseZoneRef(raw string) (*ZoneID, error) {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(raw)), GroupPrefix) {
+		return NewGroupZoneID(raw)
+	}
+	return ParseZoneID(raw)
+}
+
 // Center returns the avalanche center ID (e.g., "NWAC", "IPAC").
 func (z *ZoneID) Center() string { return z.center }
 
@@ -37,13 +66,22 @@ func (z *ZoneID) Center() string { return z.center }
 func (z *ZoneID) Zone() string { return z.zone }
 
 // IsCenterLevel indicates a center-level subscription.
-func (z *ZoneID) IsCenterLevel() bool { return z.zone == "" }
+func (z *ZoneID) IsCenterLevel() bool { return z.zone == "" && z.group == "" }
 
 // IsSpecificZone indicates a zone-level subscription.
 func (z *ZoneID) IsSpecificZone() bool { return z.zone != "" }
 
+// IsGroup indicates a reference to a zone group.
+func (z *ZoneID) IsGroup() bool { return z.group != "" }
+
+// Group returns the zone group slug (empty unless IsGroup).
+func (z *ZoneID) Group() string { return z.group }
+
 // String returns canonical string form.
 func (z *ZoneID) String() string {
+	if z.IsGroup() {
+		return GroupPrefix + z.group
+	}
 	if z.IsCenterLevel() {
 		return z.center
 	}
@@ -55,5 +93,5 @@ func (z *ZoneID) Equals(other *ZoneID) bool {
 	if other == nil {
 		return false
 	}
-	return z.center == other.center && z.zone == other.zone
+	return z.center == other.center && z.zone == other.zone && z.group == other.group
 }
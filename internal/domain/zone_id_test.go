@@ -41,3 +41,29 @@ func TestParseZoneID(t *testing.T) {
 		}
 	}
 }
+
+func TestParseZoneRef(t *testing.T) {
+	for in, want := range map[string]string{
+		"NWAC_10":           "NWAC_10",
+		"group:home-range":  "group:home-range",
+		" Group:Home-Range": "group:home-range",
+		"group:x":           "",
+		"group:no spaces":   "",
+		"group:":            "",
+	} {
+		z, err := domain.ParseZoneRef(in)
+		if want == "" {
+			if err == nil {
+				t.Errorf("expected error for %q, got %s", in, z)
+			}
+			continue
+		}
+		if err != nil || z.String() != want {
+			t.Errorf("ParseZoneRef(%q) = %v, %v; want %s", in, z, err, want)
+			continue
+		}
+		if z.IsGroup() == z.IsSpecificZone() || z.IsCenterLevel() {
+			t.Errorf("unexpected kind for %q", in)
+		}
+	}
+}
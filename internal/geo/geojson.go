@@ -0,0 +1,113 @@
+// Package geo builds GeoJSON (RFC 7946) documents from forecast zones.
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"example.com/avalanche/internal/models"
+)
+
+// ContentType is the GeoJSON media type.
+const ContentType = "application/geo+json"
+
+// FeatureCollection is a GeoJSON FeatureCollection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature. A nil Geometry is encoded as null, which
+// RFC 7946 allows for features without a known location.
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Geometry   *Geometry      `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry. Coordinates are kept as raw JSON so zone
+// polygons pass through unchanged.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Polygon is a list of linear rings of [longitude, latitude] positions; the
+// first ring is the exterior and any others are holes.
+type Polygon [][][]float64
+
+// Polygons decodes a Polygon or MultiPolygon geometry. Other geometry types
+// yield no polygons.
+func (g *Geometry) Polygons() ([]Polygon, error) {
+	if g == nil {
+		return nil, nil
+	}
+	switch g.Type {
+	case "Polygon":
+		var p Polygon
+		if err := json.Unmarshal(g.Coordinates, &p); err != nil {
+			return nil, fmt.Errorf("invalid polygon coordinates: %w", err)
+		}
+		return []Polygon{p}, nil
+	case "MultiPolygon":
+		var ps []Polygon
+		if err := json.Unmarshal(g.Coordinates, &ps); err != nil {
+			return nil, fmt.Errorf("invalid multipolygon coordinates: %w", err)
+		}
+		return ps, nil
+	}
+	return nil, nil
+}
+
+// ForecastFeatures returns one Feature per zone forecast, using the zone's
+// polygon from shapes (keyed by zone ID such as "NWAC_10") when present.
+//
+// Properties are flat so desktop GIS tools show them as attribute columns.
+// danger_level is the highest of today's band ratings, and color, fill and
+// stroke carry its scale color for clients that follow the simplestyle spec.
+func ForecastFeatures(forecasts []models.ZoneForecast, shapes map[string]*Geometry) FeatureCollection {
+	fc := FeatureCollection{Type: "FeatureCollection", Features: make([]Feature, 0, len(forecasts))}
+	for _, f := range forecasts {
+		level := f.TodayDanger.Max()
+		color := level.Color()
+		props := map[string]any{
+			"zone_id":          f.ZoneID,
+			"zone_name":        f.ZoneName,
+			"center":           f.Center,
+			"issued_time":      f.IssuedTime,
+			"start_date":       f.StartDate,
+			"end_date":         f.EndDate,
+			"bottom_line":      f.SafeBottomLine(),
+			"bottom_line_text": f.PlainBottomLine(),
+			"danger_level":     level,
+			"danger_name":      level.Name(),
+			"color":            color,
+			"fill":             color,
+			"fill-opacity":     0.6,
+			"stroke":           "#333333",
+		}
+		addBands(props, "danger", f.TodayDanger)
+		addBands(props, "tomorrow", f.FutureDanger)
+		if f.FutureDanger != nil {
+			props["tomorrow_level"] = f.FutureDanger.Max()
+		}
+		fc.Features = append(fc.Features, Feature{
+			Type:       "Feature",
+			ID:         f.ZoneID,
+			Geometry:   shapes[f.ZoneID],
+			Properties: props,
+		})
+	}
+	return fc
+}
+
+// addBands sets <prefix>_upper, _middle and _lower from d.
+func addBands(props map[string]any, prefix string, d *models.DangerRating) {
+	if d == nil {
+		return
+	}
+	props[prefix+"_upper"] = d.Upper
+	props[prefix+"_middle"] = d.Middle
+	props[prefix+"_lower"] = d.Lower
+}
@@ -0,0 +1,79 @@
+package geo_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+)
+
+func TestForecastFeatures(t *testing.T) {
+	shape := &geo.Geometry{Type: "Polygon", Coordinates: json.RawMessage(`[[[-121.5,47.4],[-121.3,47.4],[-121.3,47.6],[-121.5,47.4]]]`)}
+	forecasts := []models.ZoneForecast{
+		{
+			ZoneID:       "NWAC_10",
+			ZoneName:     "Snoqualmie Pass",
+			Center:       "Northwest Avalanche Center",
+			BottomLine:   "Wind slabs near ridgelines.",
+			TodayDanger:  &models.DangerRating{Upper: 3, Middle: 2, Lower: 1},
+			FutureDanger: &models.DangerRating{Upper: 2, Middle: 2, Lower: 1},
+		},
+		{ZoneID: "NWAC_99", ZoneName: "Unmapped"},
+	}
+
+	fc := geo.ForecastFeatures(forecasts, map[string]*geo.Geometry{"NWAC_10": shape})
+
+	raw, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type       string          `json:"type"`
+			ID         string          `json:"id"`
+			Geometry   json.RawMessage `json:"geometry"`
+			Properties map[string]any  `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Type != "FeatureCollection" || len(out.Features) != 2 {
+		t.Fatalf("unexpected collection: %s", raw)
+	}
+
+	f := out.Features[0]
+	if f.Type != "Feature" || f.ID != "NWAC_10" {
+		t.Errorf("unexpected feature header: %+v", f)
+	}
+	var g geo.Geometry
+	if err := json.Unmarshal(f.Geometry, &g); err != nil || g.Type != "Polygon" {
+		t.Errorf("expected polygon geometry, got %s", f.Geometry)
+	}
+	want := map[string]any{
+		"zone_name":      "Snoqualmie Pass",
+		"bottom_line":    "Wind slabs near ridgelines.",
+		"danger_level":   float64(3),
+		"danger_name":    "Considerable",
+		"danger_upper":   float64(3),
+		"danger_lower":   float64(1),
+		"tomorrow_level": float64(2),
+		"color":          "#F7941E",
+		"fill":           "#F7941E",
+	}
+	for k, v := range want {
+		if f.Properties[k] != v {
+			t.Errorf("property %s = %v, want %v", k, f.Properties[k], v)
+		}
+	}
+
+	unmapped := out.Features[1]
+	if string(unmapped.Geometry) != "null" {
+		t.Errorf("expected null geometry for unmapped zone, got %s", unmapped.Geometry)
+	}
+	if unmapped.Properties["danger_name"] != "No Rating" || unmapped.Properties["color"] != "#CCCCCC" {
+		t.Errorf("unexpected unrated properties: %v", unmapped.Properties)
+	}
+}
@@ -0,0 +1,187 @@
+package geo
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+// KML and KMZ media types.
+const (
+	KMLContentType = "application/vnd.google-earth.kml+xml"
+	KMZContentType = "application/vnd.google-earth.kmz"
+)
+
+// kmlFillAlpha keeps the terrain visible through zone polygons.
+const kmlFillAlpha = 0x99
+
+type kmlDoc struct {
+	XMLName  xml.Name `xml:"kml"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Document kmlDocument
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Styles     []kmlStyle     `xml:"Style"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlStyle struct {
+	ID        string `xml:"id,attr"`
+	LineColor string `xml:"LineStyle>color"`
+	LineWidth int    `xml:"LineStyle>width"`
+	PolyColor string `xml:"PolyStyle>color"`
+}
+
+type kmlPlacemark struct {
+	Name        string       `xml:"name"`
+	Description kmlCDATA     `xml:"description"`
+	StyleURL    string       `xml:"styleUrl"`
+	Data        []kmlData    `xml:"ExtendedData>Data"`
+	Polygons    []kmlPolygon `xml:"MultiGeometry>Polygon"`
+}
+
+type kmlCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPolygon struct {
+	Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+}
+
+// WriteKML writes a KML document with one styled polygon per zone forecast,
+// colored by the zone's highest danger rating. Zones without a polygon in
+// shapes are omitted.
+func WriteKML(w io.Writer, name string, forecasts []models.ZoneForecast, shapes map[string]*Geometry) error {
+	doc := kmlDoc{XMLNS: "http://www.opengis.net/kml/2.2", Document: kmlDocument{Name: name}}
+	for level := domain.DangerNoRating; level <= domain.DangerExtreme; level++ {
+		color := level.Color()
+		doc.Document.Styles = append(doc.Document.Styles, kmlStyle{
+			ID:        kmlStyleID(level),
+			LineColor: kmlColor("#333333", 0xff),
+			LineWidth: 1,
+			PolyColor: kmlColor(color, kmlFillAlpha),
+		})
+	}
+
+	for _, f := range forecasts {
+		polygons, err := shapes[f.ZoneID].Polygons()
+		if err != nil {
+			return fmt.Errorf("zone %s: %w", f.ZoneID, err)
+		}
+		if len(polygons) == 0 {
+			continue
+		}
+		level := f.TodayDanger.Max()
+		pm := kmlPlacemark{
+			Name:        fmt.Sprintf("%s (%s)", f.ZoneName, level.Name()),
+			Description: kmlCDATA{Text: kmlBalloon(f)},
+			StyleURL:    "#" + kmlStyleID(level),
+			Data: []kmlData{
+				{Name: "zone_id", Value: f.ZoneID},
+				{Name: "danger_level", Value: strconv.Itoa(int(level))},
+			},
+		}
+		for _, p := range polygons {
+			if len(p) == 0 {
+				continue
+			}
+			kp := kmlPolygon{Outer: kmlCoordinates(p[0])}
+			for _, hole := range p[1:] {
+				kp.Inner = append(kp.Inner, kmlCoordinates(hole))
+			}
+			pm.Polygons = append(pm.Polygons, kp)
+		}
+		doc.Document.Placemarks = append(doc.Document.Placemarks, pm)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode kml: %w", err)
+	}
+	return enc.Close()
+}
+
+// WriteKMZ writes the KML document zipped as doc.kml, the layout Google Earth
+// expects.
+func WriteKMZ(w io.Writer, name string, forecasts []models.ZoneForecast, shapes map[string]*Geometry) error {
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("doc.kml")
+	if err != nil {
+		return err
+	}
+	if err := WriteKML(f, name, forecasts, shapes); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// kmlBalloon renders the placemark description: danger per elevation band,
+// the bottom line and a link to the full forecast.
+func kmlBalloon(f models.ZoneForecast) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h3>%s</h3><p>%s</p>", html.EscapeString(f.ZoneName), html.EscapeString(f.Center))
+	if d := f.TodayDanger; d.Max().IsRated() {
+		b.WriteString("<table>")
+		for _, band := range []struct {
+			label string
+			level domain.DangerLevel
+		}{{"Above treeline", d.Upper}, {"Near treeline", d.Middle}, {"Below treeline", d.Lower}} {
+			fmt.Fprintf(&b, `<tr><td>%s</td><td style="background:%s">%d - %s</td></tr>`,
+				band.label, band.level.Color(), band.level, band.level.Name())
+		}
+		b.WriteString("</table>")
+	} else {
+		b.WriteString("<p>No danger rating</p>")
+	}
+	if bl := f.SafeBottomLine(); bl != "" {
+		b.WriteString("<div>" + bl + "</div>")
+	}
+	if f.URL != "" {
+		fmt.Fprintf(&b, `<p><a href="%s">Full forecast</a></p>`, html.EscapeString(f.URL))
+	}
+	return b.String()
+}
+
+func kmlStyleID(level domain.DangerLevel) string {
+	return "danger-" + strconv.Itoa(int(level))
+}
+
+// kmlColor converts "#RRGGBB" to KML's aabbggrr notation.
+func kmlColor(hex string, alpha uint8) string {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return fmt.Sprintf("%02xcccccc", alpha)
+	}
+	return strings.ToLower(fmt.Sprintf("%02x%s%s%s", alpha, hex[4:6], hex[2:4], hex[0:2]))
+}
+
+// kmlCoordinates formats a ring as space-separated "lon,lat" tuples.
+func kmlCoordinates(ring [][]float64) string {
+	parts := make([]string, 0, len(ring))
+	for _, pos := range ring {
+		if len(pos) < 2 {
+			continue
+		}
+		parts = append(parts, strconv.FormatFloat(pos[0], 'f', -1, 64)+","+strconv.FormatFloat(pos[1], 'f', -1, 64))
+	}
+	return strings.Join(parts, " ")
+}
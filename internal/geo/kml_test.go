@@ -0,0 +1,115 @@
+package geo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+)
+
+type kmlOut struct {
+	Document struct {
+		Styles []struct {
+			ID        string `xml:"id,attr"`
+			PolyColor string `xml:"PolyStyle>color"`
+		} `xml:"Style"`
+		Placemarks []struct {
+			Name        string `xml:"name"`
+			Description string `xml:"description"`
+			StyleURL    string `xml:"styleUrl"`
+			Polygons    []struct {
+				Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+				Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+			} `xml:"MultiGeometry>Polygon"`
+		} `xml:"Placemark"`
+	}
+}
+
+var kmlForecasts = []models.ZoneForecast{
+	{
+		ZoneID:      "NWAC_10",
+		ZoneName:    "Snoqualmie Pass",
+		Center:      "Northwest Avalanche Center",
+		URL:         "https://nwac.us/avalanche-forecast/#/snoqualmie-pass",
+		BottomLine:  "<p>Wind slabs near ridgelines.</p>",
+		TodayDanger: &models.DangerRating{Upper: 3, Middle: 2, Lower: 1},
+	},
+	{ZoneID: "NWAC_99", ZoneName: "Unmapped"},
+}
+
+var kmlShapes = map[string]*geo.Geometry{
+	"NWAC_10": {Type: "Polygon", Coordinates: json.RawMessage(
+		`[[[-121.5,47.4],[-121.3,47.4],[-121.3,47.6],[-121.5,47.4]],[[-121.45,47.45],[-121.4,47.45],[-121.4,47.5],[-121.45,47.45]]]`)},
+}
+
+func TestWriteKML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := geo.WriteKML(&buf, "Avalanche danger", kmlForecasts, kmlShapes); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml") || !strings.Contains(buf.String(), `xmlns="http://www.opengis.net/kml/2.2"`) {
+		t.Fatalf("missing KML header:\n%s", buf.String())
+	}
+
+	var doc kmlOut
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Document.Placemarks) != 1 {
+		t.Fatalf("expected only the mapped zone, got %d placemarks", len(doc.Document.Placemarks))
+	}
+	pm := doc.Document.Placemarks[0]
+	if pm.Name != "Snoqualmie Pass (Considerable)" || pm.StyleURL != "#danger-3" {
+		t.Errorf("unexpected placemark %q styled %q", pm.Name, pm.StyleURL)
+	}
+	for _, want := range []string{"Above treeline", "3 - Considerable", "Below treeline", "1 - Low", "Wind slabs", `href="https://nwac.us/`} {
+		if !strings.Contains(pm.Description, want) {
+			t.Errorf("balloon missing %q: %s", want, pm.Description)
+		}
+	}
+	if len(pm.Polygons) != 1 || len(pm.Polygons[0].Inner) != 1 {
+		t.Fatalf("expected one polygon with a hole, got %+v", pm.Polygons)
+	}
+	if got := pm.Polygons[0].Outer; got != "-121.5,47.4 -121.3,47.4 -121.3,47.6 -121.5,47.4" {
+		t.Errorf("unexpected coordinates %q", got)
+	}
+
+	var considerable string
+	for _, s := range doc.Document.Styles {
+		if s.ID == "danger-3" {
+			considerable = s.PolyColor
+		}
+	}
+	if considerable != "991e94f7" {
+		t.Errorf("expected aabbggrr orange, got %q", considerable)
+	}
+}
+
+func TestWriteKMZ(t *testing.T) {
+	var buf bytes.Buffer
+	if err := geo.WriteKMZ(&buf, "Avalanche danger", kmlForecasts, kmlShapes); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 1 || zr.File[0].Name != "doc.kml" {
+		t.Fatalf("expected a single doc.kml entry, got %v", zr.File)
+	}
+	f, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	body, _ := io.ReadAll(f)
+	if !strings.Contains(string(body), "<Placemark>") {
+		t.Errorf("doc.kml has no placemarks: %s", body)
+	}
+}
@@ -0,0 +1,241 @@
+package geo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// ErrNoTrack is returned when a route document contains no usable points.
+var ErrNoTrack = errors.New("no track points found")
+
+// TrackPoint is a position along a route. Ele is meters above sea level when
+// HasEle is set.
+type TrackPoint struct {
+	Lon, Lat float64
+	Ele      float64
+	HasEle   bool
+}
+
+type gpxPoint struct {
+	Lat float64  `xml:"lat,attr"`
+	Lon float64  `xml:"lon,attr"`
+	Ele *float64 `xml:"ele"`
+}
+
+type gpxDoc struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+// ParseGPX reads the points of every track segment and then every route in a
+// GPX 1.0 or 1.1 document, in document order.
+func ParseGPX(r io.Reader) ([]TrackPoint, error) {
+	var doc gpxDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid GPX: %w", err)
+	}
+	var out []TrackPoint
+	add := func(p gpxPoint) {
+		tp := TrackPoint{Lon: p.Lon, Lat: p.Lat}
+		if p.Ele != nil {
+			tp.Ele, tp.HasEle = *p.Ele, true
+		}
+		out = append(out, tp)
+	}
+	for _, t := range doc.Tracks {
+		for _, s := range t.Segments {
+			for _, p := range s.Points {
+				add(p)
+			}
+		}
+	}
+	for _, rte := range doc.Routes {
+		for _, p := range rte.Points {
+			add(p)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrNoTrack
+	}
+	return out, nil
+}
+
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Features    []geoJSONObject `json:"features"`
+}
+
+// ParseLineString reads a GeoJSON LineString or MultiLineString, either bare
+// or as a Feature, or every such feature of a FeatureCollection in order.
+// Positions may carry elevation in meters as a third value.
+func ParseLineString(data []byte) ([]TrackPoint, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	out, err := lineStringPoints(obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, ErrNoTrack
+	}
+	return out, nil
+}
+
+func lineStringPoints(obj geoJSONObject) ([]TrackPoint, error) {
+	var lines [][][]float64
+	switch obj.Type {
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil, nil
+		}
+		return lineStringPoints(*obj.Geometry)
+	case "FeatureCollection":
+		var out []TrackPoint
+		for _, f := range obj.Features {
+			pts, err := lineStringPoints(f)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, pts...)
+		}
+		return out, nil
+	case "LineString":
+		var line [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &line); err != nil {
+			return nil, fmt.Errorf("invalid LineString coordinates: %w", err)
+		}
+		lines = [][][]float64{line}
+	case "MultiLineString":
+		if err := json.Unmarshal(obj.Coordinates, &lines); err != nil {
+			return nil, fmt.Errorf("invalid MultiLineString coordinates: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	var out []TrackPoint
+	for _, line := range lines {
+		for _, pos := range line {
+			if len(pos) < 2 {
+				continue
+			}
+			tp := TrackPoint{Lon: pos[0], Lat: pos[1]}
+			if len(pos) > 2 {
+				tp.Ele, tp.HasEle = pos[2], true
+			}
+			out = append(out, tp)
+		}
+	}
+	return out, nil
+}
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b TrackPoint) float64 {
+	const earthRadius = 6371008.8
+	rad := math.Pi / 180
+	dLat := (b.Lat - a.Lat) * rad
+	dLon := (b.Lon - a.Lon) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(min(1, h)))
+}
+
+// Contains reports whether lon, lat lies inside the polygon's exterior ring
+// and outside its holes.
+func (p Polygon) Contains(lon, lat float64) bool {
+	if len(p) == 0 || !ringContains(p[0], lon, lat) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, lon, lat) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is the even-odd ray casting test.
+func ringContains(ring [][]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if len(ring[i]) < 2 || len(ring[j]) < 2 {
+			continue
+		}
+		xi, yi, xj, yj := ring[i][0], ring[i][1], ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+type indexedZone struct {
+	id                             string
+	polygons                       []Polygon
+	minLon, minLat, maxLon, maxLat float64
+}
+
+// ZoneIndex finds which zone contains a point.
+type ZoneIndex struct {
+	zones []indexedZone
+}
+
+// NewZoneIndex indexes the polygons of shapes, keyed by zone ID.
+func NewZoneIndex(shapes map[string]*Geometry) (*ZoneIndex, error) {
+	ix := &ZoneIndex{}
+	for id, g := range shapes {
+		polygons, err := g.Polygons()
+		if err != nil {
+			return nil, fmt.Errorf("zone %s: %w", id, err)
+		}
+		z := indexedZone{id: id, polygons: polygons,
+			minLon: math.Inf(1), minLat: math.Inf(1), maxLon: math.Inf(-1), maxLat: math.Inf(-1)}
+		for _, p := range polygons {
+			if len(p) == 0 {
+				continue
+			}
+			for _, pos := range p[0] {
+				if len(pos) < 2 {
+					continue
+				}
+				z.minLon, z.maxLon = min(z.minLon, pos[0]), max(z.maxLon, pos[0])
+				z.minLat, z.maxLat = min(z.minLat, pos[1]), max(z.maxLat, pos[1])
+			}
+		}
+		ix.zones = append(ix.zones, z)
+	}
+	// Overlapping zones resolve the same way on every request.
+	sort.Slice(ix.zones, func(i, j int) bool { return ix.zones[i].id < ix.zones[j].id })
+	return ix, nil
+}
+
+// Locate returns the ID of the zone containing lon, lat, or "" when the
+// point is outside every zone.
+func (ix *ZoneIndex) Locate(lon, lat float64) string {
+	for _, z := range ix.zones {
+		if lon < z.minLon || lon > z.maxLon || lat < z.minLat || lat > z.maxLat {
+			continue
+		}
+		for _, p := range z.polygons {
+			if p.Contains(lon, lat) {
+				return z.id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package geo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/geo"
+)
+
+func TestParseGPX(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><name>Tour</name>
+    <trkseg>
+      <trkpt lat="47.40" lon="-121.40"><ele>900.5</ele></trkpt>
+      <trkpt lat="47.41" lon="-121.41"><ele>1250</ele></trkpt>
+    </trkseg>
+    <trkseg><trkpt lat="47.42" lon="-121.42"></trkpt></trkseg>
+  </trk>
+  <rte><rtept lat="47.50" lon="-121.50"><ele>1800</ele></rtept></rte>
+</gpx>`
+	points, err := geo.ParseGPX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(points) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(points))
+	}
+	if p := points[0]; p.Lat != 47.40 || p.Lon != -121.40 || !p.HasEle || p.Ele != 900.5 {
+		t.Errorf("unexpected first point %+v", p)
+	}
+	if points[2].HasEle {
+		t.Error("point without <ele> should have no elevation")
+	}
+	if points[3].Ele != 1800 {
+		t.Errorf("expected route points after tracks, got %+v", points[3])
+	}
+
+	if _, err := geo.ParseGPX(strings.NewReader(`<gpx></gpx>`)); !errors.Is(err, geo.ErrNoTrack) {
+		t.Errorf("expected ErrNoTrack, got %v", err)
+	}
+}
+
+func TestParseLineString(t *testing.T) {
+	for name, doc := range map[string]string{
+		"geometry":   `{"type":"LineString","coordinates":[[-121.4,47.4,900],[-121.41,47.41]]}`,
+		"feature":    `{"type":"Feature","properties":{},"geometry":{"type":"LineString","coordinates":[[-121.4,47.4,900],[-121.41,47.41]]}}`,
+		"collection": `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]}},{"type":"Feature","geometry":{"type":"MultiLineString","coordinates":[[[-121.4,47.4,900]],[[-121.41,47.41]]]}}]}`,
+	} {
+		points, err := geo.ParseLineString([]byte(doc))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(points) != 2 || !points[0].HasEle || points[0].Ele != 900 || points[1].HasEle {
+			t.Errorf("%s: unexpected points %+v", name, points)
+		}
+	}
+	if _, err := geo.ParseLineString([]byte(`{"type":"Point","coordinates":[0,0]}`)); !errors.Is(err, geo.ErrNoTrack) {
+		t.Errorf("expected ErrNoTrack for a point, got %v", err)
+	}
+}
+
+func TestZoneIndex(t *testing.T) {
+	shapes := map[string]*geo.Geometry{
+		"NWAC_10": {Type: "Polygon", Coordinates: json.RawMessage(`[
+			[[-122,47],[-121,47],[-121,48],[-122,48],[-122,47]],
+			[[-121.6,47.4],[-121.4,47.4],[-121.4,47.6],[-121.6,47.6],[-121.6,47.4]]]`)},
+		"NWAC_11": {Type: "MultiPolygon", Coordinates: json.RawMessage(`[[[[-121,47],[-120,47],[-120,48],[-121,47]]]]`)},
+	}
+	ix, err := geo.NewZoneIndex(shapes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		lon, lat float64
+		want     string
+	}{
+		{-121.8, 47.2, "NWAC_10"},
+		{-121.5, 47.5, ""}, // in the hole
+		{-120.2, 47.5, "NWAC_11"},
+		{-120.8, 47.9, ""}, // inside the bounding box, outside the triangle
+		{-110, 40, ""},
+	} {
+		if got := ix.Locate(tt.lon, tt.lat); got != tt.want {
+			t.Errorf("Locate(%v, %v) = %q, want %q", tt.lon, tt.lat, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// One degree of latitude is about 111.2 km.
+	d := geo.Distance(geo.TrackPoint{Lon: -121, Lat: 47}, geo.TrackPoint{Lon: -121, Lat: 48})
+	if math.Abs(d-111195) > 100 {
+		t.Errorf("unexpected distance %.0f m", d)
+	}
+}
@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ShapeFetcher loads the zone polygons for one avalanche center, keyed by
+// zone ID such as "NWAC_10".
+type ShapeFetcher interface {
+	FetchZoneShapes(centerID string) (map[string]*Geometry, error)
+}
+
+// DefaultShapeTTL is how long zone polygons are cached. Zone boundaries change
+// at most a few times a season.
+const DefaultShapeTTL = 24 * time.Hour
+
+type shapeEntry struct {
+	shapes    map[string]*Geometry
+	fetchedAt time.Time
+}
+
+// ShapeCache caches zone polygons per center in memory.
+type ShapeCache struct {
+	fetcher ShapeFetcher
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	centers map[string]shapeEntry
+}
+
+// NewShapeCache creates a cache that refetches a center's polygons after ttl.
+// A zero ttl uses DefaultShapeTTL.
+func NewShapeCache(fetcher ShapeFetcher, ttl time.Duration) *ShapeCache {
+	if ttl <= 0 {
+		ttl = DefaultShapeTTL
+	}
+	return &ShapeCache{fetcher: fetcher, ttl: ttl, centers: make(map[string]shapeEntry)}
+}
+
+// Shapes returns the polygons for every zone of the given centers. When a
+// refresh fails, the previously fetched polygons are served instead.
+func (c *ShapeCache) Shapes(centerIDs []string) (map[string]*Geometry, error) {
+	out := make(map[string]*Geometry)
+	for _, id := range centerIDs {
+		shapes, err := c.center(id)
+		if err != nil {
+			return nil, err
+		}
+		for k, g := range shapes {
+			out[k] = g
+		}
+	}
+	return out, nil
+}
+
+// center returns one center's polygons. Upstream is fetched without holding
+// the lock, so a slow center does not hold up lookups for the others.
+func (c *ShapeCache) center(id string) (map[string]*Geometry, error) {
+	id = strings.ToUpper(id)
+	c.mu.Lock()
+	entry, ok := c.centers[id]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.shapes, nil
+	}
+
+	started := time.Now()
+	shapes, err := c.fetcher.FetchZoneShapes(id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Another lookup may have stored the center while this one fetched.
+	entry, ok = c.centers[id]
+	if err != nil {
+		if ok {
+			log.Printf("using cached zone shapes for %s: %v", id, err)
+			return entry.shapes, nil
+		}
+		return nil, fmt.Errorf("failed to fetch zone shapes for %s: %w", id, err)
+	}
+	if ok && entry.fetchedAt.After(started) {
+		return entry.shapes, nil
+	}
+	c.centers[id] = shapeEntry{shapes: shapes, fetchedAt: time.Now()}
+	return shapes, nil
+}
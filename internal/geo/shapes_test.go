@@ -0,0 +1,109 @@
+package geo_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/geo"
+)
+
+type stubFetcher struct {
+	calls int
+	err   error
+	// block, when set, holds fetches of that center until it is closed.
+	block   chan struct{}
+	blocked string
+}
+
+func (s *stubFetcher) FetchZoneShapes(centerID string) (map[string]*geo.Geometry, error) {
+	if centerID == s.blocked {
+		<-s.block
+		return map[string]*geo.Geometry{centerID + "_1": {Type: "Polygon"}}, nil
+	}
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return map[string]*geo.Geometry{centerID + "_1": {Type: "Polygon"}}, nil
+}
+
+func TestShapeCache_CachesPerCenter(t *testing.T) {
+	f := &stubFetcher{}
+	c := geo.NewShapeCache(f, time.Hour)
+
+	for range 2 {
+		shapes, err := c.Shapes([]string{"NWAC", "IPAC"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shapes["NWAC_1"] == nil || shapes["IPAC_1"] == nil {
+			t.Fatalf("missing shapes: %v", shapes)
+		}
+	}
+	if f.calls != 2 {
+		t.Errorf("expected one fetch per center, got %d", f.calls)
+	}
+}
+
+func TestShapeCache_IgnoresCenterCase(t *testing.T) {
+	f := &stubFetcher{}
+	c := geo.NewShapeCache(f, time.Hour)
+	for _, id := range []string{"nwac", "NWAC", "Nwac"} {
+		if shapes, err := c.Shapes([]string{id}); err != nil || shapes["NWAC_1"] == nil {
+			t.Fatalf("%s: %v %v", id, shapes, err)
+		}
+	}
+	if f.calls != 1 {
+		t.Errorf("expected one fetch for every spelling, got %d", f.calls)
+	}
+}
+
+func TestShapeCache_SlowCenterDoesNotBlockOthers(t *testing.T) {
+	f := &stubFetcher{block: make(chan struct{}), blocked: "SLOW"}
+	c := geo.NewShapeCache(f, time.Hour)
+
+	done := make(chan error)
+	go func() {
+		_, err := c.Shapes([]string{"SLOW"})
+		done <- err
+	}()
+	fast := make(chan error)
+	go func() {
+		_, err := c.Shapes([]string{"NWAC"})
+		fast <- err
+	}()
+	select {
+	case err := <-fast:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a slow center blocked lookups for another")
+	}
+	close(f.block)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShapeCache_ServesStaleOnError(t *testing.T) {
+	f := &stubFetcher{}
+	c := geo.NewShapeCache(f, time.Nanosecond)
+	if _, err := c.Shapes([]string{"NWAC"}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.err = errors.New("upstream down")
+	shapes, err := c.Shapes([]string{"NWAC"})
+	if err != nil {
+		t.Fatalf("expected stale shapes, got %v", err)
+	}
+	if shapes["NWAC_1"] == nil {
+		t.Error("expected cached NWAC_1 shape")
+	}
+
+	if _, err := c.Shapes([]string{"IPAC"}); err == nil {
+		t.Error("expected error for a center never fetched")
+	}
+}
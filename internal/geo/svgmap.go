@@ -0,0 +1,215 @@
+package geo
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+// ErrNoZoneShapes is returned by SVGMap when no forecast zone has a polygon.
+var ErrNoZoneShapes = errors.New("no zone polygons to draw")
+
+const (
+	mapWidth     = 600
+	mapMaxHeight = 700
+	mapPad       = 12
+	legendHeight = 28
+	// simplifyTolerance drops vertices closer than this many pixels to the
+	// simplified outline; zone polygons carry far more detail than a map this
+	// size can show.
+	simplifyTolerance = 0.75
+)
+
+type point struct{ x, y float64 }
+
+type mapZone struct {
+	name     string
+	level    domain.DangerLevel
+	polygons []Polygon
+}
+
+// SVGMap draws the zones of forecasts that have a polygon in shapes, filled
+// with the scale color of each zone's highest danger rating today and
+// labelled with the zone name, above a legend of the danger scale.
+func SVGMap(title string, forecasts []models.ZoneForecast, shapes map[string]*Geometry) ([]byte, error) {
+	var zones []mapZone
+	minLon, minLat := math.Inf(1), math.Inf(1)
+	maxLon, maxLat := math.Inf(-1), math.Inf(-1)
+	for _, f := range forecasts {
+		polygons, err := shapes[f.ZoneID].Polygons()
+		if err != nil {
+			return nil, fmt.Errorf("zone %s: %w", f.ZoneID, err)
+		}
+		drawn := false
+		for _, p := range polygons {
+			if len(p) == 0 {
+				continue
+			}
+			for _, pos := range p[0] {
+				if len(pos) < 2 {
+					continue
+				}
+				minLon, maxLon = min(minLon, pos[0]), max(maxLon, pos[0])
+				minLat, maxLat = min(minLat, pos[1]), max(maxLat, pos[1])
+				drawn = true
+			}
+		}
+		if drawn {
+			zones = append(zones, mapZone{name: cmp.Or(f.ZoneName, f.ZoneID), level: f.TodayDanger.Max(), polygons: polygons})
+		}
+	}
+	if len(zones) == 0 {
+		return nil, ErrNoZoneShapes
+	}
+
+	// Equirectangular projection with longitude scaled by the cosine of the
+	// middle latitude, which is accurate to a few percent over one center.
+	kx := math.Cos((minLat + maxLat) / 2 * math.Pi / 180)
+	spanX := max((maxLon-minLon)*kx, 1e-9)
+	spanY := max(maxLat-minLat, 1e-9)
+	scale := (mapWidth - 2*mapPad) / spanX
+	if spanY*scale > mapMaxHeight-2*mapPad {
+		scale = (mapMaxHeight - 2*mapPad) / spanY
+	}
+	offsetX := mapPad + (mapWidth-2*mapPad-spanX*scale)/2
+	plotHeight := math.Ceil(spanY * scale)
+	height := int(plotHeight) + 2*mapPad + legendHeight
+	project := func(pos []float64) point {
+		return point{offsetX + (pos[0]-minLon)*kx*scale, mapPad + (maxLat-pos[1])*scale}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s" font-family="Arial,Helvetica,sans-serif" font-size="10">`,
+		mapWidth, height, mapWidth, height, html.EscapeString(title))
+	fmt.Fprintf(&b, `<title>%s</title>`, html.EscapeString(title))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`, mapWidth, height)
+
+	labels := make([]point, len(zones))
+	for i, z := range zones {
+		var d strings.Builder
+		var largest float64
+		for _, p := range z.polygons {
+			for r, ring := range p {
+				pts := make([]point, 0, len(ring))
+				for _, pos := range ring {
+					if len(pos) >= 2 {
+						pts = append(pts, project(pos))
+					}
+				}
+				if largest == 0 && len(pts) > 0 {
+					// Placeholder label for zones too small to draw.
+					labels[i] = pts[0]
+				}
+				pts = simplify(pts, simplifyTolerance)
+				if len(pts) < 3 {
+					continue
+				}
+				for j, pt := range pts {
+					cmd := "L"
+					if j == 0 {
+						cmd = "M"
+					}
+					fmt.Fprintf(&d, "%s%.1f %.1f", cmd, pt.x, pt.y)
+				}
+				d.WriteString("Z")
+				if r == 0 {
+					if c, area := centroid(pts); area > largest {
+						labels[i], largest = c, area
+					}
+				}
+			}
+		}
+		if d.Len() > 0 {
+			fmt.Fprintf(&b, `<path d="%s" fill="%s" fill-opacity="0.85" fill-rule="evenodd" stroke="#444" stroke-width="0.75"><title>%s: %s</title></path>`,
+				d.String(), z.level.Color(), html.EscapeString(z.name), z.level.Name())
+		}
+	}
+	// Labels go on top of every zone so neighbors cannot cover them.
+	for i, z := range zones {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" fill="#222" stroke="#fff" stroke-width="3" paint-order="stroke">%s</text>`,
+			labels[i].x, labels[i].y+3, html.EscapeString(z.name))
+	}
+
+	legendY := int(plotHeight) + 2*mapPad + 6
+	for i, level := range []domain.DangerLevel{1, 2, 3, 4, 5, 0} {
+		x := mapPad + i*96
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s" stroke="#444" stroke-width="0.5"/>`, x, legendY, level.Color())
+		label := level.Name()
+		if level.IsRated() {
+			label = fmt.Sprintf("%d - %s", level, label)
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#222">%s</text>`, x+16, legendY+10, label)
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String()), nil
+}
+
+// simplify reduces a ring with the Douglas-Peucker algorithm, keeping points
+// that deviate from the simplified outline by more than tolerance.
+func simplify(pts []point, tolerance float64) []point {
+	if len(pts) < 3 {
+		return pts
+	}
+	keep := make([]bool, len(pts))
+	keep[0], keep[len(pts)-1] = true, true
+	var walk func(first, last int)
+	walk = func(first, last int) {
+		index, dmax := 0, 0.0
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(pts[i], pts[first], pts[last]); d > dmax {
+				index, dmax = i, d
+			}
+		}
+		if dmax > tolerance {
+			keep[index] = true
+			walk(first, index)
+			walk(index, last)
+		}
+	}
+	walk(0, len(pts)-1)
+
+	out := make([]point, 0, len(pts))
+	for i, p := range pts {
+		if keep[i] {
+			out = append(out, p)
+		}
+	}
+	// Rings repeat their first point; the path closes itself.
+	if len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// segmentDistance returns the distance from p to the segment a-b.
+func segmentDistance(p, a, b point) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	if dx == 0 && dy == 0 {
+		return math.Hypot(p.x-a.x, p.y-a.y)
+	}
+	t := ((p.x-a.x)*dx + (p.y-a.y)*dy) / (dx*dx + dy*dy)
+	t = max(0, min(1, t))
+	return math.Hypot(p.x-(a.x+t*dx), p.y-(a.y+t*dy))
+}
+
+// centroid returns the area centroid of a ring and its absolute area.
+func centroid(pts []point) (point, float64) {
+	var a, cx, cy float64
+	for i := range pts {
+		p, q := pts[i], pts[(i+1)%len(pts)]
+		cross := p.x*q.y - q.x*p.y
+		a += cross
+		cx += (p.x + q.x) * cross
+		cy += (p.y + q.y) * cross
+	}
+	if a == 0 {
+		return point{}, 0
+	}
+	return point{cx / (3 * a), cy / (3 * a)}, math.Abs(a / 2)
+}
@@ -0,0 +1,74 @@
+package geo_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+)
+
+func TestSVGMap(t *testing.T) {
+	// A square zone whose edges carry many nearly collinear points, and a
+	// neighbor with a hole.
+	var ring [][]float64
+	for i := 0; i <= 100; i++ {
+		ring = append(ring, []float64{-121 + float64(i)*0.005, 47})
+	}
+	ring = append(ring, []float64{-120.5, 47.5}, []float64{-121, 47.5}, []float64{-121, 47})
+	square, _ := json.Marshal([][][]float64{ring})
+	shapes := map[string]*geo.Geometry{
+		"NWAC_10": {Type: "Polygon", Coordinates: square},
+		"NWAC_11": {Type: "Polygon", Coordinates: json.RawMessage(`[
+			[[-120.5,47],[-120,47],[-120,47.5],[-120.5,47.5],[-120.5,47]],
+			[[-120.3,47.2],[-120.2,47.2],[-120.2,47.3],[-120.3,47.2]]]`)},
+	}
+	forecasts := []models.ZoneForecast{
+		{ZoneID: "NWAC_10", ZoneName: "Snoqualmie Pass", TodayDanger: &models.DangerRating{Upper: 4, Middle: 3, Lower: 2}},
+		{ZoneID: "NWAC_11", ZoneName: "Stevens Pass & Skykomish"},
+		{ZoneID: "NWAC_12", ZoneName: "No polygon"},
+	}
+
+	svg, err := geo.SVGMap("NWAC danger", forecasts, shapes)
+	if err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	dec := xml.NewDecoder(strings.NewReader(string(svg)))
+	for {
+		if _, err := dec.Token(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("malformed SVG: %v", err)
+		}
+	}
+
+	out := string(svg)
+	if n := strings.Count(out, "<path"); n != 2 {
+		t.Fatalf("expected a path per zone with a polygon, got %d", n)
+	}
+	_, first, _ := strings.Cut(out, `<path d="`)
+	first, _, _ = strings.Cut(first, `"`)
+	if n := strings.Count(first, "L"); n > 5 {
+		t.Errorf("expected the collinear edge to be simplified, got %d segments: %s", n, first)
+	}
+	for _, want := range []string{
+		`fill="#ED1C24"`, "Snoqualmie Pass: High",
+		"Stevens Pass &amp; Skykomish: No Rating",
+		"5 - Extreme", // legend
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("map missing %q", want)
+		}
+	}
+	if strings.Contains(out, "No polygon") {
+		t.Error("zones without polygons should be left out")
+	}
+
+	if _, err := geo.SVGMap("empty", forecasts[2:], shapes); !errors.Is(err, geo.ErrNoZoneShapes) {
+		t.Errorf("expected ErrNoZoneShapes, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/avalanche/internal/badge"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/services"
+)
+
+type ZoneBadges interface {
+	ZoneBadge(zoneID *domain.ZoneID) ([]byte, error)
+	ZoneHistory(ctx context.Context, zoneID *domain.ZoneID, days int) ([]byte, error)
+}
+
+const (
+	// badgeMaxAge lets pages and mail clients reuse an image for a while;
+	// revalidation afterwards is cheap thanks to the ETag.
+	badgeMaxAge = "public, max-age=900"
+
+	defaultHistoryDays = 30
+	maxHistoryDays     = 365
+)
+
+// BadgeHandler serves embeddable SVG images of zone danger.
+type BadgeHandler struct {
+	badges ZoneBadges
+	zones  ZoneResolver
+}
+
+func NewBadgeHandler(badges ZoneBadges) *BadgeHandler {
+	return &BadgeHandler{badges: badges}
+}
+
+// SetZoneResolver lets badge paths name zones by name, slug or alias.
+func (h *BadgeHandler) SetZoneResolver(zones ZoneResolver) {
+	h.zones = zones
+}
+
+// GET /api/zones/{zoneID}/badge.svg
+func (h *BadgeHandler) Badge(w http.ResponseWriter, r *http.Request) {
+	zoneID, ok := h.badgeZone(w, r)
+	if !ok {
+		return
+	}
+	svg, err := h.badges.ZoneBadge(zoneID)
+	switch {
+	case errors.Is(err, services.ErrZoneNotFound):
+		http.Error(w, "no current forecast for "+zoneID.String(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "error fetching forecast: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeSVG(w, r, svg)
+}
+
+// GET /api/zones/{zoneID}/history.svg?days=30
+func (h *BadgeHandler) History(w http.ResponseWriter, r *http.Request) {
+	zoneID, ok := h.badgeZone(w, r)
+	if !ok {
+		return
+	}
+	days := defaultHistoryDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 2 || n > maxHistoryDays {
+			http.Error(w, "days must be between 2 and "+strconv.Itoa(maxHistoryDays), http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	svg, err := h.badges.ZoneHistory(r.Context(), zoneID, days)
+	if err != nil {
+		http.Error(w, "error loading history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeSVG(w, r, svg)
+}
+
+func (h *BadgeHandler) badgeZone(w http.ResponseWriter, r *http.Request) (*domain.ZoneID, bool) {
+	zoneID, ok := resolveZone(w, r, h.zones, r.PathValue("zoneID"), "zone ID")
+	if !ok {
+		return nil, false
+	}
+	if !zoneID.IsSpecificZone() {
+		http.Error(w, "invalid zone ID", http.StatusBadRequest)
+		return nil, false
+	}
+	return zoneID, true
+}
+
+// writeSVG sends an image tagged with a hash of its content, answering
+// conditional requests for an unchanged image with 304 Not Modified.
+func writeSVG(w http.ResponseWriter, r *http.Request, svg []byte) {
+	sum := sha256.Sum256(svg)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", badgeMaxAge)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", badge.ContentType)
+	if _, err := w.Write(svg); err != nil {
+		log.Printf("[BadgeHandler] failed to write image: %v", err)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the
+// weak comparison RFC 9110 requires for that header.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
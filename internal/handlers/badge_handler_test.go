@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/services"
+)
+
+type stubBadges struct {
+	level int
+	days  int
+}
+
+func (s *stubBadges) ZoneBadge(zoneID *domain.ZoneID) ([]byte, error) {
+	if zoneID.String() != "NWAC_10" {
+		return nil, services.ErrZoneNotFound
+	}
+	return []byte("<svg>" + zoneID.String() + string(rune('0'+s.level)) + "</svg>"), nil
+}
+
+func (s *stubBadges) ZoneHistory(ctx context.Context, zoneID *domain.ZoneID, days int) ([]byte, error) {
+	s.days = days
+	return []byte("<svg>history</svg>"), nil
+}
+
+func TestBadgeHandler_ETag(t *testing.T) {
+	badges := &stubBadges{level: 3}
+	h := handlers.NewBadgeHandler(badges)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/zones/{zoneID}/badge.svg", h.Badge)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/zones/NWAC_10/badge.svg", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if rec.Header().Get("Cache-Control") == "" {
+		t.Error("expected Cache-Control")
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	rec = get(`"other", W/` + etag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("expected empty 304 for matching ETag, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// A new rating changes the image and its ETag.
+	badges.level = 4
+	rec = get(etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("expected a fresh image with a new ETag, got %d %s", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestBadgeHandler_Errors(t *testing.T) {
+	badges := &stubBadges{}
+	h := handlers.NewBadgeHandler(badges)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/zones/{zoneID}/badge.svg", h.Badge)
+	mux.HandleFunc("GET /api/zones/{zoneID}/history.svg", h.History)
+
+	for path, want := range map[string]int{
+		"/api/zones/NWAC_99/badge.svg":           http.StatusNotFound,
+		"/api/zones/NWAC/badge.svg":              http.StatusBadRequest,
+		"/api/zones/NWAC_10/history.svg?days=1":  http.StatusBadRequest,
+		"/api/zones/NWAC_10/history.svg?days=x":  http.StatusBadRequest,
+		"/api/zones/NWAC_10/history.svg?days=90": http.StatusOK,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+	if badges.days != 90 {
+		t.Errorf("expected 90 days of history, got %d", badges.days)
+	}
+}
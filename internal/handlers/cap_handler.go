@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/atom"
+	"example.com/avalanche/internal/cap"
+	"example.com/avalanche/internal/services"
+)
+
+type AlertFeed interface {
+	ActiveAlerts(centerIDs []string) ([]cap.Alert, error)
+	Alert(identifier string) (*cap.Alert, error)
+}
+
+// CAPHandler publishes avalanche alerts in the Common Alerting Protocol, with
+// an Atom index for aggregators.
+type CAPHandler struct {
+	alerts AlertFeed
+	// baseURL is the server's public address, which index links are rooted
+	// at; they are paths when it is empty.
+	baseURL string
+}
+
+func NewCAPHandler(alerts AlertFeed, baseURL string) *CAPHandler {
+	return &CAPHandler{alerts: alerts, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// GET /api/cap/alerts.atom?centers=NWAC,IPAC
+func (h *CAPHandler) Index(w http.ResponseWriter, r *http.Request) {
+	var centerIDs []string
+	for _, id := range strings.Split(r.URL.Query().Get("centers"), ",") {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+			centerIDs = append(centerIDs, id)
+		}
+	}
+	alerts, err := h.alerts.ActiveAlerts(centerIDs)
+	if err != nil {
+		http.Error(w, "error fetching alerts: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	base := h.baseURL
+	feed := atom.Feed{
+		ID:      base + r.URL.RequestURI(),
+		Title:   "Avalanche alerts",
+		Updated: atom.Time(time.Now()),
+		Links:   []atom.Link{{Href: base + r.URL.RequestURI(), Rel: "self", Type: atom.ContentType}},
+	}
+	for _, a := range alerts {
+		sent := capToAtomTime(a.Sent)
+		href := base + "/api/cap/alerts/" + a.Identifier
+		entry := atom.Entry{
+			ID:      href,
+			Updated: sent,
+			Links:   []atom.Link{{Href: href, Rel: "alternate", Type: cap.ContentType}},
+		}
+		if len(a.Info) > 0 {
+			info := a.Info[0]
+			entry.Title = info.Headline
+			entry.Author = &atom.Person{Name: info.SenderName}
+			entry.Summary = &atom.Text{Type: "text", Body: info.Instruction}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Updated
+	}
+
+	w.Header().Set("Content-Type", atom.ContentType)
+	if err := atom.Write(w, feed); err != nil {
+		log.Printf("[CAPHandler] failed to write alert index: %v", err)
+	}
+}
+
+// GET /api/cap/alerts/{id}
+func (h *CAPHandler) GetAlert(w http.ResponseWriter, r *http.Request) {
+	alert, err := h.alerts.Alert(r.PathValue("id"))
+	switch {
+	case errors.Is(err, services.ErrInvalidAlertID):
+		http.Error(w, "alert not found", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "error fetching alert: "+err.Error(), http.StatusBadGateway)
+		return
+	case alert == nil:
+		http.Error(w, "alert has expired or been superseded", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", cap.ContentType)
+	if err := cap.Write(w, *alert); err != nil {
+		log.Printf("[CAPHandler] failed to write alert %s: %v", alert.Identifier, err)
+	}
+}
+
+// capToAtomTime converts a CAP timestamp to RFC 3339 UTC.
+func capToAtomTime(s string) string {
+	t, err := time.Parse("2006-01-02T15:04:05-07:00", s)
+	if err != nil {
+		return s
+	}
+	return atom.Time(t)
+}
@@ -0,0 +1,103 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/cap"
+	"example.com/avalanche/internal/handlers"
+)
+
+type stubAlertFeed struct {
+	alerts  []cap.Alert
+	centers []string
+}
+
+func (s *stubAlertFeed) ActiveAlerts(centerIDs []string) ([]cap.Alert, error) {
+	s.centers = centerIDs
+	return s.alerts, nil
+}
+
+func (s *stubAlertFeed) Alert(identifier string) (*cap.Alert, error) {
+	for i := range s.alerts {
+		if s.alerts[i].Identifier == identifier {
+			return &s.alerts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func newCAPMux(feed *stubAlertFeed) *http.ServeMux {
+	h := handlers.NewCAPHandler(feed, "https://avy.example.com/")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/cap/alerts.atom", h.Index)
+	mux.HandleFunc("GET /api/cap/alerts/{id}", h.GetAlert)
+	return mux
+}
+
+func TestCAPHandler_Index(t *testing.T) {
+	feed := &stubAlertFeed{alerts: []cap.Alert{{
+		Identifier: "NWAC-103-1736924400",
+		Sent:       "2025-01-15T07:00:00-00:00",
+		Info:       []cap.Info{{Headline: "Avalanche Warning: Snoqualmie Pass", SenderName: "NWAC"}},
+	}}}
+	req := httptest.NewRequest(http.MethodGet, "/api/cap/alerts.atom?centers=nwac", nil)
+	req.Header.Set("X-Forwarded-Proto", "http")
+	req.Host = "attacker.example"
+	rec := httptest.NewRecorder()
+	newCAPMux(feed).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/atom+xml" {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if len(feed.centers) != 1 || feed.centers[0] != "NWAC" {
+		t.Errorf("expected center filter NWAC, got %v", feed.centers)
+	}
+	var out struct {
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+				Type string `xml:"type,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Entries) != 1 {
+		t.Fatalf("expected one entry: %s", rec.Body.String())
+	}
+	e := out.Entries[0]
+	if e.Title != "Avalanche Warning: Snoqualmie Pass" || e.Updated != "2025-01-15T07:00:00Z" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if e.Link.Href != "https://avy.example.com/api/cap/alerts/NWAC-103-1736924400" || e.Link.Type != "application/cap+xml" {
+		t.Errorf("unexpected link %+v", e.Link)
+	}
+}
+
+func TestCAPHandler_GetAlert(t *testing.T) {
+	feed := &stubAlertFeed{alerts: []cap.Alert{{Identifier: "NWAC-103-1736924400", Status: cap.StatusActual}}}
+	mux := newCAPMux(feed)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/cap/alerts/NWAC-103-1736924400", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/cap+xml" {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "<identifier>NWAC-103-1736924400</identifier>") {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/cap/alerts/NWAC-103-1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for superseded alert, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/services"
+)
+
+type ForecastExporter interface {
+	Export(ctx context.Context, w io.Writer, format string, filter db.ArchiveFilter) error
+}
+
+// ExportHandler streams archived forecast history for research use.
+type ExportHandler struct {
+	exporter ForecastExporter
+	zones    ZoneResolver
+}
+
+func NewExportHandler(exporter ForecastExporter) *ExportHandler {
+	return &ExportHandler{exporter: exporter}
+}
+
+// SetZoneResolver lets the zones filter name zones by name, slug or alias.
+func (h *ExportHandler) SetZoneResolver(zones ZoneResolver) {
+	h.zones = zones
+}
+
+// exportContentTypes maps export formats to response media types.
+var exportContentTypes = map[string]string{
+	services.ExportCSV:    "text/csv; charset=utf-8",
+	services.ExportNDJSON: "application/x-ndjson",
+}
+
+// GET /api/export/forecasts?format=csv|ndjson&centers=&zones=&from=&to=
+func (h *ExportHandler) ExportForecasts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = services.ExportCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		http.Error(w, services.ErrUnknownExportFormat.Error(), http.StatusBadRequest)
+		return
+	}
+	zones, ok := resolveZoneList(w, r, h.zones, q.Get("zones"), "zones")
+	if !ok {
+		return
+	}
+	filter, err := services.ParseExportFilter(q.Get("centers"), zones, q.Get("from"), q.Get("to"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filename := "avalanche-forecasts-" + time.Now().UTC().Format("20060102") + "." + format
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	// Headers are already sent once rows stream, so a failure part way
+	// through can only truncate the response.
+	if err := h.exporter.Export(r.Context(), w, format, filter); err != nil && r.Context().Err() == nil {
+		log.Printf("[ExportHandler] %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/handlers"
+)
+
+type stubExporter struct {
+	format string
+	filter db.ArchiveFilter
+}
+
+func (s *stubExporter) Export(ctx context.Context, w io.Writer, format string, filter db.ArchiveFilter) error {
+	s.format, s.filter = format, filter
+	_, err := io.WriteString(w, "date,center_id\n")
+	return err
+}
+
+func TestExportHandler(t *testing.T) {
+	exp := &stubExporter{}
+	h := handlers.NewExportHandler(exp)
+
+	rec := httptest.NewRecorder()
+	h.ExportForecasts(rec, httptest.NewRequest(http.MethodGet, "/api/export/forecasts?centers=nwac&from=2024-11-01&to=2025-04-30", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), ".csv") {
+		t.Errorf("unexpected disposition %q", rec.Header().Get("Content-Disposition"))
+	}
+	if exp.format != "csv" || len(exp.filter.CenterIDs) != 1 || exp.filter.CenterIDs[0] != "NWAC" || exp.filter.From != "2024-11-01" {
+		t.Errorf("unexpected export call %q %+v", exp.format, exp.filter)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ExportForecasts(rec, httptest.NewRequest(http.MethodGet, "/api/export/forecasts?format=ndjson", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("unexpected ndjson content type %q", ct)
+	}
+
+	for _, path := range []string{"/api/export/forecasts?format=xlsx", "/api/export/forecasts?from=yesterday"} {
+		rec = httptest.NewRecorder()
+		h.ExportForecasts(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, rec.Code)
+		}
+	}
+}
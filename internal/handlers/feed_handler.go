@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"example.com/avalanche/internal/atom"
+	"example.com/avalanche/internal/domain"
+)
+
+type FeedService interface {
+	ZoneFeed(zoneID *domain.ZoneID, selfURL string) (atom.Feed, error)
+	CenterFeed(centerID string, selfURL string) (atom.Feed, error)
+}
+
+// feedMaxAge lets feed readers and proxies cache feeds between polls.
+const feedMaxAge = "public, max-age=300"
+
+// FeedHandler serves Atom feeds of issued forecasts.
+type FeedHandler struct {
+	feeds FeedService
+	zones ZoneResolver
+	// baseURL is the server's public address, which feed links are rooted
+	// at; they are paths when it is empty.
+	baseURL string
+}
+
+func NewFeedHandler(feeds FeedService, baseURL string) *FeedHandler {
+	return &FeedHandler{feeds: feeds, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// SetZoneResolver lets zone feeds name zones by name, slug or alias.
+func (h *FeedHandler) SetZoneResolver(zones ZoneResolver) {
+	h.zones = zones
+}
+
+// GET /feeds/zones/{zoneID}.atom
+func (h *FeedHandler) ZoneFeed(w http.ResponseWriter, r *http.Request) {
+	name, ok := atomFileName(r.PathValue("file"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	zoneID, ok := resolveZone(w, r, h.zones, name, "zone ID")
+	if !ok {
+		return
+	}
+	if !zoneID.IsSpecificZone() {
+		http.Error(w, "invalid zone ID", http.StatusBadRequest)
+		return
+	}
+	feed, err := h.feeds.ZoneFeed(zoneID, h.baseURL+r.URL.Path)
+	if err != nil {
+		http.Error(w, "error building feed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeFeed(w, feed)
+}
+
+// GET /feeds/centers/{centerID}.atom
+func (h *FeedHandler) CenterFeed(w http.ResponseWriter, r *http.Request) {
+	name, ok := atomFileName(r.PathValue("file"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	zoneID, err := domain.ParseZoneID(name)
+	if err != nil || !zoneID.IsCenterLevel() {
+		http.Error(w, "invalid center ID", http.StatusBadRequest)
+		return
+	}
+	feed, err := h.feeds.CenterFeed(zoneID.Center(), h.baseURL+r.URL.Path)
+	if err != nil {
+		http.Error(w, "error building feed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeFeed(w, feed)
+}
+
+// atomFileName strips the .atom extension from a feed path segment.
+func atomFileName(file string) (string, bool) {
+	name, ok := strings.CutSuffix(file, ".atom")
+	return name, ok && name != ""
+}
+
+func writeFeed(w http.ResponseWriter, feed atom.Feed) {
+	w.Header().Set("Content-Type", atom.ContentType+"; charset=utf-8")
+	w.Header().Set("Cache-Control", feedMaxAge)
+	if err := atom.Write(w, feed); err != nil {
+		log.Printf("[FeedHandler] failed to write feed %s: %v", feed.ID, err)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type atomFeed struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Updated   string `xml:"updated"`
+		Published string `xml:"published"`
+		Content   struct {
+			Type string `xml:"type,attr"`
+			Body string `xml:",chardata"`
+		} `xml:"content"`
+	} `xml:"entry"`
+}
+
+func newFeedMux(t *testing.T) (*http.ServeMux, *gorm.DB) {
+	t.Helper()
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.ArchivedForecast{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	h := handlers.NewFeedHandler(services.NewFeedService(db.NewArchiveRepository(gdb)), "https://avy.example.com")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /feeds/zones/{file}", h.ZoneFeed)
+	mux.HandleFunc("GET /feeds/centers/{file}", h.CenterFeed)
+	return mux, gdb
+}
+
+func getFeed(t *testing.T, mux *http.ServeMux, path string) atomFeed {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: expected 200, got %d: %s", path, rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("decode feed: %v", err)
+	}
+	return feed
+}
+
+func TestFeedHandler_ZoneAndCenterFeeds(t *testing.T) {
+	mux, gdb := newFeedMux(t)
+	issued := time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC)
+	rows := []models.ArchivedForecast{
+		{ZoneID: "NWAC_10", ProductID: 501, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			CenterName: "Northwest Avalanche Center", Title: "Snoqualmie Pass: High", Content: "<p>Upper: 4</p>",
+			IssuedAt: issued, AmendedAt: issued.Add(2 * time.Hour)},
+		{ZoneID: "NWAC_11", ProductID: 502, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Stevens Pass",
+			CenterName: "Northwest Avalanche Center", Title: "Stevens Pass: Considerable", Content: "<p>Upper: 3</p>",
+			IssuedAt: issued, AmendedAt: issued},
+	}
+	if err := gdb.Create(&rows).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	zone := getFeed(t, mux, "/feeds/zones/NWAC_10.atom")
+	if zone.Title != "Avalanche forecasts: Snoqualmie Pass" || len(zone.Entries) != 1 {
+		t.Fatalf("unexpected zone feed: %+v", zone)
+	}
+	e := zone.Entries[0]
+	if e.Updated != "2025-12-01T16:00:00Z" || e.Published != "2025-12-01T14:00:00Z" {
+		t.Errorf("expected amendment in updated and issuance in published, got %s / %s", e.Updated, e.Published)
+	}
+	if e.Content.Type != "html" || e.Content.Body != "<p>Upper: 4</p>" {
+		t.Errorf("unexpected content %+v", e.Content)
+	}
+	if zone.Updated != e.Updated {
+		t.Errorf("feed updated %s should match newest entry %s", zone.Updated, e.Updated)
+	}
+
+	// Entry IDs are stable across requests and shared with the center feed.
+	again := getFeed(t, mux, "/feeds/zones/NWAC_10.atom")
+	if again.Entries[0].ID != e.ID || again.ID != zone.ID {
+		t.Errorf("feed IDs changed between requests")
+	}
+	center := getFeed(t, mux, "/feeds/centers/nwac.atom")
+	if len(center.Entries) != 2 || center.Entries[0].ID != e.ID {
+		t.Fatalf("unexpected center feed: %+v", center)
+	}
+	if center.Title != "Avalanche forecasts: Northwest Avalanche Center" || center.ID == zone.ID {
+		t.Errorf("unexpected center feed header %q %q", center.Title, center.ID)
+	}
+}
+
+func TestFeedHandler_BadPaths(t *testing.T) {
+	mux, _ := newFeedMux(t)
+	for path, want := range map[string]int{
+		"/feeds/zones/NWAC_10.rss":    http.StatusNotFound,
+		"/feeds/zones/NWAC.atom":      http.StatusBadRequest,
+		"/feeds/centers/NWAC_10.atom": http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"example.com/avalanche/internal/geo"
 	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
 )
 
 type ForecastService interface {
@@ -17,29 +23,159 @@ type CenterRepository interface {
 	GetActiveCenters() ([]models.AvalancheCenter, error)
 }
 
+// ZoneShapeSource provides zone polygons for GeoJSON output.
+type ZoneShapeSource interface {
+	Shapes(centerIDs []string) (map[string]*geo.Geometry, error)
+}
+
+type GroupForecasts interface {
+	Forecast(ctx context.Context, slug string, date time.Time) (*services.GroupForecast, error)
+}
+
 type ForecastHandler struct {
 	service ForecastService
 	repo    CenterRepository
+	shapes  ZoneShapeSource
+	groups  GroupForecasts
 }
 
 func NewForecastHandlerWithRepo(s ForecastService, r CenterRepository) *ForecastHandler {
 	return &ForecastHandler{service: s, repo: r}
 }
 
+// SetZoneShapes enables map output using zone polygons from shapes: GeoJSON
+// for clients asking for application/geo+json, and the KML/KMZ exports.
+func (h *ForecastHandler) SetZoneShapes(shapes ZoneShapeSource) {
+	h.shapes = shapes
+}
+
+// SetZoneGroups enables the group parameter, which limits output to the
+// zones of a zone group.
+func (h *ForecastHandler) SetZoneGroups(groups GroupForecasts) {
+	h.groups = groups
+}
+
+// GET /api/forecast?date=&centers=
+// GET /api/forecast?date=&group=
+// Responds with GeoJSON for format=geojson or Accept: application/geo+json.
+// Group forecasts are returned with the group's worst-case rating.
 func (h *ForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
-	dateStr := r.URL.Query().Get("date")
-	centersStr := r.URL.Query().Get("centers")
+	_, centerIDs, results, group, ok := h.loadForecasts(w, r)
+	if !ok {
+		return
+	}
 
-	var targetDate time.Time
-	var err error
-	if dateStr == "" {
-		targetDate = time.Now().UTC()
-	} else {
-		targetDate, err = time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			http.Error(w, "invalid date format (use YYYY-MM-DD)", http.StatusBadRequest)
+	w.Header().Add("Vary", "Accept")
+	if wantsGeoJSON(r) {
+		shapes, ok := h.zoneShapes(w, centerIDs)
+		if !ok {
 			return
 		}
+		w.Header().Set("Content-Type", geo.ContentType)
+		_ = json.NewEncoder(w).Encode(geo.ForecastFeatures(results, shapes))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if group != nil {
+		_ = json.NewEncoder(w).Encode(group)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(results)
+}
+
+// GET /api/forecast.kml?date=&centers=
+// GET /api/forecast.kmz?date=&centers=
+// Both also accept group= in place of centers.
+func (h *ForecastHandler) GetForecastKML(w http.ResponseWriter, r *http.Request) {
+	zipped := strings.HasSuffix(r.URL.Path, ".kmz")
+	targetDate, centerIDs, results, _, ok := h.loadForecasts(w, r)
+	if !ok {
+		return
+	}
+	shapes, ok := h.zoneShapes(w, centerIDs)
+	if !ok {
+		return
+	}
+
+	day := targetDate.Format("2006-01-02")
+	name := "Avalanche danger " + day
+	filename := "avalanche-danger-" + day
+	write := geo.WriteKML
+	if zipped {
+		w.Header().Set("Content-Type", geo.KMZContentType)
+		filename += ".kmz"
+		write = geo.WriteKMZ
+	} else {
+		w.Header().Set("Content-Type", geo.KMLContentType)
+		filename += ".kml"
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err := write(w, name, results, shapes); err != nil {
+		log.Printf("[ForecastHandler] failed to write %s: %v", filename, err)
+	}
+}
+
+// GET /api/centers/{id}/map.svg?date=
+func (h *ForecastHandler) GetCenterMap(w http.ResponseWriter, r *http.Request) {
+	centerID := strings.ToUpper(r.PathValue("id"))
+	targetDate, ok := forecastDate(w, r)
+	if !ok {
+		return
+	}
+	results, err := h.service.GetForecastsForCenters([]string{centerID}, targetDate)
+	if err != nil {
+		http.Error(w, "error fetching forecasts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(results) == 0 {
+		http.Error(w, "no forecasts for "+centerID, http.StatusNotFound)
+		return
+	}
+	shapes, ok := h.zoneShapes(w, []string{centerID})
+	if !ok {
+		return
+	}
+
+	title := cmp.Or(results[0].Center, centerID) + " avalanche danger " + targetDate.Format("2006-01-02")
+	svg, err := geo.SVGMap(title, results, shapes)
+	switch {
+	case errors.Is(err, geo.ErrNoZoneShapes):
+		http.Error(w, "no zone map for "+centerID, http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "error drawing map: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeSVG(w, r, svg)
+}
+
+// loadForecasts parses the date and centers or group parameters and fetches
+// the zone forecasts, with the group forecast for group requests. It writes
+// the error response and returns false on failure.
+func (h *ForecastHandler) loadForecasts(w http.ResponseWriter, r *http.Request) (time.Time, []string, []models.ZoneForecast, *services.GroupForecast, bool) {
+	centersStr := r.URL.Query().Get("centers")
+
+	targetDate, ok := forecastDate(w, r)
+	if !ok {
+		return time.Time{}, nil, nil, nil, false
+	}
+
+	if slug := r.URL.Query().Get("group"); slug != "" {
+		if h.groups == nil {
+			http.Error(w, "zone groups are not available", http.StatusNotFound)
+			return time.Time{}, nil, nil, nil, false
+		}
+		group, err := h.groups.Forecast(r.Context(), slug, targetDate)
+		switch {
+		case errors.Is(err, services.ErrZoneGroupNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return time.Time{}, nil, nil, nil, false
+		case err != nil:
+			http.Error(w, "error fetching forecasts: "+err.Error(), http.StatusInternalServerError)
+			return time.Time{}, nil, nil, nil, false
+		}
+		return targetDate, group.Centers(), group.Zones, group, true
 	}
 
 	var centerIDs []string
@@ -54,7 +190,7 @@ func (h *ForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
 		centers, err := h.repo.GetActiveCenters()
 		if err != nil {
 			http.Error(w, "failed to load centers: "+err.Error(), http.StatusInternalServerError)
-			return
+			return time.Time{}, nil, nil, nil, false
 		}
 		for _, c := range centers {
 			centerIDs = append(centerIDs, c.ID)
@@ -64,9 +200,52 @@ func (h *ForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
 	results, err := h.service.GetForecastsForCenters(centerIDs, targetDate)
 	if err != nil {
 		http.Error(w, "error fetching forecasts: "+err.Error(), http.StatusInternalServerError)
-		return
+		return time.Time{}, nil, nil, nil, false
 	}
+	return targetDate, centerIDs, results, nil, true
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(results)
+// forecastDate parses the optional date parameter, defaulting to today. It
+// writes the error response and returns false when the date is invalid.
+func forecastDate(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	dateStr := r.URL.Query().Get("date")
+	if dateStr == "" {
+		return time.Now().UTC(), true
+	}
+	targetDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		http.Error(w, "invalid date format (use YYYY-MM-DD)", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return targetDate, true
+}
+
+// zoneShapes loads the zone polygons for centerIDs. It writes the error
+// response and returns false when map output is unavailable.
+func (h *ForecastHandler) zoneShapes(w http.ResponseWriter, centerIDs []string) (map[string]*geo.Geometry, bool) {
+	if h.shapes == nil {
+		http.Error(w, "map output is not available", http.StatusNotAcceptable)
+		return nil, false
+	}
+	shapes, err := h.shapes.Shapes(centerIDs)
+	if err != nil {
+		http.Error(w, "error fetching zone shapes: "+err.Error(), http.StatusBadGateway)
+		return nil, false
+	}
+	return shapes, true
+}
+
+// wantsGeoJSON reports whether the request asks for GeoJSON, either with the
+// format query parameter or the Accept header.
+func wantsGeoJSON(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.EqualFold(format, "geojson")
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), geo.ContentType) {
+			return true
+		}
+	}
+	return false
 }
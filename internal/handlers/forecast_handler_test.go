@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"example.com/avalanche/internal/geo"
 	"example.com/avalanche/internal/handlers"
 	"example.com/avalanche/internal/models"
 )
@@ -96,3 +97,151 @@ func TestForecastHandler_BadDate(t *testing.T) {
 		t.Errorf("expected invalid date message, got %s", body)
 	}
 }
+
+type stubShapes struct{}
+
+func (stubShapes) Shapes(centerIDs []string) (map[string]*geo.Geometry, error) {
+	return map[string]*geo.Geometry{
+		"IPAC_1": {Type: "Polygon", Coordinates: json.RawMessage(`[[[-116.5,48.2],[-116.1,48.2],[-116.1,48.5],[-116.5,48.2]]]`)},
+	}, nil
+}
+
+func TestForecastHandler_GeoJSON(t *testing.T) {
+	ms := &mockService{
+		forecasts: []models.ZoneForecast{{
+			ZoneID:      "IPAC_1",
+			ZoneName:    "East Cabinet Mountains",
+			Center:      "IPAC",
+			TodayDanger: &models.DangerRating{Upper: 2, Middle: 2, Lower: 1},
+		}},
+	}
+	h := handlers.NewForecastHandlerWithRepo(ms, &mockRepo{})
+	h.SetZoneShapes(stubShapes{})
+
+	for name, req := range map[string]*http.Request{
+		"accept": func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/api/forecast?centers=IPAC", nil)
+			r.Header.Set("Accept", "application/geo+json;q=1.0, application/json;q=0.5")
+			return r
+		}(),
+		"format": httptest.NewRequest(http.MethodGet, "/api/forecast?centers=IPAC&format=geojson", nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.GetForecast(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/geo+json" {
+				t.Errorf("unexpected content type %q", ct)
+			}
+			var fc geo.FeatureCollection
+			if err := json.NewDecoder(rec.Body).Decode(&fc); err != nil {
+				t.Fatal(err)
+			}
+			if fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+				t.Fatalf("unexpected collection: %+v", fc)
+			}
+			f := fc.Features[0]
+			if f.Geometry == nil || f.Geometry.Type != "Polygon" {
+				t.Errorf("expected zone polygon, got %+v", f.Geometry)
+			}
+			if f.Properties["color"] != "#FFF200" || f.Properties["danger_name"] != "Moderate" {
+				t.Errorf("unexpected properties: %v", f.Properties)
+			}
+		})
+	}
+}
+
+func TestForecastHandler_JSONStillDefault(t *testing.T) {
+	ms := &mockService{forecasts: []models.ZoneForecast{{ZoneID: "IPAC_1"}}}
+	h := handlers.NewForecastHandlerWithRepo(ms, &mockRepo{})
+	h.SetZoneShapes(stubShapes{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/forecast?centers=IPAC", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	h.GetForecast(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	var out []models.ZoneForecast
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil || len(out) != 1 {
+		t.Fatalf("expected JSON array, got %v", err)
+	}
+}
+
+func TestForecastHandler_KML(t *testing.T) {
+	ms := &mockService{
+		forecasts: []models.ZoneForecast{{
+			ZoneID:      "IPAC_1",
+			ZoneName:    "East Cabinet Mountains",
+			TodayDanger: &models.DangerRating{Upper: 2, Middle: 2, Lower: 1},
+		}},
+	}
+	h := handlers.NewForecastHandlerWithRepo(ms, &mockRepo{})
+	h.SetZoneShapes(stubShapes{})
+
+	tests := []struct {
+		path        string
+		contentType string
+		filename    string
+	}{
+		{"/api/forecast.kml?centers=IPAC&date=2025-01-15", "application/vnd.google-earth.kml+xml", "avalanche-danger-2025-01-15.kml"},
+		{"/api/forecast.kmz?centers=IPAC&date=2025-01-15", "application/vnd.google-earth.kmz", "avalanche-danger-2025-01-15.kmz"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.GetForecastKML(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", tt.path, rec.Code, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%s: unexpected content type %q", tt.path, ct)
+		}
+		if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, tt.filename) {
+			t.Errorf("%s: unexpected disposition %q", tt.path, cd)
+		}
+	}
+}
+
+func TestForecastHandler_CenterMap(t *testing.T) {
+	ms := &mockService{
+		forecasts: []models.ZoneForecast{{
+			ZoneID:      "IPAC_1",
+			ZoneName:    "East Cabinet Mountains",
+			Center:      "Idaho Panhandle Avalanche Center",
+			TodayDanger: &models.DangerRating{Upper: 3, Middle: 2, Lower: 1},
+		}},
+	}
+	h := handlers.NewForecastHandlerWithRepo(ms, &mockRepo{})
+	h.SetZoneShapes(stubShapes{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/centers/{id}/map.svg", h.GetCenterMap)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/centers/ipac/map.svg?date=2025-01-15", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("expected an ETag")
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Idaho Panhandle Avalanche Center avalanche danger 2025-01-15") || !strings.Contains(body, "#F7941E") {
+		t.Errorf("unexpected map: %s", body)
+	}
+
+	ms.forecasts[0].ZoneID = "IPAC_9"
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/centers/IPAC/map.svg", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without zone shapes, got %d", rec.Code)
+	}
+}
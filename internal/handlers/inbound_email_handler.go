@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+)
+
+type ReplyCommandService interface {
+	ApplyReplyCommand(ctx context.Context, subID uint, cmd services.ReplyCommand) (*models.Subscription, error)
+}
+
+// InboundEmailHandler receives replies to forecast emails from SendGrid Inbound
+// Parse and applies the command they contain to the subscription named by the
+// signed reply-to address.
+type InboundEmailHandler struct {
+	service ReplyCommandService
+	replies *notifier.ReplyAddresser
+	token   string
+}
+
+// NewInboundEmailHandler creates a handler. replies may be nil, which disables
+// the endpoint. When token is set it must be passed as the "token" query
+// parameter of the Inbound Parse URL.
+func NewInboundEmailHandler(service ReplyCommandService, replies *notifier.ReplyAddresser, token string) *InboundEmailHandler {
+	return &InboundEmailHandler{service: service, replies: replies, token: token}
+}
+
+// POST /api/webhooks/email/inbound
+// Inbound Parse retries non-2xx responses, so replies that cannot be acted on
+// are acknowledged and logged rather than rejected.
+func (h *InboundEmailHandler) HandleInbound(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.replies == nil {
+		http.Error(w, "reply handling is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if h.token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(h.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxInboundEmailBody)
+	if err := r.ParseMultipartForm(maxInboundEmailBody); err != nil {
+		http.Error(w, "invalid inbound email payload", http.StatusBadRequest)
+		return
+	}
+
+	if isAutoReply(r.FormValue("headers")) {
+		log.Printf("[InboundEmailHandler] ignoring auto-reply from %s", r.FormValue("from"))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	subID, ok := h.subscriptionID(r)
+	if !ok {
+		log.Printf("[InboundEmailHandler] no valid reply address in message from %s", r.FormValue("from"))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	cmd, err := services.ParseReplyCommand(r.FormValue("text"), r.FormValue("subject"))
+	if err != nil {
+		log.Printf("[InboundEmailHandler] no command in reply for subscription %d: %v", subID, err)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	sub, err := h.service.ApplyReplyCommand(r.Context(), subID, cmd)
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound):
+		log.Printf("[InboundEmailHandler] reply for missing subscription %d", subID)
+		w.WriteHeader(http.StatusOK)
+		return
+	case err != nil:
+		log.Printf("[InboundEmailHandler] failed to apply %s to subscription %d: %v", cmd.Action, subID, err)
+		http.Error(w, "failed to apply command", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"command": cmd.Action, "subscription": sub})
+}
+
+// maxInboundEmailBody caps Inbound Parse payloads, which include attachments.
+const maxInboundEmailBody = 10 << 20
+
+// subscriptionID finds the signed reply address among the envelope recipients,
+// falling back to the To and Cc headers.
+func (h *InboundEmailHandler) subscriptionID(r *http.Request) (uint, bool) {
+	var recipients []string
+	var envelope struct {
+		To []string `json:"to"`
+	}
+	if err := json.Unmarshal([]byte(r.FormValue("envelope")), &envelope); err == nil {
+		recipients = append(recipients, envelope.To...)
+	}
+	for _, field := range []string{"to", "cc"} {
+		if list, err := mail.ParseAddressList(r.FormValue(field)); err == nil {
+			for _, a := range list {
+				recipients = append(recipients, a.Address)
+			}
+		}
+	}
+	for _, addr := range recipients {
+		if id, err := h.replies.SubscriptionID(addr); err == nil {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// isAutoReply detects vacation responders and bounces so they never trigger
+// commands or confirmation loops.
+func isAutoReply(headers string) bool {
+	for _, line := range strings.Split(headers, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "auto-submitted":
+			if value != "no" {
+				return true
+			}
+		case "x-autoreply", "x-autorespond":
+			return true
+		case "precedence":
+			if value == "bulk" || value == "junk" || value == "auto_reply" {
+				return true
+			}
+		}
+	}
+	return false
+}
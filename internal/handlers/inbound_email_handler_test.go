@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+)
+
+type fakeReplyService struct {
+	subID uint
+	cmd   services.ReplyCommand
+	calls int
+}
+
+func (f *fakeReplyService) ApplyReplyCommand(ctx context.Context, subID uint, cmd services.ReplyCommand) (*models.Subscription, error) {
+	f.subID, f.cmd = subID, cmd
+	f.calls++
+	return &models.Subscription{ID: subID}, nil
+}
+
+// inboundParseRequest builds a multipart body shaped like a SendGrid Inbound
+// Parse post.
+func inboundParseRequest(t *testing.T, url string, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, url, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestInboundEmailHandler_AppliesReplyCommand(t *testing.T) {
+	replies := notifier.NewReplyAddresser("secret", "reply.example.com")
+	svc := &fakeReplyService{}
+	h := handlers.NewInboundEmailHandler(svc, replies, "tok")
+	addr := replies.Address(42)
+
+	fields := map[string]string{
+		"headers":  "Received: by mx.sendgrid.net\nFrom: Skier <skier@example.com>\nSubject: Re: Snoqualmie Pass forecast\n",
+		"to":       "Avy Notifier <" + addr + ">",
+		"from":     "Skier <skier@example.com>",
+		"subject":  "Re: Snoqualmie Pass forecast",
+		"text":     "PAUSE 5\r\n\r\nOn Mon, Dec 1, 2025 at 7:00 AM Avy Notifier <" + addr + "> wrote:\r\n> Today: Considerable\r\n",
+		"envelope": `{"to":["` + addr + `"],"from":"skier@example.com"}`,
+		"charsets": `{"to":"UTF-8","subject":"UTF-8","from":"UTF-8","text":"UTF-8"}`,
+		"SPF":      "pass",
+	}
+
+	rec := httptest.NewRecorder()
+	h.HandleInbound(rec, inboundParseRequest(t, "/api/webhooks/email/inbound", fields))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.HandleInbound(rec, inboundParseRequest(t, "/api/webhooks/email/inbound?token=tok", fields))
+	if rec.Code != http.StatusOK || svc.subID != 42 || svc.cmd.Action != services.ReplyPause || svc.cmd.Days != 5 {
+		t.Fatalf("unexpected result: %d sub=%d cmd=%+v", rec.Code, svc.subID, svc.cmd)
+	}
+
+	// A forged address and an out-of-office reply are acknowledged but ignored.
+	fields["to"] = "reply+s7-0000000000000000000000000000000000000000@reply.example.com"
+	fields["envelope"] = `{"to":["` + fields["to"] + `"]}`
+	rec = httptest.NewRecorder()
+	h.HandleInbound(rec, inboundParseRequest(t, "/api/webhooks/email/inbound?token=tok", fields))
+	if rec.Code != http.StatusOK || svc.calls != 1 {
+		t.Fatalf("expected forged address ignored, got %d after %d calls", rec.Code, svc.calls)
+	}
+
+	fields["to"], fields["envelope"] = addr, `{"to":["`+addr+`"]}`
+	fields["headers"] += "Auto-Submitted: auto-replied\n"
+	rec = httptest.NewRecorder()
+	h.HandleInbound(rec, inboundParseRequest(t, "/api/webhooks/email/inbound?token=tok", fields))
+	if rec.Code != http.StatusOK || svc.calls != 1 {
+		t.Fatalf("expected auto-reply ignored, got %d after %d calls", rec.Code, svc.calls)
+	}
+}
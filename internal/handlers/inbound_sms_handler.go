@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/services"
+)
+
+type SMSQueryService interface {
+	HandleInbound(ctx context.Context, from *domain.PhoneNumber, text string) (string, error)
+}
+
+// InboundSMSHandler receives messages forwarded by the SMS gateway and answers
+// forecast queries.
+type InboundSMSHandler struct {
+	service SMSQueryService
+	token   string
+}
+
+// NewInboundSMSHandler creates a handler. The gateway must present token as a
+// bearer token or a "token" query parameter; without a token every message is
+// refused.
+func NewInboundSMSHandler(service SMSQueryService, token string) *InboundSMSHandler {
+	return &InboundSMSHandler{service: service, token: token}
+}
+
+// POST /api/webhooks/sms/inbound
+// Accepts JSON {"from","text"} or a form with from/text (or Twilio-style From/Body).
+func (h *InboundSMSHandler) HandleInbound(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.token == "" {
+		http.Error(w, "inbound sms is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	fromStr, text, err := parseInboundSMS(r)
+	if err != nil {
+		http.Error(w, "invalid message payload", http.StatusBadRequest)
+		return
+	}
+	from, err := domain.NewPhoneNumber(fromStr)
+	if err != nil {
+		http.Error(w, "invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reply, err := h.service.HandleInbound(r.Context(), from, text)
+	switch {
+	case errors.Is(err, services.ErrSenderNotAllowed), errors.Is(err, services.ErrSenderRateLimited):
+		// Accept without replying so the gateway does not retry and we are not billed.
+		log.Printf("[InboundSMSHandler] ignoring message from %s: %v", from, err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case err != nil:
+		log.Printf("[InboundSMSHandler] failed to answer %s: %v", from, err)
+		http.Error(w, "failed to answer message", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"reply": reply})
+}
+
+func (h *InboundSMSHandler) authorized(r *http.Request) bool {
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		provided = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(h.token)) == 1
+}
+
+func parseInboundSMS(r *http.Request) (from, text string, err error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		return "", "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		var msg struct {
+			From string `json:"from"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return "", "", err
+		}
+		return msg.From, msg.Text, nil
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", "", err
+	}
+	from = firstNonEmpty(form.Get("from"), form.Get("From"))
+	text = firstNonEmpty(form.Get("text"), form.Get("Body"))
+	return from, text, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
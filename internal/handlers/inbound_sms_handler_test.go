@@ -0,0 +1,68 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/services"
+)
+
+type fakeSMSQueryService struct {
+	from, text string
+	err        error
+}
+
+func (f *fakeSMSQueryService) HandleInbound(ctx context.Context, from *domain.PhoneNumber, text string) (string, error) {
+	f.from, f.text = from.String(), text
+	return "reply", f.err
+}
+
+func TestInboundSMSHandler_ParsesFormAndChecksToken(t *testing.T) {
+	svc := &fakeSMSQueryService{}
+	h := handlers.NewInboundSMSHandler(svc, "tok")
+
+	body := "From=%2B12065550100&Body=NWAC_10+TMRW"
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/sms/inbound", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.HandleInbound(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/webhooks/sms/inbound?token=tok", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.HandleInbound(rec, req)
+	if rec.Code != http.StatusOK || svc.from != "+12065550100" || svc.text != "NWAC_10 TMRW" {
+		t.Fatalf("unexpected result: %d from=%q text=%q", rec.Code, svc.from, svc.text)
+	}
+
+	svc.err = services.ErrSenderRateLimited
+	req = httptest.NewRequest(http.MethodPost, "/api/webhooks/sms/inbound", strings.NewReader(`{"from":"+12065550100","text":"HELP"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer tok")
+	rec = httptest.NewRecorder()
+	h.HandleInbound(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected rate-limited message to be dropped, got %d", rec.Code)
+	}
+}
+
+func TestInboundSMSHandler_RefusesWithoutToken(t *testing.T) {
+	svc := &fakeSMSQueryService{}
+	h := handlers.NewInboundSMSHandler(svc, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/sms/inbound", strings.NewReader(`{"from":"+12065550100","text":"STOP"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.HandleInbound(rec, req)
+	if rec.Code != http.StatusServiceUnavailable || svc.from != "" {
+		t.Fatalf("expected 503 without a configured token, got %d from=%q", rec.Code, svc.from)
+	}
+}
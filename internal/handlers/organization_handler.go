@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type Organizations interface {
+	Create(ctx context.Context, actor string, req services.CreateOrganizationRequest) (*services.OrganizationDetail, error)
+	List(ctx context.Context) ([]models.Organization, error)
+	Get(ctx context.Context, id uint) (*services.OrganizationDetail, error)
+	SetZones(ctx context.Context, actor string, id uint, zoneIDs []*domain.ZoneID) (*services.OrganizationDetail, error)
+	AddMembers(ctx context.Context, actor string, id uint, reqs []services.MemberRequest) (*services.MemberChanges, error)
+	RemoveMembers(ctx context.Context, actor string, id uint, emails []*domain.Email) (*services.MemberChanges, error)
+	Delete(ctx context.Context, actor string, id uint) error
+	Audit(ctx context.Context, id uint) ([]models.OrganizationAuditEntry, error)
+}
+
+// auditActorHeader names the person behind an admin request in the audit
+// log; the shared admin token does not identify them.
+const auditActorHeader = "X-Audit-Actor"
+
+// OrganizationHandler exposes organization and bulk membership management to
+// administrators.
+type OrganizationHandler struct {
+	orgs Organizations
+}
+
+func NewOrganizationHandler(orgs Organizations) *OrganizationHandler {
+	return &OrganizationHandler{orgs: orgs}
+}
+
+// POST /api/admin/organizations
+// {"name": "...", "zone_ids": ["NWAC_10"], "owner_email": "owner@example.com"}
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name       string   `json:"name"`
+		ZoneIDs    []string `json:"zone_ids"`
+		OwnerEmail string   `json:"owner_email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	zones, ok := parseZoneIDs(w, req.ZoneIDs)
+	if !ok {
+		return
+	}
+	owner, err := domain.NewEmail(req.OwnerEmail)
+	if err != nil {
+		http.Error(w, "invalid owner_email: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	org, err := h.orgs.Create(r.Context(), auditActor(r), services.CreateOrganizationRequest{Name: req.Name, ZoneIDs: zones, Owner: owner})
+	if !organizationError(w, err, "create organization") {
+		return
+	}
+	writeJSON(w, http.StatusCreated, org)
+}
+
+// GET /api/admin/organizations
+func (h *OrganizationHandler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	out, err := h.orgs.List(r.Context())
+	if !organizationError(w, err, "list organizations") {
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// GET /api/admin/organizations/{id}
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	org, err := h.orgs.Get(r.Context(), id)
+	if !organizationError(w, err, "load organization") {
+		return
+	}
+	writeJSON(w, http.StatusOK, org)
+}
+
+// DELETE /api/admin/organizations/{id}
+func (h *OrganizationHandler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	if !organizationError(w, h.orgs.Delete(r.Context(), auditActor(r), id), "delete organization") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PUT /api/admin/organizations/{id}/zones
+// {"zone_ids": ["NWAC_10", "NWAC_11"]}
+func (h *OrganizationHandler) SetZones(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		ZoneIDs []string `json:"zone_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	zones, ok := parseZoneIDs(w, req.ZoneIDs)
+	if !ok {
+		return
+	}
+	org, err := h.orgs.SetZones(r.Context(), auditActor(r), id, zones)
+	if !organizationError(w, err, "update organization zones") {
+		return
+	}
+	writeJSON(w, http.StatusOK, org)
+}
+
+// POST /api/admin/organizations/{id}/members
+// {"members": [{"email": "guide@example.com", "role": "member"}]}, or a CSV
+// body of email,role rows with Content-Type text/csv.
+func (h *OrganizationHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	reqs, err := parseMembers(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	changes, err := h.orgs.AddMembers(r.Context(), auditActor(r), id, reqs)
+	if !organizationError(w, err, "add members") {
+		return
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// DELETE /api/admin/organizations/{id}/members
+// {"emails": ["guide@example.com"]}
+func (h *OrganizationHandler) RemoveMembers(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Emails []string `json:"emails"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	emails := make([]*domain.Email, 0, len(req.Emails))
+	for _, raw := range req.Emails {
+		email, err := domain.NewEmail(raw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid email %q: %v", raw, err), http.StatusBadRequest)
+			return
+		}
+		emails = append(emails, email)
+	}
+	changes, err := h.orgs.RemoveMembers(r.Context(), auditActor(r), id, emails)
+	if !organizationError(w, err, "remove members") {
+		return
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// GET /api/admin/organizations/{id}/audit
+func (h *OrganizationHandler) Audit(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.orgs.Audit(r.Context(), id)
+	if !organizationError(w, err, "load audit log") {
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// parseMembers reads a bulk member list from JSON or CSV. A CSV header row
+// starting with "email" is skipped.
+func parseMembers(r *http.Request) ([]services.MemberRequest, error) {
+	type row struct{ email, role string }
+	var rows []row
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		cr := csv.NewReader(r.Body)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		for line := 1; ; line++ {
+			rec, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV: %w", err)
+			}
+			if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), "email") {
+				continue
+			}
+			rw := row{email: rec[0]}
+			if len(rec) > 1 {
+				rw.role = rec[1]
+			}
+			rows = append(rows, rw)
+		}
+	} else {
+		var req struct {
+			Members []struct {
+				Email string `json:"email"`
+				Role  string `json:"role"`
+			} `json:"members"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, errors.New("invalid request body")
+		}
+		for _, m := range req.Members {
+			rows = append(rows, row{m.Email, m.Role})
+		}
+	}
+
+	out := make([]services.MemberRequest, 0, len(rows))
+	for _, rw := range rows {
+		email, err := domain.NewEmail(rw.email)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email %q: %v", rw.email, err)
+		}
+		out = append(out, services.MemberRequest{Email: email, Role: strings.ToLower(strings.TrimSpace(rw.role))})
+	}
+	return out, nil
+}
+
+func parseZoneIDs(w http.ResponseWriter, raw []string) ([]*domain.ZoneID, bool) {
+	out := make([]*domain.ZoneID, 0, len(raw))
+	for _, s := range raw {
+		zoneID, err := domain.ParseZoneID(s)
+		if err != nil {
+			http.Error(w, "invalid zone_ids: "+err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		out = append(out, zoneID)
+	}
+	return out, true
+}
+
+func auditActor(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get(auditActorHeader)); actor != "" {
+		return actor
+	}
+	return "admin"
+}
+
+func organizationID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid organization id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// organizationError writes the response for a failed organization operation
+// and reports whether err was nil.
+func organizationError(w http.ResponseWriter, err error, action string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidOrganization):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrLastOwner):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("[OrganizationHandler] failed to %s: %v", action, err)
+		http.Error(w, "failed to "+action, http.StatusInternalServerError)
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,103 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type stubOrganizations struct {
+	actor   string
+	members []services.MemberRequest
+	removed []string
+}
+
+func (s *stubOrganizations) Create(ctx context.Context, actor string, req services.CreateOrganizationRequest) (*services.OrganizationDetail, error) {
+	s.actor = actor
+	return &services.OrganizationDetail{Organization: &models.Organization{ID: 1, Name: req.Name}}, nil
+}
+
+func (s *stubOrganizations) List(ctx context.Context) ([]models.Organization, error) { return nil, nil }
+
+func (s *stubOrganizations) Get(ctx context.Context, id uint) (*services.OrganizationDetail, error) {
+	return nil, services.ErrOrganizationNotFound
+}
+
+func (s *stubOrganizations) SetZones(ctx context.Context, actor string, id uint, zoneIDs []*domain.ZoneID) (*services.OrganizationDetail, error) {
+	return nil, nil
+}
+
+func (s *stubOrganizations) AddMembers(ctx context.Context, actor string, id uint, reqs []services.MemberRequest) (*services.MemberChanges, error) {
+	s.actor, s.members = actor, reqs
+	return &services.MemberChanges{}, nil
+}
+
+func (s *stubOrganizations) RemoveMembers(ctx context.Context, actor string, id uint, emails []*domain.Email) (*services.MemberChanges, error) {
+	for _, e := range emails {
+		s.removed = append(s.removed, e.String())
+	}
+	return nil, services.ErrLastOwner
+}
+
+func (s *stubOrganizations) Delete(ctx context.Context, actor string, id uint) error { return nil }
+
+func (s *stubOrganizations) Audit(ctx context.Context, id uint) ([]models.OrganizationAuditEntry, error) {
+	return nil, nil
+}
+
+func TestOrganizationHandler_BulkMembers(t *testing.T) {
+	orgs := &stubOrganizations{}
+	h := handlers.NewOrganizationHandler(orgs)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/admin/organizations", h.CreateOrganization)
+	mux.HandleFunc("GET /api/admin/organizations/{id}", h.GetOrganization)
+	mux.HandleFunc("POST /api/admin/organizations/{id}/members", h.AddMembers)
+	mux.HandleFunc("DELETE /api/admin/organizations/{id}/members", h.RemoveMembers)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/organizations/1/members",
+		strings.NewReader("email,role\nGuide@Example.com, member\nlead@example.com,Admin\nnew@example.com\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("X-Audit-Actor", "dana")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if orgs.actor != "dana" || len(orgs.members) != 3 {
+		t.Fatalf("actor %q, members %+v", orgs.actor, orgs.members)
+	}
+	if m := orgs.members[1]; m.Email.String() != "lead@example.com" || m.Role != models.RoleAdmin {
+		t.Errorf("second member = %s %q", m.Email, m.Role)
+	}
+	if orgs.members[2].Role != "" {
+		t.Errorf("member without a role column got %q", orgs.members[2].Role)
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/admin/organizations", `{"name":"Patrol","zone_ids":["NWAC_10"],"owner_email":"chief@example.com"}`, http.StatusCreated},
+		{http.MethodPost, "/api/admin/organizations", `{"name":"Patrol","zone_ids":["NWAC_10"],"owner_email":"chief"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/admin/organizations/1/members", `{"members":[{"email":"not an email"}]}`, http.StatusBadRequest},
+		{http.MethodDelete, "/api/admin/organizations/1/members", `{"emails":["chief@example.com"]}`, http.StatusConflict},
+		{http.MethodGet, "/api/admin/organizations/9", "", http.StatusNotFound},
+		{http.MethodGet, "/api/admin/organizations/x", "", http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		if rec.Code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d: %s", tc.method, tc.path, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+	if orgs.actor != "admin" {
+		t.Errorf("actor without header = %q, want admin", orgs.actor)
+	}
+}
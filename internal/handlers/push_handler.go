@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+// PushHandler registers browser push subscriptions for the web frontend.
+type PushHandler struct {
+	service   *services.SubscriptionService
+	publicKey string
+	zones     ZoneResolver
+}
+
+// NewPushHandler creates a handler advertising the VAPID publicKey. An empty
+// key disables Web Push.
+func NewPushHandler(service *services.SubscriptionService, publicKey string) *PushHandler {
+	return &PushHandler{service: service, publicKey: publicKey}
+}
+
+// SetZoneResolver lets zone_id name zones by name, slug or alias.
+func (h *PushHandler) SetZoneResolver(zones ZoneResolver) {
+	h.zones = zones
+}
+
+// pushSubscriptionRequest wraps the browser's PushSubscription.toJSON().
+type pushSubscriptionRequest struct {
+	ZoneID       string `json:"zone_id"`
+	Subscription struct {
+		Endpoint string `json:"endpoint"`
+		Keys     struct {
+			P256DH string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+	} `json:"subscription"`
+}
+
+// GET /api/push/vapid-public-key
+func (h *PushHandler) PublicKey(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"public_key": h.publicKey})
+}
+
+// POST /api/push/subscriptions
+func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	var req pushSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	zoneID, ok := resolveZone(w, r, h.zones, req.ZoneID, "zone_id")
+	if !ok {
+		return
+	}
+	push, err := domain.NewPushSubscription(req.Subscription.Endpoint, req.Subscription.Keys.P256DH, req.Subscription.Keys.Auth)
+	if err != nil {
+		http.Error(w, "invalid subscription: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.service.Create(r.Context(), services.CreateSubscriptionRequest{
+		ZoneID:  zoneID,
+		Channel: models.ChannelWebPush,
+		Push:    push,
+	})
+	if err != nil {
+		log.Printf("[PushHandler] failed to create push subscription: %v", err)
+		http.Error(w, "failed to create subscription", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// DELETE /api/push/subscriptions?endpoint=URL[&zone_id=ZONE_ID]
+// Without zone_id, every zone for the endpoint is removed.
+func (h *PushHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	endpoint, err := domain.NewWebhookURL(r.URL.Query().Get("endpoint"))
+	if err != nil {
+		http.Error(w, "invalid endpoint: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var zoneID *domain.ZoneID
+	if raw := r.URL.Query().Get("zone_id"); raw != "" {
+		if zoneID, err = domain.ParseZoneID(raw); err != nil {
+			http.Error(w, "invalid zone_id: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.service.DeletePush(r.Context(), endpoint, zoneID); err != nil {
+		log.Printf("[PushHandler] failed to delete push subscription: %v", err)
+		http.Error(w, "failed to delete subscription", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PushHandler) enabled(w http.ResponseWriter) bool {
+	if h.publicKey == "" {
+		http.Error(w, "web push is not configured", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type emptyForecastClient struct{}
+
+func (emptyForecastClient) FetchForecasts(centerID string) ([]models.Forecast, error) {
+	return nil, nil
+}
+
+func TestPushHandler_SubscribeIsIdempotent(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.AvalancheCenter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	subRepo := db.NewSubscriptionRepository(gdb)
+	svc := services.NewSubscriptionService(subRepo, db.NewCenterRepository(gdb), services.NewForecast(emptyForecastClient{}), notifier.LogEmailSender{})
+	h := handlers.NewPushHandler(svc, "BPublicKey")
+
+	endpoint := "https://push.example.com/send/abc"
+	p256dh := base64.RawURLEncoding.EncodeToString(append([]byte{0x04}, make([]byte, 64)...))
+	auth := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+	body := `{"zone_id":"NWAC_10","subscription":{"endpoint":"` + endpoint + `","expirationTime":null,` +
+		`"keys":{"p256dh":"` + p256dh + `","auth":"` + auth + `"}}}`
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		h.Subscribe(rec, httptest.NewRequest(http.MethodPost, "/api/push/subscriptions", strings.NewReader(body)))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("subscribe: %d %s", rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), p256dh) {
+			t.Fatalf("response leaks push keys: %s", rec.Body.String())
+		}
+	}
+	subs, _ := subRepo.GetByTarget(endpoint)
+	if len(subs) != 1 || subs[0].Channel != models.ChannelWebPush || subs[0].PushKeys == nil || subs[0].PushKeys.Auth != auth {
+		t.Fatalf("expected one push subscription with keys, got %+v", subs)
+	}
+
+	rec := httptest.NewRecorder()
+	h.Unsubscribe(rec, httptest.NewRequest(http.MethodDelete, "/api/push/subscriptions?endpoint="+url.QueryEscape(endpoint), nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("unsubscribe: %d", rec.Code)
+	}
+	if subs, _ = subRepo.GetByTarget(endpoint); len(subs) != 0 {
+		t.Fatalf("expected subscriptions removed, got %+v", subs)
+	}
+}
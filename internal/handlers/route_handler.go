@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+// maxRouteBody caps uploaded tracks; a day's GPX recording is well under this.
+const maxRouteBody = 10 << 20
+
+type RouteAssessor interface {
+	AssessRoute(points []geo.TrackPoint, date time.Time, centerIDs []string) (*services.RouteAssessment, error)
+	ElevationBands() ([]models.ZoneElevationBands, error)
+	SetElevationBands(zoneID *domain.ZoneID, nearTreelineM, aboveTreelineM float64) (*models.ZoneElevationBands, error)
+}
+
+// RouteHandler rates planned routes against the day's forecasts.
+type RouteHandler struct {
+	routes RouteAssessor
+}
+
+func NewRouteHandler(routes RouteAssessor) *RouteHandler {
+	return &RouteHandler{routes: routes}
+}
+
+// POST /api/route-assessment?date=&centers=
+// The body is a GPX document or a GeoJSON LineString.
+func (h *RouteHandler) AssessRoute(w http.ResponseWriter, r *http.Request) {
+	date, ok := forecastDate(w, r)
+	if !ok {
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRouteBody))
+	if err != nil {
+		http.Error(w, "route is too large or unreadable", http.StatusRequestEntityTooLarge)
+		return
+	}
+	points, err := parseRoute(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var centerIDs []string
+	for _, id := range strings.Split(r.URL.Query().Get("centers"), ",") {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+			centerIDs = append(centerIDs, id)
+		}
+	}
+	assessment, err := h.routes.AssessRoute(points, date, centerIDs)
+	if err != nil {
+		http.Error(w, "error assessing route: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(assessment)
+}
+
+// parseRoute decodes GPX or GeoJSON by content type, or by sniffing the body
+// when the type is generic.
+func parseRoute(contentType string, body []byte) ([]geo.TrackPoint, error) {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return geo.ParseLineString(body)
+	case strings.Contains(contentType, "xml"), strings.Contains(contentType, "gpx"),
+		bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")):
+		return geo.ParseGPX(bytes.NewReader(body))
+	default:
+		return geo.ParseLineString(body)
+	}
+}
+
+// GET /api/admin/elevation-bands
+func (h *RouteHandler) ListElevationBands(w http.ResponseWriter, r *http.Request) {
+	bands, err := h.routes.ElevationBands()
+	if err != nil {
+		http.Error(w, "failed to list elevation bands", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(bands)
+}
+
+// PUT /api/admin/elevation-bands/{zoneID}
+// {"near_treeline_m": 1200, "above_treeline_m": 1700}
+func (h *RouteHandler) SetElevationBands(w http.ResponseWriter, r *http.Request) {
+	zoneID, err := domain.ParseZoneID(r.PathValue("zoneID"))
+	if err != nil || !zoneID.IsSpecificZone() {
+		http.Error(w, "invalid zone ID", http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		NearTreelineM  float64 `json:"near_treeline_m"`
+		AboveTreelineM float64 `json:"above_treeline_m"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	bands, err := h.routes.SetElevationBands(zoneID, req.NearTreelineM, req.AboveTreelineM)
+	switch {
+	case errors.Is(err, services.ErrInvalidElevationBands):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, "failed to save elevation bands", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(bands)
+}
@@ -0,0 +1,94 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type stubRoutes struct {
+	points  []geo.TrackPoint
+	date    time.Time
+	centers []string
+}
+
+func (s *stubRoutes) AssessRoute(points []geo.TrackPoint, date time.Time, centerIDs []string) (*services.RouteAssessment, error) {
+	s.points, s.date, s.centers = points, date, centerIDs
+	return &services.RouteAssessment{Date: date.Format(time.DateOnly), Summary: services.RouteSummary{MaxDanger: 3}}, nil
+}
+
+func (s *stubRoutes) ElevationBands() ([]models.ZoneElevationBands, error) { return nil, nil }
+
+func (s *stubRoutes) SetElevationBands(zoneID *domain.ZoneID, near, above float64) (*models.ZoneElevationBands, error) {
+	if near >= above {
+		return nil, services.ErrInvalidElevationBands
+	}
+	return &models.ZoneElevationBands{ZoneID: zoneID.String(), NearTreelineM: near, AboveTreelineM: above}, nil
+}
+
+func TestRouteHandler_AssessRoute(t *testing.T) {
+	routes := &stubRoutes{}
+	h := handlers.NewRouteHandler(routes)
+
+	gpx := `<gpx><trk><trkseg><trkpt lat="47.4" lon="-121.4"><ele>1000</ele></trkpt></trkseg></trk></gpx>`
+	req := httptest.NewRequest(http.MethodPost, "/api/route-assessment?date=2025-02-01&centers=nwac", strings.NewReader(gpx))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	rec := httptest.NewRecorder()
+	h.AssessRoute(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(routes.points) != 1 || routes.points[0].Ele != 1000 || routes.date.Format(time.DateOnly) != "2025-02-01" ||
+		len(routes.centers) != 1 || routes.centers[0] != "NWAC" {
+		t.Errorf("unexpected assessment call %+v", routes)
+	}
+	if !strings.Contains(rec.Body.String(), `"max_danger":3`) {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+
+	geojson := `{"type":"LineString","coordinates":[[-121.4,47.4],[-121.3,47.5]]}`
+	req = httptest.NewRequest(http.MethodPost, "/api/route-assessment", strings.NewReader(geojson))
+	req.Header.Set("Content-Type", "application/geo+json")
+	rec = httptest.NewRecorder()
+	h.AssessRoute(rec, req)
+	if rec.Code != http.StatusOK || len(routes.points) != 2 {
+		t.Errorf("expected GeoJSON route to be accepted, got %d %+v", rec.Code, routes.points)
+	}
+
+	for _, body := range []string{`{"type":"Point","coordinates":[0,0]}`, `<gpx>`} {
+		rec = httptest.NewRecorder()
+		h.AssessRoute(rec, httptest.NewRequest(http.MethodPost, "/api/route-assessment", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestRouteHandler_SetElevationBands(t *testing.T) {
+	h := handlers.NewRouteHandler(&stubRoutes{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/admin/elevation-bands/{zoneID}", h.SetElevationBands)
+
+	for path, tt := range map[string]struct {
+		body string
+		want int
+	}{
+		"/api/admin/elevation-bands/NWAC_10": {`{"near_treeline_m":1200,"above_treeline_m":1700}`, http.StatusOK},
+		"/api/admin/elevation-bands/NWAC_11": {`{"near_treeline_m":1700,"above_treeline_m":1200}`, http.StatusBadRequest},
+		"/api/admin/elevation-bands/NWAC":    {`{"near_treeline_m":1200,"above_treeline_m":1700}`, http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, path, strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d: %s", path, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
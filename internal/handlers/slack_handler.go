@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"example.com/avalanche/internal/services"
+	"example.com/avalanche/internal/signing"
+)
+
+type SlackCommandService interface {
+	HandleCommand(ctx context.Context, cmd services.SlackCommand) (map[string]any, error)
+	HandleAction(ctx context.Context, action services.SlackAction) error
+}
+
+// maxSlackBody caps slash command and interactivity payloads.
+const maxSlackBody = 1 << 20
+
+// SlackHandler serves the Slack app's slash command and interactivity request
+// URLs. Every request must carry a valid Slack signature.
+type SlackHandler struct {
+	service       SlackCommandService
+	signingSecret string
+}
+
+// NewSlackHandler creates a handler verifying requests with the Slack app's
+// signing secret. An empty secret disables the endpoints.
+func NewSlackHandler(service SlackCommandService, signingSecret string) *SlackHandler {
+	return &SlackHandler{service: service, signingSecret: signingSecret}
+}
+
+// POST /api/slack/commands
+func (h *SlackHandler) HandleCommand(w http.ResponseWriter, r *http.Request) {
+	form, ok := h.verifiedForm(w, r)
+	if !ok {
+		return
+	}
+	reply, err := h.service.HandleCommand(r.Context(), services.SlackCommand{
+		Text:      form.Get("text"),
+		ChannelID: form.Get("channel_id"),
+		UserID:    form.Get("user_id"),
+	})
+	if err != nil {
+		// Slack shows non-200 responses as a generic failure, so errors are
+		// reported to the caller as an ephemeral message instead.
+		log.Printf("[SlackHandler] command %q failed: %v", form.Get("text"), err)
+		reply = map[string]any{"response_type": "ephemeral", "text": "Sorry, something went wrong looking that up."}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reply)
+}
+
+// slackInteraction is the subset of a block_actions payload the service uses.
+type slackInteraction struct {
+	Type    string              `json:"type"`
+	User    struct{ ID string } `json:"user"`
+	Channel struct{ ID string } `json:"channel"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// POST /api/slack/interactions
+func (h *SlackHandler) HandleInteraction(w http.ResponseWriter, r *http.Request) {
+	form, ok := h.verifiedForm(w, r)
+	if !ok {
+		return
+	}
+	var payload slackInteraction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if payload.Type != "block_actions" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	for _, a := range payload.Actions {
+		err := h.service.HandleAction(r.Context(), services.SlackAction{
+			ActionID:  a.ActionID,
+			Value:     a.Value,
+			ChannelID: payload.Channel.ID,
+			UserID:    payload.User.ID,
+		})
+		if err != nil && !errors.Is(err, services.ErrUnknownSlackAction) {
+			log.Printf("[SlackHandler] action %s failed: %v", a.ActionID, err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifiedForm reads the request body, checks its Slack signature and parses
+// it as a form. It writes the error response and returns false on failure.
+func (h *SlackHandler) verifiedForm(w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	if h.signingSecret == "" {
+		http.Error(w, "slack integration is not configured", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackBody))
+	if err != nil {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	err = signing.VerifySlack(h.signingSecret,
+		r.Header.Get(signing.SlackHeaderSignature), r.Header.Get(signing.SlackHeaderTimestamp),
+		body, signing.DefaultTolerance, time.Now())
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return nil, false
+	}
+	return form, true
+}
@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/services"
+	"example.com/avalanche/internal/signing"
+)
+
+type fakeSlackService struct {
+	command services.SlackCommand
+	actions []services.SlackAction
+}
+
+func (f *fakeSlackService) HandleCommand(ctx context.Context, cmd services.SlackCommand) (map[string]any, error) {
+	f.command = cmd
+	return map[string]any{"response_type": "in_channel", "text": "forecast"}, nil
+}
+
+func (f *fakeSlackService) HandleAction(ctx context.Context, action services.SlackAction) error {
+	f.actions = append(f.actions, action)
+	return nil
+}
+
+func signedSlackRequest(path, secret, body string) *http.Request {
+	now := time.Now()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(signing.SlackHeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(signing.SlackHeaderSignature, signing.SignSlack(secret, now, []byte(body)))
+	return req
+}
+
+func TestSlackHandler_VerifiesSignature(t *testing.T) {
+	svc := &fakeSlackService{}
+	h := handlers.NewSlackHandler(svc, "slack-secret")
+	body := "command=%2Favy&text=NWAC_10+tomorrow&channel_id=C1&user_id=U1"
+
+	rec := httptest.NewRecorder()
+	h.HandleCommand(rec, signedSlackRequest("/api/slack/commands", "wrong-secret", body))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.HandleCommand(rec, signedSlackRequest("/api/slack/commands", "slack-secret", body))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"in_channel"`) {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if svc.command.Text != "NWAC_10 tomorrow" || svc.command.ChannelID != "C1" || svc.command.UserID != "U1" {
+		t.Fatalf("unexpected command: %+v", svc.command)
+	}
+}
+
+func TestSlackHandler_Interaction(t *testing.T) {
+	svc := &fakeSlackService{}
+	h := handlers.NewSlackHandler(svc, "slack-secret")
+	payload := `{"type":"block_actions","user":{"id":"U1"},"channel":{"id":"C1"},` +
+		`"actions":[{"action_id":"avy_subscribe","value":"NWAC_10","type":"button"}]}`
+	body := url.Values{"payload": {payload}}.Encode()
+
+	rec := httptest.NewRecorder()
+	h.HandleInteraction(rec, signedSlackRequest("/api/slack/interactions", "slack-secret", body))
+	if rec.Code != http.StatusOK || len(svc.actions) != 1 {
+		t.Fatalf("unexpected result: %d %+v", rec.Code, svc.actions)
+	}
+	want := services.SlackAction{ActionID: services.SlackActionSubscribe, Value: "NWAC_10", ChannelID: "C1", UserID: "U1"}
+	if svc.actions[0] != want {
+		t.Fatalf("got action %+v, want %+v", svc.actions[0], want)
+	}
+}
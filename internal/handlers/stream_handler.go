@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type EventStream interface {
+	Subscribe(filter services.StreamFilter) (<-chan models.ForecastEvent, func())
+	Replay(ctx context.Context, after uint64, filter services.StreamFilter, send func(models.ForecastEvent) error) (uint64, error)
+}
+
+const (
+	defaultStreamHeartbeat = 15 * time.Second
+	// streamRetry is the reconnection delay suggested to EventSource clients.
+	streamRetry = 5 * time.Second
+)
+
+// StreamHandler serves forecast events as Server-Sent Events.
+type StreamHandler struct {
+	stream EventStream
+	zones  ZoneResolver
+	// Heartbeat is the interval between keep-alive comments.
+	Heartbeat time.Duration
+}
+
+// NewStreamHandler creates a handler sending a heartbeat every 15 seconds.
+func NewStreamHandler(stream EventStream) *StreamHandler {
+	return &StreamHandler{stream: stream, Heartbeat: defaultStreamHeartbeat}
+}
+
+// SetZoneResolver lets the zones filter name zones by name, slug or alias.
+func (h *StreamHandler) SetZoneResolver(zones ZoneResolver) {
+	h.zones = zones
+}
+
+// GET /api/stream?zones=NWAC_10,NWAC_2&centers=IPAC
+// Clients resume with the Last-Event-ID header, or the last_event_id query
+// parameter for the first connection.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	zones, ok := resolveZoneList(w, r, h.zones, r.URL.Query().Get("zones"), "zones")
+	if !ok {
+		return
+	}
+	filter, err := services.NewStreamFilter(zones, r.URL.Query().Get("centers"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var lastID uint64
+	if raw := firstNonEmpty(r.Header.Get("Last-Event-ID"), r.URL.Query().Get("last_event_id")); raw != "" {
+		if lastID, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before replaying so nothing published in between is missed;
+	// duplicates are skipped by sequence number.
+	live, cancel := h.stream.Subscribe(filter)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	flusher.Flush()
+
+	ctx := r.Context()
+	if lastID > 0 {
+		lastID, err = h.stream.Replay(ctx, lastID, filter, func(ev models.ForecastEvent) error {
+			return writeEvent(w, ev)
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[StreamHandler] replay failed: %v", err)
+			}
+			return
+		}
+		flusher.Flush()
+	}
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-live:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resumes.
+				return
+			}
+			if ev.Seq <= lastID {
+				continue
+			}
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+			lastID = ev.Seq
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes ev in SSE framing, with its log sequence as the event ID
+// and its type as the event name.
+func writeEvent(w http.ResponseWriter, ev models.ForecastEvent) error {
+	data := strings.ReplaceAll(ev.Data, "\n", "\ndata: ")
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data)
+	return err
+}
@@ -0,0 +1,94 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// readEvent reads SSE lines up to the end of the next event, skipping comments.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			if _, ok := fields["id"]; ok {
+				return fields
+			}
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ": "); ok && k != "" {
+			fields[k] = v
+		}
+	}
+}
+
+func TestStreamHandler_ResumesAndStreamsLiveEvents(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// The stream polls from another goroutine; keep it on the same in-memory database.
+	sqlDB, _ := gdb.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := gdb.AutoMigrate(&models.ForecastEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	logEvent := func(id, zone string) {
+		center, _, _ := strings.Cut(zone, "_")
+		ev := models.ForecastEvent{EventID: id, Type: models.EventForecastIssued, ZoneID: zone, CenterID: center, Data: `{"id":"` + id + `"}`}
+		if err := gdb.Create(&ev).Error; err != nil {
+			t.Fatalf("log event: %v", err)
+		}
+	}
+	logEvent("evt_1", "NWAC_10")
+	logEvent("evt_2", "NWAC_2")
+	logEvent("evt_3", "NWAC_10")
+
+	stream := services.NewStreamService(db.NewEventRepository(gdb), 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	server := httptest.NewServer(http.HandlerFunc(handlers.NewStreamHandler(stream).Stream))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?zones=NWAC_10", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+
+	if ev := readEvent(t, r); ev["id"] != "3" || ev["event"] != models.EventForecastIssued || ev["data"] != `{"id":"evt_3"}` {
+		t.Fatalf("expected replay of event 3, got %v", ev)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	logEvent("evt_4", "NWAC_2")
+	logEvent("evt_5", "NWAC_10")
+	if ev := readEvent(t, r); ev["id"] != "5" {
+		t.Fatalf("expected live event 5, got %v", ev)
+	}
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
 
 	"example.com/avalanche/internal/domain"
 	"example.com/avalanche/internal/models"
@@ -13,6 +15,7 @@ import (
 // SubscriptionHandler handles HTTP requests for subscription management.
 type SubscriptionHandler struct {
 	service *services.SubscriptionService
+	zones   ZoneResolver
 }
 
 // NewSubscriptionHandler creates a new subscription handler with the given service.
@@ -20,11 +23,21 @@ func NewSubscriptionHandler(service *services.SubscriptionService) *Subscription
 	return &SubscriptionHandler{service: service}
 }
 
+// SetZoneResolver lets zone_id name zones by name, slug or alias rather than
+// only by upstream ID.
+func (h *SubscriptionHandler) SetZoneResolver(zones ZoneResolver) {
+	h.zones = zones
+}
+
 // POST /api/subscriptions
 func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Email  string `json:"email"`
-		ZoneID string `json:"zone_id"`
+		Email      string `json:"email"`
+		ZoneID     string `json:"zone_id"`
+		Group      string `json:"group"`
+		Channel    string `json:"channel"`
+		WebhookURL string `json:"webhook_url"`
+		Phone      string `json:"phone"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -32,22 +45,40 @@ func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.
 		return
 	}
 
-	email, err := domain.NewEmail(req.Email)
-	if err != nil {
-		http.Error(w, "invalid email: "+err.Error(), http.StatusBadRequest)
+	zoneID, ok := h.subscriptionZone(w, r, req.ZoneID, req.Group, false)
+	if !ok {
 		return
 	}
 
-	zoneID, err := domain.ParseZoneID(req.ZoneID)
-	if err != nil {
-		http.Error(w, "invalid zone_id: "+err.Error(), http.StatusBadRequest)
+	var err error
+	create := services.CreateSubscriptionRequest{ZoneID: zoneID, Channel: req.Channel}
+	switch req.Channel {
+	case "", models.ChannelEmail:
+		create.Channel = models.ChannelEmail
+		if create.Email, err = domain.NewEmail(req.Email); err != nil {
+			http.Error(w, "invalid email: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	case models.ChannelSMS:
+		if create.Phone, err = domain.NewPhoneNumber(req.Phone); err != nil {
+			http.Error(w, "invalid phone: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	case models.ChannelSlack, models.ChannelDiscord, models.ChannelMattermost:
+		if create.WebhookURL, err = domain.NewWebhookURL(req.WebhookURL); err != nil {
+			http.Error(w, "invalid webhook_url: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "unsupported channel: "+req.Channel, http.StatusBadRequest)
 		return
 	}
 
-	sub, err := h.service.Create(r.Context(), services.CreateSubscriptionRequest{
-		Email:  email,
-		ZoneID: zoneID,
-	})
+	sub, err := h.service.Create(r.Context(), create)
+	if errors.Is(err, services.ErrZoneGroupNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		log.Printf("[SubscriptionHandler] failed to create subscription: %v", err)
 		http.Error(w, "failed to create subscription", http.StatusInternalServerError)
@@ -58,30 +89,53 @@ func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(sub)
 }
 
-// DELETE /api/subscriptions
+// DELETE /api/subscriptions?email=EMAIL&zone_id=ZONE_ID
+// DELETE /api/subscriptions?webhook_url=URL&zone_id=ZONE_ID
+// DELETE /api/subscriptions?phone=PHONE&zone_id=ZONE_ID
+// Zone group subscriptions pass group=SLUG instead of zone_id.
 func (h *SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	emailStr := r.URL.Query().Get("email")
+	webhookStr := r.URL.Query().Get("webhook_url")
+	phoneStr := r.URL.Query().Get("phone")
 	zoneIDStr := r.URL.Query().Get("zone_id")
+	groupStr := r.URL.Query().Get("group")
 
-	if emailStr == "" || zoneIDStr == "" {
-		http.Error(w, "email and zone_id query parameters are required", http.StatusBadRequest)
-		return
-	}
-
-	email, err := domain.NewEmail(emailStr)
-	if err != nil {
-		http.Error(w, "invalid email: "+err.Error(), http.StatusBadRequest)
+	if (emailStr == "" && webhookStr == "" && phoneStr == "") || (zoneIDStr == "" && groupStr == "") {
+		http.Error(w, "email (or webhook_url or phone) and zone_id (or group) query parameters are required", http.StatusBadRequest)
 		return
 	}
 
-	zoneID, err := domain.ParseZoneID(zoneIDStr)
-	if err != nil {
-		http.Error(w, "invalid zone_id: "+err.Error(), http.StatusBadRequest)
+	zoneID, ok := h.subscriptionZone(w, r, zoneIDStr, groupStr, true)
+	if !ok {
 		return
 	}
 
 	// Delete subscription via service
-	if err := h.service.Delete(r.Context(), email, zoneID); err != nil {
+	var err error
+	switch {
+	case phoneStr != "":
+		phone, parseErr := domain.NewPhoneNumber(phoneStr)
+		if parseErr != nil {
+			http.Error(w, "invalid phone: "+parseErr.Error(), http.StatusBadRequest)
+			return
+		}
+		err = h.service.DeleteByPhone(r.Context(), phone, zoneID)
+	case webhookStr != "":
+		target, parseErr := domain.NewWebhookURL(webhookStr)
+		if parseErr != nil {
+			http.Error(w, "invalid webhook_url: "+parseErr.Error(), http.StatusBadRequest)
+			return
+		}
+		err = h.service.DeleteByTarget(r.Context(), target, zoneID)
+	default:
+		email, parseErr := domain.NewEmail(emailStr)
+		if parseErr != nil {
+			http.Error(w, "invalid email: "+parseErr.Error(), http.StatusBadRequest)
+			return
+		}
+		err = h.service.Delete(r.Context(), email, zoneID)
+	}
+	if err != nil {
 		log.Printf("[SubscriptionHandler] failed to delete subscription: %v", err)
 		http.Error(w, "failed to delete subscription", http.StatusInternalServerError)
 		return
@@ -115,9 +169,8 @@ func (h *SubscriptionHandler) GetSubscriptions(w http.ResponseWriter, r *http.Re
 		}
 		subs, err = h.service.GetByEmail(r.Context(), email)
 	} else {
-		zoneID, parseErr := domain.ParseZoneID(zoneIDStr)
-		if parseErr != nil {
-			http.Error(w, "invalid zone_id: "+parseErr.Error(), http.StatusBadRequest)
+		zoneID, ok := h.subscriptionZone(w, r, zoneIDStr, "", true)
+		if !ok {
 			return
 		}
 		subs, err = h.service.GetByZone(r.Context(), zoneID)
@@ -132,3 +185,79 @@ func (h *SubscriptionHandler) GetSubscriptions(w http.ResponseWriter, r *http.Re
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(subs)
 }
+
+// POST /api/subscriptions/verify
+// Confirms an SMS subscriber's phone number with the code texted to it.
+func (h *SubscriptionHandler) VerifyPhone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Phone string `json:"phone"`
+		Code  string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	phone, err := domain.NewPhoneNumber(req.Phone)
+	if err != nil {
+		http.Error(w, "invalid phone: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resumed, err := h.service.VerifyPhone(r.Context(), phone, req.Code)
+	switch {
+	case errors.Is(err, services.ErrVerificationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, services.ErrVerificationInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, services.ErrVerificationExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	case errors.Is(err, services.ErrVerificationLocked):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	case err != nil:
+		log.Printf("[SubscriptionHandler] failed to verify phone: %v", err)
+		http.Error(w, "failed to verify phone", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"verified": true, "subscriptions_resumed": resumed})
+}
+
+// subscriptionZone parses the zone a subscription follows: a zone group when
+// group is set, otherwise a "group:<slug>" reference or a zone or center the
+// resolver recognizes. Existing subscriptions may follow IDs no longer in
+// the zone catalog, so lookups and removals also accept any well-formed ID.
+func (h *SubscriptionHandler) subscriptionZone(w http.ResponseWriter, r *http.Request, zoneID, group string, existing bool) (*domain.ZoneID, bool) {
+	if group != "" || strings.HasPrefix(strings.ToLower(strings.TrimSpace(zoneID)), domain.GroupPrefix) {
+		if group == "" {
+			group = zoneID
+		}
+		id, err := domain.NewGroupZoneID(group)
+		if err != nil {
+			http.Error(w, "invalid group: "+err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		return id, true
+	}
+	if !existing || h.zones == nil {
+		return resolveZone(w, r, h.zones, zoneID, "zone_id")
+	}
+	id, err := h.zones.Resolve(r.Context(), zoneID)
+	switch {
+	case errors.Is(err, services.ErrUnknownZone):
+		return resolveZone(w, r, nil, zoneID, "zone_id")
+	case err != nil:
+		log.Printf("[SubscriptionHandler] failed to resolve %q: %v", zoneID, err)
+		http.Error(w, "failed to resolve zone_id", http.StatusInternalServerError)
+		return nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// maxWebhookBody caps inbound webhook payloads.
+const maxWebhookBody = 1 << 20
+
+type SuppressionService interface {
+	IngestSendGridEvents(ctx context.Context, events []notifier.SendGridEvent) (int, error)
+	List(ctx context.Context) ([]models.EmailSuppression, error)
+	Clear(ctx context.Context, email string) (bool, error)
+}
+
+// SuppressionHandler ingests provider bounce/complaint webhooks and exposes the
+// suppression list to administrators.
+type SuppressionHandler struct {
+	service    SuppressionService
+	webhookKey *ecdsa.PublicKey
+}
+
+// NewSuppressionHandler creates a handler. webhookKey verifies SendGrid Event
+// Webhook signatures; when nil the webhook endpoint is disabled.
+func NewSuppressionHandler(service SuppressionService, webhookKey *ecdsa.PublicKey) *SuppressionHandler {
+	return &SuppressionHandler{service: service, webhookKey: webhookKey}
+}
+
+// POST /api/webhooks/sendgrid/events
+func (h *SuppressionHandler) HandleSendGridEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.webhookKey == nil {
+		http.Error(w, "event webhook verification is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	err = notifier.VerifySendGridSignature(h.webhookKey,
+		r.Header.Get(notifier.SendGridSignatureHeader),
+		r.Header.Get(notifier.SendGridTimestampHeader),
+		payload)
+	if errors.Is(err, notifier.ErrInvalidSignature) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	events, err := notifier.ParseSendGridEvents(payload)
+	if err != nil {
+		http.Error(w, "invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.service.IngestSendGridEvents(r.Context(), events)
+	if err != nil {
+		log.Printf("[SuppressionHandler] failed to ingest events: %v", err)
+		http.Error(w, "failed to ingest events", http.StatusInternalServerError)
+		return
+	}
+	if n > 0 {
+		log.Printf("[SuppressionHandler] ingested %d suppressions from %d events", n, len(events))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/admin/suppressions
+func (h *SuppressionHandler) ListSuppressions(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.List(r.Context())
+	if err != nil {
+		log.Printf("[SuppressionHandler] failed to list suppressions: %v", err)
+		http.Error(w, "failed to load suppressions", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// DELETE /api/admin/suppressions?email=EMAIL
+func (h *SuppressionHandler) ClearSuppression(w http.ResponseWriter, r *http.Request) {
+	email := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("email")))
+	if email == "" {
+		http.Error(w, "email query parameter is required", http.StatusBadRequest)
+		return
+	}
+	found, err := h.service.Clear(r.Context(), email)
+	if err != nil {
+		log.Printf("[SuppressionHandler] failed to clear suppression: %v", err)
+		http.Error(w, "failed to clear suppression", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "suppression not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+type mockSuppressionService struct {
+	events  []notifier.SendGridEvent
+	cleared string
+}
+
+func (m *mockSuppressionService) IngestSendGridEvents(ctx context.Context, events []notifier.SendGridEvent) (int, error) {
+	m.events = events
+	return len(events), nil
+}
+
+func (m *mockSuppressionService) List(ctx context.Context) ([]models.EmailSuppression, error) {
+	return []models.EmailSuppression{{Email: "gone@example.com", Reason: models.SuppressionBounce}}, nil
+}
+
+func (m *mockSuppressionService) Clear(ctx context.Context, email string) (bool, error) {
+	m.cleared = email
+	return email == "gone@example.com", nil
+}
+
+func signSendGrid(t *testing.T, key *ecdsa.PrivateKey, ts, body string) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(ts + body))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestSuppressionHandler_VerifiesSendGridSignature(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	svc := &mockSuppressionService{}
+	h := handlers.NewSuppressionHandler(svc, &key.PublicKey)
+
+	body := `[{"email":"gone@example.com","event":"bounce","type":"bounce","reason":"550 no such user","timestamp":1700000000}]`
+	ts := "1700000000"
+
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/sendgrid/events", strings.NewReader(body))
+	req.Header.Set(notifier.SendGridTimestampHeader, ts)
+	req.Header.Set(notifier.SendGridSignatureHeader, signSendGrid(t, key, ts, body))
+	rec := httptest.NewRecorder()
+	h.HandleSendGridEvents(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(svc.events) != 1 || svc.events[0].Email != "gone@example.com" {
+		t.Fatalf("unexpected events: %+v", svc.events)
+	}
+
+	tampered := httptest.NewRequest(http.MethodPost, "/api/webhooks/sendgrid/events", strings.NewReader(strings.Replace(body, "gone", "other", 1)))
+	tampered.Header.Set(notifier.SendGridTimestampHeader, ts)
+	tampered.Header.Set(notifier.SendGridSignatureHeader, signSendGrid(t, key, ts, body))
+	rec = httptest.NewRecorder()
+	h.HandleSendGridEvents(rec, tampered)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for tampered payload, got %d", rec.Code)
+	}
+}
+
+func TestSuppressionHandler_AdminEndpointsRequireToken(t *testing.T) {
+	svc := &mockSuppressionService{}
+	h := handlers.NewSuppressionHandler(svc, nil)
+	list := handlers.RequireAdmin("secret", h.ListSuppressions)
+
+	rec := httptest.NewRecorder()
+	list(rec, httptest.NewRequest(http.MethodGet, "/api/admin/suppressions", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/suppressions", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	list(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "gone@example.com") {
+		t.Fatalf("unexpected list response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ClearSuppression(rec, httptest.NewRequest(http.MethodDelete, "/api/admin/suppressions?email=Unknown@example.com", nil))
+	if rec.Code != http.StatusNotFound || svc.cleared != "unknown@example.com" {
+		t.Fatalf("expected 404 for unknown address, got %d (cleared %q)", rec.Code, svc.cleared)
+	}
+}
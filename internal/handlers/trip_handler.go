@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/services"
+)
+
+type TripPlans interface {
+	Create(ctx context.Context, req services.CreateTripPlanRequest) (*services.SharedTripPlan, error)
+	Get(ctx context.Context, token string) (*services.SharedTripPlan, error)
+	Join(ctx context.Context, token string, email *domain.Email) (*services.SharedTripPlan, error)
+	Confirm(ctx context.Context, token string) (*services.SharedTripPlan, error)
+	Leave(ctx context.Context, token string) (*services.SharedTripPlan, error)
+	Cancel(ctx context.Context, token string) error
+}
+
+// TripHandler manages trip plans. Plans are viewed and joined with their
+// signed share token, cancelled with the organizer token returned on creation,
+// and confirmed or left with the links emailed to each participant.
+type TripHandler struct {
+	trips TripPlans
+}
+
+func NewTripHandler(trips TripPlans) *TripHandler {
+	return &TripHandler{trips: trips}
+}
+
+// POST /api/trips
+// {"name": "...", "zone_ids": ["NWAC_10"], "date": "2025-12-06", "timezone": "America/Los_Angeles", "participants": ["a@example.com"]}
+func (h *TripHandler) CreateTrip(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name         string   `json:"name"`
+		ZoneIDs      []string `json:"zone_ids"`
+		Date         string   `json:"date"`
+		Timezone     string   `json:"timezone"`
+		Participants []string `json:"participants"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	create := services.CreateTripPlanRequest{Name: req.Name, Date: req.Date, Timezone: req.Timezone}
+	for _, raw := range req.ZoneIDs {
+		zoneID, err := domain.ParseZoneID(raw)
+		if err != nil {
+			http.Error(w, "invalid zone_ids: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		create.ZoneIDs = append(create.ZoneIDs, zoneID)
+	}
+	for _, raw := range req.Participants {
+		email, err := domain.NewEmail(raw)
+		if err != nil {
+			http.Error(w, "invalid participants: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		create.Participants = append(create.Participants, email)
+	}
+
+	plan, err := h.trips.Create(r.Context(), create)
+	if !tripError(w, err, "create") {
+		return
+	}
+	writeJSON(w, http.StatusCreated, plan)
+}
+
+// GET /api/trips/{token}
+func (h *TripHandler) GetTrip(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.trips.Get(r.Context(), r.PathValue("token"))
+	if !tripError(w, err, "load") {
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// DELETE /api/trips/{token}, where token is the plan's organizer token.
+func (h *TripHandler) CancelTrip(w http.ResponseWriter, r *http.Request) {
+	if !tripError(w, h.trips.Cancel(r.Context(), r.PathValue("token")), "cancel") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/trips/{token}/participants
+// {"email": "partner@example.com"}
+// The participant is emailed a confirmation link before joining.
+func (h *TripHandler) JoinTrip(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	email, err := domain.NewEmail(req.Email)
+	if err != nil {
+		http.Error(w, "invalid email: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	plan, err := h.trips.Join(r.Context(), r.PathValue("token"), email)
+	if !tripError(w, err, "join") {
+		return
+	}
+	writeJSON(w, http.StatusAccepted, plan)
+}
+
+// GET /api/trips/{token}/confirm, linked from the confirmation email.
+func (h *TripHandler) ConfirmTrip(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.trips.Confirm(r.Context(), r.PathValue("token"))
+	if !tripError(w, err, "confirm") {
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// GET /api/trips/{token}/leave, linked from every countdown email.
+func (h *TripHandler) LeaveTrip(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.trips.Leave(r.Context(), r.PathValue("token"))
+	if !tripError(w, err, "leave") {
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// tripError writes the response for a failed trip plan operation and reports
+// whether err was nil.
+func tripError(w http.ResponseWriter, err error, action string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidTripPlan):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrTripPlanNotFound):
+		http.Error(w, "trip plan not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrTripPlansDisabled):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("[TripHandler] failed to %s trip plan: %v", action, err)
+		http.Error(w, "failed to "+action+" trip plan", http.StatusInternalServerError)
+	}
+	return false
+}
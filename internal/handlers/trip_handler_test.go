@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type stubTrips struct {
+	created services.CreateTripPlanRequest
+	joined  string
+}
+
+func (s *stubTrips) Create(ctx context.Context, req services.CreateTripPlanRequest) (*services.SharedTripPlan, error) {
+	s.created = req
+	if req.Date == "2020-01-01" {
+		return nil, services.ErrInvalidTripPlan
+	}
+	return &services.SharedTripPlan{TripPlan: &models.TripPlan{ID: 7, TripDate: req.Date}, ShareURL: "/api/trips/t7-abc"}, nil
+}
+
+func (s *stubTrips) Get(ctx context.Context, token string) (*services.SharedTripPlan, error) {
+	if token != "t7-abc" {
+		return nil, services.ErrTripPlanNotFound
+	}
+	return &services.SharedTripPlan{TripPlan: &models.TripPlan{ID: 7}, ShareURL: "https://avy.example/api/trips/t7-abc"}, nil
+}
+
+func (s *stubTrips) Join(ctx context.Context, token string, email *domain.Email) (*services.SharedTripPlan, error) {
+	s.joined = email.String()
+	return s.Get(ctx, token)
+}
+
+func (s *stubTrips) Confirm(ctx context.Context, token string) (*services.SharedTripPlan, error) {
+	if token != "c7.1a2b-abc" {
+		return nil, services.ErrTripPlanNotFound
+	}
+	return s.Get(ctx, "t7-abc")
+}
+
+func (s *stubTrips) Leave(ctx context.Context, token string) (*services.SharedTripPlan, error) {
+	if token != "p7.1a2b-abc" {
+		return nil, services.ErrTripPlanNotFound
+	}
+	return s.Get(ctx, "t7-abc")
+}
+
+func (s *stubTrips) Cancel(ctx context.Context, token string) error {
+	if token != "o7-abc" {
+		return services.ErrTripPlanNotFound
+	}
+	return nil
+}
+
+func TestTripHandler_CreateAndShare(t *testing.T) {
+	trips := &stubTrips{}
+	h := handlers.NewTripHandler(trips)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/trips", h.CreateTrip)
+	mux.HandleFunc("GET /api/trips/{token}", h.GetTrip)
+	mux.HandleFunc("DELETE /api/trips/{token}", h.CancelTrip)
+	mux.HandleFunc("POST /api/trips/{token}/participants", h.JoinTrip)
+	mux.HandleFunc("GET /api/trips/{token}/confirm", h.ConfirmTrip)
+	mux.HandleFunc("GET /api/trips/{token}/leave", h.LeaveTrip)
+
+	body := `{"name":"Tour","zone_ids":["nwac_10"],"date":"2025-12-06","participants":["A@example.com"]}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://avy.example/api/trips", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(trips.created.ZoneIDs) != 1 || trips.created.ZoneIDs[0].String() != "NWAC_10" ||
+		len(trips.created.Participants) != 1 || trips.created.Participants[0].String() != "a@example.com" {
+		t.Errorf("unexpected request %+v", trips.created)
+	}
+	var plan services.SharedTripPlan
+	if err := json.NewDecoder(rec.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if plan.ShareURL != "/api/trips/t7-abc" {
+		t.Errorf("share URL = %q, want it left as the service built it", plan.ShareURL)
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/trips", `{"zone_ids":["NWAC_10"],"date":"2020-01-01","participants":["a@example.com"]}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/trips", `{"zone_ids":["NWAC_10"],"date":"2025-12-06","participants":["nope"]}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/trips/t7-abc", "", http.StatusOK},
+		{http.MethodGet, "/api/trips/t7-bad", "", http.StatusNotFound},
+		{http.MethodPost, "/api/trips/t7-abc/participants", `{"email":"partner@example.com"}`, http.StatusAccepted},
+		{http.MethodGet, "/api/trips/c7.1a2b-abc/confirm", "", http.StatusOK},
+		{http.MethodGet, "/api/trips/t7-abc/confirm", "", http.StatusNotFound},
+		{http.MethodGet, "/api/trips/p7.1a2b-abc/leave", "", http.StatusOK},
+		{http.MethodGet, "/api/trips/t7-abc/leave", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/trips/t7-abc", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/trips/o7-abc", "", http.StatusNoContent},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		if rec.Code != tc.want {
+			t.Errorf("%s %s: expected %d, got %d: %s", tc.method, tc.path, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+	if trips.joined != "partner@example.com" {
+		t.Errorf("joined %q", trips.joined)
+	}
+}
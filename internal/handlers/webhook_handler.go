@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, req services.CreateWebhookRequest) (*models.WebhookEndpoint, string, error)
+	List(ctx context.Context) ([]models.WebhookEndpoint, error)
+	Delete(ctx context.Context, id uint) (bool, error)
+	Enable(ctx context.Context, id uint) (bool, error)
+	Deliveries(ctx context.Context, id uint) ([]models.WebhookDelivery, error)
+}
+
+// WebhookHandler exposes partner webhook endpoint management to administrators.
+type WebhookHandler struct {
+	service WebhookService
+}
+
+// NewWebhookHandler creates a handler backed by service.
+func NewWebhookHandler(service WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// POST /api/admin/webhooks
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL    string   `json:"url"`
+		ZoneID string   `json:"zone_id"`
+		Events []string `json:"events"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	url, err := domain.NewWebhookURL(req.URL)
+	if err != nil {
+		http.Error(w, "invalid url: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	zoneID, err := domain.ParseZoneID(req.ZoneID)
+	if err != nil {
+		http.Error(w, "invalid zone_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ep, secret, err := h.service.Create(r.Context(), services.CreateWebhookRequest{URL: url, ZoneID: zoneID, Events: req.Events})
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("[WebhookHandler] failed to create webhook: %v", err)
+		http.Error(w, "failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(struct {
+		*models.WebhookEndpoint
+		Secret string `json:"secret"`
+	}{ep, secret})
+}
+
+// GET /api/admin/webhooks
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.List(r.Context())
+	if err != nil {
+		log.Printf("[WebhookHandler] failed to list webhooks: %v", err)
+		http.Error(w, "failed to load webhooks", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// DELETE /api/admin/webhooks/{id}
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	h.mutate(w, r, h.service.Delete, "delete")
+}
+
+// POST /api/admin/webhooks/{id}/enable
+func (h *WebhookHandler) EnableWebhook(w http.ResponseWriter, r *http.Request) {
+	h.mutate(w, r, h.service.Enable, "enable")
+}
+
+// GET /api/admin/webhooks/{id}/deliveries
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.service.Deliveries(r.Context(), id)
+	if err != nil {
+		log.Printf("[WebhookHandler] failed to list deliveries: %v", err)
+		http.Error(w, "failed to load deliveries", http.StatusInternalServerError)
+		return
+	}
+	if out == nil {
+		http.Error(w, "webhook not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (h *WebhookHandler) mutate(w http.ResponseWriter, r *http.Request, fn func(context.Context, uint) (bool, error), action string) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	found, err := fn(r.Context(), id)
+	if err != nil {
+		log.Printf("[WebhookHandler] failed to %s webhook %d: %v", action, id, err)
+		http.Error(w, "failed to "+action+" webhook", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "webhook not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func webhookID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid webhook id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
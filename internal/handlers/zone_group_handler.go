@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type ZoneGroups interface {
+	Create(ctx context.Context, req services.CreateZoneGroupRequest) (*models.ZoneGroup, error)
+	Get(ctx context.Context, slug string) (*models.ZoneGroup, error)
+	ListByOwner(ctx context.Context, owner *domain.Email) ([]models.ZoneGroup, error)
+	ListByOrganization(ctx context.Context, orgID uint) ([]models.ZoneGroup, error)
+	Update(ctx context.Context, slug string, owner *domain.Email, name string, zoneIDs []*domain.ZoneID) (*models.ZoneGroup, error)
+	Delete(ctx context.Context, slug string, owner *domain.Email) error
+	ManageToken(g *models.ZoneGroup) string
+	Authorize(ctx context.Context, slug, token string) (*domain.Email, error)
+	OwnerToken(owner *domain.Email) string
+	AuthorizeOwner(ctx context.Context, owner *domain.Email, token string) error
+}
+
+// ZoneGroupHandler manages named zone groups. Subscribers manage their own
+// groups with the manage token returned when the group is created, and list
+// them with the owner token returned alongside it; administrators manage
+// every group.
+type ZoneGroupHandler struct {
+	groups ZoneGroups
+}
+
+func NewZoneGroupHandler(groups ZoneGroups) *ZoneGroupHandler {
+	return &ZoneGroupHandler{groups: groups}
+}
+
+type zoneGroupRequest struct {
+	Slug       string   `json:"slug"`
+	Name       string   `json:"name"`
+	ZoneIDs    []string `json:"zone_ids"`
+	OwnerEmail string   `json:"owner_email"`
+}
+
+// POST /api/groups
+// {"slug": "home-range", "name": "...", "zone_ids": ["NWAC_10", "IPAC_1"], "owner_email": "me@example.com"}
+// The response carries the manage_token needed to change or delete the group
+// and the owner_token needed to list the owner's groups.
+func (h *ZoneGroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	req, create, ok := decodeZoneGroup(w, r)
+	if !ok {
+		return
+	}
+	var err error
+	if create.Owner, err = domain.NewEmail(req.OwnerEmail); err != nil {
+		http.Error(w, "invalid owner_email: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	g, err := h.groups.Create(r.Context(), create)
+	if !zoneGroupError(w, err, "create zone group") {
+		return
+	}
+	writeJSON(w, http.StatusCreated, services.ManagedZoneGroup{ZoneGroup: g,
+		ManageToken: h.groups.ManageToken(g), OwnerToken: h.groups.OwnerToken(create.Owner)})
+}
+
+// POST /api/admin/organizations/{id}/groups
+func (h *ZoneGroupHandler) CreateOrganizationGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	_, create, ok := decodeZoneGroup(w, r)
+	if !ok {
+		return
+	}
+	create.OrganizationID = &id
+	g, err := h.groups.Create(r.Context(), create)
+	if !zoneGroupError(w, err, "create zone group") {
+		return
+	}
+	writeJSON(w, http.StatusCreated, g)
+}
+
+// GET /api/groups?owner_email=EMAIL&token=OWNER_TOKEN
+func (h *ZoneGroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	owner, err := domain.NewEmail(r.URL.Query().Get("owner_email"))
+	if err != nil {
+		http.Error(w, "invalid owner_email: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	token, ok := groupToken(w, r, "owner token")
+	if !ok {
+		return
+	}
+	if !zoneGroupError(w, h.groups.AuthorizeOwner(r.Context(), owner, token), "authorize zone group listing") {
+		return
+	}
+	out, err := h.groups.ListByOwner(r.Context(), owner)
+	if !zoneGroupError(w, err, "list zone groups") {
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// GET /api/admin/organizations/{id}/groups
+func (h *ZoneGroupHandler) ListOrganizationGroups(w http.ResponseWriter, r *http.Request) {
+	id, ok := organizationID(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.groups.ListByOrganization(r.Context(), id)
+	if !zoneGroupError(w, err, "list zone groups") {
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// GET /api/groups/{slug}
+func (h *ZoneGroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	g, err := h.groups.Get(r.Context(), r.PathValue("slug"))
+	if !zoneGroupError(w, err, "load zone group") {
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+// PUT /api/groups/{slug}?token=MANAGE_TOKEN
+// {"name": "...", "zone_ids": ["NWAC_10"]}
+func (h *ZoneGroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	if owner, ok := h.groupOwner(w, r); ok {
+		h.update(w, r, owner)
+	}
+}
+
+// PUT /api/admin/groups/{slug}
+func (h *ZoneGroupHandler) AdminUpdateGroup(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, nil)
+}
+
+func (h *ZoneGroupHandler) update(w http.ResponseWriter, r *http.Request, owner *domain.Email) {
+	_, req, ok := decodeZoneGroup(w, r)
+	if !ok {
+		return
+	}
+	g, err := h.groups.Update(r.Context(), r.PathValue("slug"), owner, req.Name, req.ZoneIDs)
+	if !zoneGroupError(w, err, "update zone group") {
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+// DELETE /api/groups/{slug}?token=MANAGE_TOKEN
+func (h *ZoneGroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if owner, ok := h.groupOwner(w, r); ok {
+		h.delete(w, r, owner)
+	}
+}
+
+// DELETE /api/admin/groups/{slug}
+func (h *ZoneGroupHandler) AdminDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	h.delete(w, r, nil)
+}
+
+func (h *ZoneGroupHandler) delete(w http.ResponseWriter, r *http.Request, owner *domain.Email) {
+	if !zoneGroupError(w, h.groups.Delete(r.Context(), r.PathValue("slug"), owner), "delete zone group") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeZoneGroup parses a group body. The slug is only required when it is
+// not part of the path.
+func decodeZoneGroup(w http.ResponseWriter, r *http.Request) (zoneGroupRequest, services.CreateZoneGroupRequest, bool) {
+	var req zoneGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, services.CreateZoneGroupRequest{}, false
+	}
+	zones, ok := parseZoneIDs(w, req.ZoneIDs)
+	if !ok {
+		return req, services.CreateZoneGroupRequest{}, false
+	}
+	create := services.CreateZoneGroupRequest{Name: req.Name, ZoneIDs: zones}
+	if r.PathValue("slug") == "" {
+		slug, err := domain.NewGroupZoneID(req.Slug)
+		if err != nil {
+			http.Error(w, "invalid slug: "+err.Error(), http.StatusBadRequest)
+			return req, services.CreateZoneGroupRequest{}, false
+		}
+		create.Slug = slug
+	}
+	return req, create, true
+}
+
+// groupOwner verifies the manage token of a public request, given as a bearer
+// token or a "token" query parameter, and returns the group's owner.
+func (h *ZoneGroupHandler) groupOwner(w http.ResponseWriter, r *http.Request) (*domain.Email, bool) {
+	token, ok := groupToken(w, r, "manage token")
+	if !ok {
+		return nil, false
+	}
+	owner, err := h.groups.Authorize(r.Context(), r.PathValue("slug"), token)
+	if !zoneGroupError(w, err, "authorize zone group change") {
+		return nil, false
+	}
+	return owner, true
+}
+
+// groupToken reads a bearer token or "token" query parameter, answering 401
+// when there is none.
+func groupToken(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		http.Error(w, name+" is required", http.StatusUnauthorized)
+		return "", false
+	}
+	return token, true
+}
+
+// zoneGroupError writes the response for a failed zone group operation and
+// reports whether err was nil.
+func zoneGroupError(w http.ResponseWriter, err error, action string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidZoneGroup):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrZoneGroupNotFound), errors.Is(err, services.ErrOrganizationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrZoneGroupExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrZoneGroupManagementDisabled):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("[ZoneGroupHandler] failed to %s: %v", action, err)
+		http.Error(w, "failed to "+action, http.StatusInternalServerError)
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type stubZoneGroups struct {
+	created services.CreateZoneGroupRequest
+	owner   *domain.Email
+}
+
+func (s *stubZoneGroups) Create(ctx context.Context, req services.CreateZoneGroupRequest) (*models.ZoneGroup, error) {
+	s.created = req
+	return &models.ZoneGroup{Slug: req.Slug.Group(), Name: req.Name, OrganizationID: req.OrganizationID}, nil
+}
+
+func (s *stubZoneGroups) Get(ctx context.Context, slug string) (*models.ZoneGroup, error) {
+	if slug != "home-range" {
+		return nil, services.ErrZoneGroupNotFound
+	}
+	return &models.ZoneGroup{Slug: slug, ZoneIDs: []string{"NWAC_10", "IPAC_1"}}, nil
+}
+
+func (s *stubZoneGroups) ListByOwner(ctx context.Context, owner *domain.Email) ([]models.ZoneGroup, error) {
+	return nil, nil
+}
+
+func (s *stubZoneGroups) ListByOrganization(ctx context.Context, orgID uint) ([]models.ZoneGroup, error) {
+	return nil, nil
+}
+
+func (s *stubZoneGroups) Update(ctx context.Context, slug string, owner *domain.Email, name string, zoneIDs []*domain.ZoneID) (*models.ZoneGroup, error) {
+	s.owner = owner
+	if owner != nil && owner.String() != "owner@example.com" {
+		return nil, services.ErrZoneGroupNotFound
+	}
+	return &models.ZoneGroup{Slug: slug, Name: name}, nil
+}
+
+func (s *stubZoneGroups) Delete(ctx context.Context, slug string, owner *domain.Email) error {
+	s.owner = owner
+	return nil
+}
+
+func (s *stubZoneGroups) ManageToken(g *models.ZoneGroup) string {
+	if g.OrganizationID != nil {
+		return ""
+	}
+	return "manage-" + g.Slug
+}
+
+func (s *stubZoneGroups) Authorize(ctx context.Context, slug, token string) (*domain.Email, error) {
+	if token != "manage-"+slug {
+		return nil, services.ErrZoneGroupNotFound
+	}
+	return domain.NewEmail("owner@example.com")
+}
+
+func (s *stubZoneGroups) OwnerToken(owner *domain.Email) string {
+	return "owner-" + owner.String()
+}
+
+func (s *stubZoneGroups) AuthorizeOwner(ctx context.Context, owner *domain.Email, token string) error {
+	if token != "owner-"+owner.String() {
+		return services.ErrZoneGroupNotFound
+	}
+	return nil
+}
+
+func (s *stubZoneGroups) Forecast(ctx context.Context, slug string, date time.Time) (*services.GroupForecast, error) {
+	g, err := s.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return &services.GroupForecast{Group: g, Date: date.Format(time.DateOnly),
+		Zones:     []models.ZoneForecast{{ZoneID: "NWAC_10"}, {ZoneID: "IPAC_1"}},
+		WorstCase: []services.GroupDangerDay{{Day: "today", Max: 3}}}, nil
+}
+
+func TestZoneGroupHandler(t *testing.T) {
+	groups := &stubZoneGroups{}
+	h := handlers.NewZoneGroupHandler(groups)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/groups", h.CreateGroup)
+	mux.HandleFunc("GET /api/groups", h.ListGroups)
+	mux.HandleFunc("GET /api/groups/{slug}", h.GetGroup)
+	mux.HandleFunc("PUT /api/groups/{slug}", h.UpdateGroup)
+	mux.HandleFunc("DELETE /api/groups/{slug}", h.DeleteGroup)
+	mux.HandleFunc("DELETE /api/admin/groups/{slug}", h.AdminDeleteGroup)
+	mux.HandleFunc("POST /api/admin/organizations/{id}/groups", h.CreateOrganizationGroup)
+
+	for _, tt := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/groups", `{"slug":"home-range","name":"Home","zone_ids":["NWAC_10","IPAC_1"],"owner_email":"owner@example.com"}`, http.StatusCreated},
+		{http.MethodPost, "/api/groups", `{"slug":"Home Range","name":"Home","zone_ids":["NWAC_10"],"owner_email":"owner@example.com"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/groups", `{"slug":"home-range","name":"Home","zone_ids":["NWAC_10"]}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/admin/organizations/7/groups", `{"slug":"club","name":"Club","zone_ids":["NWAC_10"]}`, http.StatusCreated},
+		{http.MethodGet, "/api/groups?owner_email=owner@example.com&token=owner-owner@example.com", "", http.StatusOK},
+		{http.MethodGet, "/api/groups?owner_email=owner@example.com&token=owner-other@example.com", "", http.StatusNotFound},
+		{http.MethodGet, "/api/groups?owner_email=owner@example.com", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/groups/home-range", "", http.StatusOK},
+		{http.MethodGet, "/api/groups/unknown", "", http.StatusNotFound},
+		{http.MethodPut, "/api/groups/home-range?token=manage-home-range", `{"name":"Home","zone_ids":["NWAC_10"]}`, http.StatusOK},
+		{http.MethodPut, "/api/groups/home-range?token=forged", `{"name":"Home","zone_ids":["NWAC_10"]}`, http.StatusNotFound},
+		{http.MethodPut, "/api/groups/home-range?owner_email=owner@example.com", `{"name":"Home","zone_ids":["NWAC_10"]}`, http.StatusUnauthorized},
+		{http.MethodDelete, "/api/groups/home-range?token=forged", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/admin/groups/home-range", "", http.StatusNoContent},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+	if groups.created.OrganizationID == nil || *groups.created.OrganizationID != 7 || groups.created.Owner != nil {
+		t.Errorf("expected an organization group, got %+v", groups.created)
+	}
+	if groups.owner != nil {
+		t.Errorf("expected admin delete to skip the owner check")
+	}
+}
+
+func TestZoneGroupHandler_CreateReturnsManageToken(t *testing.T) {
+	groups := &stubZoneGroups{}
+	h := handlers.NewZoneGroupHandler(groups)
+
+	rec := httptest.NewRecorder()
+	h.CreateGroup(rec, httptest.NewRequest(http.MethodPost, "/api/groups",
+		strings.NewReader(`{"slug":"home-range","name":"Home","zone_ids":["NWAC_10"],"owner_email":"owner@example.com"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var out struct {
+		Slug        string `json:"slug"`
+		ManageToken string `json:"manage_token"`
+		OwnerToken  string `json:"owner_token"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.Slug != "home-range" || out.ManageToken != "manage-home-range" || out.OwnerToken != "owner-owner@example.com" {
+		t.Errorf("unexpected response %+v", out)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/groups/home-range", nil)
+	req.SetPathValue("slug", "home-range")
+	req.Header.Set("Authorization", "Bearer "+out.ManageToken)
+	rec = httptest.NewRecorder()
+	h.DeleteGroup(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 with a bearer token, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if groups.owner == nil || groups.owner.String() != "owner@example.com" {
+		t.Errorf("expected the delete to act as the token's owner, got %v", groups.owner)
+	}
+}
+
+func TestForecastHandler_Group(t *testing.T) {
+	h := handlers.NewForecastHandlerWithRepo(&mockService{}, &mockRepo{})
+	h.SetZoneGroups(&stubZoneGroups{})
+
+	rec := httptest.NewRecorder()
+	h.GetForecast(rec, httptest.NewRequest(http.MethodGet, "/api/forecast?group=home-range&date=2025-02-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var out services.GroupForecast
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.Date != "2025-02-01" || len(out.Zones) != 2 || len(out.WorstCase) != 1 || out.WorstCase[0].Max != 3 {
+		t.Errorf("unexpected group forecast %+v", out)
+	}
+
+	rec = httptest.NewRecorder()
+	h.GetForecast(rec, httptest.NewRequest(http.MethodGet, "/api/forecast?group=unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown group, got %d", rec.Code)
+	}
+}
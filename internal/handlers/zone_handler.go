@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type ZoneResolver interface {
+	Resolve(ctx context.Context, raw string) (*domain.ZoneID, error)
+}
+
+type ZoneCatalog interface {
+	ZoneResolver
+	Catalog() ([]services.ZoneCatalogEntry, error)
+	ListAliases(ctx context.Context) ([]models.ZoneAlias, error)
+	SetAlias(ctx context.Context, alias string, zoneID *domain.ZoneID) (*models.ZoneAlias, error)
+	DeleteAlias(ctx context.Context, alias string) error
+	Renumber(ctx context.Context, from, to *domain.ZoneID) (int64, error)
+}
+
+// ZoneHandler lists the zone catalog, resolves zone input and lets
+// administrators manage zone aliases.
+type ZoneHandler struct {
+	zones ZoneCatalog
+}
+
+func NewZoneHandler(zones ZoneCatalog) *ZoneHandler {
+	return &ZoneHandler{zones: zones}
+}
+
+// GET /api/zones
+func (h *ZoneHandler) ListZones(w http.ResponseWriter, r *http.Request) {
+	catalog, err := h.zones.Catalog()
+	if err != nil {
+		log.Printf("[ZoneHandler] failed to load zone catalog: %v", err)
+		http.Error(w, "failed to load zones", http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, http.StatusOK, catalog)
+}
+
+// GET /api/zones/resolve?q=NWAC/Mt Hood
+// Unresolved input answers 404 with the closest zones as suggestions.
+func (h *ZoneHandler) ResolveZone(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		http.Error(w, "q query parameter is required", http.StatusBadRequest)
+		return
+	}
+	zoneID, err := h.zones.Resolve(r.Context(), q)
+	var unresolved *services.UnresolvedZoneError
+	switch {
+	case errors.As(err, &unresolved):
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": err.Error(), "suggestions": unresolved.Suggestions})
+	case err != nil:
+		log.Printf("[ZoneHandler] failed to resolve %q: %v", q, err)
+		http.Error(w, "failed to resolve zone", http.StatusInternalServerError)
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"zone_id": zoneID.String()})
+	}
+}
+
+// GET /api/admin/zone-aliases
+func (h *ZoneHandler) ListAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.zones.ListAliases(r.Context())
+	if !zoneAliasError(w, err, "list zone aliases") {
+		return
+	}
+	writeJSON(w, http.StatusOK, aliases)
+}
+
+// PUT /api/admin/zone-aliases/{alias}
+// {"zone_id": "NWAC_10"}
+func (h *ZoneHandler) SetAlias(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ZoneID string `json:"zone_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	zoneID, err := domain.ParseZoneID(req.ZoneID)
+	if err != nil {
+		http.Error(w, "invalid zone_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	alias, err := h.zones.SetAlias(r.Context(), r.PathValue("alias"), zoneID)
+	if !zoneAliasError(w, err, "save zone alias") {
+		return
+	}
+	writeJSON(w, http.StatusOK, alias)
+}
+
+// DELETE /api/admin/zone-aliases/{alias}
+func (h *ZoneHandler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	if !zoneAliasError(w, h.zones.DeleteAlias(r.Context(), r.PathValue("alias")), "delete zone alias") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/admin/zones/renumber
+// {"from": "NWAC_164", "to": "NWAC_1646"}
+func (h *ZoneHandler) Renumber(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	zones, ok := parseZoneIDs(w, []string{req.From, req.To})
+	if !ok {
+		return
+	}
+	moved, err := h.zones.Renumber(r.Context(), zones[0], zones[1])
+	if !zoneAliasError(w, err, "renumber zone") {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"from": zones[0].String(), "to": zones[1].String(), "subscriptions_moved": moved})
+}
+
+// zoneAliasError writes the response for a failed alias operation and reports
+// whether err was nil.
+func zoneAliasError(w http.ResponseWriter, err error, action string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidZoneAlias):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrZoneAliasNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		log.Printf("[ZoneHandler] failed to %s: %v", action, err)
+		http.Error(w, "failed to "+action, http.StatusInternalServerError)
+	}
+	return false
+}
+
+// resolveZone turns zone input given as param into a zone ID. Without a
+// resolver only upstream IDs are accepted. Unresolved input answers 400 with
+// the closest zones as suggestions.
+func resolveZone(w http.ResponseWriter, r *http.Request, zones ZoneResolver, raw, param string) (*domain.ZoneID, bool) {
+	if zones == nil {
+		zoneID, err := domain.ParseZoneID(raw)
+		if err != nil {
+			http.Error(w, "invalid "+param+": "+err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		return zoneID, true
+	}
+	zoneID, err := zones.Resolve(r.Context(), raw)
+	var unresolved *services.UnresolvedZoneError
+	switch {
+	case err == nil:
+		return zoneID, true
+	case errors.As(err, &unresolved):
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid " + param + ": " + err.Error(), "suggestions": unresolved.Suggestions})
+	default:
+		log.Printf("[ZoneHandler] failed to resolve %q: %v", raw, err)
+		http.Error(w, "failed to resolve "+param, http.StatusInternalServerError)
+	}
+	return nil, false
+}
+
+// resolveZoneList resolves a comma-separated list of zones, as given in query
+// filters, and returns their canonical IDs in the same form.
+func resolveZoneList(w http.ResponseWriter, r *http.Request, zones ZoneResolver, raw, param string) (string, bool) {
+	if zones == nil || strings.TrimSpace(raw) == "" {
+		return raw, true
+	}
+	var ids []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		zoneID, ok := resolveZone(w, r, zones, part, param)
+		if !ok {
+			return "", false
+		}
+		ids = append(ids, zoneID.String())
+	}
+	return strings.Join(ids, ","), true
+}
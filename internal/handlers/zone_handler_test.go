@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/handlers"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+)
+
+type stubZoneCatalog struct {
+	renumbered [2]string
+}
+
+func (s *stubZoneCatalog) Resolve(ctx context.Context, raw string) (*domain.ZoneID, error) {
+	if strings.EqualFold(raw, "NWAC/Mt Hood") {
+		return domain.ParseZoneID("NWAC_11")
+	}
+	return nil, &services.UnresolvedZoneError{Input: raw, Suggestions: []services.ZoneSuggestion{
+		{ZoneCatalogEntry: services.ZoneCatalogEntry{ZoneID: "NWAC_11", Name: "Mt Hood", Center: "NWAC"}, Score: 0.6},
+	}}
+}
+
+func (s *stubZoneCatalog) Catalog() ([]services.ZoneCatalogEntry, error) {
+	return []services.ZoneCatalogEntry{{ZoneID: "NWAC_11", Name: "Mt Hood", Center: "NWAC"}}, nil
+}
+
+func (s *stubZoneCatalog) ListAliases(ctx context.Context) ([]models.ZoneAlias, error) {
+	return nil, nil
+}
+
+func (s *stubZoneCatalog) SetAlias(ctx context.Context, alias string, zoneID *domain.ZoneID) (*models.ZoneAlias, error) {
+	if alias == "NWAC_10" {
+		return nil, services.ErrInvalidZoneAlias
+	}
+	return &models.ZoneAlias{Alias: alias, ZoneID: zoneID.String()}, nil
+}
+
+func (s *stubZoneCatalog) DeleteAlias(ctx context.Context, alias string) error {
+	return services.ErrZoneAliasNotFound
+}
+
+func (s *stubZoneCatalog) Renumber(ctx context.Context, from, to *domain.ZoneID) (int64, error) {
+	s.renumbered = [2]string{from.String(), to.String()}
+	return 3, nil
+}
+
+func TestZoneHandler(t *testing.T) {
+	zones := &stubZoneCatalog{}
+	h := handlers.NewZoneHandler(zones)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/zones", h.ListZones)
+	mux.HandleFunc("GET /api/zones/resolve", h.ResolveZone)
+	mux.HandleFunc("PUT /api/admin/zone-aliases/{alias}", h.SetAlias)
+	mux.HandleFunc("DELETE /api/admin/zone-aliases/{alias}", h.DeleteAlias)
+	mux.HandleFunc("POST /api/admin/zones/renumber", h.Renumber)
+
+	for _, tt := range []struct {
+		method, path, body string
+		want               int
+		contains           string
+	}{
+		{http.MethodGet, "/api/zones", "", http.StatusOK, `"zone_id":"NWAC_11"`},
+		{http.MethodGet, "/api/zones/resolve?q=NWAC%2FMt+Hood", "", http.StatusOK, `{"zone_id":"NWAC_11"}`},
+		{http.MethodGet, "/api/zones/resolve?q=hod", "", http.StatusNotFound, `"suggestions":[{"zone_id":"NWAC_11","name":"Mt Hood","center":"NWAC","score":0.6}]`},
+		{http.MethodGet, "/api/zones/resolve", "", http.StatusBadRequest, ""},
+		{http.MethodPut, "/api/admin/zone-aliases/hood", `{"zone_id":"NWAC_11"}`, http.StatusOK, `"alias":"hood"`},
+		{http.MethodPut, "/api/admin/zone-aliases/NWAC_10", `{"zone_id":"NWAC_11"}`, http.StatusBadRequest, ""},
+		{http.MethodDelete, "/api/admin/zone-aliases/hood", "", http.StatusNotFound, ""},
+		{http.MethodPost, "/api/admin/zones/renumber", `{"from":"NWAC_164","to":"NWAC_1646"}`, http.StatusOK, `"subscriptions_moved":3`},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != tt.want || !strings.Contains(rec.Body.String(), tt.contains) {
+			t.Errorf("%s %s: expected %d containing %s, got %d: %s", tt.method, tt.path, tt.want, tt.contains, rec.Code, rec.Body.String())
+		}
+	}
+	if zones.renumbered != [2]string{"NWAC_164", "NWAC_1646"} {
+		t.Errorf("unexpected renumbering %v", zones.renumbered)
+	}
+}
+
+func TestPushHandler_ResolvesZoneNames(t *testing.T) {
+	h := handlers.NewPushHandler(nil, "public-key")
+	h.SetZoneResolver(&stubZoneCatalog{})
+
+	rec := httptest.NewRecorder()
+	h.Subscribe(rec, httptest.NewRequest(http.MethodPost, "/api/push/subscriptions", strings.NewReader(`{"zone_id":"hod"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var body struct {
+		Error       string                    `json:"error"`
+		Suggestions []services.ZoneSuggestion `json:"suggestions"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || len(body.Suggestions) != 1 || !strings.HasPrefix(body.Error, "invalid zone_id") {
+		t.Errorf("expected suggestions, got %+v %v", body, err)
+	}
+}
+
+func TestZoneFilters_ResolveZoneNames(t *testing.T) {
+	exp := &stubExporter{}
+	export := handlers.NewExportHandler(exp)
+	export.SetZoneResolver(&stubZoneCatalog{})
+
+	rec := httptest.NewRecorder()
+	export.ExportForecasts(rec, httptest.NewRequest(http.MethodGet, "/api/export/forecasts?zones=NWAC%2FMt+Hood", nil))
+	if rec.Code != http.StatusOK || len(exp.filter.ZoneIDs) != 1 || exp.filter.ZoneIDs[0] != "NWAC_11" {
+		t.Errorf("expected the export filter to resolve the zone name, got %d %+v", rec.Code, exp.filter)
+	}
+	rec = httptest.NewRecorder()
+	export.ExportForecasts(rec, httptest.NewRequest(http.MethodGet, "/api/export/forecasts?zones=hod", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "suggestions") {
+		t.Errorf("expected suggestions for an unknown zone, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	badges := handlers.NewBadgeHandler(&stubBadges{})
+	badges.SetZoneResolver(&stubZoneCatalog{})
+	req := httptest.NewRequest(http.MethodGet, "/api/zones/x/badge.svg", nil)
+	req.SetPathValue("zoneID", "NWAC/Mt Hood")
+	rec = httptest.NewRecorder()
+	badges.Badge(rec, req)
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "NWAC_11") {
+		t.Errorf("expected the badge zone to resolve to NWAC_11, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
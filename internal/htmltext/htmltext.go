@@ -0,0 +1,436 @@
+// Package htmltext sanitizes the HTML fragments avalanche centers publish in
+// bottom lines and danger messages, and converts them to plain text and
+// Markdown for channels that cannot show HTML.
+package htmltext
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// allowedTags are the elements Sanitize keeps. Other elements are removed but
+// their text is kept, except for droppedTags, whose content is removed too.
+var allowedTags = map[string]bool{
+	"p": true, "br": true, "b": true, "strong": true, "i": true, "em": true, "u": true,
+	"ul": true, "ol": true, "li": true, "a": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "textarea": true, "title": true, "head": true, "svg": true, "math": true, "select": true,
+}
+
+var voidTags = map[string]bool{
+	"br": true, "hr": true, "img": true, "input": true, "meta": true, "link": true, "wbr": true,
+	"area": true, "base": true, "col": true, "embed": true, "source": true, "track": true, "param": true,
+}
+
+// blockTags separate paragraphs in text and Markdown output.
+var blockTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "blockquote": true, "table": true,
+	"ul": true, "ol": true, "hr": true, "pre": true, "figure": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// sanitizeAliases are removed elements Sanitize replaces with an allowed one.
+var sanitizeAliases = map[string]string{"div": "p"}
+
+var urlSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// Sanitize returns fragment reduced to an allowlist of formatting elements:
+// paragraphs, line breaks, emphasis, lists, headings, block quotes and links
+// to http, https and mailto URLs. All other attributes are removed and
+// unclosed elements are closed.
+func Sanitize(fragment string) string {
+	var b strings.Builder
+	var open []string
+	skip := 0
+	for _, t := range tokenize(fragment) {
+		if alias, ok := sanitizeAliases[t.data]; ok && t.typ != textToken {
+			t.data = alias
+		}
+		switch t.typ {
+		case textToken:
+			if skip == 0 {
+				b.WriteString(html.EscapeString(html.UnescapeString(t.data)))
+			}
+		case startTag:
+			if droppedTags[t.data] {
+				if !voidTags[t.data] && !t.selfClosing {
+					skip++
+				}
+				continue
+			}
+			if skip > 0 || !allowedTags[t.data] {
+				continue
+			}
+			if t.data == "br" {
+				b.WriteString("<br>")
+				continue
+			}
+			b.WriteString("<" + t.data)
+			if href, ok := safeURL(t.attrs["href"]); ok && t.data == "a" {
+				b.WriteString(` href="` + html.EscapeString(href) + `" rel="nofollow noopener"`)
+			}
+			b.WriteString(">")
+			open = append(open, t.data)
+		case endTag:
+			if droppedTags[t.data] {
+				skip = max(skip-1, 0)
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == t.data {
+					for j := len(open) - 1; j >= i; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Text converts fragment to plain text. Paragraphs are separated by blank
+// lines, list items start with "- " or their number, and link targets follow
+// the link text in parentheses.
+func Text(fragment string) string {
+	return render(fragment, &writer{links: true})
+}
+
+// Markdown converts fragment to CommonMark, keeping emphasis, lists,
+// headings and links. When limit is positive, the text is truncated to limit
+// runes, not counting Markdown syntax, and emphasis and links open at the cut
+// are closed.
+func Markdown(fragment string, limit int) string {
+	w := &writer{markdown: true}
+	out := render(fragment, w)
+	if limit <= 0 || w.visible <= limit {
+		return out
+	}
+	return render(fragment, &writer{markdown: true, limit: limit - 1})
+}
+
+// Line converts fragment to a single line of plain text without link
+// targets, truncated to limit runes when limit is positive.
+func Line(fragment string, limit int) string {
+	return Truncate(strings.Join(strings.Fields(render(fragment, &writer{})), " "), limit)
+}
+
+// Truncate shortens s to limit runes, ending it with an ellipsis, when limit
+// is positive and s is longer.
+func Truncate(s string, limit int) string {
+	if r := []rune(s); limit > 0 && len(r) > limit {
+		return strings.TrimSpace(string(r[:limit-1])) + "…"
+	}
+	return s
+}
+
+func render(fragment string, w *writer) string {
+	skip := 0
+	for _, t := range tokenize(fragment) {
+		switch {
+		case droppedTags[t.data] && t.typ == startTag:
+			if !voidTags[t.data] && !t.selfClosing {
+				skip++
+			}
+		case droppedTags[t.data] && t.typ == endTag:
+			skip = max(skip-1, 0)
+		case skip > 0:
+		case t.typ == textToken:
+			w.text(html.UnescapeString(t.data))
+		case t.typ == startTag:
+			w.start(t)
+		case t.typ == endTag:
+			w.end(t.data)
+		}
+	}
+	w.closeMarks(0)
+	lines := strings.Split(w.b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+type list struct {
+	ordered bool
+	n       int
+}
+
+type link struct {
+	href  string
+	start int
+}
+
+// mark is an open Markdown emphasis or link.
+type mark struct {
+	name             string
+	opening, closing string
+}
+
+// writer accumulates text, deferring whitespace and line breaks until the
+// next word so that neither leads nor trails a block.
+type writer struct {
+	b        strings.Builder
+	markdown bool
+	links    bool
+	space    bool
+	breaks   int
+	// prefix is the pending list item marker.
+	prefix string
+	// pending holds Markdown markers opened since the last word; they are
+	// written before the next word, or dropped if the element is empty.
+	pending string
+	lists   []list
+	anchors []link
+	marks   []mark
+	// visible counts the runes of text written. When limit is positive, text
+	// past limit runes is replaced by an ellipsis and truncated is set.
+	limit     int
+	visible   int
+	truncated bool
+}
+
+func (w *writer) flush() {
+	switch {
+	case w.b.Len() == 0:
+	case w.breaks > 0:
+		w.b.WriteString(strings.Repeat("\n", w.breaks))
+	case w.space:
+		w.b.WriteByte(' ')
+	}
+	w.breaks, w.space = 0, false
+}
+
+func (w *writer) block(n int) {
+	if w.prefix != "" {
+		return
+	}
+	w.breaks = max(w.breaks, n)
+	w.space = false
+}
+
+func (w *writer) text(s string) {
+	if s == "" {
+		return
+	}
+	if strings.TrimLeftFunc(s, unicode.IsSpace) != s {
+		w.space = true
+	}
+	for i, word := range strings.Fields(s) {
+		if w.truncated {
+			return
+		}
+		if i > 0 {
+			w.space = true
+		}
+		if !w.fit(&word) {
+			return
+		}
+		w.flush()
+		w.b.WriteString(w.prefix + w.pending)
+		w.prefix, w.pending = "", ""
+		if w.markdown {
+			word = escapeMarkdown(word)
+		}
+		w.b.WriteString(word)
+		if w.truncated {
+			w.b.WriteString("…")
+			return
+		}
+	}
+	if strings.TrimRightFunc(s, unicode.IsSpace) != s {
+		w.space = true
+	}
+}
+
+// fit counts word, with the space before it, against the limit. A word that
+// does not fit is cut to the room left; fit reports false when none is left,
+// after writing the ellipsis.
+func (w *writer) fit(word *string) bool {
+	sep := 0
+	if w.b.Len() > 0 && (w.space || w.breaks > 0) {
+		sep = 1
+	}
+	n := utf8.RuneCountInString(*word)
+	if w.limit <= 0 || w.visible+sep+n <= w.limit {
+		w.visible += sep + n
+		return true
+	}
+	w.truncated = true
+	room := w.limit - w.visible - sep
+	if room <= 0 {
+		w.b.WriteString("…")
+		return false
+	}
+	*word = string([]rune(*word)[:room])
+	return true
+}
+
+// open defers marker until the element's first word.
+func (w *writer) open(marker string) {
+	if w.markdown {
+		w.pending += marker
+	}
+}
+
+// close writes marker, or drops the element's opening marker when no word
+// followed it.
+func (w *writer) close(opening, marker string) bool {
+	if !w.markdown {
+		return true
+	}
+	if strings.HasSuffix(w.pending, opening) {
+		w.pending = strings.TrimSuffix(w.pending, opening)
+		return false
+	}
+	w.b.WriteString(marker)
+	return true
+}
+
+// openMark opens an emphasis or link, closed by closeMark or at the end of
+// the fragment.
+func (w *writer) openMark(name, opening, closing string) {
+	if w.markdown {
+		w.open(opening)
+		w.marks = append(w.marks, mark{name: name, opening: opening, closing: closing})
+	}
+}
+
+// closeMark closes the innermost open name and the marks opened inside it.
+// End tags with nothing open are ignored.
+func (w *writer) closeMark(name string) {
+	for i := len(w.marks) - 1; i >= 0; i-- {
+		if w.marks[i].name == name {
+			w.closeMarks(i)
+			return
+		}
+	}
+}
+
+// closeMarks closes the marks from the innermost down to marks[i].
+func (w *writer) closeMarks(i int) {
+	for j := len(w.marks) - 1; j >= i; j-- {
+		w.close(w.marks[j].opening, w.marks[j].closing)
+	}
+	w.marks = w.marks[:i]
+}
+
+func (w *writer) paragraph() int {
+	if len(w.lists) > 0 {
+		return 1
+	}
+	return 2
+}
+
+func (w *writer) start(t token) {
+	switch name := t.data; {
+	case name == "br":
+		w.block(1)
+	case name == "li":
+		w.prefix = ""
+		w.block(1)
+		w.prefix = "- "
+		if n := len(w.lists); n > 0 {
+			l := &w.lists[n-1]
+			if l.ordered {
+				l.n++
+				w.prefix = fmt.Sprintf("%d. ", l.n)
+			}
+			w.prefix = strings.Repeat("  ", n-1) + w.prefix
+		}
+	case name == "ul" || name == "ol":
+		w.block(w.paragraph())
+		w.lists = append(w.lists, list{ordered: name == "ol"})
+	case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+		w.block(2)
+		w.open(strings.Repeat("#", int(name[1]-'0')) + " ")
+	case blockTags[name]:
+		w.block(w.paragraph())
+	case name == "tr":
+		w.block(1)
+	case name == "td" || name == "th":
+		w.space = true
+	case name == "b" || name == "strong":
+		w.openMark("b", "**", "**")
+	case name == "i" || name == "em":
+		w.openMark("i", "_", "_")
+	case name == "a":
+		href, _ := safeURL(t.attrs["href"])
+		w.anchors = append(w.anchors, link{href: href, start: w.b.Len()})
+		if href != "" {
+			w.openMark("a", "[", "]("+href+")")
+		} else {
+			w.openMark("a", "", "")
+		}
+	}
+}
+
+func (w *writer) end(name string) {
+	switch {
+	case name == "ul" || name == "ol":
+		if n := len(w.lists); n > 0 {
+			w.lists = w.lists[:n-1]
+		}
+		w.block(w.paragraph())
+	case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+		w.close(strings.Repeat("#", int(name[1]-'0'))+" ", "")
+		w.block(2)
+	case blockTags[name]:
+		w.block(w.paragraph())
+	case name == "b" || name == "strong":
+		w.closeMark("b")
+	case name == "i" || name == "em":
+		w.closeMark("i")
+	case name == "a":
+		w.closeMark("a")
+		n := len(w.anchors)
+		if n == 0 {
+			return
+		}
+		a := w.anchors[n-1]
+		w.anchors = w.anchors[:n-1]
+		if a.href == "" || w.markdown {
+			return
+		}
+		label := strings.TrimSpace(w.b.String()[a.start:])
+		if w.links && label != a.href && "mailto:"+label != a.href {
+			w.space = true
+			w.flush()
+			w.b.WriteString("(" + a.href + ")")
+			w.space = true
+		}
+	}
+}
+
+// safeURL returns href when it is an absolute URL with an allowed scheme.
+func safeURL(href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	u, err := url.Parse(href)
+	if err != nil || !urlSchemes[strings.ToLower(u.Scheme)] {
+		return "", false
+	}
+	return href, true
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`")
+
+func escapeMarkdown(s string) string { return markdownEscaper.Replace(s) }
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,84 @@
+package htmltext_test
+
+import (
+	"testing"
+
+	"example.com/avalanche/internal/htmltext"
+)
+
+const bottomLine = `<p>Wind slabs on <strong>north</strong> aspects&nbsp;near ridgelines.</p>` +
+	`<ul><li>Avoid <em>cornices</em></li><li>See <a href="https://nwac.us/obs" onclick="x()">observations</a></li></ul>` +
+	`<script>alert("x")</script><p>Stay <b></b>safe &amp; have fun</p>`
+
+func TestSanitize(t *testing.T) {
+	for in, want := range map[string]string{
+		bottomLine: "<p>Wind slabs on <strong>north</strong> aspects\u00a0near ridgelines.</p>" +
+			`<ul><li>Avoid <em>cornices</em></li><li>See <a href="https://nwac.us/obs" rel="nofollow noopener">observations</a></li></ul>` +
+			`<p>Stay <b></b>safe &amp; have fun</p>`,
+		`<a href="javascript:alert(1)">x</a>`:                    `<a>x</a>`,
+		`<p style="color:red" class="x">open <i>tags`:            `<p>open <i>tags</i></p>`,
+		`<div>one</div><img src=x onerror=alert(1)>two`:          `<p>one</p>two`,
+		`<iframe src="https://evil"><p>hidden</p></iframe>shown`: `shown`,
+		`1 < 2 <!-- note --> <STYLE>p{}</STYLE>and </b>3`:        `1 &lt; 2  and 3`,
+		`<p>unterminated <a href="x`:                             `<p>unterminated </p>`,
+	} {
+		if got := htmltext.Sanitize(in); got != want {
+			t.Errorf("Sanitize(%q)\n got %q\nwant %q", in, got, want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	want := "Wind slabs on north aspects near ridgelines.\n\n" +
+		"- Avoid cornices\n- See observations (https://nwac.us/obs)\n\n" +
+		"Stay safe & have fun"
+	if got := htmltext.Text(bottomLine); got != want {
+		t.Errorf("Text\n got %q\nwant %q", got, want)
+	}
+	if got := htmltext.Text(`<ol><li><p>first</p></li><li>second<br>line</li></ol><a href="https://a.b">https://a.b</a>`); got != "1. first\n2. second\nline\n\nhttps://a.b" {
+		t.Errorf("unexpected ordered list text %q", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	want := "Wind slabs on **north** aspects near ridgelines.\n\n" +
+		"- Avoid _cornices_\n- See [observations](https://nwac.us/obs)\n\n" +
+		"Stay safe & have fun"
+	if got := htmltext.Markdown(bottomLine, 0); got != want {
+		t.Errorf("Markdown\n got %q\nwant %q", got, want)
+	}
+	if got := htmltext.Markdown(`<h2>Storm_slab *warning*</h2><p><strong> Large </strong>slides</p>`, 0); got != "## Storm\\_slab \\*warning\\*\n\n**Large** slides" {
+		t.Errorf("unexpected heading markdown %q", got)
+	}
+}
+
+func TestMarkdown_ClosesMarkup(t *testing.T) {
+	for _, tt := range []struct {
+		fragment string
+		limit    int
+		want     string
+	}{
+		{"<b>x", 0, "**x**"},
+		{`<i>a <a href="https://nwac.us">b`, 0, "_a [b](https://nwac.us)_"},
+		{"<b>bold <i>both</b> plain</i>", 0, "**bold _both_** plain"},
+		{"plain</b> text", 0, "plain text"},
+		{"<p>Wind <b>slabs on</b> north</p>", 11, "Wind **slabs…**"},
+		{`<p>See <a href="https://nwac.us/obs">the observations</a></p>`, 10, "See [the o…](https://nwac.us/obs)"},
+		{"<p>Wind <b>slabs</b></p>", 10, "Wind **slabs**"},
+		{"<p>Wind <b>slabs</b> today</p>", 10, "Wind **slab…**"},
+		{"<p>Wind</p><p><b>slabs</b></p>", 5, "Wind…"},
+	} {
+		if got := htmltext.Markdown(tt.fragment, tt.limit); got != tt.want {
+			t.Errorf("Markdown(%q, %d) = %q, want %q", tt.fragment, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+	if got := htmltext.Line(bottomLine, 0); got != "Wind slabs on north aspects near ridgelines. - Avoid cornices - See observations Stay safe & have fun" {
+		t.Errorf("unexpected line %q", got)
+	}
+	if got := htmltext.Line("<p>Considerable danger today</p>", 20); got != "Considerable danger…" {
+		t.Errorf("unexpected truncation %q", got)
+	}
+}
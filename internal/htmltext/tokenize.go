@@ -0,0 +1,165 @@
+package htmltext
+
+import (
+	"html"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	textToken tokenType = iota
+	startTag
+	endTag
+)
+
+type token struct {
+	typ         tokenType
+	data        string // text, or the lower-cased tag name
+	attrs       map[string]string
+	selfClosing bool
+}
+
+// rawTextTags hold text up to their end tag, even when it looks like markup.
+var rawTextTags = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+	"xmp": true, "iframe": true, "noembed": true, "noframes": true,
+}
+
+// tokenize splits an HTML fragment into text and tags. Comments, doctypes
+// and processing instructions are dropped, as is a tag left unterminated at
+// the end of the input. Text is returned still escaped.
+func tokenize(s string) []token {
+	var tokens []token
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt != 0 {
+			if lt < 0 {
+				lt = len(s)
+			}
+			tokens = append(tokens, token{typ: textToken, data: s[:lt]})
+			s = s[lt:]
+			continue
+		}
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				return tokens
+			}
+			s = s[4+end+3:]
+		case len(s) > 1 && (s[1] == '!' || s[1] == '?'):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return tokens
+			}
+			s = s[end+1:]
+		case len(s) > 2 && s[1] == '/' && isLetter(s[2]):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return tokens
+			}
+			name, _ := splitName(s[2:end])
+			tokens = append(tokens, token{typ: endTag, data: name})
+			s = s[end+1:]
+		case len(s) > 1 && isLetter(s[1]):
+			t, n, ok := parseStartTag(s[1:])
+			if !ok {
+				return tokens
+			}
+			tokens = append(tokens, t)
+			s = s[1+n:]
+			if rawTextTags[t.data] && !t.selfClosing {
+				end := indexFold(s, "</"+t.data)
+				if end < 0 {
+					end = len(s)
+				}
+				tokens = append(tokens, token{typ: textToken, data: s[:end]})
+				s = s[end:]
+			}
+		default:
+			tokens = append(tokens, token{typ: textToken, data: "&lt;"})
+			s = s[1:]
+		}
+	}
+	return tokens
+}
+
+// parseStartTag parses s, which follows the opening '<', up to and including
+// the closing '>', and returns the number of bytes consumed.
+func parseStartTag(s string) (token, int, bool) {
+	name, i := splitName(s)
+	t := token{typ: startTag, data: name}
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '>':
+			return t, i + 1, true
+		case c == '/':
+			t.selfClosing = i+1 < len(s) && s[i+1] == '>'
+			i++
+		case isSpace(c):
+			i++
+		default:
+			start := i
+			for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+				i++
+			}
+			key := strings.ToLower(s[start:i])
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			var val string
+			if i < len(s) && s[i] == '=' {
+				i++
+				for i < len(s) && isSpace(s[i]) {
+					i++
+				}
+				if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+					q := s[i]
+					end := strings.IndexByte(s[i+1:], q)
+					if end < 0 {
+						return t, 0, false
+					}
+					val = s[i+1 : i+1+end]
+					i += end + 2
+				} else {
+					start := i
+					for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+						i++
+					}
+					val = s[start:i]
+				}
+			}
+			if t.attrs == nil {
+				t.attrs = map[string]string{}
+			}
+			if _, dup := t.attrs[key]; !dup {
+				t.attrs[key] = html.UnescapeString(val)
+			}
+		}
+	}
+	return t, 0, false
+}
+
+// splitName returns the lower-cased tag name at the start of s and its length.
+func splitName(s string) (string, int) {
+	i := 0
+	for i < len(s) && !isSpace(s[i]) && s[i] != '/' && s[i] != '>' {
+		i++
+	}
+	return strings.ToLower(s[:i]), i
+}
+
+// indexFold returns the index of the ASCII string substr in s, ignoring case.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
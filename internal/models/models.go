@@ -1,9 +1,13 @@
 package models
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
 	"time"
 
 	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/htmltext"
 )
 
 // AvalancheCenter represents a specific avalanche forecasting center,
@@ -27,6 +31,7 @@ func (AvalancheCenter) TableName() string { return "avalanche_centers" }
 type Forecast struct {
 	AvalancheCenter AvalancheCenter `json:"avalanche_center"`
 	ID              int             `json:"id"`
+	ProductType     string          `json:"product_type,omitempty"`
 	StartDate       time.Time       `json:"start_date"`
 	EndDate         time.Time       `json:"end_date"`
 	PublishedTime   time.Time       `json:"published_time"`
@@ -35,6 +40,17 @@ type Forecast struct {
 	DangerLevelText string          `json:"danger_level_text"`
 	ForecastZone    []Zone          `json:"forecast_zone"`
 	Danger          []DangerRating  `json:"danger"`
+	// Problems is only included when a single product is fetched.
+	Problems []AvalancheProblem `json:"forecast_avalanche_problems,omitempty"`
+}
+
+// AvalancheProblem is one of a forecast's avalanche problems. Location lists
+// the aspect and elevation band pairs where it occurs, such as "north upper".
+type AvalancheProblem struct {
+	Name       string   `json:"name"`
+	Rank       int      `json:"rank"`
+	Likelihood string   `json:"likelihood"`
+	Location   []string `json:"location"`
 }
 
 // Zone represents an individual geographic forecast zone within an avalanche center's coverage area.
@@ -49,11 +65,30 @@ type Zone struct {
 // ValidDay typically corresponds to "current" or "tomorrow".
 // Elevation-specific ratings are provided for upper, middle, and lower elevation bands.
 type DangerRating struct {
-	Upper    int    `json:"upper"`
-	Middle   int    `json:"middle"`
-	Lower    int    `json:"lower"`
-	ValidDay string `json:"valid_day"`
-	Message  string `json:"message,omitempty"`
+	Upper    domain.DangerLevel `json:"upper"`
+	Middle   domain.DangerLevel `json:"middle"`
+	Lower    domain.DangerLevel `json:"lower"`
+	ValidDay string             `json:"valid_day"`
+	Message  string             `json:"message,omitempty"`
+}
+
+// Max returns the highest band rating, or DangerNoRating when d is nil.
+func (d *DangerRating) Max() domain.DangerLevel {
+	if d == nil {
+		return domain.DangerNoRating
+	}
+	return domain.MaxDanger(d.Upper, d.Middle, d.Lower)
+}
+
+// MarshalJSON adds the scale name of each band rating next to its number.
+func (d DangerRating) MarshalJSON() ([]byte, error) {
+	type rating DangerRating
+	return json.Marshal(struct {
+		rating
+		UpperName  string `json:"upper_name"`
+		MiddleName string `json:"middle_name"`
+		LowerName  string `json:"lower_name"`
+	}{rating(d), d.Upper.Name(), d.Middle.Name(), d.Lower.Name()})
 }
 
 // ZoneForecast represents the processed and normalized forecast for a single avalanche zone.
@@ -63,21 +98,109 @@ type ZoneForecast struct {
 	ZoneID       string        `json:"zone_id"`
 	ZoneName     string        `json:"zone_name"`
 	Center       string        `json:"center"`
+	ProductID    int           `json:"product_id,omitempty"`
+	URL          string        `json:"url,omitempty"`
 	IssuedTime   string        `json:"issued_time"`
 	StartDate    string        `json:"start_date"`
 	EndDate      string        `json:"end_date"`
 	BottomLine   string        `json:"bottom_line"`
 	TodayDanger  *DangerRating `json:"today_danger,omitempty"`
 	FutureDanger *DangerRating `json:"future_danger,omitempty"`
+
+	// BottomLineHTML is the sanitized bottom line, which BottomLine also
+	// holds for existing clients, and BottomLineText its plain-text form.
+	BottomLineHTML string `json:"bottom_line_html"`
+	BottomLineText string `json:"bottom_line_text"`
 }
 
+// SafeBottomLine returns the bottom line as sanitized HTML, sanitizing
+// BottomLine for forecasts not built by the forecast service.
+func (zf *ZoneForecast) SafeBottomLine() string {
+	if zf.BottomLineHTML != "" {
+		return zf.BottomLineHTML
+	}
+	return htmltext.Sanitize(zf.BottomLine)
+}
+
+// PlainBottomLine returns the bottom line as plain text.
+func (zf *ZoneForecast) PlainBottomLine() string {
+	if zf.BottomLineText != "" {
+		return zf.BottomLineText
+	}
+	return htmltext.Text(zf.BottomLine)
+}
+
+// Notification channels a subscription can target.
+const (
+	ChannelEmail      = "email"
+	ChannelSlack      = "slack"
+	ChannelDiscord    = "discord"
+	ChannelMattermost = "mattermost"
+	ChannelSMS        = "sms"
+	ChannelSlackApp   = "slack_app"
+	ChannelWebPush    = "webpush"
+)
+
+// Subscription links a zone to a notification target. Email subscriptions use
+// Email; other channels (chat webhooks, SMS, Slack channel IDs, ...) store
+// their address in Target. Web Push subscriptions keep the push endpoint in
+// Target and its encryption keys in PushKeys.
+// Digest subscriptions are notified at most once per DigestInterval.
 type Subscription struct {
 	ID           uint       `json:"id,omitempty" gorm:"primaryKey"`
 	ZoneID       string     `json:"zone_id" gorm:"index;not null"`
-	Email        string     `json:"email" gorm:"index;not null"`
+	Email        string     `json:"email,omitempty" gorm:"index"`
+	Channel      string     `json:"channel" gorm:"not null;default:email"`
+	Target       string     `json:"-" gorm:"index"`
 	LastNotified *time.Time `json:"last_notified,omitempty"`
-	CreatedAt    time.Time  `json:"created_at,omitempty"`
-	UpdatedAt    time.Time  `json:"updated_at,omitempty"`
+	PausedAt     *time.Time `json:"paused_at,omitempty"`
+	PausedUntil  *time.Time `json:"paused_until,omitempty"`
+	PauseReason  string     `json:"pause_reason,omitempty"`
+	Digest       bool       `json:"digest" gorm:"not null;default:false"`
+	PushKeys     *PushKeys  `json:"-" gorm:"serializer:json"`
+	// OrganizationID is set on subscriptions an organization manages for its
+	// members; they are removed with the membership.
+	OrganizationID *uint     `json:"organization_id,omitempty" gorm:"index"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+// PushKeys holds the encryption keys of a browser push subscription.
+type PushKeys struct {
+	P256DH string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// Reasons a subscription may be paused.
+const (
+	// PauseReasonSuppressed marks subscriptions paused because their address is on the suppression list.
+	PauseReasonSuppressed = "suppressed"
+	// PauseReasonUnverified marks SMS subscriptions waiting for phone verification.
+	PauseReasonUnverified = "unverified"
+	// PauseReasonStopped marks SMS subscriptions paused because the subscriber texted STOP.
+	PauseReasonStopped = "stopped"
+	// PauseReasonRequested marks subscriptions the subscriber paused for a while.
+	PauseReasonRequested = "requested"
+)
+
+// DigestInterval is the minimum time between notifications for digest subscriptions.
+const DigestInterval = 24 * time.Hour
+
+// ChannelOrDefault returns the subscription channel, treating empty as email.
+func (s *Subscription) ChannelOrDefault() string {
+	if s.Channel == "" {
+		return ChannelEmail
+	}
+	return s.Channel
+}
+
+// IsPaused reports whether notifications for this subscription are on hold.
+// Pauses with a PausedUntil time lapse on their own.
+func (s *Subscription) IsPaused() bool {
+	if s.PausedAt == nil {
+		return false
+	}
+	return s.PausedUntil == nil || time.Now().Before(*s.PausedUntil)
 }
 
 // IsDueForNotification checks if enough time has passed since the last notification.
@@ -107,7 +230,12 @@ func (s *Subscription) GetCenterID() string {
 
 // IsCenterLevel returns true if this is a center-level subscription (no specific zone).
 func (s *Subscription) IsCenterLevel() bool {
-	return stringIndexByte(s.ZoneID, '_') == -1
+	return stringIndexByte(s.ZoneID, '_') == -1 && !s.IsGroup()
+}
+
+// IsGroup returns true if this subscription follows a zone group.
+func (s *Subscription) IsGroup() bool {
+	return strings.HasPrefix(s.ZoneID, domain.GroupPrefix)
 }
 
 // IsSpecificZone returns true if this subscription targets a specific zone within a center.
@@ -125,12 +253,381 @@ func stringIndexByte(s string, b byte) int {
 }
 
 // ForecastCache stores the last issued forecast time per zone for the notifier service.
+// ProductID lets the notifier tell a new product from an amendment of the same one.
 type ForecastCache struct {
 	ZoneID     string    `gorm:"primaryKey"`
 	LastIssued time.Time `gorm:"not null"`
+	ProductID  int
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
 
 // TableName overrides GORM's default pluralization for ForecastCache.
 func (ForecastCache) TableName() string { return "forecast_cache" }
+
+// Delivery statuses recorded for outbound notification attempts.
+const (
+	DeliveryStatusSent   = "sent"
+	DeliveryStatusFailed = "failed"
+)
+
+// EmailDeliveryAttempt records one try at handing an email to a provider.
+// Attempts sharing a MessageID belong to the same logical send, so the row with
+// status "sent" identifies the provider that delivered it.
+type EmailDeliveryAttempt struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	MessageID  string    `json:"message_id" gorm:"index;not null"`
+	Provider   string    `json:"provider" gorm:"not null"`
+	Recipient  string    `json:"recipient" gorm:"index;not null"`
+	Subject    string    `json:"subject"`
+	Status     string    `json:"status" gorm:"not null"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName overrides GORM's default pluralization for EmailDeliveryAttempt.
+func (EmailDeliveryAttempt) TableName() string { return "email_delivery_attempts" }
+
+// Suppression reasons recorded from provider event webhooks.
+const (
+	SuppressionBounce      = "bounce"
+	SuppressionDropped     = "dropped"
+	SuppressionSpamReport  = "spamreport"
+	SuppressionUnsubscribe = "unsubscribe"
+)
+
+// EmailSuppression marks an address that must not receive further email,
+// typically after a hard bounce or spam complaint reported by the provider.
+type EmailSuppression struct {
+	Email     string    `json:"email" gorm:"primaryKey"`
+	Reason    string    `json:"reason" gorm:"not null"`
+	Detail    string    `json:"detail,omitempty"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for EmailSuppression.
+func (EmailSuppression) TableName() string { return "email_suppressions" }
+
+// Forecast event types delivered to outbound webhooks.
+const (
+	EventForecastIssued  = "forecast.issued"
+	EventForecastUpdated = "forecast.updated"
+	EventWarningIssued   = "warning.issued"
+)
+
+// WebhookEventTypes lists every event type a webhook endpoint may subscribe to.
+var WebhookEventTypes = []string{EventForecastIssued, EventForecastUpdated, EventWarningIssued}
+
+// WebhookEndpoint is a partner URL that receives signed forecast events for a
+// zone ("NWAC_10") or every zone of a center ("NWAC").
+type WebhookEndpoint struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	URL    string `json:"url" gorm:"not null"`
+	Secret string `json:"-" gorm:"not null"`
+	ZoneID string `json:"zone_id" gorm:"index;not null"`
+	// Events limits delivery to these event types; empty means all.
+	Events              []string   `json:"events" gorm:"serializer:json"`
+	Active              bool       `json:"active" gorm:"not null;default:true"`
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for WebhookEndpoint.
+func (WebhookEndpoint) TableName() string { return "webhook_endpoints" }
+
+// Matches reports whether the endpoint wants eventType for zoneID.
+func (w WebhookEndpoint) Matches(eventType, zoneID string) bool {
+	center := zoneID
+	if idx := stringIndexByte(zoneID, '_'); idx != -1 {
+		center = zoneID[:idx]
+	}
+	if w.ZoneID != zoneID && w.ZoneID != center {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook endpoint.
+type WebhookDelivery struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EndpointID uint      `json:"endpoint_id" gorm:"index;not null"`
+	EventID    string    `json:"event_id" gorm:"index;not null"`
+	EventType  string    `json:"event_type" gorm:"not null"`
+	Attempt    int       `json:"attempt" gorm:"not null"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName overrides GORM's default pluralization for WebhookDelivery.
+func (WebhookDelivery) TableName() string { return "webhook_deliveries" }
+
+// PhoneVerification tracks the one-time code sent to confirm ownership of a
+// phone number before SMS notifications are delivered to it.
+type PhoneVerification struct {
+	Phone      string     `json:"phone" gorm:"primaryKey"`
+	CodeHash   string     `json:"-" gorm:"not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	Attempts   int        `json:"attempts" gorm:"not null;default:0"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for PhoneVerification.
+func (PhoneVerification) TableName() string { return "phone_verifications" }
+
+// IsVerified reports whether the phone number has been confirmed.
+func (p *PhoneVerification) IsVerified() bool {
+	return p != nil && p.VerifiedAt != nil
+}
+
+// ForecastEvent is an entry in the persisted log of forecast events, read by
+// the SSE stream. Seq orders the log and serves as the SSE event ID; Data
+// holds the event as JSON in the outbound webhook payload format.
+type ForecastEvent struct {
+	Seq       uint64    `json:"seq" gorm:"primaryKey;autoIncrement"`
+	EventID   string    `json:"event_id" gorm:"uniqueIndex;not null"`
+	Type      string    `json:"type" gorm:"not null"`
+	ZoneID    string    `json:"zone_id" gorm:"index;not null"`
+	CenterID  string    `json:"center_id" gorm:"index;not null"`
+	Data      string    `json:"data" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// ArchivedForecast is a forecast issuance kept for the Atom feeds, one row per
+// zone and product. Amendments to the same product update the row in place
+// and advance AmendedAt.
+type ArchivedForecast struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ZoneID      string    `json:"zone_id" gorm:"uniqueIndex:idx_archived_forecasts_zone_product;not null"`
+	ProductID   int       `json:"product_id" gorm:"uniqueIndex:idx_archived_forecasts_zone_product;not null"`
+	CenterID    string    `json:"center_id" gorm:"index;not null"`
+	ProductType string    `json:"product_type" gorm:"not null"`
+	ZoneName    string    `json:"zone_name"`
+	CenterName  string    `json:"center_name"`
+	Title       string    `json:"title" gorm:"not null"`
+	Link        string    `json:"link"`
+	Content     string    `json:"content" gorm:"not null"`
+	IssuedAt    time.Time `json:"issued_at" gorm:"not null"`
+	AmendedAt   time.Time `json:"amended_at" gorm:"index;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// ValidDate is the day the forecast covers as YYYY-MM-DD; the danger
+	// ratings and bottom line below are for that day.
+	ValidDate    string             `json:"valid_date" gorm:"index"`
+	ValidFrom    time.Time          `json:"valid_from"`
+	ValidUntil   time.Time          `json:"valid_until"`
+	DangerUpper  domain.DangerLevel `json:"danger_upper"`
+	DangerMiddle domain.DangerLevel `json:"danger_middle"`
+	DangerLower  domain.DangerLevel `json:"danger_lower"`
+	BottomLine   string             `json:"bottom_line"`
+}
+
+// ZoneElevationBands holds the elevations, in meters, separating a zone's
+// danger rating bands: below treeline up to NearTreelineM, near treeline up
+// to AboveTreelineM, and above treeline from there up.
+type ZoneElevationBands struct {
+	ZoneID         string    `json:"zone_id" gorm:"primaryKey"`
+	NearTreelineM  float64   `json:"near_treeline_m" gorm:"not null"`
+	AboveTreelineM float64   `json:"above_treeline_m" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for ZoneElevationBands.
+func (ZoneElevationBands) TableName() string { return "zone_elevation_bands" }
+
+// Trip plan notification outlooks: the next day's outlook in the evenings
+// before a trip and the day's rating on the morning of it.
+const (
+	TripOutlookTomorrow = "tomorrow"
+	TripOutlookToday    = "today"
+)
+
+// TripStep is one notification of a trip plan's countdown, sent at Hour local
+// time Day days from the trip date.
+type TripStep struct {
+	Day     int    `json:"day"`
+	Hour    int    `json:"hour"`
+	Outlook string `json:"outlook"`
+}
+
+// TripSchedule is the countdown sent for every trip plan: two evenings before
+// the trip and the morning of it.
+var TripSchedule = []TripStep{
+	{Day: -2, Hour: 18, Outlook: TripOutlookTomorrow},
+	{Day: -1, Hour: 18, Outlook: TripOutlookTomorrow},
+	{Day: 0, Hour: 6, Outlook: TripOutlookToday},
+}
+
+// TripPlan is a tour in one or more zones on TripDate. Participants receive
+// the TripSchedule countdown once they confirm; until then they are Pending.
+// The plan expires at the end of the trip day in Timezone. StepsSent counts
+// the schedule steps already handled.
+type TripPlan struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name,omitempty"`
+	ZoneIDs      []string  `json:"zone_ids" gorm:"serializer:json;not null"`
+	TripDate     string    `json:"trip_date" gorm:"not null"`
+	Timezone     string    `json:"timezone" gorm:"not null"`
+	Participants []string  `json:"participants" gorm:"serializer:json;not null"`
+	Pending      []string  `json:"-" gorm:"serializer:json;not null"`
+	StepsSent    int       `json:"steps_sent" gorm:"not null;default:0"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index;not null"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for TripPlan.
+func (TripPlan) TableName() string { return "trip_plans" }
+
+// Day returns midnight at the start of the trip date in the plan's timezone.
+func (p TripPlan) Day() (time.Time, error) {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(time.DateOnly, p.TripDate, loc)
+}
+
+// StepTime returns when TripSchedule[step] is due.
+func (p TripPlan) StepTime(step int) (time.Time, error) {
+	day, err := p.Day()
+	if err != nil {
+		return time.Time{}, err
+	}
+	s := TripSchedule[step]
+	return time.Date(day.Year(), day.Month(), day.Day()+s.Day, s.Hour, 0, 0, 0, day.Location()), nil
+}
+
+// DueStep returns the latest schedule step due at now that has not been
+// handled, or -1. Earlier steps that were missed are skipped in its favor.
+func (p TripPlan) DueStep(now time.Time) int {
+	if !now.Before(p.ExpiresAt) {
+		return -1
+	}
+	for i := len(TripSchedule) - 1; i >= p.StepsSent; i-- {
+		at, err := p.StepTime(i)
+		if err == nil && !at.After(now) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Organization member roles. Every organization keeps at least one owner.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// OrganizationRoles lists every role a member may hold.
+var OrganizationRoles = []string{RoleOwner, RoleAdmin, RoleMember}
+
+// Organization is a guide service, patrol or similar team whose members are
+// all subscribed by email to the organization's zones.
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	ZoneIDs   []string  `json:"zone_ids" gorm:"serializer:json;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for Organization.
+func (Organization) TableName() string { return "organizations" }
+
+// OrganizationMember is one person in an organization.
+type OrganizationMember struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organization_id" gorm:"uniqueIndex:idx_organization_members_org_email;not null"`
+	Email          string    `json:"email" gorm:"uniqueIndex:idx_organization_members_org_email;not null"`
+	Role           string    `json:"role" gorm:"not null;default:member"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for OrganizationMember.
+func (OrganizationMember) TableName() string { return "organization_members" }
+
+// Organization audit actions.
+const (
+	AuditOrganizationCreated = "organization.created"
+	AuditOrganizationDeleted = "organization.deleted"
+	AuditZonesChanged        = "organization.zones_changed"
+	AuditMemberAdded         = "member.added"
+	AuditMemberRoleChanged   = "member.role_changed"
+	AuditMemberRemoved       = "member.removed"
+)
+
+// OrganizationAuditEntry records one change to an organization. Entries
+// outlive the organization so deletions stay accountable.
+type OrganizationAuditEntry struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID uint   `json:"organization_id" gorm:"index;not null"`
+	Actor          string `json:"actor" gorm:"not null"`
+	Action         string `json:"action" gorm:"not null"`
+	// Subject is the member email the change applies to, if any.
+	Subject   string    `json:"subject,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName overrides GORM's default pluralization for OrganizationAuditEntry.
+func (OrganizationAuditEntry) TableName() string { return "organization_audit_log" }
+
+// ZoneGroup is a named set of zones, possibly spanning centers, that can be
+// queried and subscribed to like a single zone. Subscriptions reference a
+// group by ZoneID "group:<slug>". A group is owned by a subscriber email or
+// by an organization.
+type ZoneGroup struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Slug           string    `json:"slug" gorm:"uniqueIndex;not null"`
+	Name           string    `json:"name" gorm:"not null"`
+	ZoneIDs        []string  `json:"zone_ids" gorm:"serializer:json;not null"`
+	OwnerEmail     string    `json:"owner_email,omitempty" gorm:"index"`
+	OrganizationID *uint     `json:"organization_id,omitempty" gorm:"index"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for ZoneGroup.
+func (ZoneGroup) TableName() string { return "zone_groups" }
+
+// Ref returns the subscription zone ID that references the group.
+func (g *ZoneGroup) Ref() string { return domain.GroupPrefix + g.Slug }
+
+// Contains reports whether zoneID is a member of the group.
+func (g *ZoneGroup) Contains(zoneID string) bool { return slices.Contains(g.ZoneIDs, zoneID) }
+
+// ZoneAlias maps an alternative name for a zone, normalized to lowercase
+// words joined by dashes ("mt-hood"), to its canonical zone ID. Renumbered
+// aliases record the previous ID of a zone renumbered upstream.
+type ZoneAlias struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Alias      string    `json:"alias" gorm:"uniqueIndex;not null"`
+	ZoneID     string    `json:"zone_id" gorm:"index;not null"`
+	Renumbered bool      `json:"renumbered" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName overrides GORM's default pluralization for ZoneAlias.
+func (ZoneAlias) TableName() string { return "zone_aliases" }
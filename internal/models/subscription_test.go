@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -39,3 +41,45 @@ func TestSubscription_IsDueForNotification(t *testing.T) {
 		t.Fatalf("should be due after interval elapsed")
 	}
 }
+
+func TestSubscription_IsPaused_Expires(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	if (&Subscription{}).IsPaused() {
+		t.Fatal("unpaused subscription reported paused")
+	}
+	if !(&Subscription{PausedAt: &past}).IsPaused() {
+		t.Fatal("indefinite pause should hold")
+	}
+	if !(&Subscription{PausedAt: &past, PausedUntil: &future}).IsPaused() {
+		t.Fatal("pause should hold until PausedUntil")
+	}
+	if (&Subscription{PausedAt: &past, PausedUntil: &past}).IsPaused() {
+		t.Fatal("pause should lapse after PausedUntil")
+	}
+}
+
+func TestDangerRating_JSONIncludesNames(t *testing.T) {
+	b, err := json.Marshal(&DangerRating{Upper: 4, Middle: 2, Lower: 0, ValidDay: "current"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"upper":4,"middle":2,"lower":0,"valid_day":"current","upper_name":"High","middle_name":"Moderate","lower_name":"No Rating"}`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+	var d DangerRating
+	if err := json.Unmarshal([]byte(`{"upper":3,"middle":-1,"lower":1}`), &d); err != nil || d.Max() != 3 || d.Middle != 0 {
+		t.Fatalf("unexpected %+v %v", d, err)
+	}
+}
+
+func TestSubscription_JSONOmitsTarget(t *testing.T) {
+	b, err := json.Marshal(Subscription{ZoneID: "NWAC_10", Channel: ChannelSlack, Target: "https://hooks.slack.com/services/T/B/secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "hooks.slack.com") {
+		t.Errorf("expected the target to be omitted, got %s", b)
+	}
+}
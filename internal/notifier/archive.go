@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+// ArchiveStore persists forecast issuances for the Atom feeds.
+type ArchiveStore interface {
+	// ArchiveForecast inserts f or updates the row for the same zone and product.
+	ArchiveForecast(ctx context.Context, f models.ArchivedForecast) error
+}
+
+// ForecastArchive is an EventSink that keeps every issued forecast and
+// warning, rendered with the forecast email template, for the Atom feeds.
+type ForecastArchive struct {
+	store    ArchiveStore
+	renderer *emailRenderer
+}
+
+func NewForecastArchive(store ArchiveStore) *ForecastArchive {
+	renderer := newEmailRenderer()
+	// Badges always show the current danger, which would misrepresent
+	// archived forecasts.
+	renderer.imageBaseURL = ""
+	return &ForecastArchive{store: store, renderer: renderer}
+}
+
+func (a *ForecastArchive) HandleEvent(ctx context.Context, ev Event) error {
+	data := EmailData{ZoneID: ev.ZoneID, ZoneName: ev.ZoneName, IssuedAt: ev.IssuedAt, CenterLink: ev.CenterLink}
+	link := ev.CenterLink
+	if ev.Forecast != nil {
+		data.Today = ev.Forecast.TodayDanger
+		data.Tomorrow = ev.Forecast.FutureDanger
+		data.BottomLine = ev.Forecast.SafeBottomLine()
+		if ev.Forecast.URL != "" {
+			link = ev.Forecast.URL
+		}
+	}
+	content, err := a.renderer.forecastHTML(data)
+	if err != nil {
+		return err
+	}
+
+	productType := "forecast"
+	title := "Avalanche forecast for " + ev.Label()
+	if ev.Forecast != nil {
+		title = fmt.Sprintf("%s: %s", ev.Label(), ev.Forecast.TodayDanger.Max().Name())
+	}
+	if ev.Type == models.EventWarningIssued {
+		productType = ProductTypeWarning
+		title = "Avalanche Warning: " + ev.Label()
+		content = "<p><strong>" + html.EscapeString(title) + "</strong></p>" + content
+	}
+
+	row := models.ArchivedForecast{
+		ZoneID:      ev.ZoneID,
+		ProductID:   ev.ProductID,
+		CenterID:    ev.CenterID,
+		ProductType: productType,
+		ZoneName:    ev.ZoneName,
+		CenterName:  ev.CenterName,
+		Title:       title,
+		Link:        link,
+		Content:     content,
+		IssuedAt:    ev.IssuedAt,
+		AmendedAt:   ev.IssuedAt,
+		ValidDate:   ev.IssuedAt.UTC().Format(time.DateOnly),
+	}
+	if f := ev.Forecast; f != nil {
+		row.ValidFrom, _ = time.Parse(time.RFC3339, f.StartDate)
+		row.ValidUntil, _ = time.Parse(time.RFC3339, f.EndDate)
+		if !row.ValidUntil.IsZero() {
+			row.ValidDate = forecastDay(row.ValidUntil)
+		}
+		if d := f.TodayDanger; d != nil {
+			row.DangerUpper, row.DangerMiddle, row.DangerLower = d.Upper, d.Middle, d.Lower
+		}
+		row.BottomLine = f.SafeBottomLine()
+	}
+	if err := a.store.ArchiveForecast(ctx, row); err != nil {
+		return fmt.Errorf("archive forecast: %w", err)
+	}
+	return nil
+}
+
+// forecastDayOffset places a forecast period's end on the day it covers.
+// North American centers end forecasts in the evening or the following
+// morning local time, which is 18 to 42 hours later in UTC than the start of
+// the covered day.
+const forecastDayOffset = 18 * time.Hour
+
+// forecastDay returns the day, as YYYY-MM-DD, of a forecast valid until end.
+func forecastDay(end time.Time) string {
+	return end.UTC().Add(-forecastDayOffset).Format(time.DateOnly)
+}
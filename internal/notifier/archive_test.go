@@ -0,0 +1,83 @@
+package notifier_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestForecastArchive_AmendmentsUpdateInPlace(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.ArchivedForecast{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	sink := notifier.NewForecastArchive(notifier.NewGormRepository(gdb))
+
+	issued := time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC)
+	ev := notifier.Event{
+		Type:      models.EventForecastIssued,
+		ProductID: 501,
+		Notification: notifier.Notification{
+			ZoneID:     "NWAC_10",
+			ZoneName:   "Snoqualmie Pass",
+			CenterID:   "NWAC",
+			CenterName: "Northwest Avalanche Center",
+			CenterLink: "https://nwac.us",
+			IssuedAt:   issued,
+			Forecast: &models.ZoneForecast{
+				StartDate:   "2025-12-01T02:00:00Z",
+				EndDate:     "2025-12-02T02:00:00Z",
+				BottomLine:  "<p>Avoid wind-loaded slopes.</p>",
+				TodayDanger: &models.DangerRating{Upper: 3, Middle: 2, Lower: 1},
+			},
+		},
+	}
+	if err := sink.HandleEvent(context.Background(), ev); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	amended := issued.Add(2 * time.Hour)
+	ev.Type = models.EventForecastUpdated
+	ev.IssuedAt = amended
+	ev.Forecast = &models.ZoneForecast{
+		StartDate:   "2025-12-01T02:00:00Z",
+		EndDate:     "2025-12-02T02:00:00Z",
+		BottomLine:  "<p>Danger is rising with new snow.</p>",
+		TodayDanger: &models.DangerRating{Upper: 4, Middle: 3, Lower: 2},
+	}
+	if err := sink.HandleEvent(context.Background(), ev); err != nil {
+		t.Fatalf("archive amendment: %v", err)
+	}
+
+	var rows []models.ArchivedForecast
+	if err := gdb.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected one row per product, got %d", len(rows))
+	}
+	row := rows[0]
+	if !row.IssuedAt.Equal(issued) || !row.AmendedAt.Equal(amended) {
+		t.Errorf("expected issued %v amended %v, got %v and %v", issued, amended, row.IssuedAt, row.AmendedAt)
+	}
+	if row.Title != "Snoqualmie Pass: High" || row.ProductType != "forecast" {
+		t.Errorf("unexpected title %q type %q", row.Title, row.ProductType)
+	}
+	if row.ValidDate != "2025-12-01" || row.DangerUpper != 4 || row.DangerLower != 2 || row.BottomLine != "<p>Danger is rising with new snow.</p>" {
+		t.Errorf("unexpected export columns: %+v", row)
+	}
+	for _, want := range []string{"Snoqualmie Pass (NWAC_10)", "Danger is rising"} {
+		if !strings.Contains(row.Content, want) {
+			t.Errorf("content missing %q: %s", want, row.Content)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+// Notification describes a newly detected forecast for delivery to a subscriber.
+type Notification struct {
+	ZoneID     string
+	ZoneName   string
+	CenterID   string
+	CenterName string
+	CenterLink string
+	IssuedAt   time.Time
+	// Forecast holds the processed zone forecast when available.
+	Forecast *models.ZoneForecast
+}
+
+// Label returns the zone name, falling back to the zone ID.
+func (n Notification) Label() string {
+	if n.ZoneName != "" {
+		return n.ZoneName
+	}
+	return n.ZoneID
+}
+
+// Channel delivers notifications to subscriptions that target something other
+// than an email address. Implementations are registered on the Service by the
+// subscription channel name they handle.
+type Channel interface {
+	Notify(ctx context.Context, sub models.Subscription, n Notification) error
+}
@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/htmltext"
+	"example.com/avalanche/internal/models"
+)
+
+// chatBottomLineLimit keeps chat messages readable on mobile.
+const chatBottomLineLimit = 500
+
+// ChatWebhookSender posts forecast notifications to Slack, Discord and
+// Mattermost incoming webhooks. The subscription Target holds the webhook URL
+// and the subscription Channel selects the message format.
+type ChatWebhookSender struct {
+	client *http.Client
+}
+
+// NewChatWebhookSender returns a sender whose client only connects to public
+// hosts.
+func NewChatWebhookSender() *ChatWebhookSender {
+	return &ChatWebhookSender{client: newWebhookClient()}
+}
+
+// Notify renders n for the subscription's chat platform and posts it.
+func (c *ChatWebhookSender) Notify(ctx context.Context, sub models.Subscription, n Notification) error {
+	var payload any
+	switch sub.Channel {
+	case models.ChannelSlack:
+		payload = slackMessage(n)
+	case models.ChannelDiscord:
+		payload = discordMessage(n)
+	case models.ChannelMattermost:
+		payload = mattermostMessage(n)
+	default:
+		return fmt.Errorf("unsupported chat channel %q", sub.Channel)
+	}
+	if sub.Target == "" {
+		return fmt.Errorf("subscription %d has no webhook URL", sub.ID)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s webhook failed: %w", sub.Channel, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return &ProviderError{Provider: sub.Channel, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	log.Printf("sent %s notification for zone %s", sub.Channel, n.ZoneID)
+	return nil
+}
+
+type bandRating struct {
+	Name  string
+	Level domain.DangerLevel
+}
+
+// bands returns today's ratings from top to bottom.
+func bands(n Notification) []bandRating {
+	if n.Forecast == nil {
+		return nil
+	}
+	return ratingBands(n.Forecast.TodayDanger)
+}
+
+func ratingBands(d *models.DangerRating) []bandRating {
+	if d == nil {
+		return nil
+	}
+	return []bandRating{{"Upper", d.Upper}, {"Middle", d.Middle}, {"Lower", d.Lower}}
+}
+
+func chatTitle(n Notification) string {
+	return "Avalanche forecast: " + n.Label()
+}
+
+func chatSummary(n Notification) string {
+	issued := n.IssuedAt.UTC().Format("Mon Jan 2 15:04 MST")
+	if n.CenterName != "" {
+		return fmt.Sprintf("%s issued %s", n.CenterName, issued)
+	}
+	return "Issued " + issued
+}
+
+// chatBottomLine returns the bottom line as plain text, for Slack, whose
+// mrkdwn is not Markdown.
+func chatBottomLine(n Notification) string {
+	if n.Forecast == nil {
+		return ""
+	}
+	return htmltext.Truncate(n.Forecast.PlainBottomLine(), chatBottomLineLimit)
+}
+
+// chatBottomLineMarkdown returns the bottom line as Markdown, for Discord
+// and Mattermost.
+func chatBottomLineMarkdown(n Notification) string {
+	if n.Forecast == nil {
+		return ""
+	}
+	return htmltext.Markdown(n.Forecast.SafeBottomLine(), chatBottomLineLimit)
+}
+
+func chatColor(n Notification) string {
+	if n.Forecast == nil {
+		return domain.DangerNoRating.Color()
+	}
+	return n.Forecast.TodayDanger.Max().Color()
+}
+
+// slackMessage builds a Block Kit message wrapped in a colored attachment so
+// the sidebar reflects the highest danger rating.
+func slackMessage(n Notification) map[string]any {
+	var today *models.DangerRating
+	if n.Forecast != nil {
+		today = n.Forecast.TodayDanger
+	}
+	return slackForecast(n, chatTitle(n), today)
+}
+
+// slackForecast renders rating and the bottom line under title. buttons are
+// added next to the "Full forecast" link.
+func slackForecast(n Notification, title string, rating *models.DangerRating, buttons ...map[string]any) map[string]any {
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": title}},
+		{"type": "context", "elements": []map[string]any{{"type": "mrkdwn", "text": chatSummary(n)}}},
+	}
+	if b := ratingBands(rating); len(b) > 0 {
+		fields := make([]map[string]any, 0, len(b))
+		for _, band := range b {
+			fields = append(fields, map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", band.Name, band.Level.Label())})
+		}
+		blocks = append(blocks, map[string]any{"type": "section", "fields": fields})
+	}
+	if bl := chatBottomLine(n); bl != "" {
+		blocks = append(blocks, map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": bl}})
+	}
+	var elements []map[string]any
+	if n.CenterLink != "" {
+		elements = append(elements, map[string]any{
+			"type": "button",
+			"text": map[string]any{"type": "plain_text", "text": "Full forecast"},
+			"url":  n.CenterLink,
+		})
+	}
+	elements = append(elements, buttons...)
+	if len(elements) > 0 {
+		blocks = append(blocks, map[string]any{"type": "actions", "elements": elements})
+	}
+	return map[string]any{
+		"text":        title,
+		"attachments": []map[string]any{{"color": rating.Max().Color(), "blocks": blocks}},
+	}
+}
+
+// discordMessage builds a single embed colored by the highest danger rating.
+func discordMessage(n Notification) map[string]any {
+	embed := map[string]any{
+		"title":       chatTitle(n),
+		"description": chatBottomLineMarkdown(n),
+		"color":       hexToInt(chatColor(n)),
+		"footer":      map[string]any{"text": chatSummary(n)},
+	}
+	if n.CenterLink != "" {
+		embed["url"] = n.CenterLink
+	}
+	if !n.IssuedAt.IsZero() {
+		embed["timestamp"] = n.IssuedAt.UTC().Format(time.RFC3339)
+	}
+	var fields []map[string]any
+	for _, band := range bands(n) {
+		fields = append(fields, map[string]any{"name": band.Name, "value": band.Level.Label(), "inline": true})
+	}
+	if len(fields) > 0 {
+		embed["fields"] = fields
+	}
+	return map[string]any{"embeds": []map[string]any{embed}}
+}
+
+// mattermostMessage uses Slack-compatible attachments, which Mattermost renders natively.
+func mattermostMessage(n Notification) map[string]any {
+	att := map[string]any{
+		"fallback": chatTitle(n),
+		"color":    chatColor(n),
+		"title":    chatTitle(n),
+		"text":     chatBottomLineMarkdown(n),
+		"footer":   chatSummary(n),
+	}
+	if n.CenterLink != "" {
+		att["title_link"] = n.CenterLink
+	}
+	var fields []map[string]any
+	for _, band := range bands(n) {
+		fields = append(fields, map[string]any{"short": true, "title": band.Name, "value": band.Level.Label()})
+	}
+	if len(fields) > 0 {
+		att["fields"] = fields
+	}
+	return map[string]any{"attachments": []map[string]any{att}}
+}
+
+func hexToInt(hex string) int {
+	v, _ := strconv.ParseInt(strings.TrimPrefix(hex, "#"), 16, 32)
+	return int(v)
+}
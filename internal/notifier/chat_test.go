@@ -0,0 +1,153 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// fakeRepo is an in-memory notifier.Repository.
+type fakeRepo struct {
+	mu       sync.Mutex
+	subs     []models.Subscription
+	cache    map[string]models.ForecastCache
+	notified []uint
+}
+
+func (r *fakeRepo) ListSubscribedCenters(ctx context.Context) ([]string, error) {
+	return []string{"NWAC"}, nil
+}
+
+func (r *fakeRepo) ListSubscribedZones(ctx context.Context) ([]string, error) {
+	var out []string
+	for _, s := range r.subs {
+		out = append(out, s.ZoneID)
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetSubscriptionsForZone(ctx context.Context, zoneID string) ([]models.Subscription, error) {
+	var out []models.Subscription
+	for _, s := range r.subs {
+		if s.ZoneID == zoneID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) UpdateLastNotified(ctx context.Context, subID uint, t time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notified = append(r.notified, subID)
+	return nil
+}
+
+func (r *fakeRepo) GetCachedForecast(ctx context.Context, key string) (models.ForecastCache, error) {
+	return r.cache[key], nil
+}
+
+func (r *fakeRepo) UpsertCachedForecast(ctx context.Context, fc models.ForecastCache) error {
+	r.cache[fc.ZoneID] = fc
+	return nil
+}
+
+func (r *fakeRepo) GetCenterByID(ctx context.Context, centerID string) (*models.AvalancheCenter, error) {
+	return &models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center", URL: "https://nwac.us/"}, nil
+}
+
+type nopEmailSender struct{}
+
+func (nopEmailSender) SendForecastEmail(ctx context.Context, recipient string, data notifier.EmailData) error {
+	return nil
+}
+
+func (nopEmailSender) SendCenterForecastEmail(ctx context.Context, recipient, centerName, centerLink string, zones []notifier.ZoneSummary) error {
+	return nil
+}
+
+func TestService_DeliversNewForecastsToChatWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	payloads := map[string]map[string]any{}
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		payloads[r.URL.Path] = body
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer stand.Close()
+
+	repo := &fakeRepo{
+		cache: map[string]models.ForecastCache{},
+		subs: []models.Subscription{
+			{ID: 1, ZoneID: "NWAC_10", Channel: models.ChannelSlack, Target: stand.URL + "/slack"},
+			{ID: 2, ZoneID: "NWAC_10", Channel: models.ChannelDiscord, Target: stand.URL + "/discord"},
+			{ID: 3, ZoneID: "NWAC_10", Channel: models.ChannelMattermost, Target: stand.URL + "/mattermost"},
+		},
+	}
+	issued := time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC)
+	fetch := func(ctx context.Context, centerID string) ([]notifier.Forecast, error) {
+		return []notifier.Forecast{{ZoneID: "NWAC_10", IssuedAt: issued}}, nil
+	}
+
+	svc := notifier.NewService(repo, nopEmailSender{}, time.Hour, fetch)
+	svc.SetForecastDetails(func(ctx context.Context, centerID string) ([]models.ZoneForecast, error) {
+		return []models.ZoneForecast{{
+			ZoneID:      "NWAC_10",
+			ZoneName:    "Mt Hood",
+			BottomLine:  "<p>Watch for <b>wind slabs</b> near ridgelines.</p>",
+			TodayDanger: &models.DangerRating{Upper: 3, Middle: 2, Lower: 1, ValidDay: "current"},
+		}}, nil
+	})
+	chat := notifier.NewChatWebhookSender()
+	for _, ch := range []string{models.ChannelSlack, models.ChannelDiscord, models.ChannelMattermost} {
+		svc.RegisterChannel(ch, chat)
+	}
+
+	ctx := context.Background()
+	svc.RunOnce(ctx)
+
+	if len(repo.notified) != 3 {
+		t.Fatalf("expected 3 notified subscriptions, got %v", repo.notified)
+	}
+
+	slack, _ := json.Marshal(payloads["/slack"])
+	for _, want := range []string{"Mt Hood", "#F7941E", "Considerable", "Watch for wind slabs near ridgelines.", "https://nwac.us/"} {
+		if !strings.Contains(string(slack), want) {
+			t.Fatalf("slack payload missing %q: %s", want, slack)
+		}
+	}
+	if strings.Contains(string(slack), "<b>") {
+		t.Fatalf("slack payload should not contain HTML: %s", slack)
+	}
+
+	embeds := payloads["/discord"]["embeds"].([]any)
+	embed := embeds[0].(map[string]any)
+	if embed["color"].(float64) != 0xF7941E || embed["url"] != "https://nwac.us/" {
+		t.Fatalf("unexpected discord embed: %v", embed)
+	}
+	if embed["description"] != "Watch for **wind slabs** near ridgelines." {
+		t.Fatalf("expected a Markdown bottom line, got %q", embed["description"])
+	}
+
+	mm, _ := json.Marshal(payloads["/mattermost"])
+	if !strings.Contains(string(mm), `"title_link":"https://nwac.us/"`) {
+		t.Fatalf("unexpected mattermost payload: %s", mm)
+	}
+
+	// A second pass with the same issue time must not re-notify.
+	svc.RunOnce(ctx)
+	if len(repo.notified) != 3 {
+		t.Fatalf("expected no duplicate notifications, got %v", repo.notified)
+	}
+}
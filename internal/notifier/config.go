@@ -0,0 +1,194 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoEmailProvider is returned by NewEmailSenderFromEnv when no provider is configured.
+var ErrNoEmailProvider = errors.New("no email provider configured (set SENDGRID_API_KEY, POSTMARK_SERVER_TOKEN, MAILGUN_API_KEY or SMTP_HOST)")
+
+// defaultProviderOrder is the priority used when providers are inferred from credentials.
+var defaultProviderOrder = []string{"sendgrid", "postmark", "mailgun", "smtp"}
+
+// NewEmailSenderFromEnv builds a FailoverEmailSender over the providers listed
+// in EMAIL_PROVIDERS (comma separated, highest priority first). EMAIL_PROVIDER
+// selects a single provider. When neither is set, every provider with
+// credentials present is used in the order sendgrid, postmark, mailgun, smtp.
+// Each provider may be throttled with <NAME>_RATE_LIMIT (e.g. "10/s", "600/m")
+// and <NAME>_RATE_BURST. recorder may be nil.
+func NewEmailSenderFromEnv(recorder AttemptRecorder) (EmailSender, error) {
+	names := splitProviderList(os.Getenv("EMAIL_PROVIDERS"))
+	if len(names) == 0 {
+		names = splitProviderList(os.Getenv("EMAIL_PROVIDER"))
+	}
+	if len(names) == 0 {
+		for _, name := range defaultProviderOrder {
+			if providerConfigured(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, ErrNoEmailProvider
+	}
+
+	providers := make([]Provider, 0, len(names))
+	for i, name := range names {
+		sender, err := newProviderFromEnv(name)
+		if err != nil {
+			return nil, fmt.Errorf("email provider %s: %w", name, err)
+		}
+		rate, burst, err := rateLimitFromEnv(name)
+		if err != nil {
+			return nil, fmt.Errorf("email provider %s: %w", name, err)
+		}
+		providers = append(providers, Provider{Name: name, Sender: sender, Priority: i, RateLimit: rate, Burst: burst})
+	}
+	return NewFailoverEmailSender(recorder, providers...)
+}
+
+func splitProviderList(raw string) []string {
+	var out []string
+	for _, p := range strings.Split(raw, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func providerConfigured(name string) bool {
+	switch name {
+	case "sendgrid":
+		return os.Getenv("SENDGRID_API_KEY") != ""
+	case "postmark":
+		return os.Getenv("POSTMARK_SERVER_TOKEN") != ""
+	case "mailgun":
+		return os.Getenv("MAILGUN_API_KEY") != ""
+	case "smtp":
+		return os.Getenv("SMTP_HOST") != ""
+	}
+	return false
+}
+
+func newProviderFromEnv(name string) (MessageSender, error) {
+	switch name {
+	case "sendgrid":
+		return newSendGridEmailClientFromEnv()
+	case "postmark":
+		return NewPostmarkClient(os.Getenv("POSTMARK_BASE_URL"), os.Getenv("POSTMARK_SERVER_TOKEN"), EmailFromEnv(), os.Getenv("POSTMARK_MESSAGE_STREAM"))
+	case "mailgun":
+		return NewMailgunClient(os.Getenv("MAILGUN_BASE_URL"), os.Getenv("MAILGUN_DOMAIN"), os.Getenv("MAILGUN_API_KEY"), EmailFromEnv())
+	case "smtp":
+		cfg, err := SMTPConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewSMTPEmailClient(cfg)
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", name)
+	}
+}
+
+// rateLimitFromEnv parses <NAME>_RATE_LIMIT ("count/unit" with unit s, m or h)
+// into sends per second, along with <NAME>_RATE_BURST.
+func rateLimitFromEnv(name string) (float64, int, error) {
+	prefix := strings.ToUpper(name)
+	raw := strings.TrimSpace(os.Getenv(prefix + "_RATE_LIMIT"))
+	if raw == "" {
+		return 0, 0, nil
+	}
+	countStr, unit, _ := strings.Cut(raw, "/")
+	count, err := strconv.ParseFloat(countStr, 64)
+	if err != nil || count <= 0 {
+		return 0, 0, fmt.Errorf("invalid %s_RATE_LIMIT %q", prefix, raw)
+	}
+	per := time.Second
+	switch unit {
+	case "", "s":
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return 0, 0, fmt.Errorf("invalid %s_RATE_LIMIT unit %q", prefix, unit)
+	}
+	burst := 1
+	if b := os.Getenv(prefix + "_RATE_BURST"); b != "" {
+		if burst, err = strconv.Atoi(b); err != nil {
+			return 0, 0, fmt.Errorf("invalid %s_RATE_BURST: %w", prefix, err)
+		}
+	}
+	return count / per.Seconds(), burst, nil
+}
+
+// SMTPConfigFromEnv reads SMTP_* and DKIM_* environment variables.
+func SMTPConfigFromEnv() (SMTPConfig, error) {
+	cfg := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		TLSMode:  SMTPTLSMode(strings.ToLower(os.Getenv("SMTP_TLS"))),
+		From:     EmailFromEnv(),
+		HeloName: os.Getenv("SMTP_HELO_NAME"),
+	}
+	if p := os.Getenv("SMTP_PORT"); p != "" {
+		port, err := strconv.Atoi(p)
+		if err != nil {
+			return SMTPConfig{}, fmt.Errorf("invalid SMTP_PORT: %w", err)
+		}
+		cfg.Port = port
+	}
+	if d := os.Getenv("SMTP_IDLE_TIMEOUT"); d != "" {
+		timeout, err := time.ParseDuration(d)
+		if err != nil {
+			return SMTPConfig{}, fmt.Errorf("invalid SMTP_IDLE_TIMEOUT: %w", err)
+		}
+		cfg.IdleTimeout = timeout
+	}
+
+	domain := os.Getenv("DKIM_DOMAIN")
+	if domain == "" {
+		return cfg, nil
+	}
+	keyPEM := []byte(os.Getenv("DKIM_PRIVATE_KEY"))
+	if path := os.Getenv("DKIM_PRIVATE_KEY_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return SMTPConfig{}, fmt.Errorf("read DKIM_PRIVATE_KEY_FILE: %w", err)
+		}
+		keyPEM = b
+	}
+	signer, err := NewDKIMSigner(domain, os.Getenv("DKIM_SELECTOR"), keyPEM)
+	if err != nil {
+		return SMTPConfig{}, err
+	}
+	cfg.DKIM = signer
+	return cfg, nil
+}
+
+// LogEmailSender logs notifications instead of delivering them. It keeps the
+// API usable in environments without an email provider.
+type LogEmailSender struct{}
+
+func (LogEmailSender) SendForecastEmail(ctx context.Context, recipient string, data EmailData) error {
+	log.Printf("email disabled: skipping forecast email to %s for zone %s", recipient, data.ZoneID)
+	return nil
+}
+
+func (LogEmailSender) SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
+	log.Printf("email disabled: skipping center forecast email to %s for center %s", recipient, centerName)
+	return nil
+}
+
+func (LogEmailSender) SendMessage(ctx context.Context, msg Message) error {
+	log.Printf("email disabled: skipping %q to %s", msg.Subject, msg.To)
+	return nil
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+)
+
+// newWebhookClient returns a client for URLs supplied by users, such as chat
+// webhooks and push endpoints. It refuses to connect to loopback, private and
+// link-local addresses, checking the address actually dialed so that neither
+// DNS nor redirects can lead it to internal services.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			return domain.CheckWebhookIP(net.ParseIP(host))
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: 10 * time.Second, Transport: transport}
+}
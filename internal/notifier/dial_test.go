@@ -0,0 +1,36 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// TestMain lets the senders reach the httptest servers the tests run on
+// loopback.
+func TestMain(m *testing.M) {
+	domain.AllowLoopbackWebhooks = true
+	os.Exit(m.Run())
+}
+
+func TestChatWebhookSender_RefusesLoopbackByDefault(t *testing.T) {
+	domain.AllowLoopbackWebhooks = false
+	defer func() { domain.AllowLoopbackWebhooks = true }()
+
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+	defer srv.Close()
+
+	sub := models.Subscription{ID: 1, ZoneID: "NWAC_10", Channel: models.ChannelSlack, Target: srv.URL}
+	err := notifier.NewChatWebhookSender().Notify(context.Background(), sub, notifier.Notification{ZoneID: "NWAC_10"})
+	if !errors.Is(err, domain.ErrPrivateWebhookHost) || called {
+		t.Errorf("expected the loopback webhook to be refused, got %v (called %v)", err, called)
+	}
+}
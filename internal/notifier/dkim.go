@@ -0,0 +1,168 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dkimSignedHeaders lists the headers covered by the signature, in signing order.
+var dkimSignedHeaders = []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type"}
+
+// DKIMSigner adds an RFC 6376 DKIM-Signature header (rsa-sha256, relaxed/relaxed)
+// to outgoing messages.
+type DKIMSigner struct {
+	Domain   string
+	Selector string
+	key      *rsa.PrivateKey
+	now      func() time.Time
+}
+
+// NewDKIMSigner parses a PEM-encoded RSA private key (PKCS#1 or PKCS#8) and
+// returns a signer for the given domain and selector.
+func NewDKIMSigner(domain, selector string, keyPEM []byte) (*DKIMSigner, error) {
+	if domain == "" || selector == "" {
+		return nil, errors.New("dkim domain and selector are required")
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("dkim private key is not PEM encoded")
+	}
+	var key *rsa.PrivateKey
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = k
+	} else {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse dkim private key: %w", err)
+		}
+		rk, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("dkim private key must be RSA")
+		}
+		key = rk
+	}
+	return &DKIMSigner{Domain: domain, Selector: selector, key: key, now: time.Now}, nil
+}
+
+// Sign returns the message with a DKIM-Signature header prepended. The message
+// must use CRLF line endings and separate headers from body with a blank line.
+func (s *DKIMSigner) Sign(msg []byte) ([]byte, error) {
+	idx := bytes.Index(msg, []byte("\r\n\r\n"))
+	if idx < 0 {
+		return nil, errors.New("dkim: message has no header/body separator")
+	}
+	headerBlock := string(msg[:idx+2])
+	body := msg[idx+4:]
+
+	bodyHash := sha256.Sum256(dkimCanonicalBody(body))
+	headers := parseHeaderFields(headerBlock)
+
+	var signed []string
+	var h bytes.Buffer
+	for _, name := range dkimSignedHeaders {
+		field, ok := headers[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		signed = append(signed, strings.ToLower(name))
+		h.WriteString(dkimCanonicalHeader(field))
+		h.WriteString("\r\n")
+	}
+
+	sigValue := fmt.Sprintf("v=1; a=rsa-sha256; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		s.Domain, s.Selector, s.now().Unix(), strings.Join(signed, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+	h.WriteString(dkimCanonicalHeader("DKIM-Signature: " + sigValue))
+
+	digest := sha256.Sum256(h.Bytes())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("dkim sign: %w", err)
+	}
+
+	out := make([]byte, 0, len(msg)+512)
+	out = append(out, "DKIM-Signature: "+sigValue+base64.StdEncoding.EncodeToString(sig)+"\r\n"...)
+	out = append(out, msg...)
+	return out, nil
+}
+
+// parseHeaderFields maps lower-cased header names to their raw (possibly folded)
+// field text. When a header repeats, the last occurrence wins, matching the
+// bottom-up selection rule in RFC 6376 section 5.4.2.
+func parseHeaderFields(block string) map[string]string {
+	fields := map[string]string{}
+	var current string
+	flush := func() {
+		if current == "" {
+			return
+		}
+		if i := strings.IndexByte(current, ':'); i > 0 {
+			fields[strings.ToLower(strings.TrimSpace(current[:i]))] = current
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(block, "\r\n"), "\r\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			current += "\r\n" + line
+			continue
+		}
+		flush()
+		current = line
+	}
+	flush()
+	return fields
+}
+
+// dkimCanonicalHeader applies the "relaxed" header canonicalization algorithm.
+func dkimCanonicalHeader(field string) string {
+	i := strings.IndexByte(field, ':')
+	if i < 0 {
+		return field
+	}
+	name := strings.ToLower(strings.TrimSpace(field[:i]))
+	value := strings.ReplaceAll(field[i+1:], "\r\n", "")
+	value = strings.Join(strings.Fields(value), " ")
+	return name + ":" + value
+}
+
+// dkimCanonicalBody applies the "relaxed" body canonicalization algorithm.
+func dkimCanonicalBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		lines[i] = collapseWSP(line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func collapseWSP(s string) string {
+	var b strings.Builder
+	inWSP := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			if !inWSP {
+				b.WriteByte(' ')
+			}
+			inWSP = true
+			continue
+		}
+		inWSP = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
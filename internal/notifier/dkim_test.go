@@ -0,0 +1,79 @@
+package notifier_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/notifier"
+)
+
+func TestDKIMSigner_SignsVerifiableHeader(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	signer, err := notifier.NewDKIMSigner("example.com", "avy", keyPEM)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+
+	msg := "From: Avy <alerts@example.com>\r\n" +
+		"To: <user@example.com>\r\n" +
+		"Subject:  New   forecast\r\n" +
+		"\r\n" +
+		"Hello  world \r\n\r\n"
+	signed, err := signer.Sign([]byte(msg))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	header, rest, ok := strings.Cut(string(signed), "\r\n")
+	if !ok || rest != msg {
+		t.Fatalf("expected signature header prepended to original message")
+	}
+	tags := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(header, "DKIM-Signature: "), "; ") {
+		k, v, _ := strings.Cut(part, "=")
+		tags[k] = v
+	}
+	if tags["d"] != "example.com" || tags["s"] != "avy" || tags["h"] != "from:to:subject" {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	bodyHash := sha256.Sum256([]byte("Hello world\r\n"))
+	if tags["bh"] != base64.StdEncoding.EncodeToString(bodyHash[:]) {
+		t.Fatalf("unexpected body hash %s", tags["bh"])
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		t.Fatalf("decode b=: %v", err)
+	}
+	unsigned := strings.TrimSuffix(header, tags["b"])
+	canonical := "from:Avy <alerts@example.com>\r\n" +
+		"to:<user@example.com>\r\n" +
+		"subject:New forecast\r\n" +
+		"dkim-signature:" + strings.TrimPrefix(unsigned, "DKIM-Signature: ")
+	digest := sha256.Sum256([]byte(canonical))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("signature did not verify: %v", err)
+	}
+}
+
+func TestNewDKIMSigner_RejectsBadKey(t *testing.T) {
+	if _, err := notifier.NewDKIMSigner("example.com", "avy", []byte("not a key")); err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+	if _, err := notifier.NewDKIMSigner("", "avy", nil); err == nil {
+		t.Fatal("expected error for missing domain")
+	}
+}
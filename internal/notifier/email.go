@@ -1,15 +1,14 @@
 package notifier
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"html/template"
 	"log"
 	"os"
 	"strings"
 	"time"
 
+	"example.com/avalanche/internal/domain"
 	"example.com/avalanche/internal/models"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
@@ -22,6 +21,23 @@ type EmailData struct {
 	Today      *models.DangerRating
 	Tomorrow   *models.DangerRating
 	CenterLink string
+	// BottomLine is the forecast's sanitized HTML bottom line.
+	BottomLine string
+	// ReplyTo routes subscriber replies to the inbound command webhook when set.
+	ReplyTo string
+	// Trip is set for trip plan countdown emails.
+	Trip *TripEmail
+}
+
+// TripEmail describes the trip plan a forecast email is sent for.
+type TripEmail struct {
+	Name string
+	Date time.Time
+	// Outlook is models.TripOutlookTomorrow before the trip day and
+	// models.TripOutlookToday on it.
+	Outlook string
+	// Link is the recipient's link to leave the plan.
+	Link string
 }
 
 type EmailSender interface {
@@ -29,6 +45,21 @@ type EmailSender interface {
 	SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error
 }
 
+// Message is a fully rendered email ready to be handed to a provider.
+type Message struct {
+	To      string
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// MessageSender delivers pre-rendered messages. Every provider implements it so
+// templates are rendered once and shared across transports.
+type MessageSender interface {
+	SendMessage(ctx context.Context, msg Message) error
+}
+
 // ZoneSummary represents a simplified view of a zone for aggregated emails.
 type ZoneSummary struct {
 	ZoneID      string
@@ -38,70 +69,78 @@ type ZoneSummary struct {
 }
 
 type SendGridEmailClient struct {
-	sg   *sendgrid.Client
-	from string
-	tmpl *template.Template
+	sg       *sendgrid.Client
+	from     string
+	renderer *emailRenderer
 }
 
 func NewSendGridEmailClient() *SendGridEmailClient {
+	c, err := newSendGridEmailClientFromEnv()
+	if err != nil {
+		panic(err.Error())
+	}
+	return c
+}
+
+func newSendGridEmailClientFromEnv() (*SendGridEmailClient, error) {
 	key := os.Getenv("SENDGRID_API_KEY")
 	if key == "" {
-		panic("SENDGRID_API_KEY is required")
-	}
-	from := os.Getenv("EMAIL_FROM")
-	if from == "" {
-		from = "alerts@example.com"
+		return nil, fmt.Errorf("SENDGRID_API_KEY is required")
 	}
+	return &SendGridEmailClient{
+		sg:       sendgrid.NewSendClient(key),
+		from:     EmailFromEnv(),
+		renderer: newEmailRenderer(),
+	}, nil
+}
 
-	tmpl, err := template.ParseFiles("internal/email/templates/forecast.html")
+func (c *SendGridEmailClient) SendForecastEmail(ctx context.Context, recipient string, data EmailData) error {
+	msg, err := c.renderer.forecastMessage(recipient, data)
 	if err != nil {
-		tmpl = template.Must(template.New("forecast").Parse(defaultTemplate))
+		return err
 	}
-
-	return &SendGridEmailClient{
-		sg:   sendgrid.NewSendClient(key),
-		from: from,
-		tmpl: tmpl,
+	if err := c.SendMessage(ctx, msg); err != nil {
+		return err
 	}
+	log.Printf("sent forecast email to %s for zone %s", recipient, data.ZoneID)
+	return nil
 }
 
-func (c *SendGridEmailClient) SendForecastEmail(ctx context.Context, recipient string, data EmailData) error {
-	var buf bytes.Buffer
-	_ = c.tmpl.Execute(&buf, map[string]any{
-		"ZoneID":     data.ZoneID,
-		"ZoneName":   data.ZoneName,
-		"IssuedAt":   data.IssuedAt.Format("Mon Jan 2 15:04 2006 MST"),
-		"Today":      data.Today,
-		"Tomorrow":   data.Tomorrow,
-		"CenterLink": data.CenterLink,
-	})
-
-	label := data.ZoneID
-	if strings.TrimSpace(data.ZoneName) != "" {
-		label = data.ZoneName
+// SendCenterForecastEmail sends a single aggregated email listing all zones for a center using the HTML template.
+func (c *SendGridEmailClient) SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
+	if len(zones) == 0 {
+		return nil
+	}
+	msg, err := c.renderer.centerForecastMessage(recipient, centerName, centerLink, zones)
+	if err != nil {
+		return err
 	}
-	subject := fmt.Sprintf("New Avalanche Forecast for %s", label)
+	if err := c.SendMessage(ctx, msg); err != nil {
+		return err
+	}
+	log.Printf("sent aggregated center forecast email to %s for center %s", recipient, centerName)
+	return nil
+}
+
+// SendMessage delivers a rendered message through the SendGrid v3 mail API.
+func (c *SendGridEmailClient) SendMessage(ctx context.Context, msg Message) error {
 	from := mail.NewEmail("Avy Notifier", c.from)
-	to := mail.NewEmail("Subscriber", recipient)
-	m := mail.NewSingleEmail(from, subject, to, "A new avalanche forecast is available.", buf.String())
+	to := mail.NewEmail("Subscriber", msg.To)
+	m := mail.NewSingleEmail(from, msg.Subject, to, msg.Text, msg.HTML)
+	if msg.ReplyTo != "" {
+		m.SetReplyTo(mail.NewEmail("", msg.ReplyTo))
+	}
 
-	resp, err := c.sg.Send(m)
+	resp, err := c.sg.SendWithContext(ctx, m)
 	if err != nil {
-		return err
+		return fmt.Errorf("sendgrid send failed: %w", err)
 	}
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("sendgrid send failed: %d %s", resp.StatusCode, resp.Body)
+		return &ProviderError{Provider: "sendgrid", StatusCode: resp.StatusCode, Body: resp.Body}
 	}
-	log.Printf("sent forecast email to %s for zone %s", recipient, data.ZoneID)
 	return nil
 }
 
-const defaultTemplate = `<div style="font-family:Arial,sans-serif;">
-	<h2>New Avalanche Forecast</h2>
-	<p>A new forecast has been issued for zone <b>{{if .ZoneName}}{{.ZoneName}} ({{.ZoneID}}){{else}}{{.ZoneID}}{{end}}</b> at <b>{{.IssuedAt}}</b>.</p>
-	<p>Check the latest details on <a href="{{.CenterLink}}">Visit Center Website</a>.</p>
-</div>`
-
 // BuildZoneSummaries derives zone summaries from processed zone forecasts.
 func BuildZoneSummaries(forecasts []models.ZoneForecast) []ZoneSummary {
 	out := make([]ZoneSummary, 0, len(forecasts))
@@ -118,91 +157,34 @@ func BuildZoneSummaries(forecasts []models.ZoneForecast) []ZoneSummary {
 	return out
 }
 
-// formatDanger converts a DangerRating to a display string.
+// formatDanger converts a DangerRating to a display string such as
+// "Considerable (U:3 M:2 L:1)", naming the highest band rating.
 func formatDanger(d *models.DangerRating) string {
-	if d == nil {
-		return "No rating"
-	}
-	parts := []string{}
-	if d.Upper != 0 {
-		parts = append(parts, fmt.Sprintf("U:%d", d.Upper))
-	}
-	if d.Middle != 0 {
-		parts = append(parts, fmt.Sprintf("M:%d", d.Middle))
-	}
-	if d.Lower != 0 {
-		parts = append(parts, fmt.Sprintf("L:%d", d.Lower))
-	}
-	if len(parts) == 0 && d.Message != "" {
-		return d.Message
-	}
-	if len(parts) == 0 {
-		return "No rating"
-	}
-	return strings.Join(parts, "/")
-}
-
-// SendCenterForecastEmail sends a single aggregated email listing all zones for a center using the HTML template.
-func (c *SendGridEmailClient) SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
-	if len(zones) == 0 {
-		return nil
-	}
-
-	// Load and execute the center forecast template
-	tmpl, err := template.ParseFiles("internal/email/templates/center_forecast.html")
-	if err != nil {
-		// Fallback to inline HTML if template not found
-		log.Printf("center template not found, using fallback: %v", err)
-		return c.sendCenterForecastFallback(ctx, recipient, centerName, centerLink, zones)
-	}
-
-	var buf bytes.Buffer
-	data := map[string]any{
-		"CenterName": centerName,
-		"CenterLink": centerLink,
-		"ZoneCount":  len(zones),
-		"Zones":      zones,
-	}
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("template execute failed: %w", err)
-	}
-
-	from := mail.NewEmail("Avy Notifier", c.from)
-	to := mail.NewEmail("Subscriber", recipient)
-	subject := fmt.Sprintf("%s Avalanche Center Forecast Summary", centerName)
-	msg := mail.NewSingleEmail(from, subject, to, "Your avalanche center forecast summary is available.", buf.String())
-	resp, err := c.sg.Send(msg)
-	if err != nil {
-		return fmt.Errorf("sendgrid send failed: %w", err)
-	}
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("sendgrid send failed: %d %s", resp.StatusCode, resp.Body)
-	}
-	log.Printf("sent aggregated center forecast email to %s for center %s", recipient, centerName)
-	return nil
+	if !d.Max().IsRated() {
+		if d != nil && d.Message != "" {
+			return d.Message
+		}
+		return domain.DangerNoRating.Name()
+	}
+	parts := make([]string, 0, 3)
+	for _, b := range []struct {
+		label string
+		level domain.DangerLevel
+	}{{"U", d.Upper}, {"M", d.Middle}, {"L", d.Lower}} {
+		if b.level.IsRated() {
+			parts = append(parts, fmt.Sprintf("%s:%d", b.label, b.level))
+		} else {
+			parts = append(parts, b.label+":-")
+		}
+	}
+	return fmt.Sprintf("%s (%s)", d.Max().Name(), strings.Join(parts, " "))
 }
 
-// sendCenterForecastFallback sends a simple HTML email if the template is unavailable.
-func (c *SendGridEmailClient) sendCenterForecastFallback(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
-	var b strings.Builder
-	fmt.Fprintf(&b, "<h2>Latest Avalanche Forecasts - %s</h2>", centerName)
-	fmt.Fprintf(&b, "<p>%d zones have current forecasts.</p><ul>", len(zones))
-	for _, z := range zones {
-		fmt.Fprintf(&b, "<li><strong>%s</strong> (%s) - Today: %s | Tomorrow: %s</li>", z.ZoneName, z.ZoneID, z.TodayStr, z.TomorrowStr)
-	}
-	fmt.Fprintf(&b, "</ul><p>For details visit <a href=\"%s\">%s</a>.</p>", centerLink, centerName)
-
-	from := mail.NewEmail("Avy Notifier", c.from)
-	to := mail.NewEmail("Subscriber", recipient)
-	subject := fmt.Sprintf("%s Avalanche Center Forecast Summary", centerName)
-	msg := mail.NewSingleEmail(from, subject, to, "Your avalanche center forecast summary is available.", b.String())
-	resp, err := c.sg.Send(msg)
-	if err != nil {
-		return fmt.Errorf("sendgrid send failed: %w", err)
-	}
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("sendgrid send failed: %d %s", resp.StatusCode, resp.Body)
+// EmailFromEnv returns the EMAIL_FROM sender address shared by all providers.
+func EmailFromEnv() string {
+	from := os.Getenv("EMAIL_FROM")
+	if from == "" {
+		from = "alerts@example.com"
 	}
-	log.Printf("sent aggregated center forecast email (fallback) to %s for center %s", recipient, centerName)
-	return nil
+	return from
 }
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+// defaultEventRetention is how long events stay available for stream resume.
+const defaultEventRetention = 7 * 24 * time.Hour
+
+// EventLogStore appends to and trims the persisted forecast event log.
+type EventLogStore interface {
+	// AppendForecastEvent stores ev unless an event with the same EventID exists.
+	AppendForecastEvent(ctx context.Context, ev models.ForecastEvent) error
+	PruneForecastEvents(ctx context.Context, before time.Time) error
+}
+
+// EventLog is an EventSink that records every event so the API can stream
+// them to dashboards and replay them to reconnecting clients.
+type EventLog struct {
+	store EventLogStore
+	// Retention is how long events are kept; zero keeps them forever.
+	Retention time.Duration
+}
+
+// NewEventLog returns an event log keeping events for a week.
+func NewEventLog(store EventLogStore) *EventLog {
+	return &EventLog{store: store, Retention: defaultEventRetention}
+}
+
+func (l *EventLog) HandleEvent(ctx context.Context, ev Event) error {
+	data, err := json.Marshal(webhookPayload(ev))
+	if err != nil {
+		return err
+	}
+	err = l.store.AppendForecastEvent(ctx, models.ForecastEvent{
+		EventID:  ev.ID,
+		Type:     ev.Type,
+		ZoneID:   ev.ZoneID,
+		CenterID: ev.CenterID,
+		Data:     string(data),
+	})
+	if err != nil {
+		return fmt.Errorf("append forecast event: %w", err)
+	}
+	if l.Retention > 0 {
+		if err := l.store.PruneForecastEvents(ctx, time.Now().Add(-l.Retention)); err != nil {
+			log.Printf("failed to prune forecast events: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestEventLog_AppendsOnceAndWatchesZones(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.ForecastEvent{}, &models.Subscription{}, &models.WebhookEndpoint{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repo := notifier.NewGormRepository(gdb)
+	sink := notifier.NewEventLog(repo)
+
+	ev := notifier.Event{
+		ID:   "evt_1",
+		Type: models.EventForecastIssued,
+		Notification: notifier.Notification{
+			ZoneID:   "NWAC_10",
+			CenterID: "NWAC",
+			IssuedAt: time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC),
+			Forecast: &models.ZoneForecast{BottomLine: "Avoid wind-loaded slopes."},
+		},
+	}
+	for range 2 {
+		if err := sink.HandleEvent(context.Background(), ev); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	var events []models.ForecastEvent
+	gdb.Find(&events)
+	if len(events) != 1 || events[0].ZoneID != "NWAC_10" || events[0].CenterID != "NWAC" {
+		t.Fatalf("expected one logged event, got %+v", events)
+	}
+	var payload notifier.WebhookPayload
+	if err := json.Unmarshal([]byte(events[0].Data), &payload); err != nil || payload.Data.BottomLine != "Avoid wind-loaded slopes." {
+		t.Fatalf("unexpected event data %s (%v)", events[0].Data, err)
+	}
+
+	repo.Watch("IPAC")
+	zones, err := repo.ListSubscribedZones(context.Background())
+	if err != nil || len(zones) != 1 || zones[0] != "IPAC" {
+		t.Fatalf("expected watched center, got %v (%v)", zones, err)
+	}
+}
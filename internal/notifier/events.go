@@ -0,0 +1,50 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+// Event is a forecast change detected by the notifier.
+type Event struct {
+	// ID is deterministic so receivers can deduplicate redelivered events.
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	ProductID  int
+	Notification
+}
+
+// EventSink receives every event the Service detects, independent of
+// subscriber notifications.
+type EventSink interface {
+	HandleEvent(ctx context.Context, ev Event) error
+}
+
+func newEvent(eventType string, f Forecast, n Notification) Event {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%d", eventType, f.ZoneID, f.ProductID, f.IssuedAt.Unix())))
+	return Event{
+		ID:           "evt_" + hex.EncodeToString(sum[:12]),
+		Type:         eventType,
+		OccurredAt:   time.Now().UTC(),
+		ProductID:    f.ProductID,
+		Notification: n,
+	}
+}
+
+// eventType classifies f against the previously cached product for the same
+// key. A newer publish time on the same product ID is an update.
+func eventType(f Forecast, cached models.ForecastCache) string {
+	if f.IsWarning() {
+		return models.EventWarningIssued
+	}
+	if cached.ProductID != 0 && cached.ProductID == f.ProductID {
+		return models.EventForecastUpdated
+	}
+	return models.EventForecastIssued
+}
@@ -0,0 +1,315 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/textproto"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+// ProviderError reports a non-2xx response from an HTTP email API.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s send failed: %d %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Provider is one email transport participating in failover.
+type Provider struct {
+	Name   string
+	Sender MessageSender
+	// Priority orders providers; lower values are tried first.
+	Priority int
+	// RateLimit caps sends per second; zero means unlimited.
+	RateLimit float64
+	// Burst is the number of sends allowed at once before RateLimit applies.
+	Burst int
+}
+
+// AttemptRecorder persists each delivery attempt made by FailoverEmailSender.
+type AttemptRecorder interface {
+	RecordEmailAttempt(ctx context.Context, attempt models.EmailDeliveryAttempt) error
+}
+
+// ProviderHealth is a snapshot of a provider's circuit state.
+type ProviderHealth struct {
+	Name                string
+	Healthy             bool
+	ConsecutiveFailures int
+	DownUntil           time.Time
+}
+
+const (
+	failoverBaseCooldown = 30 * time.Second
+	failoverMaxCooldown  = 10 * time.Minute
+)
+
+type providerState struct {
+	Provider
+	limiter *rateLimiter
+
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+}
+
+func (p *providerState) healthy(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !now.Before(p.downUntil)
+}
+
+func (p *providerState) markSuccess() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		log.Printf("email provider %s recovered", p.Name)
+	}
+	p.failures = 0
+	p.downUntil = time.Time{}
+}
+
+func (p *providerState) markFailure(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures++
+	cooldown := failoverBaseCooldown << (p.failures - 1)
+	if cooldown > failoverMaxCooldown || cooldown <= 0 {
+		cooldown = failoverMaxCooldown
+	}
+	p.downUntil = now.Add(cooldown)
+	log.Printf("email provider %s marked unhealthy for %s after %d consecutive failures", p.Name, cooldown, p.failures)
+}
+
+// FailoverEmailSender renders forecast emails once and delivers them through
+// an ordered list of providers. Providers that return 5xx responses, time out
+// or cannot be reached are put on a cooling-off period and the next provider
+// is tried. Permanent rejections (4xx) are returned without failing over.
+type FailoverEmailSender struct {
+	providers []*providerState
+	renderer  *emailRenderer
+	recorder  AttemptRecorder
+	now       func() time.Time
+}
+
+// NewFailoverEmailSender returns a sender over the given providers. recorder may be nil.
+func NewFailoverEmailSender(recorder AttemptRecorder, providers ...Provider) (*FailoverEmailSender, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("at least one email provider is required")
+	}
+	states := make([]*providerState, 0, len(providers))
+	for _, p := range providers {
+		if p.Name == "" || p.Sender == nil {
+			return nil, errors.New("email provider requires a name and sender")
+		}
+		states = append(states, &providerState{Provider: p, limiter: newRateLimiter(p.RateLimit, p.Burst)})
+	}
+	sort.SliceStable(states, func(i, j int) bool { return states[i].Priority < states[j].Priority })
+	return &FailoverEmailSender{
+		providers: states,
+		renderer:  newEmailRenderer(),
+		recorder:  recorder,
+		now:       time.Now,
+	}, nil
+}
+
+func (f *FailoverEmailSender) SendForecastEmail(ctx context.Context, recipient string, data EmailData) error {
+	msg, err := f.renderer.forecastMessage(recipient, data)
+	if err != nil {
+		return err
+	}
+	provider, err := f.deliver(ctx, msg)
+	if err != nil {
+		return err
+	}
+	log.Printf("sent forecast email to %s for zone %s via %s", recipient, data.ZoneID, provider)
+	return nil
+}
+
+func (f *FailoverEmailSender) SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
+	if len(zones) == 0 {
+		return nil
+	}
+	msg, err := f.renderer.centerForecastMessage(recipient, centerName, centerLink, zones)
+	if err != nil {
+		return err
+	}
+	provider, err := f.deliver(ctx, msg)
+	if err != nil {
+		return err
+	}
+	log.Printf("sent aggregated center forecast email to %s for center %s via %s", recipient, centerName, provider)
+	return nil
+}
+
+// SendMessage delivers a pre-rendered message with failover.
+func (f *FailoverEmailSender) SendMessage(ctx context.Context, msg Message) error {
+	_, err := f.deliver(ctx, msg)
+	return err
+}
+
+// Health reports the circuit state of every provider in priority order.
+func (f *FailoverEmailSender) Health() []ProviderHealth {
+	now := f.now()
+	out := make([]ProviderHealth, 0, len(f.providers))
+	for _, p := range f.providers {
+		p.mu.Lock()
+		out = append(out, ProviderHealth{
+			Name:                p.Name,
+			Healthy:             !now.Before(p.downUntil),
+			ConsecutiveFailures: p.failures,
+			DownUntil:           p.downUntil,
+		})
+		p.mu.Unlock()
+	}
+	return out
+}
+
+// deliver tries providers in order and returns the name of the one that accepted msg.
+// Healthy providers are tried first; unhealthy ones are used only as a last resort.
+func (f *FailoverEmailSender) deliver(ctx context.Context, msg Message) (string, error) {
+	messageID, err := randomToken(8)
+	if err != nil {
+		return "", err
+	}
+
+	now := f.now()
+	ordered := make([]*providerState, 0, len(f.providers))
+	var cooling []*providerState
+	for _, p := range f.providers {
+		if p.healthy(now) {
+			ordered = append(ordered, p)
+		} else {
+			cooling = append(cooling, p)
+		}
+	}
+	ordered = append(ordered, cooling...)
+
+	var errs []string
+	for len(ordered) > 0 {
+		var limited []*providerState
+		var minWait time.Duration
+		for _, p := range ordered {
+			if wait := p.limiter.reserve(f.now()); wait > 0 {
+				limited = append(limited, p)
+				if minWait == 0 || wait < minWait {
+					minWait = wait
+				}
+				continue
+			}
+
+			start := f.now()
+			err := p.Sender.SendMessage(ctx, msg)
+			f.record(ctx, messageID, p.Name, msg, start, err)
+			if err == nil {
+				p.markSuccess()
+				return p.Name, nil
+			}
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name, err))
+			if !shouldFailover(err) {
+				return "", fmt.Errorf("email rejected by %s: %w", p.Name, err)
+			}
+			p.markFailure(f.now())
+		}
+		if len(limited) == 0 {
+			break
+		}
+		// Every remaining provider is rate limited; wait for the first slot to open.
+		select {
+		case <-time.After(minWait):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		ordered = limited
+	}
+	return "", fmt.Errorf("all email providers failed: %s", strings.Join(errs, "; "))
+}
+
+func (f *FailoverEmailSender) record(ctx context.Context, messageID, provider string, msg Message, start time.Time, sendErr error) {
+	if f.recorder == nil {
+		return
+	}
+	attempt := models.EmailDeliveryAttempt{
+		MessageID:  messageID,
+		Provider:   provider,
+		Recipient:  msg.To,
+		Subject:    msg.Subject,
+		Status:     models.DeliveryStatusSent,
+		DurationMS: f.now().Sub(start).Milliseconds(),
+	}
+	if sendErr != nil {
+		attempt.Status = models.DeliveryStatusFailed
+		attempt.Error = sendErr.Error()
+	}
+	if err := f.recorder.RecordEmailAttempt(ctx, attempt); err != nil {
+		log.Printf("failed to record email attempt for %s via %s: %v", msg.To, provider, err)
+	}
+}
+
+// shouldFailover reports whether err is transient and another provider should be tried.
+func shouldFailover(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		return pe.StatusCode >= 500 || pe.StatusCode == 429
+	}
+	// SMTP 4xx replies are transient; 5xx replies are permanent rejections.
+	var tpe *textproto.Error
+	if errors.As(err, &tpe) {
+		return tpe.Code >= 400 && tpe.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// rateLimiter is a token bucket allowing rate events per second with the given burst.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve takes a token if available and returns zero; otherwise it returns how
+// long to wait before one will be available.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
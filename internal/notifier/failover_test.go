@@ -0,0 +1,150 @@
+package notifier_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+type fakeProvider struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+	last  notifier.Message
+}
+
+func (f *fakeProvider) SendMessage(ctx context.Context, msg notifier.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.last = msg
+	return f.err
+}
+
+type memoryRecorder struct {
+	mu       sync.Mutex
+	attempts []models.EmailDeliveryAttempt
+}
+
+func (r *memoryRecorder) RecordEmailAttempt(ctx context.Context, a models.EmailDeliveryAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, a)
+	return nil
+}
+
+func TestFailoverEmailSender_FailsOverOn5xxAndRecordsAttempts(t *testing.T) {
+	var postmarkCalls int
+	postmark := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postmarkCalls++
+		if r.Header.Get("X-Postmark-Server-Token") != "pm-token" {
+			t.Errorf("missing postmark token header")
+		}
+		http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+	}))
+	defer postmark.Close()
+
+	var mailgunForm url.Values
+	mailgun := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "api" || pass != "mg-key" {
+			t.Errorf("unexpected basic auth")
+		}
+		if r.URL.Path != "/v3/mg.example.com/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		mailgunForm, _ = url.ParseQuery(string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mailgun.Close()
+
+	pm, _ := notifier.NewPostmarkClient(postmark.URL, "pm-token", "alerts@example.com", "")
+	mg, _ := notifier.NewMailgunClient(mailgun.URL, "mg.example.com", "mg-key", "alerts@example.com")
+	rec := &memoryRecorder{}
+	sender, err := notifier.NewFailoverEmailSender(rec,
+		notifier.Provider{Name: "mailgun", Sender: mg, Priority: 2},
+		notifier.Provider{Name: "postmark", Sender: pm, Priority: 1},
+	)
+	if err != nil {
+		t.Fatalf("new sender: %v", err)
+	}
+
+	if err := sender.SendForecastEmail(context.Background(), "user@example.com", notifier.EmailData{ZoneID: "NWAC_10"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if postmarkCalls != 1 {
+		t.Fatalf("expected postmark to be tried first, calls=%d", postmarkCalls)
+	}
+	if mailgunForm.Get("to") != "user@example.com" || !strings.Contains(mailgunForm.Get("subject"), "NWAC_10") {
+		t.Fatalf("unexpected mailgun form: %v", mailgunForm)
+	}
+
+	if len(rec.attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(rec.attempts))
+	}
+	first, second := rec.attempts[0], rec.attempts[1]
+	if first.Provider != "postmark" || first.Status != models.DeliveryStatusFailed {
+		t.Fatalf("unexpected first attempt: %+v", first)
+	}
+	if second.Provider != "mailgun" || second.Status != models.DeliveryStatusSent {
+		t.Fatalf("unexpected second attempt: %+v", second)
+	}
+	if first.MessageID == "" || first.MessageID != second.MessageID {
+		t.Fatalf("attempts should share a message id: %q vs %q", first.MessageID, second.MessageID)
+	}
+
+	// postmark is now cooling off, so the next send goes straight to mailgun.
+	if err := sender.SendMessage(context.Background(), notifier.Message{To: "other@example.com", Subject: "s"}); err != nil {
+		t.Fatalf("second send: %v", err)
+	}
+	if postmarkCalls != 1 {
+		t.Fatalf("expected unhealthy postmark to be skipped, calls=%d", postmarkCalls)
+	}
+	health := sender.Health()
+	if health[0].Name != "postmark" || health[0].Healthy || health[1].Name != "mailgun" || !health[1].Healthy {
+		t.Fatalf("unexpected health: %+v", health)
+	}
+}
+
+func TestFailoverEmailSender_DoesNotFailOverOnPermanentRejection(t *testing.T) {
+	primary := &fakeProvider{err: &notifier.ProviderError{Provider: "primary", StatusCode: 400, Body: "bad recipient"}}
+	backup := &fakeProvider{}
+	sender, _ := notifier.NewFailoverEmailSender(nil,
+		notifier.Provider{Name: "primary", Sender: primary},
+		notifier.Provider{Name: "backup", Sender: backup, Priority: 1},
+	)
+
+	err := sender.SendMessage(context.Background(), notifier.Message{To: "x@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "rejected by primary") {
+		t.Fatalf("expected rejection error, got %v", err)
+	}
+	if backup.calls != 0 {
+		t.Fatalf("backup should not be tried on a 4xx, calls=%d", backup.calls)
+	}
+}
+
+func TestFailoverEmailSender_SpillsOverWhenRateLimited(t *testing.T) {
+	primary := &fakeProvider{}
+	backup := &fakeProvider{}
+	sender, _ := notifier.NewFailoverEmailSender(nil,
+		notifier.Provider{Name: "primary", Sender: primary, RateLimit: 0.001, Burst: 1},
+		notifier.Provider{Name: "backup", Sender: backup, Priority: 1},
+	)
+
+	for i := 0; i < 3; i++ {
+		if err := sender.SendMessage(context.Background(), notifier.Message{To: "x@example.com"}); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+	if primary.calls != 1 || backup.calls != 2 {
+		t.Fatalf("expected 1 primary and 2 backup sends, got %d/%d", primary.calls, backup.calls)
+	}
+}
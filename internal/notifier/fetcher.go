@@ -7,9 +7,26 @@ import (
 	"time"
 )
 
+// ProductTypeWarning is the upstream product_type of avalanche warnings.
+const ProductTypeWarning = "warning"
+
 type Forecast struct {
-	ZoneID   string
-	IssuedAt time.Time
+	ZoneID      string
+	IssuedAt    time.Time
+	ProductID   int
+	ProductType string
+}
+
+// IsWarning reports whether the forecast is an avalanche warning product.
+func (f Forecast) IsWarning() bool { return f.ProductType == ProductTypeWarning }
+
+// cacheKey identifies the forecast cache row; warnings are tracked separately
+// from regular forecasts for the same zone.
+func (f Forecast) cacheKey() string {
+	if f.IsWarning() {
+		return f.ZoneID + ":" + ProductTypeWarning
+	}
+	return f.ZoneID
 }
 
 type ForecastFetcher func(ctx context.Context, centerID string) ([]Forecast, error)
@@ -20,8 +37,10 @@ func FetchLatestStub(ctx context.Context) ([]Forecast, error) {
 }
 
 // MakeFetchFromSubscriptions returns a fetcher that inspects current subscriptions,
-// fetches forecasts per center, and returns the latest publish time per subscribed zone.
-// It expects zone IDs in the form "CENTER_ZONEID" (e.g., "NWAC_164") so it can infer the center.
+// fetches forecasts per center, and returns the latest product per subscribed zone
+// (warnings are reported separately from regular forecasts).
+// It expects zone IDs in the form "CENTER_ZONEID" (e.g., "NWAC_164") so it can infer the center;
+// a bare center ID (e.g., "NWAC") selects every zone of that center.
 func MakeFetchFromSubscriptions(repo Repository, api interface {
 	FetchForecasts(centerID string) ([]ForecastSource, error)
 }) ForecastFetcher {
@@ -37,10 +56,12 @@ func MakeFetchFromSubscriptions(repo Repository, api interface {
 
 		// Filter zones to only those for the requested center
 		zoneSet := make(map[string]struct{})
+		allZones := false
 		for _, z := range zones {
 			parts := strings.SplitN(z, "_", 2)
 			if len(parts) > 0 && parts[0] == centerID {
 				zoneSet[z] = struct{}{}
+				allZones = allZones || len(parts) == 1
 			}
 		}
 		if len(zoneSet) == 0 {
@@ -53,23 +74,23 @@ func MakeFetchFromSubscriptions(repo Repository, api interface {
 			return nil, err
 		}
 
-		// Track max PublishedAt per zone
-		latest := map[string]time.Time{}
+		// Track the newest product per zone and product kind
+		latest := map[string]Forecast{}
 		for _, src := range sources {
 			for _, z := range src.ZoneIDs() {
-				if _, want := zoneSet[z]; !want {
+				if _, want := zoneSet[z]; !want && !allZones {
 					continue
 				}
-				t := src.PublishedAt()
-				if t.After(latest[z]) {
-					latest[z] = t
+				f := Forecast{ZoneID: z, IssuedAt: src.PublishedAt(), ProductID: src.ProductID(), ProductType: src.ProductType()}
+				if cur, ok := latest[f.cacheKey()]; !ok || f.IssuedAt.After(cur.IssuedAt) {
+					latest[f.cacheKey()] = f
 				}
 			}
 		}
 
 		var out []Forecast
-		for z, t := range latest {
-			out = append(out, Forecast{ZoneID: z, IssuedAt: t})
+		for _, f := range latest {
+			out = append(out, f)
 		}
 		return out, nil
 	}
@@ -80,4 +101,6 @@ func MakeFetchFromSubscriptions(repo Repository, api interface {
 type ForecastSource interface {
 	ZoneIDs() []string
 	PublishedAt() time.Time
+	ProductID() int
+	ProductType() string
 }
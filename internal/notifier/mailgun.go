@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultMailgunBaseURL = "https://api.mailgun.net"
+
+// MailgunClient sends messages through the Mailgun /v3/{domain}/messages HTTP API.
+type MailgunClient struct {
+	BaseURL    string
+	Domain     string
+	APIKey     string
+	From       string
+	HTTPClient *http.Client
+}
+
+// NewMailgunClient returns a client for the given sending domain. baseURL may be
+// empty to use the US region API.
+func NewMailgunClient(baseURL, domain, apiKey, from string) (*MailgunClient, error) {
+	if domain == "" || apiKey == "" {
+		return nil, errors.New("mailgun domain and api key are required")
+	}
+	if baseURL == "" {
+		baseURL = defaultMailgunBaseURL
+	}
+	return &MailgunClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Domain:     domain,
+		APIKey:     apiKey,
+		From:       from,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SendMessage posts msg to Mailgun as a form-encoded request.
+func (c *MailgunClient) SendMessage(ctx context.Context, msg Message) error {
+	form := url.Values{}
+	form.Set("from", c.From)
+	form.Set("to", msg.To)
+	form.Set("subject", msg.Subject)
+	form.Set("text", msg.Text)
+	form.Set("html", msg.HTML)
+	if msg.ReplyTo != "" {
+		form.Set("h:Reply-To", msg.ReplyTo)
+	}
+
+	endpoint := fmt.Sprintf("%s/v3/%s/messages", c.BaseURL, url.PathEscape(c.Domain))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("api", c.APIKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailgun send failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &ProviderError{Provider: "mailgun", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultPostmarkBaseURL = "https://api.postmarkapp.com"
+
+// PostmarkClient sends messages through the Postmark /email HTTP API.
+type PostmarkClient struct {
+	BaseURL       string
+	ServerToken   string
+	From          string
+	MessageStream string
+	HTTPClient    *http.Client
+}
+
+// NewPostmarkClient returns a client for the given server token. baseURL may be
+// empty to use the public Postmark API.
+func NewPostmarkClient(baseURL, serverToken, from, messageStream string) (*PostmarkClient, error) {
+	if serverToken == "" {
+		return nil, errors.New("postmark server token is required")
+	}
+	if baseURL == "" {
+		baseURL = defaultPostmarkBaseURL
+	}
+	if messageStream == "" {
+		messageStream = "outbound"
+	}
+	return &PostmarkClient{
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		ServerToken:   serverToken,
+		From:          from,
+		MessageStream: messageStream,
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SendMessage posts msg to Postmark.
+func (c *PostmarkClient) SendMessage(ctx context.Context, msg Message) error {
+	fields := map[string]string{
+		"From":          c.From,
+		"To":            msg.To,
+		"Subject":       msg.Subject,
+		"TextBody":      msg.Text,
+		"HtmlBody":      msg.HTML,
+		"MessageStream": c.MessageStream,
+	}
+	if msg.ReplyTo != "" {
+		fields["ReplyTo"] = msg.ReplyTo
+	}
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/email", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Postmark-Server-Token", c.ServerToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("postmark send failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &ProviderError{Provider: "postmark", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"example.com/avalanche/internal/htmltext"
+	"example.com/avalanche/internal/models"
+)
+
+// emailRenderer turns forecast data into provider-neutral Messages so every
+// EmailSender implementation delivers identical content.
+type emailRenderer struct {
+	forecast *template.Template
+	// imageBaseURL is the public address of the API server, used to link
+	// zone danger badges into emails. Images are omitted when it is empty.
+	imageBaseURL string
+}
+
+func newEmailRenderer() *emailRenderer {
+	tmpl, err := template.ParseFiles("internal/email/templates/forecast.html")
+	if err != nil {
+		tmpl = template.Must(template.New("forecast").Parse(defaultTemplate))
+	}
+	return &emailRenderer{forecast: tmpl, imageBaseURL: strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/")}
+}
+
+const defaultTemplate = `<div style="font-family:Arial,sans-serif;">
+	<h2>New Avalanche Forecast</h2>
+	{{if .Trip}}<p>For your trip{{if .Trip.Name}} <b>{{.Trip.Name}}</b>{{end}} on <b>{{.Trip.Date}}</b>.</p>{{end}}
+	<p>A new forecast has been issued for zone <b>{{if .ZoneName}}{{.ZoneName}} ({{.ZoneID}}){{else}}{{.ZoneID}}{{end}}</b> at <b>{{.IssuedAt}}</b>.</p>
+	{{if .BadgeURL}}<p><a href="{{.CenterLink}}"><img src="{{.BadgeURL}}" alt="Current avalanche danger" width="220" height="88"></a></p>{{end}}
+	{{with .Today}}<p>Today: <b>{{.Max.Label}}</b>. {{.Max.TravelAdvice}}</p>{{end}}
+	{{with .BottomLine}}<h3>Bottom line</h3><div>{{.}}</div>{{end}}
+	<p>Check the latest details on <a href="{{.CenterLink}}">Visit Center Website</a>.</p>
+</div>`
+
+// replyCommandsHelp tells subscribers which commands they can reply with.
+const replyCommandsHelp = "Reply STOP to unsubscribe, PAUSE 7 to pause for a week, RESUME to resume, or DIGEST for at most one email a day."
+
+// forecastHTML renders the single-zone forecast template.
+func (r *emailRenderer) forecastHTML(data EmailData) (string, error) {
+	var badgeURL, historyURL string
+	if r.imageBaseURL != "" && strings.Contains(data.ZoneID, "_") {
+		zoneURL := r.imageBaseURL + "/api/zones/" + url.PathEscape(data.ZoneID)
+		badgeURL, historyURL = zoneURL+"/badge.svg", zoneURL+"/history.svg"
+	}
+	var buf bytes.Buffer
+	err := r.forecast.Execute(&buf, map[string]any{
+		"ZoneID":     data.ZoneID,
+		"ZoneName":   data.ZoneName,
+		"IssuedAt":   data.IssuedAt.Format("Mon Jan 2 15:04 2006 MST"),
+		"Today":      data.Today,
+		"Tomorrow":   data.Tomorrow,
+		"CenterLink": data.CenterLink,
+		"BadgeURL":   badgeURL,
+		"HistoryURL": historyURL,
+		"Trip":       tripTemplateData(data.Trip),
+		// Sanitized again in case the sender did not build the forecast.
+		"BottomLine": template.HTML(htmltext.Sanitize(data.BottomLine)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("template execute failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// forecastMessage renders the single-zone forecast email.
+func (r *emailRenderer) forecastMessage(recipient string, data EmailData) (Message, error) {
+	body, err := r.forecastHTML(data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	label := data.ZoneID
+	if strings.TrimSpace(data.ZoneName) != "" {
+		label = data.ZoneName
+	}
+	msg := Message{
+		To:      recipient,
+		ReplyTo: data.ReplyTo,
+		Subject: fmt.Sprintf("New Avalanche Forecast for %s", label),
+		Text:    "A new avalanche forecast is available.",
+		HTML:    body,
+	}
+	if t := data.Trip; t != nil {
+		day := t.Date.Format("Mon Jan 2")
+		if t.Outlook == models.TripOutlookToday {
+			msg.Subject = fmt.Sprintf("Trip day: Avalanche Forecast for %s", label)
+		} else {
+			msg.Subject = fmt.Sprintf("Avalanche Outlook for %s before your trip on %s", label, day)
+		}
+		msg.Text = fmt.Sprintf("The avalanche forecast for your trip on %s is available.", day)
+	}
+	if bl := htmltext.Text(data.BottomLine); bl != "" {
+		msg.Text += "\n\n" + bl
+	}
+	if t := data.Trip; t != nil && t.Link != "" {
+		note := "You are receiving these emails as a participant of this trip plan until the trip is over. To leave the plan, visit " + t.Link
+		msg.Text += "\n\n" + note
+		msg.HTML += "<p style=\"font-size:12px;color:#666;\">" + html.EscapeString(note) + "</p>"
+	}
+	if data.ReplyTo != "" {
+		msg.Text += "\n\n" + replyCommandsHelp
+		msg.HTML += "<p style=\"font-size:12px;color:#666;\">" + replyCommandsHelp + "</p>"
+	}
+	return msg, nil
+}
+
+// tripTemplateData exposes a trip to the forecast template, or nil for
+// subscription emails.
+func tripTemplateData(t *TripEmail) map[string]any {
+	if t == nil {
+		return nil
+	}
+	return map[string]any{
+		"Name":    t.Name,
+		"Date":    t.Date.Format("Monday, January 2"),
+		"TripDay": t.Outlook == models.TripOutlookToday,
+	}
+}
+
+// centerForecastMessage renders the aggregated center summary email, falling back
+// to inline HTML when the template file is unavailable.
+func (r *emailRenderer) centerForecastMessage(recipient, centerName, centerLink string, zones []ZoneSummary) (Message, error) {
+	msg := Message{
+		To:      recipient,
+		Subject: fmt.Sprintf("%s Avalanche Center Forecast Summary", centerName),
+		Text:    "Your avalanche center forecast summary is available.",
+	}
+
+	mapURL := r.centerMapURL(zones)
+	tmpl, err := template.ParseFiles("internal/email/templates/center_forecast.html")
+	if err != nil {
+		log.Printf("center template not found, using fallback: %v", err)
+		msg.HTML = centerForecastFallbackHTML(centerName, centerLink, mapURL, zones)
+		return msg, nil
+	}
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"CenterName": centerName,
+		"CenterLink": centerLink,
+		"MapURL":     mapURL,
+		"ZoneCount":  len(zones),
+		"Zones":      zones,
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return Message{}, fmt.Errorf("template execute failed: %w", err)
+	}
+	msg.HTML = buf.String()
+	return msg, nil
+}
+
+// centerMapURL returns the address of the danger map for the center of
+// zones, or "" when images are disabled.
+func (r *emailRenderer) centerMapURL(zones []ZoneSummary) string {
+	if r.imageBaseURL == "" || len(zones) == 0 {
+		return ""
+	}
+	centerID, _, ok := strings.Cut(zones[0].ZoneID, "_")
+	if !ok || centerID == "" {
+		return ""
+	}
+	return r.imageBaseURL + "/api/centers/" + url.PathEscape(centerID) + "/map.svg"
+}
+
+// centerForecastFallbackHTML builds a simple HTML body if the template is unavailable.
+func centerForecastFallbackHTML(centerName, centerLink, mapURL string, zones []ZoneSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h2>Latest Avalanche Forecasts - %s</h2>", centerName)
+	if mapURL != "" {
+		fmt.Fprintf(&b, "<p><a href=\"%s\"><img src=\"%s\" alt=\"%s danger map\" width=\"600\"></a></p>", centerLink, mapURL, centerName)
+	}
+	fmt.Fprintf(&b, "<p>%d zones have current forecasts.</p><ul>", len(zones))
+	for _, z := range zones {
+		fmt.Fprintf(&b, "<li><strong>%s</strong> (%s) - Today: %s | Tomorrow: %s</li>", z.ZoneName, z.ZoneID, z.TodayStr, z.TomorrowStr)
+	}
+	fmt.Fprintf(&b, "</ul><p>For details visit <a href=\"%s\">%s</a>.</p>", centerLink, centerName)
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package notifier_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+func TestForecastEmails_LinkDangerImages(t *testing.T) {
+	t.Setenv("PUBLIC_BASE_URL", "https://avy.example.com/")
+	provider := &fakeProvider{}
+	sender, _ := notifier.NewFailoverEmailSender(nil, notifier.Provider{Name: "primary", Sender: provider})
+
+	data := notifier.EmailData{ZoneID: "NWAC_10", CenterLink: "https://nwac.us"}
+	if err := sender.SendForecastEmail(context.Background(), "user@example.com", data); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if !strings.Contains(provider.last.HTML, `src="https://avy.example.com/api/zones/NWAC_10/badge.svg"`) {
+		t.Errorf("expected linked badge image in %s", provider.last.HTML)
+	}
+
+	zones := []notifier.ZoneSummary{{ZoneID: "NWAC_10", ZoneName: "Snoqualmie Pass"}}
+	if err := sender.SendCenterForecastEmail(context.Background(), "user@example.com", "NWAC", "https://nwac.us", zones); err != nil {
+		t.Fatalf("send center summary: %v", err)
+	}
+	if !strings.Contains(provider.last.HTML, `src="https://avy.example.com/api/centers/NWAC/map.svg"`) {
+		t.Errorf("expected linked center map in %s", provider.last.HTML)
+	}
+}
+
+func TestForecastEmails_TripCountdown(t *testing.T) {
+	provider := &fakeProvider{}
+	sender, _ := notifier.NewFailoverEmailSender(nil, notifier.Provider{Name: "primary", Sender: provider})
+
+	trip := &notifier.TripEmail{
+		Name:    "Saturday tour",
+		Date:    time.Date(2025, 12, 6, 0, 0, 0, 0, time.UTC),
+		Outlook: models.TripOutlookTomorrow,
+		Link:    "https://avy.example.com/api/trips/t7-abc",
+	}
+	data := notifier.EmailData{ZoneID: "CAIC_1", ZoneName: "Front Range", Trip: trip,
+		BottomLine: "<p>Wind slabs on northerly slopes.</p>"}
+	if err := sender.SendForecastEmail(context.Background(), "user@example.com", data); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if want := "Avalanche Outlook for Front Range before your trip on Sat Dec 6"; provider.last.Subject != want {
+		t.Errorf("subject = %q, want %q", provider.last.Subject, want)
+	}
+	if !strings.Contains(provider.last.HTML, "Saturday tour") || !strings.Contains(provider.last.Text, trip.Link) {
+		t.Errorf("expected trip name and share link in %+v", provider.last)
+	}
+	if !strings.HasPrefix(provider.last.Text, "The avalanche forecast for your trip on Sat Dec 6 is available.\n\nWind slabs on northerly slopes.") {
+		t.Errorf("expected the trip intro followed by the bottom line, got %q", provider.last.Text)
+	}
+
+	trip.Outlook = models.TripOutlookToday
+	if err := sender.SendForecastEmail(context.Background(), "user@example.com", data); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if want := "Trip day: Avalanche Forecast for Front Range"; provider.last.Subject != want {
+		t.Errorf("subject = %q, want %q", provider.last.Subject, want)
+	}
+}
@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"errors"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+
+	"example.com/avalanche/internal/signing"
+)
+
+// replyLocalPrefix starts the local part of every reply-to address.
+const replyLocalPrefix = "reply+"
+
+// ErrNotReplyAddress is returned for addresses that are not signed reply-to addresses.
+var ErrNotReplyAddress = errors.New("not a reply address")
+
+// ReplyAddresser builds signed per-subscription reply-to addresses such as
+// reply+s42-1a2b...@reply.example.com, so inbound replies can be matched to
+// the subscription they answer without trusting the sender's From header.
+type ReplyAddresser struct {
+	secret string
+	domain string
+}
+
+// NewReplyAddresser returns an addresser for domain signed with secret.
+func NewReplyAddresser(secret, domain string) *ReplyAddresser {
+	return &ReplyAddresser{secret: secret, domain: strings.ToLower(domain)}
+}
+
+// ReplyAddresserFromEnv reads REPLY_TOKEN_SECRET and REPLY_DOMAIN. It returns
+// nil when either is unset, disabling reply commands.
+func ReplyAddresserFromEnv() *ReplyAddresser {
+	secret, domain := os.Getenv("REPLY_TOKEN_SECRET"), os.Getenv("REPLY_DOMAIN")
+	if secret == "" || domain == "" {
+		return nil
+	}
+	return NewReplyAddresser(secret, domain)
+}
+
+// Address returns the reply-to address for a subscription.
+func (a *ReplyAddresser) Address(subID uint) string {
+	return replyLocalPrefix + signing.Token(a.secret, "s"+strconv.FormatUint(uint64(subID), 10)) + "@" + a.domain
+}
+
+// SubscriptionID extracts and verifies the subscription ID from a reply-to
+// address. addr may include a display name.
+func (a *ReplyAddresser) SubscriptionID(addr string) (uint, error) {
+	if parsed, err := mail.ParseAddress(addr); err == nil {
+		addr = parsed.Address
+	}
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(addr)), "@")
+	if !ok || domain != a.domain || !strings.HasPrefix(local, replyLocalPrefix) {
+		return 0, ErrNotReplyAddress
+	}
+	payload, err := signing.ParseToken(a.secret, strings.TrimPrefix(local, replyLocalPrefix))
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(payload, "s"), 10, 64)
+	if err != nil || !strings.HasPrefix(payload, "s") {
+		return 0, signing.ErrInvalidToken
+	}
+	return uint(id), nil
+}
@@ -6,8 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"example.com/avalanche/internal/domain"
 	"example.com/avalanche/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SubscriptionReader provides read-only access to subscription data.
@@ -22,10 +24,12 @@ type SubscriptionWriter interface {
 	UpdateLastNotified(ctx context.Context, subID uint, t time.Time) error
 }
 
-// ForecastCache provides access to forecast cache data for tracking last issued times.
+// ForecastCache provides access to forecast cache data for tracking the last
+// issued product per zone.
 type ForecastCache interface {
-	GetLastIssued(ctx context.Context, zoneID string) (time.Time, error)
-	UpsertLastIssued(ctx context.Context, zoneID string, issuedAt time.Time) error
+	// GetCachedForecast returns the cache row for key, or a zero value when none exists.
+	GetCachedForecast(ctx context.Context, key string) (models.ForecastCache, error)
+	UpsertCachedForecast(ctx context.Context, fc models.ForecastCache) error
 }
 
 // Repository combines all data access interfaces for the notifier service.
@@ -42,21 +46,56 @@ type CenterLookup interface {
 }
 
 type GormRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	watch []string
 }
 
 func NewGormRepository(db *gorm.DB) *GormRepository {
 	return &GormRepository{db: db}
 }
 
+// GetSubscriptionsForZone returns the subscriptions for zoneID, including
+// those following a zone group that contains it.
 func (r *GormRepository) GetSubscriptionsForZone(ctx context.Context, zoneID string) ([]models.Subscription, error) {
+	groups, err := r.subscribedGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{zoneID}
+	for i := range groups {
+		if groups[i].Contains(zoneID) {
+			ids = append(ids, groups[i].Ref())
+		}
+	}
 	var subs []models.Subscription
-	if err := r.db.WithContext(ctx).Where("zone_id = ?", zoneID).Find(&subs).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("zone_id IN ?", ids).Find(&subs).Error; err != nil {
 		return nil, err
 	}
 	return subs, nil
 }
 
+// subscribedGroups loads the zone groups followed by at least one
+// subscription. Zone group tables are not touched while nobody follows one.
+func (r *GormRepository) subscribedGroups(ctx context.Context) ([]models.ZoneGroup, error) {
+	var refs []string
+	if err := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("zone_id LIKE ?", domain.GroupPrefix+"%").Distinct().Pluck("zone_id", &refs).Error; err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	slugs := make([]string, len(refs))
+	for i, ref := range refs {
+		slugs[i] = strings.TrimPrefix(ref, domain.GroupPrefix)
+	}
+	var groups []models.ZoneGroup
+	if err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
 func (r *GormRepository) UpdateLastNotified(ctx context.Context, subID uint, t time.Time) error {
 	return r.db.WithContext(ctx).
 		Model(&models.Subscription{}).
@@ -64,17 +103,16 @@ func (r *GormRepository) UpdateLastNotified(ctx context.Context, subID uint, t t
 		Update("last_notified", t).Error
 }
 
-func (r *GormRepository) GetLastIssued(ctx context.Context, zoneID string) (time.Time, error) {
+func (r *GormRepository) GetCachedForecast(ctx context.Context, key string) (models.ForecastCache, error) {
 	var fc models.ForecastCache
-	err := r.db.WithContext(ctx).First(&fc, "zone_id = ?", zoneID).Error
+	err := r.db.WithContext(ctx).First(&fc, "zone_id = ?", key).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return time.Time{}, nil
+		return models.ForecastCache{}, nil
 	}
-	return fc.LastIssued, err
+	return fc, err
 }
 
-func (r *GormRepository) UpsertLastIssued(ctx context.Context, zoneID string, issuedAt time.Time) error {
-	fc := models.ForecastCache{ZoneID: zoneID, LastIssued: issuedAt}
+func (r *GormRepository) UpsertCachedForecast(ctx context.Context, fc models.ForecastCache) error {
 	return r.db.WithContext(ctx).Save(&fc).Error
 }
 
@@ -91,18 +129,46 @@ func (r *GormRepository) GetCenterByID(ctx context.Context, centerID string) (*m
 	return &center, nil
 }
 
+// Watch adds zone or center IDs that are checked for new forecasts even
+// without subscribers, so the event log covers them.
+func (r *GormRepository) Watch(ids ...string) {
+	r.watch = append(r.watch, ids...)
+}
+
+// ListSubscribedZones returns every zone or center ID watched by a subscription,
+// an active webhook endpoint or Watch. Zone groups are expanded into their
+// member zones.
 func (r *GormRepository) ListSubscribedZones(ctx context.Context) ([]string, error) {
-	var zones []string
-	if err := r.db.WithContext(ctx).Model(&models.Subscription{}).Distinct().Pluck("zone_id", &zones).Error; err != nil {
+	var subscribed, hooks []string
+	if err := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("zone_id NOT LIKE ?", domain.GroupPrefix+"%").Distinct().Pluck("zone_id", &subscribed).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).Where("active = ?", true).Distinct().Pluck("zone_id", &hooks).Error; err != nil {
 		return nil, err
 	}
+	groups, err := r.subscribedGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		subscribed = append(subscribed, groups[i].ZoneIDs...)
+	}
+	var zones []string
+	seen := make(map[string]struct{}, len(subscribed))
+	for _, z := range append(append(subscribed, hooks...), r.watch...) {
+		if _, ok := seen[z]; !ok {
+			seen[z] = struct{}{}
+			zones = append(zones, z)
+		}
+	}
 	return zones, nil
 }
 
-// ListSubscribedCenters returns all unique center IDs (prefix before underscore in zone_id) with at least one subscription.
+// ListSubscribedCenters returns all unique center IDs (prefix before underscore in zone_id) with at least one subscription or webhook endpoint.
 func (r *GormRepository) ListSubscribedCenters(ctx context.Context) ([]string, error) {
-	var zoneIDs []string
-	if err := r.db.WithContext(ctx).Model(&models.Subscription{}).Distinct().Pluck("zone_id", &zoneIDs).Error; err != nil {
+	zoneIDs, err := r.ListSubscribedZones(ctx)
+	if err != nil {
 		return nil, err
 	}
 	centerSet := make(map[string]struct{})
@@ -121,3 +187,108 @@ func (r *GormRepository) ListSubscribedCenters(ctx context.Context) ([]string, e
 	}
 	return centers, nil
 }
+
+// RecordEmailAttempt stores a single provider delivery attempt.
+func (r *GormRepository) RecordEmailAttempt(ctx context.Context, attempt models.EmailDeliveryAttempt) error {
+	return r.db.WithContext(ctx).Create(&attempt).Error
+}
+
+// IsSuppressed reports whether email is on the suppression list.
+func (r *GormRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.EmailSuppression{}).
+		Where("email = ?", strings.ToLower(strings.TrimSpace(email))).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListWebhookEndpoints returns active endpoints scoped to zoneID or its center.
+func (r *GormRepository) ListWebhookEndpoints(ctx context.Context, zoneID string) ([]models.WebhookEndpoint, error) {
+	center, _, _ := strings.Cut(zoneID, "_")
+	var out []models.WebhookEndpoint
+	err := r.db.WithContext(ctx).
+		Where("active = ? AND zone_id IN ?", true, []string{zoneID, center}).
+		Find(&out).Error
+	return out, err
+}
+
+// RecordWebhookDelivery stores a single webhook delivery attempt.
+func (r *GormRepository) RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(&d).Error
+}
+
+// RecordWebhookResult updates the endpoint's consecutive failure counter and returns it.
+func (r *GormRepository) RecordWebhookResult(ctx context.Context, endpointID uint, success bool) (int, error) {
+	q := r.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).Where("id = ?", endpointID)
+	if success {
+		return 0, q.Update("consecutive_failures", 0).Error
+	}
+	if err := q.Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+		return 0, err
+	}
+	var ep models.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Select("consecutive_failures").First(&ep, endpointID).Error; err != nil {
+		return 0, err
+	}
+	return ep.ConsecutiveFailures, nil
+}
+
+// DisableWebhookEndpoint stops deliveries to an endpoint until it is re-enabled.
+func (r *GormRepository) DisableWebhookEndpoint(ctx context.Context, endpointID uint, reason string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.WebhookEndpoint{}).
+		Where("id = ?", endpointID).
+		Updates(map[string]any{"active": false, "disabled_at": time.Now().UTC(), "disabled_reason": reason}).Error
+}
+
+// DeleteSubscription removes a subscription whose target no longer accepts deliveries.
+func (r *GormRepository) DeleteSubscription(ctx context.Context, subID uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Subscription{}, subID).Error
+}
+
+// AppendForecastEvent records ev in the event log, ignoring duplicates.
+func (r *GormRepository) AppendForecastEvent(ctx context.Context, ev models.ForecastEvent) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoNothing: true,
+	}).Create(&ev).Error
+}
+
+// PruneForecastEvents deletes events created before the cutoff.
+func (r *GormRepository) PruneForecastEvents(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.ForecastEvent{}).Error
+}
+
+// ArchiveForecast inserts f, or updates the existing row for the same zone and
+// product while keeping its original IssuedAt.
+func (r *GormRepository) ArchiveForecast(ctx context.Context, f models.ArchivedForecast) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "zone_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"product_type", "zone_name", "center_name", "title", "link", "content", "amended_at",
+			"valid_date", "valid_from", "valid_until", "danger_upper", "danger_middle", "danger_lower", "bottom_line",
+		}),
+	}).Create(&f).Error
+}
+
+// ListTripPlans returns the trip plans that have not expired at now.
+func (r *GormRepository) ListTripPlans(ctx context.Context, now time.Time) ([]models.TripPlan, error) {
+	var out []models.TripPlan
+	err := r.db.WithContext(ctx).Where("expires_at > ?", now).Order("id").Find(&out).Error
+	return out, err
+}
+
+// MarkTripStepsSent records how many schedule steps of a plan were handled.
+func (r *GormRepository) MarkTripStepsSent(ctx context.Context, planID uint, steps int) error {
+	return r.db.WithContext(ctx).
+		Model(&models.TripPlan{}).
+		Where("id = ?", planID).
+		Update("steps_sent", steps).Error
+}
+
+// DeleteExpiredTripPlans removes plans whose trip is over and returns how many.
+func (r *GormRepository) DeleteExpiredTripPlans(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.TripPlan{})
+	return res.RowsAffected, res.Error
+}
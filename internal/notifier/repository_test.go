@@ -0,0 +1,71 @@
+package notifier_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGormRepository_ExpandsZoneGroups(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.WebhookEndpoint{}, &models.ZoneGroup{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, v := range []any{
+		&models.ZoneGroup{Slug: "home-range", Name: "Home", ZoneIDs: []string{"NWAC_10", "IPAC_1"}},
+		&models.ZoneGroup{Slug: "unfollowed", Name: "Nobody", ZoneIDs: []string{"CAIC_5"}},
+		&models.Subscription{Email: "zone@example.com", ZoneID: "NWAC_10"},
+		&models.Subscription{Email: "group@example.com", ZoneID: "group:home-range"},
+		&models.Subscription{Email: "center@example.com", ZoneID: "NWAC"},
+	} {
+		if err := gdb.Create(v).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	repo := notifier.NewGormRepository(gdb)
+	ctx := context.Background()
+
+	zones, err := repo.ListSubscribedZones(ctx)
+	if err != nil {
+		t.Fatalf("list zones: %v", err)
+	}
+	slices.Sort(zones)
+	if !slices.Equal(zones, []string{"IPAC_1", "NWAC", "NWAC_10"}) {
+		t.Errorf("unexpected zones %v", zones)
+	}
+	centers, err := repo.ListSubscribedCenters(ctx)
+	if err != nil {
+		t.Fatalf("list centers: %v", err)
+	}
+	slices.Sort(centers)
+	if !slices.Equal(centers, []string{"IPAC", "NWAC"}) {
+		t.Errorf("unexpected centers %v", centers)
+	}
+
+	for zone, want := range map[string][]string{
+		"NWAC_10": {"group@example.com", "zone@example.com"},
+		"IPAC_1":  {"group@example.com"},
+		"NWAC_11": nil,
+	} {
+		subs, err := repo.GetSubscriptionsForZone(ctx, zone)
+		if err != nil {
+			t.Fatalf("subscriptions for %s: %v", zone, err)
+		}
+		var got []string
+		for _, s := range subs {
+			got = append(got, s.Email)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %v, want %v", zone, got, want)
+		}
+	}
+}
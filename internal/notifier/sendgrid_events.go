@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/avalanche/internal/models"
+)
+
+// Headers SendGrid attaches to signed Event Webhook requests.
+const (
+	SendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	SendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// ErrInvalidSignature is returned when a webhook payload fails verification.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// SendGridEvent is a single entry of a SendGrid Event Webhook payload.
+type SendGridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	Type      string `json:"type,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+	EventID   string `json:"sg_event_id,omitempty"`
+}
+
+// SuppressionReason maps the event to a suppression reason. ok is false for
+// events that should not suppress the address, such as deliveries, opens and
+// soft "blocked" bounces.
+func (e SendGridEvent) SuppressionReason() (reason string, ok bool) {
+	switch e.Event {
+	case "bounce":
+		if e.Type == "blocked" {
+			return "", false
+		}
+		return models.SuppressionBounce, true
+	case "dropped":
+		return models.SuppressionDropped, true
+	case "spamreport":
+		return models.SuppressionSpamReport, true
+	case "unsubscribe", "group_unsubscribe":
+		return models.SuppressionUnsubscribe, true
+	}
+	return "", false
+}
+
+// ParseSendGridPublicKey decodes the base64 DER verification key shown in the
+// SendGrid Event Webhook settings.
+func ParseSendGridPublicKey(b64 string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+	if err != nil {
+		return nil, fmt.Errorf("decode sendgrid webhook key: %w", err)
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse sendgrid webhook key: %w", err)
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("sendgrid webhook key must be ECDSA")
+	}
+	return key, nil
+}
+
+// VerifySendGridSignature checks the ECDSA signature SendGrid computes over the
+// timestamp header followed by the raw request body.
+func VerifySendGridSignature(key *ecdsa.PublicKey, signature, timestamp string, payload []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || timestamp == "" {
+		return ErrInvalidSignature
+	}
+	h := sha256.New()
+	h.Write([]byte(timestamp))
+	h.Write(payload)
+	if !ecdsa.VerifyASN1(key, h.Sum(nil), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseSendGridEvents decodes an Event Webhook payload.
+func ParseSendGridEvents(payload []byte) ([]SendGridEvent, error) {
+	var events []SendGridEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		return nil, fmt.Errorf("decode sendgrid events: %w", err)
+	}
+	return events, nil
+}
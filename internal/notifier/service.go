@@ -2,20 +2,52 @@ package notifier
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 	"time"
+
+	"example.com/avalanche/internal/models"
 )
 
+// ForecastDetailsFunc returns processed zone forecasts for a center. The
+// service uses it to enrich notifications with danger ratings and bottom lines.
+type ForecastDetailsFunc func(ctx context.Context, centerID string) ([]models.ZoneForecast, error)
+
 type Service struct {
-	repo     Repository
-	sender   EmailSender
-	interval time.Duration
-	fetchFn  ForecastFetcher
+	repo      Repository
+	sender    EmailSender
+	interval  time.Duration
+	fetchFn   ForecastFetcher
+	detailsFn ForecastDetailsFunc
+	channels  map[string]Channel
+	sinks     []EventSink
+	replies   *ReplyAddresser
 }
 
 func NewService(repo Repository, sender EmailSender, interval time.Duration, fetchFn ForecastFetcher) *Service {
-	return &Service{repo: repo, sender: sender, interval: interval, fetchFn: fetchFn}
+	return &Service{repo: repo, sender: sender, interval: interval, fetchFn: fetchFn, channels: map[string]Channel{}}
+}
+
+// SetReplyAddresser makes forecast emails carry a signed reply-to address so
+// subscribers can manage their subscription by replying.
+func (s *Service) SetReplyAddresser(a *ReplyAddresser) {
+	s.replies = a
+}
+
+// AddEventSink registers a sink that receives every detected forecast event.
+func (s *Service) AddEventSink(sink EventSink) {
+	s.sinks = append(s.sinks, sink)
+}
+
+// RegisterChannel routes subscriptions whose Channel equals name to ch.
+func (s *Service) RegisterChannel(name string, ch Channel) {
+	s.channels[name] = ch
+}
+
+// SetForecastDetails configures where full zone forecasts are loaded from.
+func (s *Service) SetForecastDetails(fn ForecastDetailsFunc) {
+	s.detailsFn = fn
 }
 
 func (s *Service) Run(ctx context.Context) error {
@@ -34,6 +66,11 @@ func (s *Service) Run(ctx context.Context) error {
 	}
 }
 
+// RunOnce performs a single detection and notification pass.
+func (s *Service) RunOnce(ctx context.Context) {
+	s.checkAndNotify(ctx)
+}
+
 func (s *Service) checkAndNotify(ctx context.Context) {
 	centers, err := s.repo.ListSubscribedCenters(ctx)
 	if err != nil {
@@ -56,14 +93,34 @@ func (s *Service) checkAndNotify(ctx context.Context) {
 			log.Printf("center %s has no base_url configured", centerID)
 			continue
 		}
+		var details map[string]*models.ZoneForecast
 		for _, f := range forecasts {
-			lastIssued, err := s.repo.GetLastIssued(ctx, f.ZoneID)
+			cached, err := s.repo.GetCachedForecast(ctx, f.cacheKey())
 			if err != nil {
-				log.Printf("cache read failed for %s: %v", f.ZoneID, err)
+				log.Printf("cache read failed for %s: %v", f.cacheKey(), err)
+				continue
+			}
+			if !cached.LastIssued.IsZero() && !f.IssuedAt.After(cached.LastIssued) {
+				log.Printf("no new forecast for %s", f.cacheKey())
 				continue
 			}
-			if !lastIssued.IsZero() && !f.IssuedAt.After(lastIssued) {
-				log.Printf("no new forecast for %s", f.ZoneID)
+			if details == nil {
+				details = s.loadDetails(ctx, centerID)
+			}
+			n := Notification{
+				ZoneID:     f.ZoneID,
+				CenterID:   centerID,
+				CenterName: centerMeta.Name,
+				CenterLink: centerURL,
+				IssuedAt:   f.IssuedAt,
+			}
+			if zf, ok := details[f.ZoneID]; ok {
+				n.ZoneName = zf.ZoneName
+				n.Forecast = zf
+			}
+			s.publish(ctx, newEvent(eventType(f, cached), f, n))
+			if f.IsWarning() {
+				s.updateCache(ctx, f)
 				continue
 			}
 			subs, err := s.repo.GetSubscriptionsForZone(ctx, f.ZoneID)
@@ -72,16 +129,73 @@ func (s *Service) checkAndNotify(ctx context.Context) {
 				continue
 			}
 			for _, sub := range subs {
-				data := EmailData{ZoneID: f.ZoneID, IssuedAt: f.IssuedAt, CenterLink: centerURL}
-				if err := s.sender.SendForecastEmail(ctx, sub.Email, data); err != nil {
-					log.Printf("send failed to %s: %v", sub.Email, err)
+				if sub.IsPaused() || (sub.Digest && !sub.IsDueForNotification(models.DigestInterval)) {
+					continue
+				}
+				if err := s.deliver(ctx, sub, n); err != nil {
+					log.Printf("send failed for subscription %d (%s): %v", sub.ID, sub.ChannelOrDefault(), err)
 					continue
 				}
 				_ = s.repo.UpdateLastNotified(ctx, sub.ID, time.Now())
 			}
-			if err := s.repo.UpsertLastIssued(ctx, f.ZoneID, f.IssuedAt); err != nil {
-				log.Printf("cache update failed for %s: %v", f.ZoneID, err)
-			}
+			s.updateCache(ctx, f)
+		}
+	}
+}
+
+func (s *Service) updateCache(ctx context.Context, f Forecast) {
+	fc := models.ForecastCache{ZoneID: f.cacheKey(), LastIssued: f.IssuedAt, ProductID: f.ProductID}
+	if err := s.repo.UpsertCachedForecast(ctx, fc); err != nil {
+		log.Printf("cache update failed for %s: %v", f.cacheKey(), err)
+	}
+}
+
+// publish hands ev to every registered sink. Sink failures are logged and do
+// not block subscriber notifications.
+func (s *Service) publish(ctx context.Context, ev Event) {
+	for _, sink := range s.sinks {
+		if err := sink.HandleEvent(ctx, ev); err != nil {
+			log.Printf("event sink failed for %s %s: %v", ev.Type, ev.ZoneID, err)
+		}
+	}
+}
+
+// loadDetails fetches processed forecasts for a center keyed by zone ID. It
+// never returns nil so callers load details at most once per center.
+func (s *Service) loadDetails(ctx context.Context, centerID string) map[string]*models.ZoneForecast {
+	out := map[string]*models.ZoneForecast{}
+	if s.detailsFn == nil {
+		return out
+	}
+	zones, err := s.detailsFn(ctx, centerID)
+	if err != nil {
+		log.Printf("forecast details failed for center %s: %v", centerID, err)
+		return out
+	}
+	for i := range zones {
+		out[zones[i].ZoneID] = &zones[i]
+	}
+	return out
+}
+
+// deliver sends n to sub over the subscription's channel.
+func (s *Service) deliver(ctx context.Context, sub models.Subscription, n Notification) error {
+	channel := sub.ChannelOrDefault()
+	if channel == models.ChannelEmail {
+		data := EmailData{ZoneID: n.ZoneID, ZoneName: n.ZoneName, IssuedAt: n.IssuedAt, CenterLink: n.CenterLink}
+		if n.Forecast != nil {
+			data.Today = n.Forecast.TodayDanger
+			data.Tomorrow = n.Forecast.FutureDanger
+			data.BottomLine = n.Forecast.SafeBottomLine()
 		}
+		if s.replies != nil {
+			data.ReplyTo = s.replies.Address(sub.ID)
+		}
+		return s.sender.SendForecastEmail(ctx, sub.Email, data)
+	}
+	ch, ok := s.channels[channel]
+	if !ok {
+		return fmt.Errorf("no sender registered for channel %q", channel)
 	}
+	return ch.Notify(ctx, sub, n)
 }
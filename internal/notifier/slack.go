@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+const defaultSlackBaseURL = "https://slack.com/api"
+
+// slackZoneListLimit keeps zone lists under Slack's 50-block message limit.
+const slackZoneListLimit = 45
+
+// ErrNoSlackToken is returned by NewSlackBotClientFromEnv when SLACK_BOT_TOKEN is unset.
+var ErrNoSlackToken = errors.New("no Slack bot token configured (set SLACK_BOT_TOKEN)")
+
+// SlackBotClient posts messages through the Slack Web API with a bot token.
+// It delivers to subscriptions created from the slash command, whose Target
+// holds the Slack channel ID.
+type SlackBotClient struct {
+	BaseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewSlackBotClient returns a client for token. baseURL may be empty to use
+// the public Slack API.
+func NewSlackBotClient(baseURL, token string) (*SlackBotClient, error) {
+	if token == "" {
+		return nil, ErrNoSlackToken
+	}
+	if baseURL == "" {
+		baseURL = defaultSlackBaseURL
+	}
+	return &SlackBotClient{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// NewSlackBotClientFromEnv reads SLACK_BOT_TOKEN and, optionally, SLACK_API_URL.
+func NewSlackBotClientFromEnv() (*SlackBotClient, error) {
+	return NewSlackBotClient(os.Getenv("SLACK_API_URL"), os.Getenv("SLACK_BOT_TOKEN"))
+}
+
+// Notify posts n to the subscription's Slack channel.
+func (c *SlackBotClient) Notify(ctx context.Context, sub models.Subscription, n Notification) error {
+	if sub.Target == "" {
+		return fmt.Errorf("subscription %d has no Slack channel", sub.ID)
+	}
+	return c.PostMessage(ctx, sub.Target, slackMessage(n))
+}
+
+// PostMessage posts msg, a chat.postMessage payload without a channel, to channel.
+func (c *SlackBotClient) PostMessage(ctx context.Context, channel string, msg map[string]any) error {
+	payload := make(map[string]any, len(msg)+1)
+	for k, v := range msg {
+		payload[k] = v
+	}
+	payload["channel"] = channel
+	return c.call(ctx, "chat.postMessage", payload)
+}
+
+// PostEphemeral shows text to a single user in channel.
+func (c *SlackBotClient) PostEphemeral(ctx context.Context, channel, user, text string) error {
+	return c.call(ctx, "chat.postEphemeral", map[string]any{"channel": channel, "user": user, "text": text})
+}
+
+// call invokes a Web API method. Slack reports most failures with HTTP 200 and
+// "ok": false, so both are checked.
+func (c *SlackBotClient) call(ctx context.Context, method string, payload map[string]any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if resp.StatusCode >= 300 || json.Unmarshal(b, &result) != nil || !result.OK {
+		if result.Error != "" {
+			b = []byte(result.Error)
+		}
+		return &ProviderError{Provider: "slack", StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	return nil
+}
+
+// SlackForecastReply renders one day of a zone forecast as a slash command
+// reply. buttons are appended to the message's actions.
+func SlackForecastReply(n Notification, tomorrow bool, buttons ...map[string]any) map[string]any {
+	title := chatTitle(n)
+	var rating *models.DangerRating
+	if n.Forecast != nil {
+		rating = n.Forecast.TodayDanger
+		if tomorrow {
+			title, rating = title+" (tomorrow)", n.Forecast.FutureDanger
+		}
+	}
+	return slackForecast(n, title, rating, buttons...)
+}
+
+// SlackZoneListReply lists a center's zones with their highest rating for the
+// day. button, when non-nil, builds an accessory button for each zone.
+func SlackZoneListReply(centerName string, zones []models.ZoneForecast, tomorrow bool, button func(zoneID string) map[string]any) map[string]any {
+	day := "today"
+	if tomorrow {
+		day = "tomorrow"
+	}
+	title := fmt.Sprintf("%s zones %s", centerName, day)
+	blocks := []map[string]any{{"type": "header", "text": map[string]any{"type": "plain_text", "text": title}}}
+	for i, z := range zones {
+		if i == slackZoneListLimit {
+			blocks = append(blocks, map[string]any{"type": "context", "elements": []map[string]any{{
+				"type": "mrkdwn", "text": fmt.Sprintf("%d more zones not shown", len(zones)-i),
+			}}})
+			break
+		}
+		rating := z.TodayDanger
+		if tomorrow {
+			rating = z.FutureDanger
+		}
+		section := map[string]any{"type": "section", "text": map[string]any{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("*%s* `%s`\n%s", z.ZoneName, z.ZoneID, rating.Max().Label()),
+		}}
+		if button != nil {
+			section["accessory"] = button(z.ZoneID)
+		}
+		blocks = append(blocks, section)
+	}
+	return map[string]any{"text": title, "blocks": blocks}
+}
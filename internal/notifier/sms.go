@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/htmltext"
+	"example.com/avalanche/internal/models"
+)
+
+// SMSSegmentLength is the size of a single GSM-7 SMS segment.
+const SMSSegmentLength = 160
+
+// smsZoneNameLimit keeps long zone names from crowding out the forecast.
+const smsZoneNameLimit = 28
+
+// ErrNoSMSGateway is returned by NewHTTPSMSGatewayFromEnv when SMS_GATEWAY_URL is unset.
+var ErrNoSMSGateway = errors.New("no SMS gateway configured (set SMS_GATEWAY_URL)")
+
+// SMSSender delivers a text message to an E.164 phone number.
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// HTTPSMSGateway posts messages as JSON {"from","to","text"} to a generic
+// HTTP SMS gateway, authenticating with an optional bearer token.
+type HTTPSMSGateway struct {
+	url    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+// NewHTTPSMSGateway returns a gateway client posting to url.
+func NewHTTPSMSGateway(url, token, from string) (*HTTPSMSGateway, error) {
+	if url == "" {
+		return nil, ErrNoSMSGateway
+	}
+	return &HTTPSMSGateway{url: url, token: token, from: from, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// NewHTTPSMSGatewayFromEnv reads SMS_GATEWAY_URL, SMS_GATEWAY_TOKEN and SMS_FROM.
+func NewHTTPSMSGatewayFromEnv() (*HTTPSMSGateway, error) {
+	return NewHTTPSMSGateway(os.Getenv("SMS_GATEWAY_URL"), os.Getenv("SMS_GATEWAY_TOKEN"), os.Getenv("SMS_FROM"))
+}
+
+func (g *HTTPSMSGateway) SendSMS(ctx context.Context, to, body string) error {
+	payload, err := json.Marshal(map[string]string{"from": g.from, "to": to, "text": body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &ProviderError{Provider: "sms", StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	return nil
+}
+
+// LogSMSSender logs text messages instead of delivering them.
+type LogSMSSender struct{}
+
+func (LogSMSSender) SendSMS(ctx context.Context, to, body string) error {
+	log.Printf("sms disabled: skipping %q to %s", body, to)
+	return nil
+}
+
+// SMSChannel delivers notifications to SMS subscriptions, whose Target holds
+// the phone number.
+type SMSChannel struct {
+	sender SMSSender
+}
+
+// NewSMSChannel returns a Channel sending through sender.
+func NewSMSChannel(sender SMSSender) *SMSChannel {
+	return &SMSChannel{sender: sender}
+}
+
+func (c *SMSChannel) Notify(ctx context.Context, sub models.Subscription, n Notification) error {
+	if sub.Target == "" {
+		return fmt.Errorf("subscription %d has no phone number", sub.ID)
+	}
+	return c.sender.SendSMS(ctx, sub.Target, FormatSMS(n))
+}
+
+// FormatSMS renders n into a single 160-character GSM-7 segment:
+//
+//	Snoqualmie Pass: Today U3 M3 L2, Tmrw U2 M2 L1. Bottom line...
+//
+// Bands are upper/middle/lower elevation; "-" marks a missing rating. The
+// bottom line is truncated to whatever space remains.
+func FormatSMS(n Notification) string {
+	var today, tomorrow *models.DangerRating
+	var bottomLine string
+	if n.Forecast != nil {
+		today, tomorrow = n.Forecast.TodayDanger, n.Forecast.FutureDanger
+		bottomLine = n.Forecast.SafeBottomLine()
+	}
+	head := fmt.Sprintf("%s: Today %s, Tmrw %s.",
+		truncateSMS(gsmSafe(n.Label()), smsZoneNameLimit), smsBands(today), smsBands(tomorrow))
+	return appendSMS(head, bottomLine)
+}
+
+// FormatSMSDay renders one day of a zone forecast into a single segment, for
+// replies to inbound queries:
+//
+//	Snoqualmie Pass Tmrw: U2 M2 L1 Moderate. Bottom line...
+func FormatSMSDay(zf *models.ZoneForecast, tomorrow bool) string {
+	day, rating := "Today", zf.TodayDanger
+	if tomorrow {
+		day, rating = "Tmrw", zf.FutureDanger
+	}
+	name := zf.ZoneName
+	if name == "" {
+		name = zf.ZoneID
+	}
+	head := fmt.Sprintf("%s %s: %s %s.",
+		truncateSMS(gsmSafe(name), smsZoneNameLimit), day, smsBands(rating), rating.Max().Name())
+	return appendSMS(head, zf.SafeBottomLine())
+}
+
+// appendSMS appends the plain-text form of html to head, truncated so the
+// result fits in one segment.
+func appendSMS(head, html string) string {
+	text := gsmSafe(htmltext.Line(html, 0))
+	room := SMSSegmentLength - len(head) - 1
+	if text == "" || room < 4 {
+		return truncateSMS(head, SMSSegmentLength)
+	}
+	return head + " " + truncateSMS(text, room)
+}
+
+// smsBands renders a rating as "U3 M3 L2".
+func smsBands(d *models.DangerRating) string {
+	if d == nil {
+		return "U- M- L-"
+	}
+	return "U" + smsLevel(d.Upper) + " M" + smsLevel(d.Middle) + " L" + smsLevel(d.Lower)
+}
+
+func smsLevel(level domain.DangerLevel) string {
+	if !level.IsRated() {
+		return "-"
+	}
+	return strconv.Itoa(int(level))
+}
+
+// truncateSMS shortens an ASCII string to limit bytes, marking the cut with "...".
+func truncateSMS(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	if limit <= 3 {
+		return s[:limit]
+	}
+	return strings.TrimSpace(s[:limit-3]) + "..."
+}
+
+// gsmReplacer maps typographic punctuation common in forecasts to ASCII, and
+// ASCII characters that need a GSM-7 escape (and so cost two septets) to
+// single-septet look-alikes.
+var gsmReplacer = strings.NewReplacer(
+	"\u2018", "'", "\u2019", "'", "\u201c", `"`, "\u201d", `"`,
+	"\u2013", "-", "\u2014", "-", "\u2026", "...", "\u00a0", " ",
+	"[", "(", "]", ")", "{", "(", "}", ")",
+	"~", "-", "^", "", "|", "/", `\`, "/", "`", "'",
+)
+
+// gsmSafe converts s to printable ASCII so a message always encodes as GSM-7
+// and is never split into 70-character UCS-2 segments. Other characters are dropped.
+func gsmSafe(s string) string {
+	s = gsmReplacer.Replace(s)
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 0x20 && r < 0x7F {
+			b.WriteRune(r)
+		} else if r == '\n' || r == '\t' {
+			b.WriteByte(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
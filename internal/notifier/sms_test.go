@@ -0,0 +1,71 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+func TestFormatSMS_FitsOneSegment(t *testing.T) {
+	n := notifier.Notification{
+		ZoneID:   "NWAC_10",
+		ZoneName: "Snoqualmie Pass",
+		Forecast: &models.ZoneForecast{
+			TodayDanger:  &models.DangerRating{Upper: 3, Middle: 3, Lower: 2},
+			FutureDanger: &models.DangerRating{Upper: 2, Middle: 2},
+			BottomLine:   "<p>Wind slabs are the main concern – avoid " + strings.Repeat("wind-loaded “convex” slopes near ridgelines ", 5) + "</p>",
+		},
+	}
+	got := notifier.FormatSMS(n)
+	if len(got) > notifier.SMSSegmentLength {
+		t.Fatalf("message is %d characters: %q", len(got), got)
+	}
+	if !strings.HasPrefix(got, "Snoqualmie Pass: Today U3 M3 L2, Tmrw U2 M2 L-. Wind slabs are the main concern - avoid") {
+		t.Fatalf("unexpected message: %q", got)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected truncated bottom line: %q", got)
+	}
+	for _, r := range got {
+		if r > 0x7E {
+			t.Fatalf("non-GSM character %q in %q", r, got)
+		}
+	}
+}
+
+func TestFormatSMS_NoForecast(t *testing.T) {
+	got := notifier.FormatSMS(notifier.Notification{ZoneID: "NWAC_10"})
+	if got != "NWAC_10: Today U- M- L-, Tmrw U- M- L-." {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
+
+func TestSMSChannel_PostsToGateway(t *testing.T) {
+	var got map[string]string
+	var auth string
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer stub.Close()
+
+	gw, err := notifier.NewHTTPSMSGateway(stub.URL, "secret", "+15550000000")
+	if err != nil {
+		t.Fatalf("gateway: %v", err)
+	}
+	ch := notifier.NewSMSChannel(gw)
+	sub := models.Subscription{ID: 1, Channel: models.ChannelSMS, Target: "+12065550100"}
+	if err := ch.Notify(context.Background(), sub, notifier.Notification{ZoneID: "NWAC_10", ZoneName: "Snoqualmie Pass"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if auth != "Bearer secret" || got["to"] != "+12065550100" || got["from"] != "+15550000000" || !strings.HasPrefix(got["text"], "Snoqualmie Pass:") {
+		t.Fatalf("unexpected gateway request: auth=%q body=%v", auth, got)
+	}
+}
@@ -0,0 +1,344 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SMTPTLSMode selects how the SMTP connection is secured.
+type SMTPTLSMode string
+
+const (
+	// SMTPTLSNone sends in plaintext (local sinks such as MailHog).
+	SMTPTLSNone SMTPTLSMode = "none"
+	// SMTPTLSStartTLS upgrades a plaintext connection with STARTTLS.
+	SMTPTLSStartTLS SMTPTLSMode = "starttls"
+	// SMTPTLSImplicit dials straight into TLS (usually port 465).
+	SMTPTLSImplicit SMTPTLSMode = "tls"
+)
+
+// SMTPConfig configures SMTPEmailClient.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLSMode  SMTPTLSMode
+	From     string
+	FromName string
+	// HeloName is announced in EHLO; defaults to "localhost".
+	HeloName string
+	// IdleTimeout closes a pooled connection that has not been used for this long.
+	IdleTimeout time.Duration
+	// DKIM signs outgoing messages when set.
+	DKIM *DKIMSigner
+}
+
+// SMTPEmailClient sends forecast emails over SMTP using only the standard
+// library. A single connection is kept open and reused between sends.
+type SMTPEmailClient struct {
+	cfg      SMTPConfig
+	renderer *emailRenderer
+
+	mu       sync.Mutex
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+// NewSMTPEmailClient validates cfg and returns a client. No connection is made
+// until the first message is sent.
+func NewSMTPEmailClient(cfg SMTPConfig) (*SMTPEmailClient, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if cfg.From == "" {
+		return nil, errors.New("smtp from address is required")
+	}
+	switch cfg.TLSMode {
+	case "":
+		cfg.TLSMode = SMTPTLSStartTLS
+	case SMTPTLSNone, SMTPTLSStartTLS, SMTPTLSImplicit:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", cfg.TLSMode)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = defaultSMTPPort(cfg.TLSMode)
+	}
+	if cfg.HeloName == "" {
+		cfg.HeloName = "localhost"
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = 30 * time.Second
+	}
+	if cfg.FromName == "" {
+		cfg.FromName = "Avy Notifier"
+	}
+	return &SMTPEmailClient{cfg: cfg, renderer: newEmailRenderer()}, nil
+}
+
+func defaultSMTPPort(mode SMTPTLSMode) int {
+	switch mode {
+	case SMTPTLSImplicit:
+		return 465
+	case SMTPTLSNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+func (c *SMTPEmailClient) SendForecastEmail(ctx context.Context, recipient string, data EmailData) error {
+	msg, err := c.renderer.forecastMessage(recipient, data)
+	if err != nil {
+		return err
+	}
+	if err := c.SendMessage(ctx, msg); err != nil {
+		return err
+	}
+	log.Printf("sent forecast email to %s for zone %s via smtp", recipient, data.ZoneID)
+	return nil
+}
+
+func (c *SMTPEmailClient) SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
+	if len(zones) == 0 {
+		return nil
+	}
+	msg, err := c.renderer.centerForecastMessage(recipient, centerName, centerLink, zones)
+	if err != nil {
+		return err
+	}
+	if err := c.SendMessage(ctx, msg); err != nil {
+		return err
+	}
+	log.Printf("sent aggregated center forecast email to %s for center %s via smtp", recipient, centerName)
+	return nil
+}
+
+// SendMessage builds a MIME message, optionally DKIM-signs it, and delivers it
+// over the pooled connection. A stale pooled connection is replaced once.
+func (c *SMTPEmailClient) SendMessage(ctx context.Context, msg Message) error {
+	raw, err := c.buildMIME(msg)
+	if err != nil {
+		return err
+	}
+	if c.cfg.DKIM != nil {
+		if raw, err = c.cfg.DKIM.Sign(raw); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl, reused, err := c.connection(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.deliver(ctx, cl, msg.To, raw); err != nil {
+		c.closeLocked()
+		var replyErr *textproto.Error
+		if !reused || errors.As(err, &replyErr) {
+			return err
+		}
+		// The pooled connection was dropped by the server; retry once on a fresh one.
+		if cl, _, err = c.connection(ctx); err != nil {
+			return err
+		}
+		if err := c.deliver(ctx, cl, msg.To, raw); err != nil {
+			c.closeLocked()
+			return err
+		}
+	}
+	c.lastUsed = time.Now()
+	return nil
+}
+
+// Close terminates the pooled connection, if any.
+func (c *SMTPEmailClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+	return nil
+}
+
+func (c *SMTPEmailClient) closeLocked() {
+	if c.client == nil {
+		return
+	}
+	if err := c.client.Quit(); err != nil {
+		_ = c.client.Close()
+	}
+	c.client = nil
+}
+
+// connection returns the pooled client, dialing a new one when none is open or
+// the existing one has been idle too long or fails a RSET. reused reports
+// whether the returned client came from the pool.
+func (c *SMTPEmailClient) connection(ctx context.Context) (cl *smtp.Client, reused bool, err error) {
+	if c.client != nil {
+		if time.Since(c.lastUsed) < c.cfg.IdleTimeout && c.client.Reset() == nil {
+			return c.client, true, nil
+		}
+		c.closeLocked()
+	}
+	if cl, err = c.dial(ctx); err != nil {
+		return nil, false, err
+	}
+	c.client = cl
+	c.lastUsed = time.Now()
+	return cl, false, nil
+}
+
+func (c *SMTPEmailClient) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: c.cfg.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if c.cfg.TLSMode == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("smtp dial %s: %w", addr, err)
+	}
+
+	cl, err := smtp.NewClient(conn, c.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("smtp handshake: %w", err)
+	}
+	if err := cl.Hello(c.cfg.HeloName); err != nil {
+		_ = cl.Close()
+		return nil, fmt.Errorf("smtp hello: %w", err)
+	}
+	if c.cfg.TLSMode == SMTPTLSStartTLS {
+		if ok, _ := cl.Extension("STARTTLS"); !ok {
+			_ = cl.Close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+		if err := cl.StartTLS(tlsConfig); err != nil {
+			_ = cl.Close()
+			return nil, fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if c.cfg.Username != "" {
+		auth := smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+		if err := cl.Auth(auth); err != nil {
+			_ = cl.Close()
+			return nil, fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	return cl, nil
+}
+
+func (c *SMTPEmailClient) deliver(ctx context.Context, cl *smtp.Client, to string, raw []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := cl.Mail(c.cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	if err := cl.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp RCPT TO: %w", err)
+	}
+	w, err := cl.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp end of data: %w", err)
+	}
+	return nil
+}
+
+// buildMIME renders msg as a multipart/alternative RFC 5322 message with CRLF line endings.
+func (c *SMTPEmailClient) buildMIME(msg Message) ([]byte, error) {
+	boundary, err := randomToken(12)
+	if err != nil {
+		return nil, err
+	}
+	msgID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	domain := "localhost"
+	if i := strings.LastIndexByte(c.cfg.From, '@'); i >= 0 {
+		domain = c.cfg.From[i+1:]
+	}
+
+	from := mail.Address{Name: c.cfg.FromName, Address: c.cfg.From}
+	to := mail.Address{Address: msg.To}
+
+	var b bytes.Buffer
+	writeHeader := func(k, v string) { fmt.Fprintf(&b, "%s: %s\r\n", k, v) }
+	writeHeader("From", from.String())
+	writeHeader("To", to.String())
+	if msg.ReplyTo != "" {
+		writeHeader("Reply-To", (&mail.Address{Address: msg.ReplyTo}).String())
+	}
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("Message-ID", fmt.Sprintf("<%s@%s>", msgID, domain))
+	writeHeader("MIME-Version", "1.0")
+	writeHeader("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary))
+	b.WriteString("\r\n")
+
+	writePart := func(contentType, content string) error {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+		b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&b)
+		if _, err := qp.Write([]byte(toCRLF(content))); err != nil {
+			return err
+		}
+		if err := qp.Close(); err != nil {
+			return err
+		}
+		b.WriteString("\r\n")
+		return nil
+	}
+	if err := writePart("text/plain", msg.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart("text/html", msg.HTML); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes(), nil
+}
+
+func toCRLF(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
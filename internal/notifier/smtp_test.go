@@ -0,0 +1,173 @@
+package notifier_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/notifier"
+)
+
+// smtpSink is a minimal in-process SMTP server that records delivered messages,
+// similar to running MailHog locally.
+type smtpSink struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	conns    int
+	messages []string
+	rcpts    []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpSink{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *smtpSink) port() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpSink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-sink")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH"):
+			reply("235 authenticated")
+		case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RSET"), strings.HasPrefix(cmd, "NOOP"):
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			s.mu.Unlock()
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, b.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPEmailClient_DeliversAndReusesConnection(t *testing.T) {
+	sink := newSMTPSink(t)
+	client, err := notifier.NewSMTPEmailClient(notifier.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     sink.port(),
+		TLSMode:  notifier.SMTPTLSNone,
+		Username: "user",
+		Password: "pass",
+		From:     "alerts@example.com",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	data := notifier.EmailData{ZoneID: "NWAC_10", ZoneName: "Mt Hood", IssuedAt: time.Now(), CenterLink: "https://nwac.us/"}
+	if err := client.SendForecastEmail(ctx, "one@example.com", data); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	if err := client.SendForecastEmail(ctx, "two@example.com", data); err != nil {
+		t.Fatalf("second send: %v", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.conns != 1 {
+		t.Fatalf("expected a single reused connection, got %d", sink.conns)
+	}
+	if len(sink.messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(sink.messages))
+	}
+	msg := sink.messages[0]
+	for _, want := range []string{"Subject: New Avalanche Forecast for Mt Hood", "To: <one@example.com>", "multipart/alternative"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in message:\n%s", want, msg)
+		}
+	}
+	if sink.rcpts[1] != "<two@example.com>" {
+		t.Fatalf("unexpected second recipient %q", sink.rcpts[1])
+	}
+}
+
+func TestSMTPEmailClient_RequiresStartTLSWhenConfigured(t *testing.T) {
+	sink := newSMTPSink(t)
+	client, err := notifier.NewSMTPEmailClient(notifier.SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    sink.port(),
+		TLSMode: notifier.SMTPTLSStartTLS,
+		From:    "alerts@example.com",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	err = client.SendMessage(context.Background(), notifier.Message{To: "a@example.com", Subject: "x", Text: "x", HTML: "x"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("expected STARTTLS error, got %v", err)
+	}
+}
+
+func TestNewSMTPEmailClient_Validates(t *testing.T) {
+	if _, err := notifier.NewSMTPEmailClient(notifier.SMTPConfig{From: "a@example.com"}); err == nil {
+		t.Fatal("expected error for missing host")
+	}
+	if _, err := notifier.NewSMTPEmailClient(notifier.SMTPConfig{Host: "h", From: "a@example.com", TLSMode: "ssl3"}); err == nil {
+		t.Fatal("expected error for unknown tls mode")
+	}
+	if _, err := notifier.NewSMTPEmailClient(notifier.SMTPConfig{Host: "h", From: "a@example.com", Port: 2525}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrRecipientSuppressed is returned when a message is addressed to a suppressed email.
+var ErrRecipientSuppressed = errors.New("recipient is on the suppression list")
+
+// SuppressionChecker reports whether an address must not be emailed.
+type SuppressionChecker interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+// SuppressingEmailSender wraps an EmailSender and refuses to send to addresses
+// on the suppression list, protecting sender reputation across all providers.
+type SuppressingEmailSender struct {
+	next    EmailSender
+	checker SuppressionChecker
+}
+
+// NewSuppressingEmailSender decorates next with a suppression list check.
+func NewSuppressingEmailSender(next EmailSender, checker SuppressionChecker) *SuppressingEmailSender {
+	return &SuppressingEmailSender{next: next, checker: checker}
+}
+
+func (s *SuppressingEmailSender) SendForecastEmail(ctx context.Context, recipient string, data EmailData) error {
+	if err := s.check(ctx, recipient); err != nil {
+		return err
+	}
+	return s.next.SendForecastEmail(ctx, recipient, data)
+}
+
+func (s *SuppressingEmailSender) SendCenterForecastEmail(ctx context.Context, recipient string, centerName string, centerLink string, zones []ZoneSummary) error {
+	if err := s.check(ctx, recipient); err != nil {
+		return err
+	}
+	return s.next.SendCenterForecastEmail(ctx, recipient, centerName, centerLink, zones)
+}
+
+// SendMessage checks the suppression list and forwards to the wrapped sender
+// when it can deliver pre-rendered messages.
+func (s *SuppressingEmailSender) SendMessage(ctx context.Context, msg Message) error {
+	ms, ok := s.next.(MessageSender)
+	if !ok {
+		return errors.New("wrapped email sender cannot send raw messages")
+	}
+	if err := s.check(ctx, msg.To); err != nil {
+		return err
+	}
+	return ms.SendMessage(ctx, msg)
+}
+
+func (s *SuppressingEmailSender) check(ctx context.Context, recipient string) error {
+	suppressed, err := s.checker.IsSuppressed(ctx, recipient)
+	if err != nil {
+		return fmt.Errorf("suppression lookup failed: %w", err)
+	}
+	if suppressed {
+		log.Printf("skipping email to suppressed address %s", recipient)
+		return ErrRecipientSuppressed
+	}
+	return nil
+}
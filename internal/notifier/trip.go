@@ -0,0 +1,139 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/models"
+)
+
+// TripPlanStore provides access to trip plans for the countdown notifications.
+type TripPlanStore interface {
+	// ListTripPlans returns the plans that have not expired at now.
+	ListTripPlans(ctx context.Context, now time.Time) ([]models.TripPlan, error)
+	MarkTripStepsSent(ctx context.Context, planID uint, steps int) error
+	DeleteExpiredTripPlans(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TripNotifier emails trip plan participants the forecast for the plan's zones
+// at each step of models.TripSchedule and deletes plans once the trip is over.
+type TripNotifier struct {
+	store     TripPlanStore
+	sender    EmailSender
+	detailsFn ForecastDetailsFunc
+	links     *TripLinks
+	interval  time.Duration
+}
+
+// NewTripNotifier checks plans every interval, which bounds how late a step
+// may be sent after it is due. links may be nil to omit leave links.
+func NewTripNotifier(store TripPlanStore, sender EmailSender, details ForecastDetailsFunc, links *TripLinks, interval time.Duration) *TripNotifier {
+	return &TripNotifier{store: store, sender: sender, detailsFn: details, links: links, interval: interval}
+}
+
+func (t *TripNotifier) Run(ctx context.Context) error {
+	t.RunOnce(ctx, time.Now())
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			t.RunOnce(ctx, now)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// RunOnce expires finished trips and sends every step due at now.
+func (t *TripNotifier) RunOnce(ctx context.Context, now time.Time) {
+	if n, err := t.store.DeleteExpiredTripPlans(ctx, now); err != nil {
+		log.Printf("failed to expire trip plans: %v", err)
+	} else if n > 0 {
+		log.Printf("expired %d trip plans", n)
+	}
+	plans, err := t.store.ListTripPlans(ctx, now)
+	if err != nil {
+		log.Printf("failed to list trip plans: %v", err)
+		return
+	}
+	details := map[string]map[string]*models.ZoneForecast{}
+	for _, plan := range plans {
+		step := plan.DueStep(now)
+		if step < 0 {
+			continue
+		}
+		zones, ok := t.planForecasts(ctx, plan, details)
+		if !ok {
+			// Retried on the next pass while the step is still the latest due.
+			continue
+		}
+		t.sendStep(ctx, plan, step, zones)
+		if err := t.store.MarkTripStepsSent(ctx, plan.ID, step+1); err != nil {
+			log.Printf("failed to record trip plan %d step %d: %v", plan.ID, step, err)
+		}
+	}
+}
+
+// planForecasts returns the current forecasts of a plan's zones, loading each
+// center at most once per pass. It fails when any center cannot be loaded.
+func (t *TripNotifier) planForecasts(ctx context.Context, plan models.TripPlan, cache map[string]map[string]*models.ZoneForecast) ([]*models.ZoneForecast, bool) {
+	out := make([]*models.ZoneForecast, 0, len(plan.ZoneIDs))
+	for _, zoneID := range plan.ZoneIDs {
+		centerID, _, _ := strings.Cut(zoneID, "_")
+		byZone, ok := cache[centerID]
+		if !ok {
+			if t.detailsFn == nil {
+				return nil, false
+			}
+			list, err := t.detailsFn(ctx, centerID)
+			if err != nil {
+				log.Printf("forecast details failed for center %s: %v", centerID, err)
+				return nil, false
+			}
+			byZone = make(map[string]*models.ZoneForecast, len(list))
+			for i := range list {
+				byZone[list[i].ZoneID] = &list[i]
+			}
+			cache[centerID] = byZone
+		}
+		zf, ok := byZone[zoneID]
+		if !ok {
+			log.Printf("no forecast for zone %s of trip plan %d", zoneID, plan.ID)
+			zf = &models.ZoneForecast{ZoneID: zoneID}
+		}
+		out = append(out, zf)
+	}
+	return out, true
+}
+
+// sendStep emails every participant one message per zone. Failed sends are
+// logged and not retried, so the others are not sent the step twice.
+func (t *TripNotifier) sendStep(ctx context.Context, plan models.TripPlan, step int, zones []*models.ZoneForecast) {
+	day, _ := plan.Day()
+	outlook := models.TripSchedule[step].Outlook
+	for _, zf := range zones {
+		data := EmailData{ZoneID: zf.ZoneID, ZoneName: zf.ZoneName, Today: zf.TodayDanger, CenterLink: zf.URL}
+		data.BottomLine = zf.SafeBottomLine()
+		if issued, err := time.Parse(time.RFC3339, zf.IssuedTime); err == nil {
+			data.IssuedAt = issued
+		}
+		if outlook == models.TripOutlookTomorrow {
+			data.Tomorrow = zf.FutureDanger
+		}
+		for _, email := range plan.Participants {
+			// Each participant gets their own link to leave the plan.
+			data.Trip = &TripEmail{Name: plan.Name, Date: day, Outlook: outlook}
+			if t.links != nil {
+				data.Trip.Link = t.links.LeaveURL(plan.ID, email)
+			}
+			if err := t.sender.SendForecastEmail(ctx, email, data); err != nil {
+				log.Printf("trip plan %d step %d send failed for %s: %v", plan.ID, step, zf.ZoneID, err)
+			}
+		}
+	}
+}
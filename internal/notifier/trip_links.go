@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+
+	"example.com/avalanche/internal/signing"
+)
+
+// Trip token kinds, the first letter of a token's payload. Share tokens let
+// anyone view and ask to join a plan; the others are given to one person.
+const (
+	tripTokenShare       = "t"
+	tripTokenOrganizer   = "o"
+	tripTokenConfirm     = "c"
+	tripTokenParticipant = "p"
+)
+
+// TripLinks signs share links for trip plans, so anyone holding a plan's link
+// can view and join it without an account, along with the tokens that let
+// the organizer cancel a plan and each participant confirm or leave it.
+type TripLinks struct {
+	secret  string
+	baseURL string
+}
+
+// NewTripLinks returns links signed with secret and rooted at the API's
+// public baseURL.
+func NewTripLinks(secret, baseURL string) *TripLinks {
+	return &TripLinks{secret: secret, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// TripLinksFromEnv reads TRIP_SHARE_SECRET and PUBLIC_BASE_URL. It returns nil
+// when the secret is unset, disabling trip plans.
+func TripLinksFromEnv() *TripLinks {
+	secret := os.Getenv("TRIP_SHARE_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return NewTripLinks(secret, os.Getenv("PUBLIC_BASE_URL"))
+}
+
+// Token returns the share token of a plan.
+func (l *TripLinks) Token(planID uint) string {
+	return l.token(tripTokenShare, planID, "")
+}
+
+// URL returns the share link of a plan. It is a path when no public base URL
+// is configured.
+func (l *TripLinks) URL(planID uint) string {
+	return l.baseURL + "/api/trips/" + l.Token(planID)
+}
+
+// OrganizerToken returns the token that cancels a plan.
+func (l *TripLinks) OrganizerToken(planID uint) string {
+	return l.token(tripTokenOrganizer, planID, "")
+}
+
+// ConfirmURL returns the link email follows to start receiving a plan's
+// countdown.
+func (l *TripLinks) ConfirmURL(planID uint, email string) string {
+	return l.baseURL + "/api/trips/" + l.token(tripTokenConfirm, planID, email) + "/confirm"
+}
+
+// LeaveURL returns the link email follows to leave a plan.
+func (l *TripLinks) LeaveURL(planID uint, email string) string {
+	return l.baseURL + "/api/trips/" + l.token(tripTokenParticipant, planID, email) + "/leave"
+}
+
+// PlanID verifies a share token and returns the plan it was issued for.
+func (l *TripLinks) PlanID(token string) (uint, error) {
+	return l.planID(tripTokenShare, token)
+}
+
+// OrganizerPlanID verifies an organizer token and returns its plan.
+func (l *TripLinks) OrganizerPlanID(token string) (uint, error) {
+	return l.planID(tripTokenOrganizer, token)
+}
+
+// TripRecipient is the person a confirmation or leave token was issued to.
+type TripRecipient struct {
+	PlanID uint
+	digest string
+}
+
+// Is reports whether the token was issued to email.
+func (r TripRecipient) Is(email string) bool {
+	return emailDigest(email) == r.digest
+}
+
+// ConfirmRecipient verifies a token from a confirmation link.
+func (l *TripLinks) ConfirmRecipient(token string) (TripRecipient, error) {
+	return l.recipient(tripTokenConfirm, token)
+}
+
+// LeaveRecipient verifies a token from a leave link.
+func (l *TripLinks) LeaveRecipient(token string) (TripRecipient, error) {
+	return l.recipient(tripTokenParticipant, token)
+}
+
+func (l *TripLinks) planID(kind, token string) (uint, error) {
+	id, digest, err := l.parse(kind, token)
+	if err == nil && digest != "" {
+		err = signing.ErrInvalidToken
+	}
+	return id, err
+}
+
+func (l *TripLinks) recipient(kind, token string) (TripRecipient, error) {
+	id, digest, err := l.parse(kind, token)
+	if err == nil && digest == "" {
+		err = signing.ErrInvalidToken
+	}
+	return TripRecipient{PlanID: id, digest: digest}, err
+}
+
+// token signs "<kind><planID>", followed by ".<digest>" for tokens issued to
+// one email address.
+func (l *TripLinks) token(kind string, planID uint, email string) string {
+	payload := kind + strconv.FormatUint(uint64(planID), 10)
+	if email != "" {
+		payload += "." + emailDigest(email)
+	}
+	return signing.Token(l.secret, payload)
+}
+
+func (l *TripLinks) parse(kind, token string) (uint, string, error) {
+	payload, err := signing.ParseToken(l.secret, token)
+	if err != nil {
+		return 0, "", err
+	}
+	rest, ok := strings.CutPrefix(payload, kind)
+	if !ok {
+		return 0, "", signing.ErrInvalidToken
+	}
+	rest, digest, _ := strings.Cut(rest, ".")
+	id, err := strconv.ParseUint(rest, 10, 64)
+	if err != nil {
+		return 0, "", signing.ErrInvalidToken
+	}
+	return uint(id), digest, nil
+}
+
+// emailDigest identifies an address in a token without exposing it.
+func emailDigest(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,170 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/signing"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type sentEmail struct {
+	to   string
+	data notifier.EmailData
+}
+
+type recordingEmailSender struct {
+	mu   sync.Mutex
+	sent []sentEmail
+}
+
+func (r *recordingEmailSender) SendForecastEmail(ctx context.Context, recipient string, data notifier.EmailData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, sentEmail{recipient, data})
+	return nil
+}
+
+func (r *recordingEmailSender) SendCenterForecastEmail(ctx context.Context, recipient, centerName, centerLink string, zones []notifier.ZoneSummary) error {
+	return nil
+}
+
+func (r *recordingEmailSender) take() []sentEmail {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.sent
+	r.sent = nil
+	return out
+}
+
+func TestTripNotifier_SendsCountdownAndExpires(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.TripPlan{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	plan := models.TripPlan{
+		Name:         "Saturday tour",
+		ZoneIDs:      []string{"CAIC_1", "CAIC_2"},
+		TripDate:     "2025-12-06",
+		Timezone:     "America/Denver",
+		Participants: []string{"a@example.com", "b@example.com"},
+		ExpiresAt:    time.Date(2025, 12, 7, 0, 0, 0, 0, denver),
+	}
+	if err := gdb.Create(&plan).Error; err != nil {
+		t.Fatalf("create plan: %v", err)
+	}
+
+	var detailCalls int
+	details := func(ctx context.Context, centerID string) ([]models.ZoneForecast, error) {
+		detailCalls++
+		return []models.ZoneForecast{
+			{ZoneID: "CAIC_1", ZoneName: "Front Range", URL: "https://caic.example/1",
+				TodayDanger: &models.DangerRating{Upper: 2}, FutureDanger: &models.DangerRating{Upper: 3}},
+			{ZoneID: "CAIC_2", ZoneName: "Vail", URL: "https://caic.example/2",
+				TodayDanger: &models.DangerRating{Upper: 3}, FutureDanger: &models.DangerRating{Upper: 3}},
+		}, nil
+	}
+	sender := &recordingEmailSender{}
+	links := notifier.NewTripLinks("secret", "https://avy.example")
+	tn := notifier.NewTripNotifier(notifier.NewGormRepository(gdb), sender, details, links, time.Hour)
+	ctx := context.Background()
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 12, day, hour, minute, 0, 0, denver) }
+
+	tn.RunOnce(ctx, at(4, 12, 0))
+	if got := sender.take(); len(got) != 0 {
+		t.Fatalf("sent %d emails before the first step", len(got))
+	}
+
+	tn.RunOnce(ctx, at(4, 18, 5))
+	got := sender.take()
+	if len(got) != 4 {
+		t.Fatalf("thursday evening: sent %d emails, want 2 zones x 2 participants", len(got))
+	}
+	if detailCalls != 1 {
+		t.Errorf("loaded center details %d times, want once per pass", detailCalls)
+	}
+	first := got[0].data
+	if first.Trip == nil || first.Trip.Outlook != models.TripOutlookTomorrow || first.Tomorrow == nil {
+		t.Errorf("evening email should carry the tomorrow outlook: %+v", first)
+	}
+	if first.Trip.Link != links.LeaveURL(plan.ID, got[0].to) || first.Trip.Date.Format(time.DateOnly) != "2025-12-06" {
+		t.Errorf("trip = %+v", first.Trip)
+	}
+	if got[1].to == got[0].to || got[1].data.Trip.Link != links.LeaveURL(plan.ID, got[1].to) {
+		t.Errorf("each participant should get their own leave link: %+v", got[1])
+	}
+
+	tn.RunOnce(ctx, at(4, 19, 0))
+	if got := sender.take(); len(got) != 0 {
+		t.Fatalf("resent %d emails for a handled step", len(got))
+	}
+
+	// Friday evening's run was missed; only the trip-day forecast goes out.
+	tn.RunOnce(ctx, at(6, 7, 0))
+	got = sender.take()
+	if len(got) != 4 {
+		t.Fatalf("saturday morning: sent %d emails, want 4", len(got))
+	}
+	if d := got[0].data; d.Trip.Outlook != models.TripOutlookToday || d.Tomorrow != nil || d.Today == nil {
+		t.Errorf("morning email should carry only today's rating: %+v", d)
+	}
+
+	tn.RunOnce(ctx, at(7, 0, 30))
+	if got := sender.take(); len(got) != 0 {
+		t.Fatalf("sent %d emails after the trip", len(got))
+	}
+	var count int64
+	gdb.Model(&models.TripPlan{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expired plan was not deleted")
+	}
+}
+
+func TestTripLinks_RoundTrip(t *testing.T) {
+	links := notifier.NewTripLinks("secret", "https://avy.example/")
+	if got := links.URL(42); got != "https://avy.example/api/trips/"+links.Token(42) {
+		t.Errorf("URL = %q", got)
+	}
+	id, err := links.PlanID(links.Token(42))
+	if err != nil || id != 42 {
+		t.Fatalf("PlanID = %d, %v", id, err)
+	}
+	if _, err := notifier.NewTripLinks("other", "").PlanID(links.Token(42)); !errors.Is(err, signing.ErrInvalidToken) {
+		t.Errorf("token from another secret: err = %v", err)
+	}
+	if _, err := links.PlanID(signing.Token("secret", "s42")); !errors.Is(err, signing.ErrInvalidToken) {
+		t.Errorf("reply token accepted as a trip token: err = %v", err)
+	}
+	if _, err := links.PlanID(links.OrganizerToken(42)); !errors.Is(err, signing.ErrInvalidToken) {
+		t.Errorf("organizer token accepted as a share token: err = %v", err)
+	}
+	if id, err := links.OrganizerPlanID(links.OrganizerToken(42)); err != nil || id != 42 {
+		t.Errorf("OrganizerPlanID = %d, %v", id, err)
+	}
+
+	leave := strings.TrimSuffix(strings.TrimPrefix(links.LeaveURL(42, "A@example.com"), "https://avy.example/api/trips/"), "/leave")
+	r, err := links.LeaveRecipient(leave)
+	if err != nil || r.PlanID != 42 || !r.Is("a@example.com") || r.Is("b@example.com") {
+		t.Errorf("LeaveRecipient = %+v, %v", r, err)
+	}
+	if _, err := links.ConfirmRecipient(leave); !errors.Is(err, signing.ErrInvalidToken) {
+		t.Errorf("leave token accepted as a confirmation: err = %v", err)
+	}
+	if _, err := links.LeaveRecipient(links.Token(42)); !errors.Is(err, signing.ErrInvalidToken) {
+		t.Errorf("share token accepted as a leave token: err = %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/signing"
+)
+
+// WebhookStore persists webhook endpoints and their delivery log.
+type WebhookStore interface {
+	// ListWebhookEndpoints returns active endpoints scoped to zoneID or its center.
+	ListWebhookEndpoints(ctx context.Context, zoneID string) ([]models.WebhookEndpoint, error)
+	RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery) error
+	// RecordWebhookResult resets the failure counter on success, increments it
+	// otherwise, and returns the resulting count.
+	RecordWebhookResult(ctx context.Context, endpointID uint, success bool) (int, error)
+	DisableWebhookEndpoint(ctx context.Context, endpointID uint, reason string) error
+}
+
+const (
+	defaultWebhookAttempts     = 4
+	defaultWebhookBackoff      = 2 * time.Second
+	defaultWebhookDisableAfter = 10
+	// maxConcurrentWebhooks bounds deliveries in flight across events.
+	maxConcurrentWebhooks = 16
+)
+
+// WebhookPayload is the JSON body posted to webhook endpoints.
+type WebhookPayload struct {
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      WebhookPayloadData `json:"data"`
+}
+
+// WebhookPayloadData describes the forecast that triggered the event.
+type WebhookPayloadData struct {
+	ZoneID         string               `json:"zone_id"`
+	ZoneName       string               `json:"zone_name,omitempty"`
+	CenterID       string               `json:"center_id"`
+	CenterName     string               `json:"center_name,omitempty"`
+	CenterLink     string               `json:"center_link,omitempty"`
+	ProductID      int                  `json:"product_id,omitempty"`
+	IssuedAt       time.Time            `json:"issued_at"`
+	BottomLine     string               `json:"bottom_line,omitempty"`
+	BottomLineText string               `json:"bottom_line_text,omitempty"`
+	TodayDanger    *models.DangerRating `json:"today_danger,omitempty"`
+	FutureDanger   *models.DangerRating `json:"future_danger,omitempty"`
+}
+
+// WebhookDispatcher is an EventSink that posts signed events to every
+// matching webhook endpoint. Failed deliveries are retried with exponential
+// backoff; endpoints that keep failing across events are disabled.
+type WebhookDispatcher struct {
+	store  WebhookStore
+	client *http.Client
+
+	// Attempts is the number of tries per event, Backoff the delay before the
+	// first retry (doubled for each further retry), and DisableAfter the
+	// number of consecutive failed events after which an endpoint is disabled.
+	Attempts     int
+	Backoff      time.Duration
+	DisableAfter int
+
+	// slots bounds concurrent deliveries; wg tracks the queued ones.
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewWebhookDispatcher returns a dispatcher with default retry settings.
+func NewWebhookDispatcher(store WebhookStore) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		store:        store,
+		client:       newWebhookClient(),
+		Attempts:     defaultWebhookAttempts,
+		Backoff:      defaultWebhookBackoff,
+		DisableAfter: defaultWebhookDisableAfter,
+		slots:        make(chan struct{}, maxConcurrentWebhooks),
+	}
+}
+
+// HandleEvent queues delivery of ev to matching endpoints and returns without
+// waiting, so slow or failing partners never delay subscriber notifications.
+func (d *WebhookDispatcher) HandleEvent(ctx context.Context, ev Event) error {
+	endpoints, err := d.store.ListWebhookEndpoints(ctx, ev.ZoneID)
+	if err != nil {
+		return fmt.Errorf("list webhook endpoints: %w", err)
+	}
+	body, err := json.Marshal(webhookPayload(ev))
+	if err != nil {
+		return err
+	}
+
+	// Deliveries and their retries outlive the poll that detected the event.
+	ctx = context.WithoutCancel(ctx)
+	for _, ep := range endpoints {
+		if !ep.Active || !ep.Matches(ev.Type, ev.ZoneID) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(ep models.WebhookEndpoint) {
+			defer d.wg.Done()
+			d.slots <- struct{}{}
+			defer func() { <-d.slots }()
+			d.deliver(ctx, ep, ev, body)
+		}(ep)
+	}
+	return nil
+}
+
+// Wait blocks until every queued delivery, including retries, has finished.
+func (d *WebhookDispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, ep models.WebhookEndpoint, ev Event, body []byte) {
+	attempts := max(d.Attempts, 1)
+	backoff := d.Backoff
+	success := false
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			backoff *= 2
+		}
+		start := time.Now()
+		status, err := d.post(ctx, ep, ev, body)
+		rec := models.WebhookDelivery{
+			EndpointID: ep.ID,
+			EventID:    ev.ID,
+			EventType:  ev.Type,
+			Attempt:    attempt,
+			StatusCode: status,
+			DurationMS: time.Since(start).Milliseconds(),
+			Success:    err == nil,
+		}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		if rerr := d.store.RecordWebhookDelivery(ctx, rec); rerr != nil {
+			log.Printf("failed to record webhook delivery for endpoint %d: %v", ep.ID, rerr)
+		}
+		if err == nil {
+			success = true
+			break
+		}
+		log.Printf("webhook %s to endpoint %d failed (attempt %d/%d): %v", ev.Type, ep.ID, attempt, attempts, err)
+		if !retryableStatus(status) {
+			break
+		}
+	}
+
+	failures, err := d.store.RecordWebhookResult(ctx, ep.ID, success)
+	if err != nil {
+		log.Printf("failed to update webhook endpoint %d: %v", ep.ID, err)
+		return
+	}
+	if !success && d.DisableAfter > 0 && failures >= d.DisableAfter {
+		reason := fmt.Sprintf("disabled after %d consecutive failed deliveries", failures)
+		if err := d.store.DisableWebhookEndpoint(ctx, ep.ID, reason); err != nil {
+			log.Printf("failed to disable webhook endpoint %d: %v", ep.ID, err)
+			return
+		}
+		log.Printf("webhook endpoint %d %s", ep.ID, reason)
+	}
+}
+
+// post sends one signed request and returns the response status. A zero status
+// means the request never got a response.
+func (d *WebhookDispatcher) post(ctx context.Context, ep models.WebhookEndpoint, ev Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signing.HeaderID, ev.ID)
+	req.Header.Set(signing.HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(signing.HeaderSignature, signing.Sign(ep.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryableStatus reports whether a failed attempt with status may succeed later.
+func retryableStatus(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+func webhookPayload(ev Event) WebhookPayload {
+	data := WebhookPayloadData{
+		ZoneID:     ev.ZoneID,
+		ZoneName:   ev.ZoneName,
+		CenterID:   ev.CenterID,
+		CenterName: ev.CenterName,
+		CenterLink: ev.CenterLink,
+		ProductID:  ev.ProductID,
+		IssuedAt:   ev.IssuedAt.UTC(),
+	}
+	if ev.Forecast != nil {
+		data.BottomLine = ev.Forecast.SafeBottomLine()
+		data.BottomLineText = ev.Forecast.PlainBottomLine()
+		data.TodayDanger = ev.Forecast.TodayDanger
+		data.FutureDanger = ev.Forecast.FutureDanger
+	}
+	return WebhookPayload{ID: ev.ID, Type: ev.Type, CreatedAt: ev.OccurredAt, Data: data}
+}
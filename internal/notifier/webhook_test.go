@@ -0,0 +1,209 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/signing"
+)
+
+// memoryWebhookStore is an in-memory notifier.WebhookStore.
+type memoryWebhookStore struct {
+	mu         sync.Mutex
+	endpoints  []models.WebhookEndpoint
+	deliveries []models.WebhookDelivery
+}
+
+func (s *memoryWebhookStore) ListWebhookEndpoints(ctx context.Context, zoneID string) ([]models.WebhookEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.WebhookEndpoint(nil), s.endpoints...), nil
+}
+
+func (s *memoryWebhookStore) RecordWebhookDelivery(ctx context.Context, d models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, d)
+	return nil
+}
+
+func (s *memoryWebhookStore) RecordWebhookResult(ctx context.Context, endpointID uint, success bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.endpoints {
+		if s.endpoints[i].ID == endpointID {
+			if success {
+				s.endpoints[i].ConsecutiveFailures = 0
+			} else {
+				s.endpoints[i].ConsecutiveFailures++
+			}
+			return s.endpoints[i].ConsecutiveFailures, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *memoryWebhookStore) DisableWebhookEndpoint(ctx context.Context, endpointID uint, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.endpoints {
+		if s.endpoints[i].ID == endpointID {
+			s.endpoints[i].Active = false
+			s.endpoints[i].DisabledReason = reason
+		}
+	}
+	return nil
+}
+
+// recordingSink collects events published by the Service.
+type recordingSink struct{ events []notifier.Event }
+
+func (s *recordingSink) HandleEvent(ctx context.Context, ev notifier.Event) error {
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func testEvent() notifier.Event {
+	return notifier.Event{
+		ID:   "evt_test",
+		Type: models.EventForecastIssued,
+		Notification: notifier.Notification{
+			ZoneID:   "NWAC_10",
+			CenterID: "NWAC",
+			IssuedAt: time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestWebhookDispatcher_SignsAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	var payload notifier.WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := signing.Verify("whsec_test", r.Header.Get(signing.HeaderSignature), r.Header.Get(signing.HeaderTimestamp), body, signing.DefaultTolerance, time.Now()); err != nil {
+			t.Errorf("signature: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	store := &memoryWebhookStore{endpoints: []models.WebhookEndpoint{
+		{ID: 1, URL: srv.URL, Secret: "whsec_test", ZoneID: "NWAC", Active: true},
+		{ID: 2, URL: srv.URL, Secret: "whsec_test", ZoneID: "NWAC", Active: true, Events: []string{models.EventWarningIssued}},
+	}}
+	d := notifier.NewWebhookDispatcher(store)
+	d.Backoff = time.Millisecond
+
+	if err := d.HandleEvent(context.Background(), testEvent()); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+	d.Wait()
+	if calls != 2 {
+		t.Fatalf("expected one retry against the subscribed endpoint, got %d calls", calls)
+	}
+	if len(store.deliveries) != 2 || store.deliveries[0].Success || !store.deliveries[1].Success || store.deliveries[1].Attempt != 2 {
+		t.Fatalf("unexpected delivery log: %+v", store.deliveries)
+	}
+	if payload.ID != "evt_test" || payload.Type != models.EventForecastIssued || payload.Data.ZoneID != "NWAC_10" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookDispatcher_DisablesAfterConsecutiveFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	store := &memoryWebhookStore{endpoints: []models.WebhookEndpoint{
+		{ID: 1, URL: srv.URL, Secret: "whsec_test", ZoneID: "NWAC_10", Active: true},
+	}}
+	d := notifier.NewWebhookDispatcher(store)
+	d.Backoff = time.Millisecond
+	d.DisableAfter = 2
+
+	for i := 0; i < 2; i++ {
+		_ = d.HandleEvent(context.Background(), testEvent())
+		d.Wait()
+	}
+	if len(store.deliveries) != 2 {
+		t.Fatalf("4xx responses should not be retried, got %d attempts", len(store.deliveries))
+	}
+	if store.endpoints[0].Active {
+		t.Fatal("expected endpoint to be disabled")
+	}
+}
+
+func TestWebhookDispatcher_DoesNotWaitForDelivery(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	store := &memoryWebhookStore{endpoints: []models.WebhookEndpoint{
+		{ID: 1, URL: srv.URL, Secret: "whsec_test", ZoneID: "NWAC_10", Active: true},
+	}}
+	d := notifier.NewWebhookDispatcher(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := d.HandleEvent(ctx, testEvent()); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+	// The delivery outlives the caller's context.
+	cancel()
+	close(release)
+	d.Wait()
+	if len(store.deliveries) != 1 || !store.deliveries[0].Success {
+		t.Fatalf("expected the queued delivery to succeed, got %+v", store.deliveries)
+	}
+}
+
+func TestService_ClassifiesForecastEvents(t *testing.T) {
+	repo := &fakeRepo{cache: map[string]models.ForecastCache{}}
+	issued := time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC)
+	forecasts := []notifier.Forecast{
+		{ZoneID: "NWAC_10", IssuedAt: issued, ProductID: 100},
+		{ZoneID: "NWAC_10", IssuedAt: issued, ProductID: 7, ProductType: notifier.ProductTypeWarning},
+	}
+	fetch := func(ctx context.Context, centerID string) ([]notifier.Forecast, error) { return forecasts, nil }
+	sink := &recordingSink{}
+	svc := notifier.NewService(repo, nopEmailSender{}, time.Hour, fetch)
+	svc.AddEventSink(sink)
+
+	svc.RunOnce(context.Background())
+	forecasts = []notifier.Forecast{{ZoneID: "NWAC_10", IssuedAt: issued.Add(time.Hour), ProductID: 100}}
+	svc.RunOnce(context.Background())
+	svc.RunOnce(context.Background())
+
+	var got []string
+	for _, ev := range sink.events {
+		got = append(got, ev.Type)
+	}
+	want := []string{models.EventForecastIssued, models.EventWarningIssued, models.EventForecastUpdated}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
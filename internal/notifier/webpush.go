@@ -0,0 +1,306 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/htmltext"
+	"example.com/avalanche/internal/models"
+)
+
+const (
+	// webPushRecordSize is the aes128gcm record size; payloads are sent as a
+	// single record, so it also bounds the plaintext.
+	webPushRecordSize = 4096
+	// webPushMaxPayload leaves room in the record for the padding delimiter
+	// and GCM tag.
+	webPushMaxPayload = webPushRecordSize - 17
+	webPushTTL        = 24 * time.Hour
+	vapidTokenTTL     = 12 * time.Hour
+)
+
+var (
+	// ErrNoVAPIDKeys is returned by VAPIDKeysFromEnv when VAPID_PRIVATE_KEY is unset.
+	ErrNoVAPIDKeys = errors.New("no VAPID keys configured (set VAPID_PRIVATE_KEY)")
+	// ErrPushSubscriptionGone is returned when the push service reports that a
+	// subscription has expired or been revoked.
+	ErrPushSubscriptionGone = errors.New("push subscription is gone")
+)
+
+// b64 is the unpadded base64url encoding used throughout Web Push.
+var b64 = base64.RawURLEncoding
+
+// VAPIDKeys identify this application server to push services (RFC 8292).
+type VAPIDKeys struct {
+	private *ecdsa.PrivateKey
+	public  []byte
+	subject string
+}
+
+// GenerateVAPIDKeys creates a P-256 key pair and returns the private scalar
+// and uncompressed public key, both base64url encoded.
+func GenerateVAPIDKeys() (privateKey, publicKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return b64.EncodeToString(key.Bytes()), b64.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// NewVAPIDKeys parses a base64url private key as produced by GenerateVAPIDKeys
+// or the web-push CLI. subject is a mailto: or https: contact for the push
+// service operator.
+func NewVAPIDKeys(privateKey, subject string) (*VAPIDKeys, error) {
+	raw, err := b64.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	pub := key.PublicKey().Bytes()
+	return &VAPIDKeys{
+		private: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(pub[1:33]),
+				Y:     new(big.Int).SetBytes(pub[33:]),
+			},
+			D: new(big.Int).SetBytes(raw),
+		},
+		public:  pub,
+		subject: subject,
+	}, nil
+}
+
+// VAPIDKeysFromEnv reads VAPID_PRIVATE_KEY and VAPID_SUBJECT, which defaults to
+// the EMAIL_FROM sender address.
+func VAPIDKeysFromEnv() (*VAPIDKeys, error) {
+	private := os.Getenv("VAPID_PRIVATE_KEY")
+	if private == "" {
+		return nil, ErrNoVAPIDKeys
+	}
+	subject := os.Getenv("VAPID_SUBJECT")
+	if subject == "" {
+		subject = "mailto:" + EmailFromEnv()
+	}
+	return NewVAPIDKeys(private, subject)
+}
+
+// PublicKey returns the base64url public key browsers pass to
+// pushManager.subscribe as applicationServerKey.
+func (k *VAPIDKeys) PublicKey() string {
+	return b64.EncodeToString(k.public)
+}
+
+// authorization returns the VAPID Authorization header for a push endpoint.
+func (k *VAPIDKeys) authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	header := b64.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidTokenTTL).Unix(),
+		"sub": k.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + b64.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return "vapid t=" + signingInput + "." + b64.EncodeToString(sig) + ", k=" + k.PublicKey(), nil
+}
+
+// EncryptWebPush encrypts plaintext for a browser subscription with the
+// aes128gcm content coding (RFC 8291, RFC 8188). p256dh and auth are the
+// base64url keys from the browser's PushSubscription.
+func EncryptWebPush(p256dh, auth string, plaintext []byte) ([]byte, error) {
+	if len(plaintext) > webPushMaxPayload {
+		return nil, fmt.Errorf("push payload is %d bytes, limit is %d", len(plaintext), webPushMaxPayload)
+	}
+	uaRaw, err := b64.DecodeString(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := b64.DecodeString(auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("invalid auth secret")
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	keyInfo := "WebPush: info\x00" + string(uaRaw) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt, record size, key ID length and the sender's public key.
+	out := make([]byte, 0, 16+4+1+len(asPublic)+len(plaintext)+17)
+	out = append(out, salt...)
+	out = binary.BigEndian.AppendUint32(out, webPushRecordSize)
+	out = append(out, byte(len(asPublic)))
+	out = append(out, asPublic...)
+	// A single, final record: the plaintext followed by the 0x02 delimiter.
+	record := append(append([]byte{}, plaintext...), 0x02)
+	return gcm.Seal(out, nonce, record, nil), nil
+}
+
+// PushSubscriptionStore removes push subscriptions the push service has revoked.
+type PushSubscriptionStore interface {
+	DeleteSubscription(ctx context.Context, subID uint) error
+}
+
+// WebPushPayload is the JSON message the service worker receives.
+type WebPushPayload struct {
+	Title     string             `json:"title"`
+	Body      string             `json:"body"`
+	URL       string             `json:"url,omitempty"`
+	ZoneID    string             `json:"zone_id"`
+	Level     domain.DangerLevel `json:"danger_level"`
+	LevelName string             `json:"danger_name,omitempty"`
+}
+
+// WebPushSender delivers notifications to browser push subscriptions, whose
+// Target holds the push endpoint and PushKeys the encryption keys.
+type WebPushSender struct {
+	keys   *VAPIDKeys
+	store  PushSubscriptionStore
+	client *http.Client
+}
+
+// NewWebPushSender returns a Channel signing requests with keys. Subscriptions
+// the push service reports as gone are deleted from store.
+func NewWebPushSender(keys *VAPIDKeys, store PushSubscriptionStore) *WebPushSender {
+	return &WebPushSender{keys: keys, store: store, client: newWebhookClient()}
+}
+
+func (p *WebPushSender) Notify(ctx context.Context, sub models.Subscription, n Notification) error {
+	if sub.Target == "" || sub.PushKeys == nil {
+		return fmt.Errorf("subscription %d has no push endpoint", sub.ID)
+	}
+	payload, err := json.Marshal(webPushPayload(n))
+	if err != nil {
+		return err
+	}
+	body, err := EncryptWebPush(sub.PushKeys.P256DH, sub.PushKeys.Auth, payload)
+	if err != nil {
+		return err
+	}
+	authz, err := p.keys.authorization(sub.Target, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", authz)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("web push failed: %w", err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		if err := p.store.DeleteSubscription(ctx, sub.ID); err != nil {
+			return fmt.Errorf("failed to remove expired push subscription %d: %w", sub.ID, err)
+		}
+		log.Printf("removed expired push subscription %d", sub.ID)
+		return ErrPushSubscriptionGone
+	case resp.StatusCode >= 300:
+		return &ProviderError{Provider: "webpush", StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	log.Printf("sent web push notification for zone %s", n.ZoneID)
+	return nil
+}
+
+func webPushPayload(n Notification) WebPushPayload {
+	p := WebPushPayload{Title: chatTitle(n), Body: chatSummary(n), URL: n.CenterLink, ZoneID: n.ZoneID}
+	if n.Forecast != nil {
+		p.Level = n.Forecast.TodayDanger.Max()
+		p.LevelName = p.Level.Name()
+		p.Title = fmt.Sprintf("%s: %s", n.Label(), p.LevelName)
+		if bl := htmltext.Line(n.Forecast.SafeBottomLine(), 180); bl != "" {
+			p.Body = bl
+		}
+	}
+	return p
+}
@@ -0,0 +1,154 @@
+package notifier_test
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+type memoryPushStore struct{ deleted []uint }
+
+func (m *memoryPushStore) DeleteSubscription(ctx context.Context, subID uint) error {
+	m.deleted = append(m.deleted, subID)
+	return nil
+}
+
+// decryptWebPush reverses aes128gcm encryption as a browser would.
+func decryptWebPush(t *testing.T, ua *ecdh.PrivateKey, authSecret, body []byte) []byte {
+	t.Helper()
+	salt, idLen := body[:16], int(body[20])
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != 4096 {
+		t.Fatalf("unexpected record size %d", rs)
+	}
+	asRaw := body[21 : 21+idLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asRaw)
+	if err != nil {
+		t.Fatalf("sender key: %v", err)
+	}
+	secret, _ := ua.ECDH(asPublic)
+	info := "WebPush: info\x00" + string(ua.PublicKey().Bytes()) + string(asRaw)
+	ikm, _ := hkdf.Key(sha256.New, secret, authSecret, info, 32)
+	prk, _ := hkdf.Extract(sha256.New, ikm, salt)
+	cek, _ := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if plain[len(plain)-1] != 0x02 {
+		t.Fatalf("missing final record delimiter")
+	}
+	return plain[:len(plain)-1]
+}
+
+// verifyVAPID checks the ES256 JWT in a "vapid t=..., k=..." header.
+func verifyVAPID(t *testing.T, header, audience string) {
+	t.Helper()
+	params := map[string]string{}
+	for _, p := range strings.Split(strings.TrimPrefix(header, "vapid "), ", ") {
+		k, v, _ := strings.Cut(p, "=")
+		params[k] = v
+	}
+	parts := strings.Split(params["t"], ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed JWT %q", params["t"])
+	}
+	pub, _ := base64.RawURLEncoding.DecodeString(params["k"])
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(pub[1:33]), Y: new(big.Int).SetBytes(pub[33:])}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if len(sig) != 64 || !ecdsa.Verify(key, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		t.Fatalf("invalid VAPID signature")
+	}
+	claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var c struct{ Aud, Sub string }
+	if err := json.Unmarshal(claims, &c); err != nil || c.Aud != audience || c.Sub != "mailto:ops@example.com" {
+		t.Fatalf("unexpected claims %s", claims)
+	}
+}
+
+func TestWebPushSender_EncryptsAndRemovesGoneSubscriptions(t *testing.T) {
+	private, public, err := notifier.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	keys, err := notifier.NewVAPIDKeys(private, "mailto:ops@example.com")
+	if err != nil || keys.PublicKey() != public {
+		t.Fatalf("load keys: %v", err)
+	}
+
+	ua, _ := ecdh.P256().GenerateKey(rand.Reader)
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+
+	var received []byte
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "aes128gcm" || r.Header.Get("TTL") == "" {
+			t.Errorf("missing push headers: %v", r.Header)
+		}
+		verifyVAPID(t, r.Header.Get("Authorization"), "http://"+r.Host)
+		body, _ := io.ReadAll(r.Body)
+		received = decryptWebPush(t, ua, authSecret, body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	store := &memoryPushStore{}
+	sender := notifier.NewWebPushSender(keys, store)
+	sub := models.Subscription{
+		ID:      7,
+		ZoneID:  "NWAC_10",
+		Channel: models.ChannelWebPush,
+		Target:  server.URL + "/push/abc",
+		PushKeys: &models.PushKeys{
+			P256DH: base64.RawURLEncoding.EncodeToString(ua.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(authSecret),
+		},
+	}
+	n := notifier.Notification{
+		ZoneID:   "NWAC_10",
+		ZoneName: "Snoqualmie Pass",
+		Forecast: &models.ZoneForecast{BottomLine: "<p>Avoid wind-loaded slopes.</p>", TodayDanger: &models.DangerRating{Upper: 3, Middle: 2, Lower: 1}},
+	}
+
+	if err := sender.Notify(context.Background(), sub, n); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	var payload notifier.WebPushPayload
+	if err := json.Unmarshal(received, &payload); err != nil {
+		t.Fatalf("payload: %v (%s)", err, received)
+	}
+	if payload.Title != "Snoqualmie Pass: Considerable" || payload.Body != "Avoid wind-loaded slopes." || payload.Level != 3 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	status = http.StatusGone
+	if err := sender.Notify(context.Background(), sub, n); !errors.Is(err, notifier.ErrPushSubscriptionGone) {
+		t.Fatalf("expected ErrPushSubscriptionGone, got %v", err)
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != 7 {
+		t.Fatalf("expected subscription 7 removed, got %v", store.deleted)
+	}
+}
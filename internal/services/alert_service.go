@@ -0,0 +1,265 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/cap"
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/htmltext"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// ErrInvalidAlertID is returned by Alert for identifiers it did not issue.
+var ErrInvalidAlertID = errors.New("invalid alert identifier")
+
+// AlertConfig controls which forecasts are published as CAP alerts.
+type AlertConfig struct {
+	// Sender identifies this service in alert messages, usually an email
+	// address or domain.
+	Sender string
+	// MinDanger is the lowest danger rating that raises an alert. Avalanche
+	// warnings are always published.
+	MinDanger domain.DangerLevel
+}
+
+// AlertConfigFromEnv reads CAP_SENDER (default EMAIL_FROM) and CAP_MIN_DANGER
+// (1-5 or a scale name such as "high", default High).
+func AlertConfigFromEnv() (AlertConfig, error) {
+	cfg := AlertConfig{Sender: os.Getenv("CAP_SENDER"), MinDanger: domain.DangerHigh}
+	if cfg.Sender == "" {
+		cfg.Sender = notifier.EmailFromEnv()
+	}
+	if raw := strings.TrimSpace(os.Getenv("CAP_MIN_DANGER")); raw != "" {
+		level, err := domain.ParseDangerLevel(raw)
+		if err != nil || !level.IsRated() {
+			return AlertConfig{}, fmt.Errorf("invalid CAP_MIN_DANGER %q (use 1-5 or Low to Extreme)", raw)
+		}
+		cfg.MinDanger = level
+	}
+	return cfg, nil
+}
+
+// AlertService publishes avalanche warnings and high danger forecasts as
+// Common Alerting Protocol alerts, one per forecast product.
+type AlertService struct {
+	client  ForecastClient
+	centers *db.CenterRepository
+	shapes  *geo.ShapeCache
+	cfg     AlertConfig
+	now     func() time.Time
+}
+
+// NewAlertService creates an alert service reading products from client and
+// alert areas from shapes.
+func NewAlertService(client ForecastClient, centers *db.CenterRepository, shapes *geo.ShapeCache, cfg AlertConfig) *AlertService {
+	return &AlertService{client: client, centers: centers, shapes: shapes, cfg: cfg, now: time.Now}
+}
+
+// ActiveAlerts returns the alerts that have not yet expired for centerIDs, or
+// for every active center when centerIDs is empty, newest first.
+func (s *AlertService) ActiveAlerts(centerIDs []string) ([]cap.Alert, error) {
+	if len(centerIDs) == 0 {
+		centers, err := s.centers.GetActiveCenters()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load centers: %w", err)
+		}
+		for _, c := range centers {
+			centerIDs = append(centerIDs, c.ID)
+		}
+	}
+
+	var alerts []cap.Alert
+	for _, id := range centerIDs {
+		centerAlerts, err := s.centerAlerts(id)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, centerAlerts...)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Sent > alerts[j].Sent })
+	return alerts, nil
+}
+
+// Alert returns the active alert with the given identifier, or nil when it
+// has expired or been superseded by a newer publication.
+func (s *AlertService) Alert(identifier string) (*cap.Alert, error) {
+	centerID, _, ok := strings.Cut(identifier, "-")
+	if !ok || centerID == "" {
+		return nil, ErrInvalidAlertID
+	}
+	alerts, err := s.centerAlerts(strings.ToUpper(centerID))
+	if err != nil {
+		return nil, err
+	}
+	for i := range alerts {
+		if alerts[i].Identifier == identifier {
+			return &alerts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *AlertService) centerAlerts(centerID string) ([]cap.Alert, error) {
+	products, err := s.client.FetchForecasts(centerID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+
+	var alerts []cap.Alert
+	var shapes map[string]*geo.Geometry
+	for _, p := range products {
+		if p.Status != "published" || !p.EndDate.After(now) {
+			continue
+		}
+		if p.ProductType != notifier.ProductTypeWarning && productDanger(p) < s.cfg.MinDanger {
+			continue
+		}
+		if shapes == nil {
+			if shapes, err = s.shapes.Shapes([]string{centerID}); err != nil {
+				// Alerts still carry zone names and IDs without polygons.
+				log.Printf("[AlertService] no zone shapes for %s: %v", centerID, err)
+				shapes = map[string]*geo.Geometry{}
+			}
+		}
+		alerts = append(alerts, s.buildAlert(centerID, p, shapes, now))
+	}
+	return alerts, nil
+}
+
+// buildAlert maps a forecast product onto a CAP alert. Warnings are
+// immediate and at least severe; danger forecasts take their severity from
+// the danger scale and are expected, or future until the forecast begins.
+func (s *AlertService) buildAlert(centerID string, p models.Forecast, shapes map[string]*geo.Geometry, now time.Time) cap.Alert {
+	level := productDanger(p)
+	warning := p.ProductType == notifier.ProductTypeWarning
+
+	var zoneNames []string
+	var areas []cap.Area
+	for _, z := range p.ForecastZone {
+		zoneID := centerID + "_" + z.ZoneID
+		area := cap.Area{
+			AreaDesc: cmp.Or(z.Name, zoneID),
+			Geocodes: []cap.Parameter{{ValueName: "zone_id", Value: zoneID}},
+		}
+		polygons, err := shapes[zoneID].Polygons()
+		if err != nil {
+			log.Printf("[AlertService] bad polygon for %s: %v", zoneID, err)
+		}
+		for _, poly := range polygons {
+			if len(poly) > 0 {
+				if ring := cap.Polygon(poly[0]); ring != "" {
+					area.Polygons = append(area.Polygons, ring)
+				}
+			}
+		}
+		zoneNames = append(zoneNames, area.AreaDesc)
+		areas = append(areas, area)
+	}
+	where := strings.Join(zoneNames, ", ")
+
+	info := cap.Info{
+		Language:     "en-US",
+		Category:     []string{cap.CategoryGeo, cap.CategorySafety},
+		Event:        "Avalanche Danger",
+		ResponseType: []string{cap.ResponseMonitor},
+		Urgency:      capUrgency(level),
+		Severity:     capSeverity(level),
+		Certainty:    cap.CertaintyLikely,
+		Effective:    cap.Time(p.StartDate),
+		Expires:      cap.Time(p.EndDate),
+		SenderName:   cmp.Or(p.AvalancheCenter.Name, centerID),
+		Headline:     fmt.Sprintf("%s avalanche danger: %s", level.Name(), where),
+		Description:  htmltext.Text(p.BottomLine),
+		Instruction:  level.TravelAdvice(),
+		Web:          p.AvalancheCenter.URL,
+		Areas:        areas,
+	}
+	if level >= domain.DangerHigh {
+		info.ResponseType = []string{cap.ResponseAvoid}
+	}
+	if warning {
+		info.Event = "Avalanche Warning"
+		info.Headline = "Avalanche Warning: " + where
+		info.Urgency = cap.UrgencyImmediate
+		info.ResponseType = []string{cap.ResponseAvoid}
+		if info.Severity != cap.SeverityExtreme {
+			info.Severity = cap.SeveritySevere
+		}
+		info.Instruction = max(level, domain.DangerHigh).TravelAdvice()
+	} else if p.StartDate.After(now) {
+		info.Urgency = cap.UrgencyFuture
+	}
+	if len(p.ForecastZone) == 1 && p.ForecastZone[0].URL != "" {
+		info.Web = p.ForecastZone[0].URL
+	}
+	if level.IsRated() {
+		info.Parameters = []cap.Parameter{{ValueName: "danger_level", Value: strconv.Itoa(int(level))}}
+	}
+
+	return cap.Alert{
+		Identifier: alertIdentifier(centerID, p),
+		Sender:     s.cfg.Sender,
+		Sent:       cap.Time(p.PublishedTime),
+		Status:     cap.StatusActual,
+		MsgType:    cap.MsgTypeAlert,
+		Scope:      cap.ScopePublic,
+		Info:       []cap.Info{info},
+	}
+}
+
+// alertIdentifier is unique per publication, so a republished product gets a
+// new identifier as CAP requires.
+func alertIdentifier(centerID string, p models.Forecast) string {
+	return fmt.Sprintf("%s-%d-%d", centerID, p.ID, p.PublishedTime.Unix())
+}
+
+// productDanger returns the highest band rating for the product's current day.
+func productDanger(p models.Forecast) domain.DangerLevel {
+	level := domain.DangerNoRating
+	for _, d := range p.Danger {
+		if d.ValidDay == "current" {
+			level = max(level, d.Max())
+		}
+	}
+	return level
+}
+
+// capSeverity maps the danger scale onto CAP severity.
+// capUrgency maps a current danger rating to how soon travelers must act:
+// now at Extreme, today at High and Considerable, and otherwise only if
+// conditions change.
+func capUrgency(level domain.DangerLevel) string {
+	switch {
+	case level >= domain.DangerExtreme:
+		return cap.UrgencyImmediate
+	case level >= domain.DangerConsiderable:
+		return cap.UrgencyExpected
+	default:
+		return cap.UrgencyFuture
+	}
+}
+
+func capSeverity(level domain.DangerLevel) string {
+	switch {
+	case level >= domain.DangerExtreme:
+		return cap.SeverityExtreme
+	case level == domain.DangerHigh:
+		return cap.SeveritySevere
+	case level == domain.DangerConsiderable:
+		return cap.SeverityModerate
+	default:
+		return cap.SeverityMinor
+	}
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/cap"
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type stubShapeFetcher struct{}
+
+func (stubShapeFetcher) FetchZoneShapes(centerID string) (map[string]*geo.Geometry, error) {
+	return map[string]*geo.Geometry{
+		"NWAC_10": {Type: "Polygon", Coordinates: json.RawMessage(`[[[-121.5,47.4],[-121.3,47.4],[-121.3,47.6],[-121.5,47.4]]]`)},
+	}, nil
+}
+
+func TestAlertService_ActiveAlerts(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.AvalancheCenter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := gdb.Create(&models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center", Active: true}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	center := models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center", URL: "https://nwac.us"}
+	client := &mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": {
+			{
+				ID: 101, ProductType: "forecast", Status: "published", AvalancheCenter: center,
+				PublishedTime: now.Add(-3 * time.Hour), StartDate: now.Add(-2 * time.Hour), EndDate: now.Add(22 * time.Hour),
+				ForecastZone: []models.Zone{{ZoneID: "10", Name: "Snoqualmie Pass"}},
+				Danger:       []models.DangerRating{{ValidDay: "current", Upper: 4, Middle: 3, Lower: 2}},
+				BottomLine:   "<p>Large <b>wind slabs</b> near ridgelines.</p>",
+			},
+			{
+				ID: 102, ProductType: "forecast", Status: "published", AvalancheCenter: center,
+				PublishedTime: now.Add(-3 * time.Hour), StartDate: now.Add(-2 * time.Hour), EndDate: now.Add(22 * time.Hour),
+				ForecastZone: []models.Zone{{ZoneID: "2", Name: "Olympics"}},
+				Danger:       []models.DangerRating{{ValidDay: "current", Upper: 2, Middle: 2, Lower: 1}},
+			},
+			{
+				ID: 103, ProductType: "warning", Status: "published", AvalancheCenter: center,
+				PublishedTime: now.Add(-time.Hour), StartDate: now.Add(-time.Hour), EndDate: now.Add(12 * time.Hour),
+				ForecastZone: []models.Zone{{ZoneID: "10", Name: "Snoqualmie Pass"}, {ZoneID: "11", Name: "Stevens Pass"}},
+			},
+			{
+				ID: 90, ProductType: "forecast", Status: "published", AvalancheCenter: center,
+				PublishedTime: now.Add(-27 * time.Hour), StartDate: now.Add(-26 * time.Hour), EndDate: now.Add(-2 * time.Hour),
+				ForecastZone: []models.Zone{{ZoneID: "10", Name: "Snoqualmie Pass"}},
+				Danger:       []models.DangerRating{{ValidDay: "current", Upper: 5, Middle: 5, Lower: 4}},
+			},
+		},
+	}}
+
+	svc := services.NewAlertService(client, db.NewCenterRepository(gdb), geo.NewShapeCache(stubShapeFetcher{}, 0),
+		services.AlertConfig{Sender: "alerts@example.com", MinDanger: 4})
+
+	alerts, err := svc.ActiveAlerts(nil)
+	if err != nil {
+		t.Fatalf("ActiveAlerts: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected the warning and the High forecast, got %d alerts", len(alerts))
+	}
+
+	warning, danger := alerts[0], alerts[1]
+	if wi := warning.Info[0]; wi.Event != "Avalanche Warning" || wi.Urgency != cap.UrgencyImmediate || wi.Severity != cap.SeveritySevere {
+		t.Errorf("unexpected warning info: %+v", wi)
+	}
+	if len(warning.Info[0].Areas) != 2 || warning.Info[0].Headline != "Avalanche Warning: Snoqualmie Pass, Stevens Pass" {
+		t.Errorf("unexpected warning areas: %+v", warning.Info[0])
+	}
+
+	info := danger.Info[0]
+	if danger.Sender != "alerts@example.com" || danger.Status != cap.StatusActual || !strings.HasPrefix(danger.Identifier, "NWAC-101-") {
+		t.Errorf("unexpected alert header: %+v", danger)
+	}
+	if info.Severity != cap.SeveritySevere || info.Urgency != cap.UrgencyExpected || info.ResponseType[0] != cap.ResponseAvoid {
+		t.Errorf("unexpected danger mapping: %+v", info)
+	}
+	if info.Expires != cap.Time(now.Add(22*time.Hour)) {
+		t.Errorf("expected expiry from EndDate, got %s", info.Expires)
+	}
+	if info.Description != "Large wind slabs near ridgelines." {
+		t.Errorf("unexpected description %q", info.Description)
+	}
+	if len(info.Areas) != 1 || len(info.Areas[0].Polygons) != 1 || !strings.HasPrefix(info.Areas[0].Polygons[0], "47.4,-121.5 ") {
+		t.Errorf("expected zone polygon in lat,lon order, got %+v", info.Areas)
+	}
+
+	got, err := svc.Alert(danger.Identifier)
+	if err != nil || got == nil || got.Identifier != danger.Identifier {
+		t.Fatalf("Alert(%s) = %v, %v", danger.Identifier, got, err)
+	}
+	if got, err := svc.Alert("NWAC-101-1"); err != nil || got != nil {
+		t.Errorf("expected superseded identifier to be gone, got %v, %v", got, err)
+	}
+}
+
+func TestAlertService_UrgencyFollowsDanger(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.AvalancheCenter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := gdb.Create(&models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center", Active: true}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	product := func(id int, zone string, level int) models.Forecast {
+		return models.Forecast{
+			ID: id, ProductType: "forecast", Status: "published",
+			PublishedTime: now.Add(-3 * time.Hour), StartDate: now.Add(-2 * time.Hour), EndDate: now.Add(22 * time.Hour),
+			ForecastZone: []models.Zone{{ZoneID: zone, Name: zone}},
+			Danger:       []models.DangerRating{{ValidDay: "current", Upper: domain.DangerLevel(level)}},
+		}
+	}
+	client := &mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": {product(1, "1", 5), product(2, "2", 4), product(3, "3", 3), product(4, "4", 2)},
+	}}
+	svc := services.NewAlertService(client, db.NewCenterRepository(gdb), geo.NewShapeCache(stubShapeFetcher{}, 0),
+		services.AlertConfig{Sender: "alerts@example.com", MinDanger: 1})
+
+	alerts, err := svc.ActiveAlerts(nil)
+	if err != nil {
+		t.Fatalf("ActiveAlerts: %v", err)
+	}
+	want := map[string]string{"1": cap.UrgencyImmediate, "2": cap.UrgencyExpected, "3": cap.UrgencyExpected, "4": cap.UrgencyFuture}
+	if len(alerts) != len(want) {
+		t.Fatalf("expected %d alerts, got %d", len(want), len(alerts))
+	}
+	for _, a := range alerts {
+		info := a.Info[0]
+		if zone := info.Areas[0].AreaDesc; info.Urgency != want[zone] {
+			t.Errorf("zone %s: urgency %s, want %s", zone, info.Urgency, want[zone])
+		}
+	}
+}
+
+func TestAlertConfigFromEnv_DefaultsSenderToEmailFrom(t *testing.T) {
+	t.Setenv("CAP_SENDER", "")
+	t.Setenv("CAP_MIN_DANGER", "")
+	t.Setenv("EMAIL_FROM", "alerts@avy.example")
+	cfg, err := services.AlertConfigFromEnv()
+	if err != nil || cfg.Sender != "alerts@avy.example" {
+		t.Errorf("expected the EMAIL_FROM sender, got %q %v", cfg.Sender, err)
+	}
+}
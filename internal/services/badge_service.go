@@ -0,0 +1,93 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/badge"
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+// ErrZoneNotFound is returned when a center has no current forecast for a zone.
+var ErrZoneNotFound = errors.New("zone not found")
+
+// BadgeService renders zone danger badges from current forecasts and danger
+// history sparklines from the forecast archive.
+type BadgeService struct {
+	forecasts *ForecastService
+	archive   *db.ArchiveRepository
+	now       func() time.Time
+}
+
+func NewBadgeService(forecasts *ForecastService, archive *db.ArchiveRepository) *BadgeService {
+	return &BadgeService{forecasts: forecasts, archive: archive, now: time.Now}
+}
+
+// ZoneBadge renders today's danger for each elevation band of a zone.
+func (s *BadgeService) ZoneBadge(zoneID *domain.ZoneID) ([]byte, error) {
+	forecasts, err := s.forecasts.GetForecastsForCenters([]string{zoneID.Center()}, s.now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch forecasts for %s: %w", zoneID.Center(), err)
+	}
+	var zone *models.ZoneForecast
+	for i := range forecasts {
+		if strings.EqualFold(forecasts[i].ZoneID, zoneID.String()) {
+			zone = &forecasts[i]
+			break
+		}
+	}
+	if zone == nil {
+		return nil, ErrZoneNotFound
+	}
+
+	levels := make([]domain.DangerLevel, len(exportBands))
+	if d := zone.TodayDanger; d != nil {
+		levels = []domain.DangerLevel{d.Upper, d.Middle, d.Lower}
+	}
+	bands := make([]badge.Band, len(exportBands))
+	for i, b := range exportBands {
+		bands[i] = badge.Band{Name: b.name, Level: levels[i]}
+	}
+	return badge.Zone(cmp.Or(zone.ZoneName, zone.ZoneID), bands), nil
+}
+
+// ZoneHistory renders the highest daily danger for a zone over the last days
+// days, ending today. Days without an archived forecast are left blank.
+func (s *BadgeService) ZoneHistory(ctx context.Context, zoneID *domain.ZoneID, days int) ([]byte, error) {
+	today := s.now().UTC()
+	from := today.AddDate(0, 0, 1-days)
+	filter := db.ArchiveFilter{
+		ZoneIDs: []string{zoneID.String()},
+		From:    from.Format(time.DateOnly),
+		To:      today.Format(time.DateOnly),
+	}
+
+	name := zoneID.String()
+	levels := make(map[string]domain.DangerLevel)
+	err := s.archive.EachForecast(ctx, filter, func(f models.ArchivedForecast) error {
+		// The latest amendment of each day comes first.
+		if _, seen := levels[f.ValidDate]; !seen {
+			levels[f.ValidDate] = domain.MaxDanger(f.DangerUpper, f.DangerMiddle, f.DangerLower)
+		}
+		if f.ZoneName != "" {
+			name = f.ZoneName
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archive: %w", err)
+	}
+
+	points := make([]badge.Day, days)
+	for i := range points {
+		date := from.AddDate(0, 0, i).Format(time.DateOnly)
+		points[i] = badge.Day{Date: date, Level: levels[date]}
+	}
+	return badge.History(fmt.Sprintf("%s: highest danger, last %d days", name, days), points), nil
+}
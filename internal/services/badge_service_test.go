@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestBadgeService_ZoneHistory(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.ArchivedForecast{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	now := time.Now().UTC()
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format(time.DateOnly) }
+	rows := []models.ArchivedForecast{
+		{ZoneID: "NWAC_10", ProductID: 1, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: now, AmendedAt: now.Add(-2 * time.Hour), ValidDate: day(0),
+			DangerUpper: 2, DangerMiddle: 1, DangerLower: 1},
+		// The day's later amendment wins.
+		{ZoneID: "NWAC_10", ProductID: 2, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: now, AmendedAt: now, ValidDate: day(0),
+			DangerUpper: 4, DangerMiddle: 3, DangerLower: 2},
+		{ZoneID: "NWAC_10", ProductID: 3, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: now, AmendedAt: now, ValidDate: day(-2),
+			DangerUpper: 3, DangerMiddle: 2, DangerLower: 1},
+		// Outside the window.
+		{ZoneID: "NWAC_10", ProductID: 4, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: now, AmendedAt: now, ValidDate: day(-10),
+			DangerUpper: 5, DangerMiddle: 5, DangerLower: 5},
+	}
+	if err := gdb.Create(&rows).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	svc := services.NewBadgeService(services.NewForecast(&mockForecastClient{}), db.NewArchiveRepository(gdb))
+
+	zoneID, _ := domain.ParseZoneID("NWAC_10")
+	svg, err := svc.ZoneHistory(context.Background(), zoneID, 7)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	out := string(svg)
+	for _, want := range []string{
+		"Snoqualmie Pass: highest danger, last 7 days",
+		day(0) + ": High",
+		day(-2) + ": Considerable",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("history missing %q", want)
+		}
+	}
+	if strings.Contains(out, "Extreme") || strings.Count(out, "<circle") != 2 {
+		t.Errorf("expected exactly the two rated days in the window:\n%s", out)
+	}
+
+	if _, err := svc.ZoneBadge(zoneID); !errors.Is(err, services.ErrZoneNotFound) {
+		t.Errorf("expected ErrZoneNotFound without a forecast, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/htmltext"
+	"example.com/avalanche/internal/models"
+)
+
+// Export formats.
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+// ErrUnknownExportFormat is returned for formats other than csv and ndjson.
+var ErrUnknownExportFormat = errors.New("unknown export format (use csv or ndjson)")
+
+// ExportRow is one zone's danger rating in one elevation band on one day.
+// Field order and names are the export schema and must stay stable; add new
+// fields at the end.
+type ExportRow struct {
+	Date        string             `json:"date"`
+	CenterID    string             `json:"center_id"`
+	ZoneID      string             `json:"zone_id"`
+	ZoneName    string             `json:"zone_name"`
+	Band        string             `json:"band"`
+	BandName    string             `json:"band_name"`
+	DangerLevel domain.DangerLevel `json:"danger_level"`
+	DangerName  string             `json:"danger_name"`
+	ProductID   int                `json:"product_id"`
+	IssuedAt    string             `json:"issued_at"`
+	AmendedAt   string             `json:"amended_at"`
+	BottomLine  string             `json:"bottom_line"`
+}
+
+// ExportColumns is the CSV header, matching the ExportRow JSON names.
+var ExportColumns = []string{
+	"date", "center_id", "zone_id", "zone_name", "band", "band_name",
+	"danger_level", "danger_name", "product_id", "issued_at", "amended_at", "bottom_line",
+}
+
+func (r ExportRow) record() []string {
+	return []string{
+		r.Date, r.CenterID, r.ZoneID, r.ZoneName, r.Band, r.BandName,
+		strconv.Itoa(int(r.DangerLevel)), r.DangerName, strconv.Itoa(r.ProductID), r.IssuedAt, r.AmendedAt, r.BottomLine,
+	}
+}
+
+// exportBands are the elevation bands in output order.
+var exportBands = []struct{ band, name string }{
+	{"upper", "Above treeline"},
+	{"middle", "Near treeline"},
+	{"lower", "Below treeline"},
+}
+
+// ExportService writes archived forecast history in flat, analysis-friendly
+// formats.
+type ExportService struct {
+	repo *db.ArchiveRepository
+}
+
+func NewExportService(repo *db.ArchiveRepository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// ParseExportFilter builds a filter from comma-separated centers and zones and
+// optional YYYY-MM-DD from and to dates.
+func ParseExportFilter(centers, zones, from, to string) (db.ArchiveFilter, error) {
+	var f db.ArchiveFilter
+	for _, c := range strings.Split(centers, ",") {
+		if c = strings.ToUpper(strings.TrimSpace(c)); c != "" {
+			f.CenterIDs = append(f.CenterIDs, c)
+		}
+	}
+	for _, z := range strings.Split(zones, ",") {
+		if z = strings.TrimSpace(z); z == "" {
+			continue
+		}
+		id, err := domain.ParseZoneID(z)
+		if err != nil || !id.IsSpecificZone() {
+			return db.ArchiveFilter{}, fmt.Errorf("invalid zone %q", z)
+		}
+		f.ZoneIDs = append(f.ZoneIDs, id.String())
+	}
+	for _, d := range []struct {
+		raw string
+		dst *string
+	}{{from, &f.From}, {to, &f.To}} {
+		if d.raw == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, d.raw); err != nil {
+			return db.ArchiveFilter{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", d.raw)
+		}
+		*d.dst = d.raw
+	}
+	if f.From != "" && f.To != "" && f.From > f.To {
+		return db.ArchiveFilter{}, errors.New("from is after to")
+	}
+	return f, nil
+}
+
+// Export writes one row per zone, day and elevation band to w. When a zone
+// has several forecasts for a day, the most recently amended one is used.
+func (s *ExportService) Export(ctx context.Context, w io.Writer, format string, filter db.ArchiveFilter) error {
+	var write func(ExportRow) error
+	var flush func() error
+	switch format {
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(ExportColumns); err != nil {
+			return err
+		}
+		write = func(r ExportRow) error { return cw.Write(r.record()) }
+		flush = func() error { cw.Flush(); return cw.Error() }
+	case ExportNDJSON:
+		enc := json.NewEncoder(w)
+		write = func(r ExportRow) error { return enc.Encode(r) }
+		flush = func() error { return nil }
+	default:
+		return ErrUnknownExportFormat
+	}
+
+	var lastDay, lastZone string
+	err := s.repo.EachForecast(ctx, filter, func(f models.ArchivedForecast) error {
+		if f.ValidDate == lastDay && f.ZoneID == lastZone {
+			return nil
+		}
+		lastDay, lastZone = f.ValidDate, f.ZoneID
+		for _, row := range exportRows(f) {
+			if err := write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	return flush()
+}
+
+func exportRows(f models.ArchivedForecast) []ExportRow {
+	levels := []domain.DangerLevel{f.DangerUpper, f.DangerMiddle, f.DangerLower}
+	bottomLine := htmltext.Line(f.BottomLine, 0)
+	rows := make([]ExportRow, len(exportBands))
+	for i, b := range exportBands {
+		rows[i] = ExportRow{
+			Date:        f.ValidDate,
+			CenterID:    f.CenterID,
+			ZoneID:      f.ZoneID,
+			ZoneName:    f.ZoneName,
+			Band:        b.band,
+			BandName:    b.name,
+			DangerLevel: levels[i],
+			DangerName:  levels[i].Name(),
+			ProductID:   f.ProductID,
+			IssuedAt:    f.IssuedAt.UTC().Format(time.RFC3339),
+			AmendedAt:   f.AmendedAt.UTC().Format(time.RFC3339),
+			BottomLine:  bottomLine,
+		}
+	}
+	return rows
+}
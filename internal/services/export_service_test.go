@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newExportService(t *testing.T) *services.ExportService {
+	t.Helper()
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.ArchivedForecast{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	at := time.Date(2025, 1, 15, 2, 0, 0, 0, time.UTC)
+	rows := []models.ArchivedForecast{
+		{ZoneID: "NWAC_10", ProductID: 1, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: at, AmendedAt: at, ValidDate: "2025-01-15",
+			DangerUpper: 3, DangerMiddle: 2, DangerLower: 1, BottomLine: "<p>Old bottom line.</p>"},
+		// An amended second product for the same zone and day wins.
+		{ZoneID: "NWAC_10", ProductID: 2, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: at.Add(time.Hour), AmendedAt: at.Add(3 * time.Hour), ValidDate: "2025-01-15",
+			DangerUpper: 4, DangerMiddle: 3, DangerLower: 2, BottomLine: "<p>Wind slabs, <b>large</b>.</p>"},
+		{ZoneID: "NWAC_10", ProductID: 3, CenterID: "NWAC", ProductType: "forecast", ZoneName: "Snoqualmie Pass",
+			Title: "t", Content: "c", IssuedAt: at.Add(24 * time.Hour), AmendedAt: at.Add(24 * time.Hour), ValidDate: "2025-01-16",
+			DangerUpper: 2, DangerMiddle: 2, DangerLower: 1},
+		{ZoneID: "NWAC_10", ProductID: 4, CenterID: "NWAC", ProductType: "warning", Title: "t", Content: "c",
+			IssuedAt: at, AmendedAt: at, ValidDate: "2025-01-15"},
+		{ZoneID: "IPAC_1", ProductID: 5, CenterID: "IPAC", ProductType: "forecast", Title: "t", Content: "c",
+			IssuedAt: at, AmendedAt: at, ValidDate: "2025-01-15", DangerUpper: 1, DangerMiddle: 1, DangerLower: 1},
+	}
+	if err := gdb.Create(&rows).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return services.NewExportService(db.NewArchiveRepository(gdb))
+}
+
+func TestExportService_CSV(t *testing.T) {
+	svc := newExportService(t)
+	filter, err := services.ParseExportFilter("nwac", "", "2025-01-15", "2025-01-15")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.Export(context.Background(), &buf, services.ExportCSV, filter); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected header and three bands, got %d records: %v", len(records), records)
+	}
+	if got := records[0]; len(got) != len(services.ExportColumns) || got[0] != "date" || got[11] != "bottom_line" {
+		t.Errorf("unexpected header %v", got)
+	}
+	upper := records[1]
+	want := []string{"2025-01-15", "NWAC", "NWAC_10", "Snoqualmie Pass", "upper", "Above treeline", "4", "High", "2"}
+	for i, w := range want {
+		if upper[i] != w {
+			t.Errorf("column %s = %q, want %q", services.ExportColumns[i], upper[i], w)
+		}
+	}
+	if upper[11] != "Wind slabs, large." {
+		t.Errorf("expected plain-text bottom line, got %q", upper[11])
+	}
+	if records[3][4] != "lower" || records[3][6] != "2" {
+		t.Errorf("unexpected lower band %v", records[3])
+	}
+}
+
+func TestExportService_NDJSON(t *testing.T) {
+	svc := newExportService(t)
+	filter, err := services.ParseExportFilter("", "NWAC_10,IPAC_1", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.Export(context.Background(), &buf, services.ExportNDJSON, filter); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var rows []services.ExportRow
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var r services.ExportRow
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		rows = append(rows, r)
+	}
+	// Two zones on the 15th and one on the 16th, three bands each.
+	if len(rows) != 9 {
+		t.Fatalf("expected 9 rows, got %d", len(rows))
+	}
+	if rows[0].ZoneID != "IPAC_1" || rows[3].ZoneID != "NWAC_10" || rows[6].Date != "2025-01-16" {
+		t.Errorf("unexpected ordering: %+v", rows)
+	}
+}
+
+func TestParseExportFilter_Invalid(t *testing.T) {
+	for _, tc := range [][4]string{
+		{"", "", "2025-13-01", ""},
+		{"", "NWAC", "", ""},
+		{"", "", "2025-02-01", "2025-01-01"},
+	} {
+		if _, err := services.ParseExportFilter(tc[0], tc[1], tc[2], tc[3]); err == nil {
+			t.Errorf("expected error for %v", tc)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"example.com/avalanche/internal/atom"
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+// feedEntryLimit is the number of archived forecasts in each feed.
+const feedEntryLimit = 50
+
+// FeedService builds Atom feeds from the forecast archive.
+type FeedService struct {
+	repo *db.ArchiveRepository
+	now  func() time.Time
+}
+
+func NewFeedService(repo *db.ArchiveRepository) *FeedService {
+	return &FeedService{repo: repo, now: time.Now}
+}
+
+// ZoneFeed returns the feed of forecasts issued for a zone. selfURL is the
+// address the feed was requested from.
+func (s *FeedService) ZoneFeed(zoneID *domain.ZoneID, selfURL string) (atom.Feed, error) {
+	if !zoneID.IsSpecificZone() {
+		return atom.Feed{}, fmt.Errorf("%s is a center, not a zone", zoneID)
+	}
+	entries, err := s.repo.ListByZone(zoneID.String(), feedEntryLimit)
+	if err != nil {
+		return atom.Feed{}, fmt.Errorf("failed to load archive: %w", err)
+	}
+	name := zoneID.String()
+	if len(entries) > 0 && entries[0].ZoneName != "" {
+		name = entries[0].ZoneName
+	}
+	return s.feed("zone:"+zoneID.String(), "Avalanche forecasts: "+name, selfURL, entries), nil
+}
+
+// CenterFeed returns the feed of forecasts issued across a center's zones.
+func (s *FeedService) CenterFeed(centerID string, selfURL string) (atom.Feed, error) {
+	entries, err := s.repo.ListByCenter(centerID, feedEntryLimit)
+	if err != nil {
+		return atom.Feed{}, fmt.Errorf("failed to load archive: %w", err)
+	}
+	name := centerID
+	if len(entries) > 0 && entries[0].CenterName != "" {
+		name = entries[0].CenterName
+	}
+	return s.feed("center:"+centerID, "Avalanche forecasts: "+name, selfURL, entries), nil
+}
+
+// feed converts archived forecasts to entries. Entry IDs derive from the zone
+// and product, so an amended forecast keeps its ID and only its updated time
+// changes.
+func (s *FeedService) feed(key, title, selfURL string, archived []models.ArchivedForecast) atom.Feed {
+	f := atom.Feed{
+		ID:      atom.NameID("avalanche-feed:" + key),
+		Title:   title,
+		Updated: atom.Time(s.now()),
+		Links:   []atom.Link{{Href: selfURL, Rel: "self", Type: atom.ContentType}},
+	}
+	for _, a := range archived {
+		e := atom.Entry{
+			ID:        atom.NameID("avalanche-forecast:" + a.ZoneID + ":" + strconv.Itoa(a.ProductID)),
+			Title:     a.Title,
+			Updated:   atom.Time(a.AmendedAt),
+			Published: atom.Time(a.IssuedAt),
+			Content:   &atom.Text{Type: "html", Body: a.Content},
+		}
+		if a.CenterName != "" {
+			e.Author = &atom.Person{Name: a.CenterName}
+		}
+		if a.Link != "" {
+			e.Links = []atom.Link{{Href: a.Link, Rel: "alternate", Type: "text/html"}}
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	if len(archived) > 0 {
+		f.Updated = atom.Time(archived[0].AmendedAt)
+	}
+	return f
+}
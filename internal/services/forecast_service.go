@@ -4,6 +4,7 @@ import (
 	"sort"
 	"time"
 
+	"example.com/avalanche/internal/htmltext"
 	"example.com/avalanche/internal/models"
 )
 
@@ -79,17 +80,23 @@ func (s *ForecastService) BuildZoneForecasts(forecasts []models.Forecast) map[st
 				continue
 			}
 
+			bottomLine := htmltext.Sanitize(bottomLineOrDefault(f))
 			zf := &models.ZoneForecast{
-				ZoneID:     fullZoneID,
-				ZoneName:   z.Name,
-				Center:     f.AvalancheCenter.Name,
-				IssuedTime: f.PublishedTime.Format(time.RFC3339),
-				StartDate:  f.StartDate.Format(time.RFC3339),
-				EndDate:    f.EndDate.Format(time.RFC3339),
-				BottomLine: bottomLineOrDefault(f),
+				ZoneID:         fullZoneID,
+				ZoneName:       z.Name,
+				Center:         f.AvalancheCenter.Name,
+				ProductID:      f.ID,
+				URL:            z.URL,
+				IssuedTime:     f.PublishedTime.Format(time.RFC3339),
+				StartDate:      f.StartDate.Format(time.RFC3339),
+				EndDate:        f.EndDate.Format(time.RFC3339),
+				BottomLine:     bottomLine,
+				BottomLineHTML: bottomLine,
+				BottomLineText: htmltext.Text(bottomLine),
 			}
 
 			for _, d := range f.Danger {
+				d.Message = htmltext.Text(d.Message)
 				switch d.ValidDay {
 				case "current":
 					zf.TodayDanger = &d
@@ -100,6 +100,26 @@ func TestProcessForecasts_SortsAndFills(t *testing.T) {
 	}
 }
 
+func TestBuildZoneForecasts_BottomLineVariants(t *testing.T) {
+	svc := services.NewForecast(nil)
+	zones := svc.BuildZoneForecasts([]models.Forecast{{
+		AvalancheCenter: models.AvalancheCenter{ID: "NWAC"},
+		ForecastZone:    []models.Zone{{ZoneID: "10"}},
+		BottomLine:      `<p onclick="steal()">Avoid <b>cornices</b>.</p><script>steal()</script><p>Stay safe.</p>`,
+		Danger:          []models.DangerRating{{ValidDay: "current", Message: "<p>Rating &amp; more</p>"}},
+	}})
+	zf := zones["NWAC_10"]
+	if zf.BottomLineHTML != "<p>Avoid <b>cornices</b>.</p><p>Stay safe.</p>" || zf.BottomLine != zf.BottomLineHTML {
+		t.Errorf("expected a sanitized bottom line, got %q and %q", zf.BottomLine, zf.BottomLineHTML)
+	}
+	if zf.BottomLineText != "Avoid cornices.\n\nStay safe." {
+		t.Errorf("unexpected plain-text bottom line %q", zf.BottomLineText)
+	}
+	if zf.TodayDanger.Message != "Rating & more" {
+		t.Errorf("expected a plain-text danger message, got %q", zf.TodayDanger.Message)
+	}
+}
+
 func TestGetForecastsForCenters_Success(t *testing.T) {
 	now := time.Now().UTC()
 	client := &mockForecastClient{
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+var (
+	// ErrInvalidOrganization is returned when an organization change is rejected.
+	ErrInvalidOrganization = errors.New("invalid organization")
+	// ErrOrganizationNotFound is returned for unknown organization IDs.
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrLastOwner is returned for changes that would leave an organization
+	// without an owner.
+	ErrLastOwner = errors.New("an organization must keep at least one owner")
+)
+
+const (
+	maxOrganizationZones  = 50
+	maxOrganizationName   = 100
+	maxMembersPerRequest  = 500
+	organizationAuditPage = 200
+)
+
+// OrganizationService manages organizations and keeps their members
+// subscribed by email to the organization's zones. Every change is recorded
+// in the organization's audit log.
+type OrganizationService struct {
+	repo *db.OrganizationRepository
+}
+
+func NewOrganizationService(repo *db.OrganizationRepository) *OrganizationService {
+	return &OrganizationService{repo: repo}
+}
+
+// CreateOrganizationRequest describes a new organization and its first owner.
+type CreateOrganizationRequest struct {
+	Name    string
+	ZoneIDs []*domain.ZoneID
+	Owner   *domain.Email
+}
+
+// MemberRequest adds a member or changes a member's role. An empty Role adds
+// new members as RoleMember and leaves existing members' roles alone.
+type MemberRequest struct {
+	Email *domain.Email
+	Role  string
+}
+
+// OrganizationDetail is an organization with its members.
+type OrganizationDetail struct {
+	*models.Organization
+	Members []models.OrganizationMember `json:"members"`
+}
+
+// MemberChanges reports the outcome of a bulk membership change by email.
+type MemberChanges struct {
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+func newMemberChanges() *MemberChanges {
+	return &MemberChanges{Added: []string{}, Updated: []string{}, Removed: []string{}, Unchanged: []string{}}
+}
+
+// Create stores an organization and subscribes its owner to its zones.
+func (s *OrganizationService) Create(ctx context.Context, actor string, req CreateOrganizationRequest) (*OrganizationDetail, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxOrganizationName {
+		return nil, fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidOrganization, maxOrganizationName)
+	}
+	zones, err := organizationZones(req.ZoneIDs)
+	if err != nil {
+		return nil, err
+	}
+	if req.Owner == nil {
+		return nil, fmt.Errorf("%w: an owner is required", ErrInvalidOrganization)
+	}
+
+	org := &models.Organization{Name: name, ZoneIDs: zones}
+	owner := models.OrganizationMember{Email: req.Owner.String(), Role: models.RoleOwner}
+	err = s.repo.Transaction(func(tx *db.OrganizationRepository) error {
+		if err := tx.Create(org); err != nil {
+			return err
+		}
+		if err := audit(tx, org.ID, actor, models.AuditOrganizationCreated, "", fmt.Sprintf("%s, zones %s", name, strings.Join(zones, ","))); err != nil {
+			return err
+		}
+		owner.OrganizationID = org.ID
+		return addMember(tx, org, &owner, actor)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	log.Printf("[OrganizationService] created organization %d %q with %d zones", org.ID, name, len(zones))
+	return &OrganizationDetail{Organization: org, Members: []models.OrganizationMember{owner}}, nil
+}
+
+// List returns every organization.
+func (s *OrganizationService) List(ctx context.Context) ([]models.Organization, error) {
+	out, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return out, nil
+}
+
+// Get returns an organization with its members.
+func (s *OrganizationService) Get(ctx context.Context, id uint) (*OrganizationDetail, error) {
+	org, err := s.load(s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.Members(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return &OrganizationDetail{Organization: org, Members: members}, nil
+}
+
+// SetZones replaces an organization's zones and resubscribes every member.
+func (s *OrganizationService) SetZones(ctx context.Context, actor string, id uint, zoneIDs []*domain.ZoneID) (*OrganizationDetail, error) {
+	zones, err := organizationZones(zoneIDs)
+	if err != nil {
+		return nil, err
+	}
+	var detail *OrganizationDetail
+	err = s.repo.Transaction(func(tx *db.OrganizationRepository) error {
+		org, err := s.load(tx, id)
+		if err != nil {
+			return err
+		}
+		previous := org.ZoneIDs
+		org.ZoneIDs = zones
+		if err := tx.SetZones(id, zones); err != nil {
+			return err
+		}
+		members, err := tx.Members(id)
+		if err != nil {
+			return err
+		}
+		var added, removed int
+		for _, m := range members {
+			subscribed, unsubscribed, err := syncMember(tx, org, m.Email)
+			if err != nil {
+				return err
+			}
+			added, removed = added+subscribed, removed+unsubscribed
+		}
+		detail = &OrganizationDetail{Organization: org, Members: members}
+		return audit(tx, id, actor, models.AuditZonesChanged, "",
+			fmt.Sprintf("%s (was %s), %d subscriptions added, %d removed", strings.Join(zones, ","), strings.Join(previous, ","), added, removed))
+	})
+	if err != nil {
+		return nil, organizationError("update organization zones", err)
+	}
+	return detail, nil
+}
+
+// AddMembers adds members and changes roles in bulk. New members are
+// subscribed to the organization's zones.
+func (s *OrganizationService) AddMembers(ctx context.Context, actor string, id uint, reqs []MemberRequest) (*MemberChanges, error) {
+	if len(reqs) == 0 || len(reqs) > maxMembersPerRequest {
+		return nil, fmt.Errorf("%w: send 1 to %d members", ErrInvalidOrganization, maxMembersPerRequest)
+	}
+	for _, req := range reqs {
+		if req.Role != "" && !slices.Contains(models.OrganizationRoles, req.Role) {
+			return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidOrganization, req.Role)
+		}
+	}
+	changes := newMemberChanges()
+	err := s.repo.Transaction(func(tx *db.OrganizationRepository) error {
+		org, err := s.load(tx, id)
+		if err != nil {
+			return err
+		}
+		members, err := tx.Members(id)
+		if err != nil {
+			return err
+		}
+		byEmail := make(map[string]*models.OrganizationMember, len(members))
+		for i := range members {
+			byEmail[members[i].Email] = &members[i]
+		}
+		for _, req := range reqs {
+			email := req.Email.String()
+			m, ok := byEmail[email]
+			if !ok {
+				m = &models.OrganizationMember{OrganizationID: id, Email: email, Role: cmp.Or(req.Role, models.RoleMember)}
+				if err := addMember(tx, org, m, actor); err != nil {
+					return err
+				}
+				byEmail[email] = m
+				changes.Added = append(changes.Added, email)
+				continue
+			}
+			if req.Role == "" || req.Role == m.Role {
+				changes.Unchanged = append(changes.Unchanged, email)
+				continue
+			}
+			if m.Role == models.RoleOwner && countOwners(byEmail) == 1 {
+				return ErrLastOwner
+			}
+			previous := m.Role
+			m.Role = req.Role
+			if err := tx.SaveMember(m); err != nil {
+				return err
+			}
+			if err := audit(tx, id, actor, models.AuditMemberRoleChanged, email, previous+" -> "+m.Role); err != nil {
+				return err
+			}
+			changes.Updated = append(changes.Updated, email)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, organizationError("add members", err)
+	}
+	return changes, nil
+}
+
+// RemoveMembers removes members in bulk and unsubscribes them from the
+// organization's zones. Their own subscriptions are kept.
+func (s *OrganizationService) RemoveMembers(ctx context.Context, actor string, id uint, emails []*domain.Email) (*MemberChanges, error) {
+	if len(emails) == 0 || len(emails) > maxMembersPerRequest {
+		return nil, fmt.Errorf("%w: send 1 to %d members", ErrInvalidOrganization, maxMembersPerRequest)
+	}
+	changes := newMemberChanges()
+	err := s.repo.Transaction(func(tx *db.OrganizationRepository) error {
+		if _, err := s.load(tx, id); err != nil {
+			return err
+		}
+		members, err := tx.Members(id)
+		if err != nil {
+			return err
+		}
+		byEmail := make(map[string]*models.OrganizationMember, len(members))
+		for i := range members {
+			byEmail[members[i].Email] = &members[i]
+		}
+		for _, e := range emails {
+			email := e.String()
+			m, ok := byEmail[email]
+			if !ok {
+				changes.Unchanged = append(changes.Unchanged, email)
+				continue
+			}
+			if m.Role == models.RoleOwner && countOwners(byEmail) == 1 {
+				return ErrLastOwner
+			}
+			subs, err := tx.MemberSubscriptions(id, email)
+			if err != nil {
+				return err
+			}
+			for _, sub := range subs {
+				if err := tx.DeleteSubscription(sub.ID); err != nil {
+					return err
+				}
+			}
+			if err := tx.DeleteMember(m.ID); err != nil {
+				return err
+			}
+			delete(byEmail, email)
+			if err := audit(tx, id, actor, models.AuditMemberRemoved, email, fmt.Sprintf("%s, unsubscribed from %d zones", m.Role, len(subs))); err != nil {
+				return err
+			}
+			changes.Removed = append(changes.Removed, email)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, organizationError("remove members", err)
+	}
+	return changes, nil
+}
+
+// Delete removes an organization, its members and every subscription it
+// manages.
+func (s *OrganizationService) Delete(ctx context.Context, actor string, id uint) error {
+	err := s.repo.Transaction(func(tx *db.OrganizationRepository) error {
+		org, err := s.load(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := audit(tx, id, actor, models.AuditOrganizationDeleted, "", org.Name); err != nil {
+			return err
+		}
+		return tx.Delete(id)
+	})
+	if err != nil {
+		return organizationError("delete organization", err)
+	}
+	log.Printf("[OrganizationService] %s deleted organization %d", actor, id)
+	return nil
+}
+
+// Audit returns an organization's most recent audit entries, newest first.
+// It works for deleted organizations too.
+func (s *OrganizationService) Audit(ctx context.Context, id uint) ([]models.OrganizationAuditEntry, error) {
+	out, err := s.repo.Audit(id, organizationAuditPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit log: %w", err)
+	}
+	return out, nil
+}
+
+func (s *OrganizationService) load(repo *db.OrganizationRepository, id uint) (*models.Organization, error) {
+	org, err := repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// addMember stores a new member, subscribes it to the organization's zones
+// and records the addition.
+func addMember(tx *db.OrganizationRepository, org *models.Organization, m *models.OrganizationMember, actor string) error {
+	if err := tx.SaveMember(m); err != nil {
+		return err
+	}
+	subscribed, _, err := syncMember(tx, org, m.Email)
+	if err != nil {
+		return err
+	}
+	return audit(tx, org.ID, actor, models.AuditMemberAdded, m.Email, fmt.Sprintf("%s, subscribed to %d zones", m.Role, subscribed))
+}
+
+// syncMember makes the subscriptions the organization manages for email
+// match its zones. Zones the member already gets by email some other way are
+// skipped so nobody receives the same forecast twice.
+func syncMember(tx *db.OrganizationRepository, org *models.Organization, email string) (subscribed, unsubscribed int, err error) {
+	subs, err := tx.MemberSubscriptions(org.ID, email)
+	if err != nil {
+		return 0, 0, err
+	}
+	stale := make(map[string]models.Subscription, len(subs))
+	for _, sub := range subs {
+		stale[sub.ZoneID] = sub
+	}
+	for _, zone := range org.ZoneIDs {
+		if _, ok := stale[zone]; ok {
+			delete(stale, zone)
+			continue
+		}
+		exists, err := tx.HasEmailSubscription(email, zone)
+		if err != nil {
+			return 0, 0, err
+		}
+		if exists {
+			continue
+		}
+		orgID := org.ID
+		sub := &models.Subscription{ZoneID: zone, Email: email, Channel: models.ChannelEmail, OrganizationID: &orgID}
+		if err := tx.CreateSubscription(sub); err != nil {
+			return 0, 0, err
+		}
+		subscribed++
+	}
+	for _, sub := range stale {
+		if err := tx.DeleteSubscription(sub.ID); err != nil {
+			return 0, 0, err
+		}
+		unsubscribed++
+	}
+	return subscribed, unsubscribed, nil
+}
+
+func audit(tx *db.OrganizationRepository, orgID uint, actor, action, subject, detail string) error {
+	return tx.AppendAudit(&models.OrganizationAuditEntry{
+		OrganizationID: orgID, Actor: actor, Action: action, Subject: subject, Detail: detail,
+	})
+}
+
+// organizationZones validates and deduplicates zone IDs.
+func organizationZones(ids []*domain.ZoneID) ([]string, error) {
+	var zones []string
+	for _, id := range ids {
+		if !slices.Contains(zones, id.String()) {
+			zones = append(zones, id.String())
+		}
+	}
+	if len(zones) == 0 || len(zones) > maxOrganizationZones {
+		return nil, fmt.Errorf("%w: organizations need 1 to %d zones", ErrInvalidOrganization, maxOrganizationZones)
+	}
+	return zones, nil
+}
+
+func countOwners(members map[string]*models.OrganizationMember) int {
+	n := 0
+	for _, m := range members {
+		if m.Role == models.RoleOwner {
+			n++
+		}
+	}
+	return n
+}
+
+// organizationError passes sentinel errors through and wraps the rest.
+func organizationError(action string, err error) error {
+	if errors.Is(err, ErrOrganizationNotFound) || errors.Is(err, ErrLastOwner) {
+		return err
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
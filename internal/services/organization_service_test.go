@@ -0,0 +1,144 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func zoneIDs(t *testing.T, raw ...string) []*domain.ZoneID {
+	t.Helper()
+	out := make([]*domain.ZoneID, 0, len(raw))
+	for _, r := range raw {
+		z, err := domain.ParseZoneID(r)
+		if err != nil {
+			t.Fatalf("zone %q: %v", r, err)
+		}
+		out = append(out, z)
+	}
+	return out
+}
+
+func mustEmail(t *testing.T, raw string) *domain.Email {
+	t.Helper()
+	e, err := domain.NewEmail(raw)
+	if err != nil {
+		t.Fatalf("email %q: %v", raw, err)
+	}
+	return e
+}
+
+// subscribedZones returns the sorted zones email is subscribed to.
+func subscribedZones(t *testing.T, gdb *gorm.DB, email string) []string {
+	t.Helper()
+	var zones []string
+	if err := gdb.Model(&models.Subscription{}).Where("email = ?", email).Order("zone_id").Pluck("zone_id", &zones).Error; err != nil {
+		t.Fatalf("subscriptions: %v", err)
+	}
+	return zones
+}
+
+func TestOrganizationService_MembersFollowZoneSet(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.Organization{}, &models.OrganizationMember{}, &models.OrganizationAuditEntry{}, &models.ZoneGroup{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	svc := services.NewOrganizationService(db.NewOrganizationRepository(gdb))
+	ctx := context.Background()
+
+	// A guide who already follows NWAC_11 personally keeps that subscription.
+	personal := models.Subscription{ZoneID: "NWAC_11", Email: "guide@example.com", Channel: models.ChannelEmail}
+	if err := gdb.Create(&personal).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	org, err := svc.Create(ctx, "ops", services.CreateOrganizationRequest{
+		Name: "Alpine Guides", ZoneIDs: zoneIDs(t, "NWAC_10", "NWAC_11"), Owner: mustEmail(t, "owner@example.com"),
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if got := subscribedZones(t, gdb, "owner@example.com"); !slices.Equal(got, []string{"NWAC_10", "NWAC_11"}) {
+		t.Errorf("owner subscribed to %v", got)
+	}
+
+	changes, err := svc.AddMembers(ctx, "ops", org.ID, []services.MemberRequest{
+		{Email: mustEmail(t, "guide@example.com")},
+		{Email: mustEmail(t, "lead@example.com"), Role: models.RoleAdmin},
+		{Email: mustEmail(t, "owner@example.com"), Role: models.RoleOwner},
+	})
+	if err != nil {
+		t.Fatalf("add members: %v", err)
+	}
+	if len(changes.Added) != 2 || len(changes.Unchanged) != 1 {
+		t.Errorf("changes = %+v", changes)
+	}
+	if got := subscribedZones(t, gdb, "guide@example.com"); !slices.Equal(got, []string{"NWAC_10", "NWAC_11"}) {
+		t.Errorf("guide subscribed to %v, want no duplicate of the personal subscription", got)
+	}
+
+	if _, err := svc.SetZones(ctx, "ops", org.ID, zoneIDs(t, "NWAC_11", "NWAC_12")); err != nil {
+		t.Fatalf("set zones: %v", err)
+	}
+	if got := subscribedZones(t, gdb, "lead@example.com"); !slices.Equal(got, []string{"NWAC_11", "NWAC_12"}) {
+		t.Errorf("lead subscribed to %v after zone change", got)
+	}
+
+	if _, err := svc.RemoveMembers(ctx, "ops", org.ID, []*domain.Email{mustEmail(t, "owner@example.com")}); !errors.Is(err, services.ErrLastOwner) {
+		t.Errorf("removing the last owner: err = %v", err)
+	}
+	if _, err := svc.AddMembers(ctx, "ops", org.ID, []services.MemberRequest{{Email: mustEmail(t, "owner@example.com"), Role: models.RoleMember}}); !errors.Is(err, services.ErrLastOwner) {
+		t.Errorf("demoting the last owner: err = %v", err)
+	}
+
+	changes, err = svc.RemoveMembers(ctx, "ops", org.ID, []*domain.Email{mustEmail(t, "guide@example.com"), mustEmail(t, "nobody@example.com")})
+	if err != nil {
+		t.Fatalf("remove members: %v", err)
+	}
+	if !slices.Equal(changes.Removed, []string{"guide@example.com"}) || !slices.Equal(changes.Unchanged, []string{"nobody@example.com"}) {
+		t.Errorf("changes = %+v", changes)
+	}
+	if got := subscribedZones(t, gdb, "guide@example.com"); !slices.Equal(got, []string{"NWAC_11"}) {
+		t.Errorf("removed guide still subscribed to %v, want only the personal subscription", got)
+	}
+
+	if err := svc.Delete(ctx, "ops", org.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got := subscribedZones(t, gdb, "lead@example.com"); len(got) != 0 {
+		t.Errorf("members of a deleted organization still subscribed to %v", got)
+	}
+	if _, err := svc.Get(ctx, org.ID); !errors.Is(err, services.ErrOrganizationNotFound) {
+		t.Errorf("get deleted organization: err = %v", err)
+	}
+
+	entries, err := svc.Audit(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+	var actions []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+		if e.Actor != "ops" {
+			t.Errorf("entry %d actor = %q", e.ID, e.Actor)
+		}
+	}
+	want := []string{
+		models.AuditOrganizationDeleted, models.AuditMemberRemoved, models.AuditZonesChanged,
+		models.AuditMemberAdded, models.AuditMemberAdded, models.AuditMemberAdded, models.AuditOrganizationCreated,
+	}
+	if !slices.Equal(actions, want) {
+		t.Errorf("audit actions = %v, want %v", actions, want)
+	}
+}
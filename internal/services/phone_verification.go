@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+const (
+	verificationCodeTTL   = 10 * time.Minute
+	verificationResendGap = time.Minute
+	maxVerificationTries  = 5
+)
+
+// Phone verification errors.
+var (
+	ErrVerificationNotFound = errors.New("no verification pending for this phone number")
+	ErrVerificationExpired  = errors.New("verification code expired")
+	ErrVerificationInvalid  = errors.New("invalid verification code")
+	ErrVerificationLocked   = errors.New("too many verification attempts")
+)
+
+// EnableSMS turns on SMS subscriptions. New numbers receive a one-time code
+// through sender and their subscriptions stay paused until it is confirmed.
+func (s *SubscriptionService) EnableSMS(sender notifier.SMSSender, phones *db.PhoneVerificationRepository) {
+	s.sms = sender
+	s.phones = phones
+	s.RegisterChannel(models.ChannelSMS, notifier.NewSMSChannel(sender))
+}
+
+// phoneVerified reports whether phone has already been confirmed.
+func (s *SubscriptionService) phoneVerified(phone string) (bool, error) {
+	v, err := s.phones.Get(phone)
+	if err != nil {
+		return false, fmt.Errorf("failed to load phone verification: %w", err)
+	}
+	return v.IsVerified(), nil
+}
+
+// sendVerificationCode texts a fresh code to phone unless one was sent within
+// the last minute and is still valid.
+func (s *SubscriptionService) sendVerificationCode(ctx context.Context, phone string) error {
+	existing, err := s.phones.Get(phone)
+	if err != nil {
+		return fmt.Errorf("failed to load phone verification: %w", err)
+	}
+	now := time.Now().UTC()
+	if existing != nil && now.Sub(existing.UpdatedAt) < verificationResendGap && now.Before(existing.ExpiresAt) {
+		return nil
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return err
+	}
+	if err := s.phones.StartVerification(phone, hashVerificationCode(phone, code), now.Add(verificationCodeTTL)); err != nil {
+		return fmt.Errorf("failed to store verification code: %w", err)
+	}
+	body := fmt.Sprintf("Avalanche alerts: your verification code is %s. It expires in %d minutes.", code, int(verificationCodeTTL.Minutes()))
+	if err := s.sms.SendSMS(ctx, phone, body); err != nil {
+		return fmt.Errorf("failed to send verification code: %w", err)
+	}
+	return nil
+}
+
+// VerifyPhone confirms phone with code, resumes its pending SMS subscriptions
+// and sends each a welcome message. It returns the number of subscriptions resumed.
+func (s *SubscriptionService) VerifyPhone(ctx context.Context, phone *domain.PhoneNumber, code string) (int64, error) {
+	if s.phones == nil {
+		return 0, ErrVerificationNotFound
+	}
+	v, err := s.phones.Get(phone.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load phone verification: %w", err)
+	}
+	switch {
+	case v == nil:
+		return 0, ErrVerificationNotFound
+	case v.IsVerified():
+		return 0, nil
+	case v.Attempts >= maxVerificationTries:
+		return 0, ErrVerificationLocked
+	case time.Now().After(v.ExpiresAt):
+		return 0, ErrVerificationExpired
+	}
+	expected := hashVerificationCode(phone.String(), code)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(v.CodeHash)) != 1 {
+		if err := s.phones.IncrementAttempts(phone.String()); err != nil {
+			return 0, fmt.Errorf("failed to record verification attempt: %w", err)
+		}
+		return 0, ErrVerificationInvalid
+	}
+
+	if err := s.phones.MarkVerified(phone.String(), time.Now().UTC()); err != nil {
+		return 0, fmt.Errorf("failed to mark phone verified: %w", err)
+	}
+	subs, err := s.subRepo.GetByTarget(phone.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	resumed, err := s.subRepo.ResumeByTarget(phone.String(), models.PauseReasonUnverified)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resume subscriptions: %w", err)
+	}
+	for i := range subs {
+		if subs[i].PauseReason != models.PauseReasonUnverified {
+			continue
+		}
+		sub := subs[i]
+		sub.PausedAt, sub.PauseReason = nil, ""
+		zoneID, err := domain.ParseZoneRef(sub.ZoneID)
+		if err != nil {
+			continue
+		}
+		go s.sendWelcomeEmail(context.Background(), &sub, zoneID)
+	}
+	log.Printf("[SubscriptionService] verified %s, resumed %d subscriptions", phone, resumed)
+	return resumed, nil
+}
+
+func newVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func hashVerificationCode(phone, code string) string {
+	sum := sha256.Sum256([]byte(phone + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type capturingSMS struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (c *capturingSMS) SendSMS(ctx context.Context, to, body string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, body)
+	return nil
+}
+
+func TestSubscriptionService_SMSRequiresVerification(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.PhoneVerification{}, &models.AvalancheCenter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	subRepo := db.NewSubscriptionRepository(gdb)
+	svc := services.NewSubscriptionService(subRepo, db.NewCenterRepository(gdb),
+		services.NewForecast(&mockForecastClient{}), notifier.LogEmailSender{})
+	sms := &capturingSMS{}
+	svc.EnableSMS(sms, db.NewPhoneVerificationRepository(gdb))
+
+	phone, _ := domain.NewPhoneNumber("+12065550100")
+	zone, _ := domain.ParseZoneID("NWAC_10")
+	sub, err := svc.Create(context.Background(), services.CreateSubscriptionRequest{ZoneID: zone, Channel: models.ChannelSMS, Phone: phone})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if sub.PauseReason != models.PauseReasonUnverified || len(sms.messages) != 1 {
+		t.Fatalf("expected paused subscription and a code, got %+v, %v", sub, sms.messages)
+	}
+	code := regexp.MustCompile(`\d{6}`).FindString(sms.messages[0])
+
+	if _, err := svc.VerifyPhone(context.Background(), phone, "000000"+code); !errors.Is(err, services.ErrVerificationInvalid) {
+		t.Fatalf("expected invalid code, got %v", err)
+	}
+	resumed, err := svc.VerifyPhone(context.Background(), phone, code)
+	if err != nil || resumed != 1 {
+		t.Fatalf("verify: resumed=%d err=%v", resumed, err)
+	}
+	subs, _ := subRepo.GetByTarget(phone.String())
+	if len(subs) != 1 || subs[0].IsPaused() {
+		t.Fatalf("expected subscription resumed, got %+v", subs)
+	}
+
+	// Further subscriptions for a verified number start active without a new code.
+	other, _ := domain.ParseZoneID("NWAC_2")
+	sub, err = svc.Create(context.Background(), services.CreateSubscriptionRequest{ZoneID: other, Channel: models.ChannelSMS, Phone: phone})
+	if err != nil || sub.IsPaused() || len(sms.messages) != 1 {
+		t.Fatalf("expected active subscription without a new code, got %+v (%v), %d messages", sub, err, len(sms.messages))
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// Commands subscribers can send by replying to a forecast email.
+const (
+	ReplyStop   = "STOP"
+	ReplyPause  = "PAUSE"
+	ReplyResume = "RESUME"
+	ReplyDigest = "DIGEST"
+)
+
+const (
+	defaultPauseDays = 7
+	maxPauseDays     = 365
+)
+
+var (
+	// ErrUnknownReplyCommand is returned when a reply does not start with a known command.
+	ErrUnknownReplyCommand = errors.New("unknown reply command")
+	// ErrSubscriptionNotFound is returned when a reply refers to a deleted subscription.
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
+
+// ReplyCommand is a parsed email reply. Days applies to PAUSE; Off applies to
+// DIGEST and switches digest delivery back to immediate.
+type ReplyCommand struct {
+	Action string
+	Days   int
+	Off    bool
+}
+
+// quoteHeader matches the line mail clients insert above quoted text, e.g.
+// "On Mon, Dec 1, 2025 at 7:00 AM Avy Notifier <...> wrote:".
+var quoteHeader = regexp.MustCompile(`(?i)^on .+ wrote:?$`)
+
+// ParseReplyCommand reads the command from the first line of a reply body,
+// ignoring quoted text. subject is used when the body holds no command, since
+// some clients make it awkward to type above the quote.
+func ParseReplyCommand(body, subject string) (ReplyCommand, error) {
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, ">") || quoteHeader.MatchString(line) || line == "--" {
+			break
+		}
+		if cmd, err := parseReplyLine(line); err == nil {
+			return cmd, nil
+		}
+		break
+	}
+	subject = strings.TrimSpace(subject)
+	for {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(subject, "Re:"), "RE:"))
+		if trimmed == subject {
+			break
+		}
+		subject = trimmed
+	}
+	return parseReplyLine(subject)
+}
+
+func parseReplyLine(line string) (ReplyCommand, error) {
+	words := strings.Fields(strings.ToUpper(strings.Trim(line, ".!")))
+	if len(words) == 0 {
+		return ReplyCommand{}, ErrUnknownReplyCommand
+	}
+	cmd := ReplyCommand{Action: words[0]}
+	switch cmd.Action {
+	case ReplyStop, "UNSUBSCRIBE":
+		cmd.Action = ReplyStop
+	case ReplyResume, "START", "UNPAUSE":
+		cmd.Action = ReplyResume
+	case ReplyPause:
+		cmd.Days = defaultPauseDays
+		if len(words) > 1 {
+			days, err := strconv.Atoi(words[1])
+			if err != nil || days < 1 {
+				return ReplyCommand{}, fmt.Errorf("%w: PAUSE takes a number of days", ErrUnknownReplyCommand)
+			}
+			cmd.Days = min(days, maxPauseDays)
+		}
+	case ReplyDigest:
+		cmd.Off = len(words) > 1 && (words[1] == "OFF" || words[1] == "STOP")
+	default:
+		return ReplyCommand{}, ErrUnknownReplyCommand
+	}
+	return cmd, nil
+}
+
+// ApplyReplyCommand applies cmd to subscription subID and emails the
+// subscriber a confirmation. It returns the updated subscription, or the
+// deleted one for STOP.
+func (s *SubscriptionService) ApplyReplyCommand(ctx context.Context, subID uint, cmd ReplyCommand) (*models.Subscription, error) {
+	sub, err := s.subRepo.GetByID(subID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+	if sub == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	label := sub.ZoneID
+	now := time.Now().UTC()
+	var subject, text string
+	switch cmd.Action {
+	case ReplyStop:
+		if err := s.subRepo.DeleteByID(sub.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete subscription: %w", err)
+		}
+		subject = fmt.Sprintf("Unsubscribed from %s avalanche forecasts", label)
+		text = fmt.Sprintf("You will no longer receive avalanche forecasts for %s.", label)
+	case ReplyPause:
+		until := now.AddDate(0, 0, cmd.Days)
+		if err := s.subRepo.Pause(sub.ID, models.PauseReasonRequested, now, &until); err != nil {
+			return nil, fmt.Errorf("failed to pause subscription: %w", err)
+		}
+		sub.PausedAt, sub.PausedUntil, sub.PauseReason = &now, &until, models.PauseReasonRequested
+		subject = fmt.Sprintf("%s avalanche forecasts paused", label)
+		text = fmt.Sprintf("Forecasts for %s are paused until %s. Reply RESUME to restart them sooner.",
+			label, until.Format("Mon Jan 2, 2006"))
+	case ReplyResume:
+		if err := s.subRepo.Resume(sub.ID); err != nil {
+			return nil, fmt.Errorf("failed to resume subscription: %w", err)
+		}
+		sub.PausedAt, sub.PausedUntil, sub.PauseReason = nil, nil, ""
+		subject = fmt.Sprintf("%s avalanche forecasts resumed", label)
+		text = fmt.Sprintf("You will receive avalanche forecasts for %s again.", label)
+	case ReplyDigest:
+		if err := s.subRepo.SetDigest(sub.ID, !cmd.Off); err != nil {
+			return nil, fmt.Errorf("failed to update subscription: %w", err)
+		}
+		sub.Digest = !cmd.Off
+		if sub.Digest {
+			subject = fmt.Sprintf("%s avalanche forecasts switched to daily digest", label)
+			text = fmt.Sprintf("You will receive at most one forecast email a day for %s. Reply DIGEST OFF to get every update.", label)
+		} else {
+			subject = fmt.Sprintf("%s avalanche forecasts switched to every update", label)
+			text = fmt.Sprintf("You will receive an email for every forecast update for %s.", label)
+		}
+	default:
+		return nil, ErrUnknownReplyCommand
+	}
+
+	log.Printf("[SubscriptionService] applied reply command %s to subscription %d", cmd.Action, sub.ID)
+	s.sendConfirmation(ctx, sub.Email, subject, text)
+	return sub, nil
+}
+
+// sendConfirmation emails a short plain confirmation of a subscription change.
+func (s *SubscriptionService) sendConfirmation(ctx context.Context, to, subject, text string) {
+	ms, ok := s.emailer.(notifier.MessageSender)
+	if !ok || to == "" {
+		log.Printf("[SubscriptionService] cannot send confirmation %q to %q", subject, to)
+		return
+	}
+	msg := notifier.Message{To: to, Subject: subject, Text: text, HTML: "<p>" + html.EscapeString(text) + "</p>"}
+	if err := ms.SendMessage(ctx, msg); err != nil {
+		log.Printf("[SubscriptionService] failed to send confirmation to %s: %v", to, err)
+	}
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type capturingEmailer struct {
+	notifier.LogEmailSender
+	messages []notifier.Message
+}
+
+func (c *capturingEmailer) SendMessage(ctx context.Context, msg notifier.Message) error {
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func TestParseReplyCommand(t *testing.T) {
+	tests := []struct {
+		body, subject string
+		want          services.ReplyCommand
+		wantErr       bool
+	}{
+		{body: "stop\n\nOn Mon, Dec 1, 2025 at 7:00 AM Avy <a@b> wrote:\n> forecast", want: services.ReplyCommand{Action: services.ReplyStop}},
+		{body: "Pause 3", want: services.ReplyCommand{Action: services.ReplyPause, Days: 3}},
+		{body: "PAUSE", want: services.ReplyCommand{Action: services.ReplyPause, Days: 7}},
+		{body: "pause 1000", want: services.ReplyCommand{Action: services.ReplyPause, Days: 365}},
+		{body: "digest off", want: services.ReplyCommand{Action: services.ReplyDigest, Off: true}},
+		{body: "> quoted only", subject: "Re: RESUME", want: services.ReplyCommand{Action: services.ReplyResume}},
+		{body: "pause soon", wantErr: true},
+		{body: "thanks for the forecast!", subject: "Re: Snoqualmie Pass forecast", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := services.ParseReplyCommand(tt.body, tt.subject)
+		if tt.wantErr {
+			if !errors.Is(err, services.ErrUnknownReplyCommand) {
+				t.Errorf("ParseReplyCommand(%q) error = %v, want ErrUnknownReplyCommand", tt.body, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseReplyCommand(%q) = %+v, %v; want %+v", tt.body, got, err, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionService_ApplyReplyCommand(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.AvalancheCenter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	subRepo := db.NewSubscriptionRepository(gdb)
+	emailer := &capturingEmailer{}
+	svc := services.NewSubscriptionService(subRepo, db.NewCenterRepository(gdb),
+		services.NewForecast(&mockForecastClient{}), emailer)
+
+	sub := &models.Subscription{Email: "skier@example.com", ZoneID: "NWAC_10", Channel: models.ChannelEmail}
+	if err := subRepo.Create(sub); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	ctx := context.Background()
+
+	got, err := svc.ApplyReplyCommand(ctx, sub.ID, services.ReplyCommand{Action: services.ReplyPause, Days: 3})
+	if err != nil || !got.IsPaused() || got.PausedUntil == nil {
+		t.Fatalf("pause: %+v, %v", got, err)
+	}
+	stored, _ := subRepo.GetByID(sub.ID)
+	if !stored.IsPaused() || stored.PauseReason != models.PauseReasonRequested {
+		t.Fatalf("expected stored subscription paused, got %+v", stored)
+	}
+
+	if _, err := svc.ApplyReplyCommand(ctx, sub.ID, services.ReplyCommand{Action: services.ReplyDigest}); err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	if _, err := svc.ApplyReplyCommand(ctx, sub.ID, services.ReplyCommand{Action: services.ReplyResume}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	stored, _ = subRepo.GetByID(sub.ID)
+	if stored.IsPaused() || !stored.Digest {
+		t.Fatalf("expected active digest subscription, got %+v", stored)
+	}
+
+	if _, err := svc.ApplyReplyCommand(ctx, sub.ID, services.ReplyCommand{Action: services.ReplyStop}); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if stored, _ = subRepo.GetByID(sub.ID); stored != nil {
+		t.Fatalf("expected subscription deleted, got %+v", stored)
+	}
+	if _, err := svc.ApplyReplyCommand(ctx, sub.ID, services.ReplyCommand{Action: services.ReplyResume}); !errors.Is(err, services.ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+
+	if len(emailer.messages) != 4 {
+		t.Fatalf("expected a confirmation per command, got %d", len(emailer.messages))
+	}
+	if !strings.Contains(emailer.messages[0].Subject, "paused") || emailer.messages[3].To != "skier@example.com" {
+		t.Fatalf("unexpected confirmations: %+v", emailer.messages)
+	}
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+)
+
+// ErrInvalidElevationBands is returned for band elevations that are not
+// increasing.
+var ErrInvalidElevationBands = errors.New("near treeline must start below above treeline")
+
+// ProductClient fetches a single forecast product with its avalanche problems.
+type ProductClient interface {
+	FetchProduct(productID int) (*models.Forecast, error)
+}
+
+// bandUnknown marks segments whose elevation band cannot be told, because the
+// track has no elevations or the zone has no band elevations configured.
+// They are assessed at the zone's most dangerous band.
+const bandUnknown = "unknown"
+
+// RouteProblem is an avalanche problem a segment passes through.
+type RouteProblem struct {
+	Name       string `json:"name"`
+	Likelihood string `json:"likelihood,omitempty"`
+}
+
+// RouteSegment is a run of consecutive track points in the same zone and
+// elevation band. Points outside every forecast zone form segments without a
+// zone.
+type RouteSegment struct {
+	ZoneID        string             `json:"zone_id,omitempty"`
+	ZoneName      string             `json:"zone_name,omitempty"`
+	Band          string             `json:"band,omitempty"`
+	BandName      string             `json:"band_name,omitempty"`
+	DangerLevel   domain.DangerLevel `json:"danger_level"`
+	DangerName    string             `json:"danger_name"`
+	Problems      []RouteProblem     `json:"problems,omitempty"`
+	FromPoint     int                `json:"from_point"`
+	ToPoint       int                `json:"to_point"`
+	DistanceKm    float64            `json:"distance_km"`
+	MinElevationM *float64           `json:"min_elevation_m,omitempty"`
+	MaxElevationM *float64           `json:"max_elevation_m,omitempty"`
+	ForecastURL   string             `json:"forecast_url,omitempty"`
+}
+
+// RouteSummary is the worst case over a whole route.
+type RouteSummary struct {
+	MaxDanger     domain.DangerLevel `json:"max_danger"`
+	MaxDangerName string             `json:"max_danger_name"`
+	WorstSegment  int                `json:"worst_segment"`
+	DistanceKm    float64            `json:"distance_km"`
+	// UnratedKm is the distance outside forecast zones or in zones without
+	// a danger rating for the day.
+	UnratedKm float64  `json:"unrated_km"`
+	Zones     []string `json:"zones"`
+	Problems  []string `json:"problems"`
+}
+
+// RouteAssessment is the danger along a route on one day.
+type RouteAssessment struct {
+	Date     string         `json:"date"`
+	Summary  RouteSummary   `json:"summary"`
+	Segments []RouteSegment `json:"segments"`
+}
+
+// RouteService assesses the forecast danger along GPS tracks.
+type RouteService struct {
+	forecasts *ForecastService
+	products  ProductClient
+	centers   *db.CenterRepository
+	shapes    *geo.ShapeCache
+	bands     *db.ElevationBandRepository
+}
+
+func NewRouteService(forecasts *ForecastService, products ProductClient, centers *db.CenterRepository, shapes *geo.ShapeCache, bands *db.ElevationBandRepository) *RouteService {
+	return &RouteService{forecasts: forecasts, products: products, centers: centers, shapes: shapes, bands: bands}
+}
+
+// AssessRoute splits points into segments by forecast zone and elevation
+// band and rates each with the zone's forecast for date. Zones are looked up
+// in centerIDs, or in every active center when it is empty.
+func (s *RouteService) AssessRoute(points []geo.TrackPoint, date time.Time, centerIDs []string) (*RouteAssessment, error) {
+	if len(points) == 0 {
+		return nil, geo.ErrNoTrack
+	}
+	if len(centerIDs) == 0 {
+		centers, err := s.centers.GetActiveCenters()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load centers: %w", err)
+		}
+		for _, c := range centers {
+			centerIDs = append(centerIDs, c.ID)
+		}
+	}
+	shapes, err := s.shapes.Shapes(centerIDs)
+	if err != nil {
+		return nil, err
+	}
+	index, err := geo.NewZoneIndex(shapes)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneOf := make([]string, len(points))
+	var zoneIDs, touchedCenters []string
+	seenZone, seenCenter := map[string]bool{}, map[string]bool{}
+	for i, p := range points {
+		zone := index.Locate(p.Lon, p.Lat)
+		zoneOf[i] = zone
+		if zone == "" || seenZone[zone] {
+			continue
+		}
+		seenZone[zone] = true
+		zoneIDs = append(zoneIDs, zone)
+		if center, _, _ := strings.Cut(zone, "_"); !seenCenter[center] {
+			seenCenter[center] = true
+			touchedCenters = append(touchedCenters, center)
+		}
+	}
+
+	forecasts := make(map[string]models.ZoneForecast)
+	if len(touchedCenters) > 0 {
+		list, err := s.forecasts.GetForecastsForCenters(touchedCenters, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch forecasts: %w", err)
+		}
+		for _, f := range list {
+			forecasts[f.ZoneID] = f
+		}
+	}
+	bands, err := s.bands.ForZones(zoneIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load elevation bands: %w", err)
+	}
+
+	a := &RouteAssessment{Date: date.Format(time.DateOnly)}
+	problems := make(map[int][]models.AvalancheProblem)
+	var seg *RouteSegment
+	for i, p := range points {
+		zone := zoneOf[i]
+		band := ""
+		if zone != "" {
+			band = pointBand(p, bands[zone])
+		}
+		if seg == nil || seg.ZoneID != zone || seg.Band != band {
+			a.Segments = append(a.Segments, s.newSegment(zone, band, forecasts[zone], problems))
+			seg = &a.Segments[len(a.Segments)-1]
+			seg.FromPoint = i
+		}
+		seg.ToPoint = i
+		if i > 0 {
+			seg.DistanceKm += geo.Distance(points[i-1], p) / 1000
+		}
+		if p.HasEle {
+			if seg.MinElevationM == nil {
+				lo, hi := p.Ele, p.Ele
+				seg.MinElevationM, seg.MaxElevationM = &lo, &hi
+			}
+			*seg.MinElevationM = min(*seg.MinElevationM, p.Ele)
+			*seg.MaxElevationM = max(*seg.MaxElevationM, p.Ele)
+		}
+	}
+	a.Summary = summarizeRoute(a.Segments)
+	return a, nil
+}
+
+// ElevationBands lists the configured band elevations of every zone.
+func (s *RouteService) ElevationBands() ([]models.ZoneElevationBands, error) {
+	return s.bands.List()
+}
+
+// SetElevationBands records the elevations, in meters, where a zone's near
+// treeline and above treeline bands begin.
+func (s *RouteService) SetElevationBands(zoneID *domain.ZoneID, nearTreelineM, aboveTreelineM float64) (*models.ZoneElevationBands, error) {
+	if !zoneID.IsSpecificZone() {
+		return nil, fmt.Errorf("%s is a center, not a zone", zoneID)
+	}
+	if nearTreelineM >= aboveTreelineM {
+		return nil, ErrInvalidElevationBands
+	}
+	b := &models.ZoneElevationBands{ZoneID: zoneID.String(), NearTreelineM: nearTreelineM, AboveTreelineM: aboveTreelineM}
+	if err := s.bands.Upsert(b); err != nil {
+		return nil, fmt.Errorf("failed to save elevation bands: %w", err)
+	}
+	log.Printf("[RouteService] %s bands now begin at %.0f m and %.0f m", b.ZoneID, nearTreelineM, aboveTreelineM)
+	return b, nil
+}
+
+// newSegment starts a segment rated with the zone's forecast for band.
+func (s *RouteService) newSegment(zone, band string, f models.ZoneForecast, problems map[int][]models.AvalancheProblem) RouteSegment {
+	seg := RouteSegment{ZoneID: zone, Band: band}
+	if zone == "" {
+		seg.DangerName = "Outside forecast zones"
+		return seg
+	}
+	seg.ZoneName, seg.ForecastURL = f.ZoneName, f.URL
+	seg.BandName = "Unknown elevation band"
+	for _, b := range exportBands {
+		if b.band == band {
+			seg.BandName = b.name
+		}
+	}
+	if d := f.TodayDanger; d != nil {
+		switch band {
+		case "upper":
+			seg.DangerLevel = d.Upper
+		case "middle":
+			seg.DangerLevel = d.Middle
+		case "lower":
+			seg.DangerLevel = d.Lower
+		default:
+			seg.DangerLevel = d.Max()
+		}
+	}
+	seg.DangerName = seg.DangerLevel.Name()
+
+	if f.ProductID == 0 {
+		return seg
+	}
+	list, ok := problems[f.ProductID]
+	if !ok {
+		product, err := s.products.FetchProduct(f.ProductID)
+		if err != nil {
+			// Danger ratings are still useful without the problems.
+			log.Printf("[RouteService] no avalanche problems for product %d: %v", f.ProductID, err)
+		} else {
+			list = product.Problems
+		}
+		problems[f.ProductID] = list
+	}
+	for _, p := range list {
+		if problemInBand(p, band) {
+			seg.Problems = append(seg.Problems, RouteProblem{Name: p.Name, Likelihood: p.Likelihood})
+		}
+	}
+	return seg
+}
+
+// pointBand places a point in its zone's elevation bands.
+func pointBand(p geo.TrackPoint, b models.ZoneElevationBands) string {
+	if !p.HasEle || b.ZoneID == "" {
+		return bandUnknown
+	}
+	switch {
+	case p.Ele >= b.AboveTreelineM:
+		return "upper"
+	case p.Ele >= b.NearTreelineM:
+		return "middle"
+	default:
+		return "lower"
+	}
+}
+
+// problemInBand reports whether a problem occurs in band on any aspect.
+// Locations are "<aspect> <band>" pairs such as "northeast upper".
+func problemInBand(p models.AvalancheProblem, band string) bool {
+	if band == bandUnknown {
+		return true
+	}
+	for _, loc := range p.Location {
+		fields := strings.Fields(loc)
+		if len(fields) > 0 && strings.EqualFold(fields[len(fields)-1], band) {
+			return true
+		}
+	}
+	return false
+}
+
+func summarizeRoute(segments []RouteSegment) RouteSummary {
+	sum := RouteSummary{WorstSegment: -1, Zones: []string{}, Problems: []string{}}
+	seenZone, seenProblem := map[string]bool{}, map[string]bool{}
+	for i, seg := range segments {
+		sum.DistanceKm += seg.DistanceKm
+		if !seg.DangerLevel.IsRated() {
+			sum.UnratedKm += seg.DistanceKm
+		}
+		if seg.DangerLevel > sum.MaxDanger || sum.WorstSegment < 0 {
+			sum.MaxDanger, sum.WorstSegment = seg.DangerLevel, i
+		}
+		if seg.ZoneID != "" && !seenZone[seg.ZoneID] {
+			seenZone[seg.ZoneID] = true
+			sum.Zones = append(sum.Zones, seg.ZoneID)
+		}
+		for _, p := range seg.Problems {
+			if !seenProblem[p.Name] {
+				seenProblem[p.Name] = true
+				sum.Problems = append(sum.Problems, p.Name)
+			}
+		}
+	}
+	sum.MaxDangerName = sum.MaxDanger.Name()
+	sum.DistanceKm = roundKm(sum.DistanceKm)
+	sum.UnratedKm = roundKm(sum.UnratedKm)
+	for i := range segments {
+		segments[i].DistanceKm = roundKm(segments[i].DistanceKm)
+	}
+	return sum
+}
+
+func roundKm(km float64) float64 {
+	return math.Round(km*100) / 100
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/geo"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// routeShapes has two side-by-side zones: NWAC_10 west of -121 and NWAC_11
+// east of it.
+type routeShapes struct{}
+
+func (routeShapes) FetchZoneShapes(centerID string) (map[string]*geo.Geometry, error) {
+	return map[string]*geo.Geometry{
+		"NWAC_10": {Type: "Polygon", Coordinates: json.RawMessage(`[[[-122,47],[-121,47],[-121,48],[-122,48],[-122,47]]]`)},
+		"NWAC_11": {Type: "Polygon", Coordinates: json.RawMessage(`[[[-121,47],[-120,47],[-120,48],[-121,48],[-121,47]]]`)},
+	}, nil
+}
+
+type stubProducts struct{ calls int }
+
+func (s *stubProducts) FetchProduct(productID int) (*models.Forecast, error) {
+	s.calls++
+	if productID != 201 {
+		return nil, errors.New("not found")
+	}
+	return &models.Forecast{ID: 201, Problems: []models.AvalancheProblem{
+		{Name: "Wind Slab", Likelihood: "likely", Location: []string{"north upper", "northeast upper"}},
+		{Name: "Persistent Slab", Likelihood: "possible", Location: []string{"north middle", "north upper"}},
+	}}, nil
+}
+
+func TestRouteService_AssessRoute(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.AvalancheCenter{}, &models.ZoneElevationBands{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := gdb.Create(&models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center", Active: true}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	date := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	center := models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center"}
+	client := &mockForecastClient{data: map[string][]models.Forecast{"NWAC": {
+		{ID: 201, Status: "published", AvalancheCenter: center, PublishedTime: date,
+			StartDate: date, EndDate: date.Add(24 * time.Hour),
+			ForecastZone: []models.Zone{{ZoneID: "10", Name: "Snoqualmie Pass"}},
+			Danger:       []models.DangerRating{{ValidDay: "current", Upper: 4, Middle: 3, Lower: 2}}},
+		{ID: 202, Status: "published", AvalancheCenter: center, PublishedTime: date,
+			StartDate: date, EndDate: date.Add(24 * time.Hour),
+			ForecastZone: []models.Zone{{ZoneID: "11", Name: "Stevens Pass"}},
+			Danger:       []models.DangerRating{{ValidDay: "current", Upper: 2, Middle: 2, Lower: 1}}},
+	}}}
+	products := &stubProducts{}
+	svc := services.NewRouteService(services.NewForecast(client), products, db.NewCenterRepository(gdb),
+		geo.NewShapeCache(routeShapes{}, 0), db.NewElevationBandRepository(gdb))
+
+	zone10, _ := domain.ParseZoneID("NWAC_10")
+	if _, err := svc.SetElevationBands(zone10, 1700, 1200); !errors.Is(err, services.ErrInvalidElevationBands) {
+		t.Errorf("expected ErrInvalidElevationBands, got %v", err)
+	}
+	if _, err := svc.SetElevationBands(zone10, 1200, 1700); err != nil {
+		t.Fatalf("set bands: %v", err)
+	}
+
+	points := []geo.TrackPoint{
+		{Lon: -121.5, Lat: 47.5, Ele: 900, HasEle: true},  // NWAC_10 lower
+		{Lon: -121.49, Lat: 47.5, Ele: 950, HasEle: true}, // NWAC_10 lower
+		{Lon: -121.48, Lat: 47.5, Ele: 1400, HasEle: true},
+		{Lon: -121.47, Lat: 47.5, Ele: 1800, HasEle: true},
+		{Lon: -120.5, Lat: 47.5, Ele: 1800, HasEle: true}, // NWAC_11, no bands configured
+		{Lon: -119.5, Lat: 47.5},                          // outside every zone
+	}
+	a, err := svc.AssessRoute(points, date, nil)
+	if err != nil {
+		t.Fatalf("assess: %v", err)
+	}
+	if a.Date != "2025-02-01" || len(a.Segments) != 5 {
+		t.Fatalf("expected 5 segments, got %+v", a.Segments)
+	}
+
+	want := []struct {
+		zone, band string
+		level      domain.DangerLevel
+		problems   int
+		from, to   int
+	}{
+		{"NWAC_10", "lower", 2, 0, 0, 1},
+		{"NWAC_10", "middle", 3, 1, 2, 2},
+		{"NWAC_10", "upper", 4, 2, 3, 3},
+		{"NWAC_11", "unknown", 2, 0, 4, 4},
+		{"", "", 0, 0, 5, 5},
+	}
+	for i, w := range want {
+		s := a.Segments[i]
+		if s.ZoneID != w.zone || s.Band != w.band || s.DangerLevel != w.level || len(s.Problems) != w.problems ||
+			s.FromPoint != w.from || s.ToPoint != w.to {
+			t.Errorf("segment %d = %+v, want %+v", i, s, w)
+		}
+	}
+	if s := a.Segments[0]; s.MinElevationM == nil || *s.MinElevationM != 900 || *s.MaxElevationM != 950 || s.DistanceKm <= 0 {
+		t.Errorf("unexpected elevation or distance in %+v", s)
+	}
+
+	sum := a.Summary
+	if sum.MaxDanger != 4 || sum.MaxDangerName != "High" || sum.WorstSegment != 2 {
+		t.Errorf("unexpected worst case %+v", sum)
+	}
+	if len(sum.Zones) != 2 || len(sum.Problems) != 2 || sum.Problems[0] != "Persistent Slab" {
+		t.Errorf("unexpected zones or problems %+v", sum)
+	}
+	if sum.UnratedKm <= 0 || sum.UnratedKm >= sum.DistanceKm {
+		t.Errorf("expected the last hop to count as unrated, got %+v", sum)
+	}
+	if products.calls != 2 {
+		t.Errorf("expected one product fetch per product, got %d", products.calls)
+	}
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// SlackActionSubscribe is the action ID of the "Subscribe this channel" button;
+// its value is the zone ID.
+const SlackActionSubscribe = "avy_subscribe"
+
+// ErrUnknownSlackAction is returned for interactions the service did not create.
+var ErrUnknownSlackAction = errors.New("unknown Slack action")
+
+const slackUsage = "*Avalanche forecasts*\n" +
+	"`/avy NWAC_10` today's forecast for a zone, `/avy NWAC_10 tomorrow` for tomorrow\n" +
+	"`/avy NWAC` the center's zones\n" +
+	"`/avy subscribe NWAC_10` post new forecasts for the zone to this channel\n" +
+	"`/avy unsubscribe NWAC_10` stop posting them"
+
+// SlackCommand is a parsed /avy slash command invocation.
+type SlackCommand struct {
+	Text      string
+	ChannelID string
+	UserID    string
+}
+
+// SlackAction is a button press from a message the service posted.
+type SlackAction struct {
+	ActionID  string
+	Value     string
+	ChannelID string
+	UserID    string
+}
+
+// SlackCommandService answers the /avy slash command and its buttons. Channel
+// subscriptions are delivered by bot, so they need a SlackBotClient; lookups
+// work without one.
+type SlackCommandService struct {
+	forecast *ForecastService
+	centers  *db.CenterRepository
+	subs     *SubscriptionService
+	subRepo  *db.SubscriptionRepository
+	bot      *notifier.SlackBotClient
+	zones    *ZoneResolver
+	now      func() time.Time
+}
+
+// NewSlackCommandService creates a command service. bot may be nil.
+func NewSlackCommandService(
+	forecast *ForecastService,
+	centers *db.CenterRepository,
+	subs *SubscriptionService,
+	subRepo *db.SubscriptionRepository,
+	bot *notifier.SlackBotClient,
+) *SlackCommandService {
+	return &SlackCommandService{
+		forecast: forecast,
+		centers:  centers,
+		subs:     subs,
+		subRepo:  subRepo,
+		bot:      bot,
+		now:      time.Now,
+	}
+}
+
+// SetZoneResolver lets commands name zones by name, slug or alias
+// ("/avy Mt Hood tomorrow") rather than only by zone code.
+func (s *SlackCommandService) SetZoneResolver(zones *ZoneResolver) {
+	s.zones = zones
+}
+
+// HandleCommand returns the Slack message answering cmd. Forecasts are posted
+// to the channel; usage and subscription replies are shown only to the caller.
+func (s *SlackCommandService) HandleCommand(ctx context.Context, cmd SlackCommand) (map[string]any, error) {
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 || strings.EqualFold(args[0], "help") {
+		return slackEphemeral(slackUsage), nil
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "subscribe", "unsubscribe":
+		if len(args) < 2 {
+			return slackEphemeral(fmt.Sprintf("Usage: `/avy %s NWAC_10`", strings.ToLower(args[0]))), nil
+		}
+		var reply string
+		var err error
+		arg := strings.Join(args[1:], " ")
+		if strings.EqualFold(args[0], "subscribe") {
+			reply, err = s.subscribe(ctx, cmd.ChannelID, arg)
+		} else {
+			reply, err = s.unsubscribe(ctx, cmd.ChannelID, arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return slackEphemeral(reply), nil
+	}
+
+	tomorrow := len(args) > 1 && isTomorrow(args[len(args)-1])
+	if tomorrow {
+		args = args[:len(args)-1]
+	}
+	return s.lookup(ctx, strings.Join(args, " "), tomorrow)
+}
+
+// HandleAction handles a button press and shows the outcome to the user who
+// pressed it.
+func (s *SlackCommandService) HandleAction(ctx context.Context, action SlackAction) error {
+	if action.ActionID != SlackActionSubscribe {
+		return ErrUnknownSlackAction
+	}
+	reply, err := s.subscribe(ctx, action.ChannelID, action.Value)
+	if err != nil {
+		return err
+	}
+	if s.bot == nil {
+		log.Printf("[SlackCommandService] %s", reply)
+		return nil
+	}
+	return s.bot.PostEphemeral(ctx, action.ChannelID, action.UserID, reply)
+}
+
+func (s *SlackCommandService) lookup(ctx context.Context, arg string, tomorrow bool) (map[string]any, error) {
+	zoneID, unknown, err := s.zone(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	if zoneID == nil {
+		if unknown == "" {
+			unknown = fmt.Sprintf("`%s` isn't a zone or center code. Try `/avy help`.", arg)
+		}
+		return slackEphemeral(unknown), nil
+	}
+	center, unknown, err := s.center(zoneID)
+	if err != nil {
+		return nil, err
+	}
+	if center == nil {
+		return slackEphemeral(unknown), nil
+	}
+
+	forecasts, err := s.forecast.GetForecastsForCenters([]string{center.ID}, s.now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch forecasts for %s: %w", center.ID, err)
+	}
+	if len(forecasts) == 0 {
+		return slackEphemeral(fmt.Sprintf("No current forecasts from %s.", center.Name)), nil
+	}
+
+	if zoneID.IsCenterLevel() {
+		var button func(string) map[string]any
+		if s.bot != nil {
+			button = subscribeButton
+		}
+		return slackInChannel(notifier.SlackZoneListReply(center.Name, forecasts, tomorrow, button)), nil
+	}
+	for i := range forecasts {
+		if !strings.EqualFold(forecasts[i].ZoneID, zoneID.String()) {
+			continue
+		}
+		n := notifier.Notification{
+			ZoneID:     forecasts[i].ZoneID,
+			ZoneName:   forecasts[i].ZoneName,
+			CenterID:   center.ID,
+			CenterName: center.Name,
+			CenterLink: center.URL,
+			Forecast:   &forecasts[i],
+		}
+		if issued, err := time.Parse(time.RFC3339, forecasts[i].IssuedTime); err == nil {
+			n.IssuedAt = issued
+		}
+		var buttons []map[string]any
+		if s.bot != nil {
+			buttons = append(buttons, subscribeButton(n.ZoneID))
+		}
+		return slackInChannel(notifier.SlackForecastReply(n, tomorrow, buttons...)), nil
+	}
+	return slackEphemeral(fmt.Sprintf("Unknown zone `%s`. Try `/avy %s` for its zones.", zoneID, center.ID)), nil
+}
+
+func (s *SlackCommandService) subscribe(ctx context.Context, channelID, arg string) (string, error) {
+	if s.bot == nil {
+		return "Channel subscriptions are not enabled for this workspace.", nil
+	}
+	zoneID, unknown, err := s.zone(ctx, arg)
+	if err != nil {
+		return "", err
+	}
+	if unknown != "" {
+		return unknown, nil
+	}
+	if zoneID == nil || zoneID.IsCenterLevel() {
+		return fmt.Sprintf("`%s` isn't a zone code. Try `/avy NWAC` to list a center's zones.", arg), nil
+	}
+	center, unknown, err := s.center(zoneID)
+	if err != nil {
+		return "", err
+	}
+	if center == nil {
+		return unknown, nil
+	}
+
+	existing, err := s.subRepo.GetByTarget(channelID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load channel subscriptions: %w", err)
+	}
+	for _, sub := range existing {
+		if sub.Channel == models.ChannelSlackApp && sub.ZoneID == zoneID.String() {
+			return fmt.Sprintf("This channel is already subscribed to %s.", zoneID), nil
+		}
+	}
+
+	if _, err := s.subs.Create(ctx, CreateSubscriptionRequest{
+		ZoneID:       zoneID,
+		Channel:      models.ChannelSlackApp,
+		SlackChannel: channelID,
+	}); err != nil {
+		return "", err
+	}
+	log.Printf("[SlackCommandService] subscribed channel %s to %s", channelID, zoneID)
+	return fmt.Sprintf("Subscribed this channel to %s. New forecasts will be posted here; `/avy unsubscribe %s` stops them.", zoneID, zoneID), nil
+}
+
+func (s *SlackCommandService) unsubscribe(ctx context.Context, channelID, arg string) (string, error) {
+	zoneID, err := domain.ParseZoneID(arg)
+	if s.zones != nil {
+		// Channels may follow IDs no longer in the catalog, so anything
+		// the resolver does not know is taken as given.
+		if resolved, resolveErr := s.zones.Resolve(ctx, arg); resolveErr == nil {
+			zoneID, err = resolved, nil
+		}
+	}
+	if err != nil {
+		return fmt.Sprintf("`%s` isn't a zone code.", arg), nil
+	}
+	if err := s.subRepo.DeleteByTarget(channelID, zoneID.String()); err != nil {
+		return "", fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return fmt.Sprintf("This channel will no longer receive forecasts for %s.", zoneID), nil
+}
+
+// zone resolves a zone or center argument. When it names none, zoneID is nil
+// and unknown, if set, is the reply listing the closest zones.
+func (s *SlackCommandService) zone(ctx context.Context, arg string) (zoneID *domain.ZoneID, unknown string, err error) {
+	if s.zones == nil {
+		if words := strings.Fields(arg); len(words) > 0 {
+			zoneID, _ = domain.ParseZoneID(words[0])
+		}
+		return zoneID, "", nil
+	}
+	zoneID, err = s.zones.Resolve(ctx, arg)
+	var unresolved *UnresolvedZoneError
+	switch {
+	case errors.As(err, &unresolved):
+		if len(unresolved.Suggestions) == 0 {
+			return nil, "", nil
+		}
+		codes := make([]string, len(unresolved.Suggestions))
+		for i, z := range unresolved.Suggestions {
+			codes[i] = fmt.Sprintf("`%s` (%s)", z.ZoneID, z.Name)
+		}
+		return nil, fmt.Sprintf("Unknown zone `%s`. Did you mean %s?", arg, strings.Join(codes, ", ")), nil
+	case err != nil:
+		return nil, "", fmt.Errorf("failed to resolve zone %q: %w", arg, err)
+	}
+	return zoneID, "", nil
+}
+
+// center looks zoneID's center up in the catalog of active centers. When it
+// is unknown, the returned message lists the centers that are available.
+func (s *SlackCommandService) center(zoneID *domain.ZoneID) (*models.AvalancheCenter, string, error) {
+	centers, err := s.centers.GetActiveCenters()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load centers: %w", err)
+	}
+	ids := make([]string, 0, len(centers))
+	for i := range centers {
+		if strings.EqualFold(centers[i].ID, zoneID.Center()) {
+			return &centers[i], "", nil
+		}
+		ids = append(ids, "`"+centers[i].ID+"`")
+	}
+	sort.Strings(ids)
+	return nil, fmt.Sprintf("Unknown center `%s`. Available centers: %s.", zoneID.Center(), strings.Join(ids, ", ")), nil
+}
+
+func subscribeButton(zoneID string) map[string]any {
+	return map[string]any{
+		"type":      "button",
+		"text":      map[string]any{"type": "plain_text", "text": "Subscribe this channel"},
+		"action_id": SlackActionSubscribe,
+		"value":     zoneID,
+	}
+}
+
+func isTomorrow(word string) bool {
+	switch strings.ToLower(word) {
+	case "tomorrow", "tmrw", "tmr":
+		return true
+	}
+	return false
+}
+
+func slackEphemeral(text string) map[string]any {
+	return map[string]any{"response_type": "ephemeral", "text": text}
+}
+
+func slackInChannel(msg map[string]any) map[string]any {
+	msg["response_type"] = "in_channel"
+	return msg
+}
@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeSlackAPI records Web API calls by method name.
+type fakeSlackAPI struct {
+	mu    sync.Mutex
+	calls map[string][]string
+}
+
+func (f *fakeSlackAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.calls[strings.TrimPrefix(r.URL.Path, "/")] = append(f.calls[strings.TrimPrefix(r.URL.Path, "/")], string(body))
+	f.mu.Unlock()
+	_, _ = w.Write([]byte(`{"ok":true}`))
+}
+
+func (f *fakeSlackAPI) get(method string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func TestSlackCommandService_LookupAndSubscribe(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.AvalancheCenter{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := gdb.Create(&models.AvalancheCenter{ID: "NWAC", Name: "Northwest Avalanche Center", URL: "https://nwac.us", Active: true}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	now := time.Now().UTC()
+	forecast := services.NewForecast(&mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": {{
+			PublishedTime:   time.Date(2025, 12, 1, 14, 0, 0, 0, time.UTC),
+			StartDate:       now.Add(-2 * time.Hour),
+			EndDate:         now.Add(24 * time.Hour),
+			AvalancheCenter: models.AvalancheCenter{ID: "NWAC", Name: "NWAC"},
+			ForecastZone:    []models.Zone{{ZoneID: "10", Name: "Snoqualmie Pass"}},
+			Danger: []models.DangerRating{
+				{ValidDay: "current", Upper: 3, Middle: 3, Lower: 2},
+				{ValidDay: "tomorrow", Upper: 2, Middle: 2, Lower: 1},
+			},
+			BottomLine: "<p>Avoid wind-loaded slopes.</p>",
+			Status:     "published",
+		}},
+	}})
+
+	api := &fakeSlackAPI{calls: map[string][]string{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	bot, err := notifier.NewSlackBotClient(server.URL, "xoxb-test")
+	if err != nil {
+		t.Fatalf("bot: %v", err)
+	}
+
+	subRepo := db.NewSubscriptionRepository(gdb)
+	centers := db.NewCenterRepository(gdb)
+	subs := services.NewSubscriptionService(subRepo, centers, forecast, notifier.LogEmailSender{})
+	subs.RegisterChannel(models.ChannelSlackApp, bot)
+	svc := services.NewSlackCommandService(forecast, centers, subs, subRepo, bot)
+	ctx := context.Background()
+
+	reply, err := svc.HandleCommand(ctx, services.SlackCommand{Text: "NWAC_10 tomorrow", ChannelID: "C1"})
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	raw, _ := json.Marshal(reply)
+	if reply["response_type"] != "in_channel" || !strings.Contains(string(raw), "Snoqualmie Pass (tomorrow)") ||
+		!strings.Contains(string(raw), services.SlackActionSubscribe) || !strings.Contains(string(raw), "2 - Moderate") ||
+		!strings.Contains(string(raw), "issued Mon Dec 1 14:00 UTC") {
+		t.Fatalf("unexpected forecast reply: %s", raw)
+	}
+
+	reply, _ = svc.HandleCommand(ctx, services.SlackCommand{Text: "CAIC_1", ChannelID: "C1"})
+	if reply["response_type"] != "ephemeral" || !strings.Contains(reply["text"].(string), "`NWAC`") {
+		t.Fatalf("expected unknown center to list centers, got %v", reply)
+	}
+
+	action := services.SlackAction{ActionID: services.SlackActionSubscribe, Value: "NWAC_10", ChannelID: "C1", UserID: "U1"}
+	for range 2 {
+		if err := svc.HandleAction(ctx, action); err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+	}
+	got, _ := subRepo.GetByTarget("C1")
+	if len(got) != 1 || got[0].Channel != models.ChannelSlackApp || got[0].ZoneID != "NWAC_10" {
+		t.Fatalf("expected one channel subscription, got %+v", got)
+	}
+	ephemeral := api.get("chat.postEphemeral")
+	if len(ephemeral) != 2 || !strings.Contains(ephemeral[1], "already subscribed") {
+		t.Fatalf("unexpected ephemeral replies: %v", ephemeral)
+	}
+
+	if _, err := svc.HandleCommand(ctx, services.SlackCommand{Text: "unsubscribe NWAC_10", ChannelID: "C1"}); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if got, _ = subRepo.GetByTarget("C1"); len(got) != 0 {
+		t.Fatalf("expected subscription removed, got %+v", got)
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// Inbound SMS errors. Callers should not reply to senders rejected with these.
+var (
+	ErrSenderNotAllowed  = errors.New("sender is not on the allowlist")
+	ErrSenderRateLimited = errors.New("sender exceeded the query rate limit")
+)
+
+const (
+	smsHelpReply = "Avalanche forecasts: text a zone code (e.g. NWAC_10) for today, add TMRW for tomorrow. " +
+		"Text a center (NWAC) for its zone codes. STOP ends alerts."
+	smsStopReply    = "You are unsubscribed from avalanche alerts and will receive no further messages."
+	smsUnknownReply = "Sorry, that isn't a zone code. Text HELP for usage."
+)
+
+// SMSQueryConfig controls who may query forecasts by text and how often.
+type SMSQueryConfig struct {
+	// Allowlist restricts queries to these E.164 numbers when non-empty.
+	Allowlist []string
+	// AllowVerified also admits any number verified for SMS subscriptions.
+	AllowVerified bool
+	// RateLimit is the number of queries each sender may make per RateWindow;
+	// zero disables the limit.
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// SMSQueryConfigFromEnv reads SMS_QUERY_ALLOWLIST (comma-separated numbers,
+// optionally including "verified") and SMS_QUERY_RATE_LIMIT ("count/unit"
+// with unit m, h or d; default 20/h).
+func SMSQueryConfigFromEnv() (SMSQueryConfig, error) {
+	cfg := SMSQueryConfig{RateLimit: 20, RateWindow: time.Hour}
+	for _, entry := range strings.Split(os.Getenv("SMS_QUERY_ALLOWLIST"), ",") {
+		switch entry = strings.TrimSpace(entry); {
+		case entry == "":
+		case strings.EqualFold(entry, "verified"):
+			cfg.AllowVerified = true
+		default:
+			cfg.Allowlist = append(cfg.Allowlist, entry)
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("SMS_QUERY_RATE_LIMIT")); raw != "" {
+		countStr, unit, _ := strings.Cut(raw, "/")
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 0 {
+			return SMSQueryConfig{}, fmt.Errorf("invalid SMS_QUERY_RATE_LIMIT %q", raw)
+		}
+		switch unit {
+		case "m":
+			cfg.RateWindow = time.Minute
+		case "", "h":
+			cfg.RateWindow = time.Hour
+		case "d":
+			cfg.RateWindow = 24 * time.Hour
+		default:
+			return SMSQueryConfig{}, fmt.Errorf("invalid SMS_QUERY_RATE_LIMIT unit %q", unit)
+		}
+		cfg.RateLimit = count
+	}
+	return cfg, nil
+}
+
+// SMSQueryService answers forecast queries texted from phones and satellite
+// messengers, e.g. "NWAC_10", "NWAC_10 TMRW", "HELP" and "STOP".
+type SMSQueryService struct {
+	forecast *ForecastService
+	subRepo  *db.SubscriptionRepository
+	phones   *db.PhoneVerificationRepository
+	sender   notifier.SMSSender
+	cfg      SMSQueryConfig
+	allowed  map[string]struct{}
+	limiter  *senderLimiter
+	zones    *ZoneResolver
+	now      func() time.Time
+}
+
+// NewSMSQueryService creates a query service replying through sender.
+func NewSMSQueryService(
+	forecast *ForecastService,
+	subRepo *db.SubscriptionRepository,
+	phones *db.PhoneVerificationRepository,
+	sender notifier.SMSSender,
+	cfg SMSQueryConfig,
+) *SMSQueryService {
+	allowed := make(map[string]struct{}, len(cfg.Allowlist))
+	for _, n := range cfg.Allowlist {
+		if p, err := domain.NewPhoneNumber(n); err == nil {
+			allowed[p.String()] = struct{}{}
+		}
+	}
+	return &SMSQueryService{
+		forecast: forecast,
+		subRepo:  subRepo,
+		phones:   phones,
+		sender:   sender,
+		cfg:      cfg,
+		allowed:  allowed,
+		limiter:  newSenderLimiter(cfg.RateLimit, cfg.RateWindow),
+		now:      time.Now,
+	}
+}
+
+// SetZoneResolver lets senders name zones by name or alias ("Mt Hood TMRW")
+// rather than only by zone code.
+func (s *SMSQueryService) SetZoneResolver(zones *ZoneResolver) {
+	s.zones = zones
+}
+
+// HandleInbound answers one inbound message and texts the reply back to from.
+// It returns the reply that was sent.
+func (s *SMSQueryService) HandleInbound(ctx context.Context, from *domain.PhoneNumber, text string) (string, error) {
+	command := strings.Fields(strings.ToUpper(text))
+
+	// STOP is always honored, regardless of allowlist or rate limit.
+	if len(command) > 0 && isStopWord(command[0]) {
+		paused, err := s.subRepo.PauseByTarget(from.String(), models.PauseReasonStopped, s.now().UTC())
+		if err != nil {
+			return "", fmt.Errorf("failed to pause subscriptions: %w", err)
+		}
+		log.Printf("[SMSQueryService] %s texted STOP, paused %d subscriptions", from, paused)
+		return smsStopReply, s.reply(ctx, from, smsStopReply)
+	}
+
+	if err := s.authorize(from.String()); err != nil {
+		return "", err
+	}
+	if !s.limiter.allow(from.String(), s.now()) {
+		return "", ErrSenderRateLimited
+	}
+
+	reply, err := s.answer(ctx, strings.Fields(text))
+	if err != nil {
+		return "", err
+	}
+	return reply, s.reply(ctx, from, reply)
+}
+
+func (s *SMSQueryService) authorize(from string) error {
+	if len(s.allowed) == 0 && !s.cfg.AllowVerified {
+		return nil
+	}
+	if _, ok := s.allowed[from]; ok {
+		return nil
+	}
+	if s.cfg.AllowVerified && s.phones != nil {
+		v, err := s.phones.Get(from)
+		if err != nil {
+			return fmt.Errorf("failed to load phone verification: %w", err)
+		}
+		if v.IsVerified() {
+			return nil
+		}
+	}
+	return ErrSenderNotAllowed
+}
+
+func (s *SMSQueryService) answer(ctx context.Context, words []string) (string, error) {
+	if len(words) == 0 || strings.EqualFold(words[0], "HELP") || strings.EqualFold(words[0], "INFO") {
+		return smsHelpReply, nil
+	}
+	tomorrow := false
+	if last := strings.ToUpper(words[len(words)-1]); len(words) > 1 && (last == "TMRW" || last == "TOMORROW") {
+		tomorrow, words = true, words[:len(words)-1]
+	}
+	zoneID, unknown, err := s.zone(ctx, words)
+	if err != nil || zoneID == nil {
+		return unknown, err
+	}
+
+	forecasts, err := s.forecast.GetForecastsForCenters([]string{zoneID.Center()}, s.now().UTC())
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch forecasts for %s: %w", zoneID.Center(), err)
+	}
+	if len(forecasts) == 0 {
+		return fmt.Sprintf("No forecasts found for %s. Text HELP for usage.", zoneID.Center()), nil
+	}
+	if zoneID.IsCenterLevel() {
+		return centerZoneList(zoneID.Center(), forecasts), nil
+	}
+	for i := range forecasts {
+		if strings.EqualFold(forecasts[i].ZoneID, zoneID.String()) {
+			return notifier.FormatSMSDay(&forecasts[i], tomorrow), nil
+		}
+	}
+	return fmt.Sprintf("Unknown zone %s. Text %s for its zone codes.", zoneID, zoneID.Center()), nil
+}
+
+// zone resolves the words naming a zone. When they name none, it returns the
+// reply to send instead, listing the closest zones that fit in one segment.
+func (s *SMSQueryService) zone(ctx context.Context, words []string) (*domain.ZoneID, string, error) {
+	if s.zones == nil {
+		zoneID, err := domain.ParseZoneID(words[0])
+		if err != nil {
+			return nil, smsUnknownReply, nil
+		}
+		return zoneID, "", nil
+	}
+	query := strings.Join(words, " ")
+	zoneID, err := s.zones.Resolve(ctx, query)
+	var unresolved *UnresolvedZoneError
+	switch {
+	case errors.As(err, &unresolved):
+		if len(unresolved.Suggestions) == 0 {
+			return nil, smsUnknownReply, nil
+		}
+		out := "Unknown zone " + query + ". Did you mean:"
+		for _, z := range unresolved.Suggestions {
+			entry := fmt.Sprintf(" %s %s,", z.ZoneID, z.Name)
+			if len(out)+len(entry) > notifier.SMSSegmentLength {
+				break
+			}
+			out += entry
+		}
+		return nil, strings.TrimSuffix(out, ",") + "?", nil
+	case err != nil:
+		return nil, "", fmt.Errorf("failed to resolve zone %q: %w", query, err)
+	}
+	return zoneID, "", nil
+}
+
+func (s *SMSQueryService) reply(ctx context.Context, to *domain.PhoneNumber, body string) error {
+	if err := s.sender.SendSMS(ctx, to.String(), body); err != nil {
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+	return nil
+}
+
+// centerZoneList lists a center's zone codes, as many as fit in one segment.
+func centerZoneList(center string, forecasts []models.ZoneForecast) string {
+	out := center + " zones:"
+	for _, f := range forecasts {
+		code := strings.TrimPrefix(f.ZoneID, center+"_")
+		entry := fmt.Sprintf(" %s %s,", code, f.ZoneName)
+		if len(out)+len(entry) > notifier.SMSSegmentLength {
+			break
+		}
+		out += entry
+	}
+	return strings.TrimSuffix(out, ",")
+}
+
+// isStopWord reports whether word is one of the standard carrier opt-out keywords.
+func isStopWord(word string) bool {
+	switch word {
+	case "STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT":
+		return true
+	}
+	return false
+}
+
+// senderLimiter allows limit queries per sender in each fixed window.
+type senderLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]senderWindow
+}
+
+type senderWindow struct {
+	start time.Time
+	count int
+}
+
+func newSenderLimiter(limit int, window time.Duration) *senderLimiter {
+	if window <= 0 {
+		window = time.Hour
+	}
+	return &senderLimiter{limit: limit, window: window, windows: map[string]senderWindow{}}
+}
+
+func (l *senderLimiter) allow(sender string, now time.Time) bool {
+	if l.limit <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w := l.windows[sender]
+	if now.Sub(w.start) >= l.window {
+		w = senderWindow{start: now}
+	}
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	l.windows[sender] = w
+	if len(l.windows) > 10000 {
+		for k, v := range l.windows {
+			if now.Sub(v.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+	}
+	return true
+}
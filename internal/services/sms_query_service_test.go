@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSMSQueryService_AnswersCommands(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.PhoneVerification{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	subRepo := db.NewSubscriptionRepository(gdb)
+	phone, _ := domain.NewPhoneNumber("+12065550100")
+	if err := subRepo.Create(&models.Subscription{ZoneID: "NWAC_10", Channel: models.ChannelSMS, Target: phone.String()}); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	now := time.Now().UTC()
+	client := &mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": {{
+			PublishedTime:   now,
+			StartDate:       now.Add(-2 * time.Hour),
+			EndDate:         now.Add(24 * time.Hour),
+			AvalancheCenter: models.AvalancheCenter{ID: "NWAC", Name: "NWAC"},
+			ForecastZone:    []models.Zone{{ZoneID: "10", Name: "Snoqualmie Pass"}},
+			Danger: []models.DangerRating{
+				{ValidDay: "current", Upper: 3, Middle: 3, Lower: 2},
+				{ValidDay: "tomorrow", Upper: 2, Middle: 2, Lower: 1},
+			},
+			BottomLine: "<p>Avoid wind-loaded slopes.</p>",
+			Status:     "published",
+		}},
+	}}
+	sms := &capturingSMS{}
+	svc := services.NewSMSQueryService(services.NewForecast(client), subRepo, db.NewPhoneVerificationRepository(gdb), sms,
+		services.SMSQueryConfig{RateLimit: 3, RateWindow: time.Hour})
+	ctx := context.Background()
+
+	cases := map[string]string{
+		"nwac_10":      "Snoqualmie Pass Today: U3 M3 L2 Considerable. Avoid wind-loaded slopes.",
+		"NWAC_10 tmrw": "Snoqualmie Pass Tmrw: U2 M2 L1 Moderate. Avoid wind-loaded slopes.",
+		"NWAC":         "NWAC zones: 10 Snoqualmie Pass",
+	}
+	for in, want := range cases {
+		got, err := svc.HandleInbound(ctx, phone, in)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %q (%v), want %q", in, got, err, want)
+		}
+	}
+	if _, err := svc.HandleInbound(ctx, phone, "HELP"); !errors.Is(err, services.ErrSenderRateLimited) {
+		t.Fatalf("expected rate limit, got %v", err)
+	}
+
+	// STOP bypasses the rate limit and pauses SMS alerts.
+	got, err := svc.HandleInbound(ctx, phone, "stop")
+	if err != nil || !strings.Contains(got, "unsubscribed") {
+		t.Fatalf("stop: %q (%v)", got, err)
+	}
+	subs, _ := subRepo.GetByTarget(phone.String())
+	if len(subs) != 1 || subs[0].PauseReason != models.PauseReasonStopped {
+		t.Fatalf("expected subscription paused, got %+v", subs)
+	}
+	if len(sms.messages) != 4 {
+		t.Fatalf("expected 4 replies, got %d", len(sms.messages))
+	}
+}
+
+func TestSMSQueryService_Allowlist(t *testing.T) {
+	svc := services.NewSMSQueryService(services.NewForecast(&mockForecastClient{}), nil, nil, &capturingSMS{},
+		services.SMSQueryConfig{Allowlist: []string{"+1 206 555 0100"}})
+	allowed, _ := domain.NewPhoneNumber("+12065550100")
+	other, _ := domain.NewPhoneNumber("+12065550199")
+
+	if _, err := svc.HandleInbound(context.Background(), other, "HELP"); !errors.Is(err, services.ErrSenderNotAllowed) {
+		t.Fatalf("expected sender rejected, got %v", err)
+	}
+	if _, err := svc.HandleInbound(context.Background(), allowed, "HELP"); err != nil {
+		t.Fatalf("expected allowlisted sender, got %v", err)
+	}
+}
+
+func TestSMSQueryService_ResolvesZoneNames(t *testing.T) {
+	resolver, _ := newZoneResolver(t)
+	now := time.Now().UTC()
+	client := &mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": {{
+			PublishedTime: now, StartDate: now.Add(-2 * time.Hour), EndDate: now.Add(24 * time.Hour), Status: "published",
+			AvalancheCenter: models.AvalancheCenter{ID: "NWAC", Name: "NWAC"},
+			ForecastZone:    []models.Zone{{ZoneID: "11", Name: "Mt Hood"}},
+			Danger:          []models.DangerRating{{ValidDay: "tomorrow", Upper: 4, Middle: 3, Lower: 2}},
+			BottomLine:      "Storm slabs.",
+		}},
+	}}
+	svc := services.NewSMSQueryService(services.NewForecast(client), nil, nil, &capturingSMS{}, services.SMSQueryConfig{})
+	svc.SetZoneResolver(resolver)
+	phone, _ := domain.NewPhoneNumber("+12065550100")
+
+	got, err := svc.HandleInbound(context.Background(), phone, "nwac mount hood tmrw")
+	if err != nil || got != "Mt Hood Tmrw: U4 M3 L2 High. Storm slabs." {
+		t.Errorf("unexpected reply %q (%v)", got, err)
+	}
+	got, err = svc.HandleInbound(context.Background(), phone, "mt hood")
+	if err != nil || got != "Unknown zone mt hood. Did you mean: IPAC_3 Mt Hood, NWAC_11 Mt Hood?" {
+		t.Errorf("unexpected reply %q (%v)", got, err)
+	}
+}
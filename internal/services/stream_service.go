@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+const (
+	defaultStreamPollInterval = 2 * time.Second
+	streamPageSize            = 500
+	// streamBuffer is how many events a slow client may fall behind before it
+	// is disconnected; it reconnects and catches up with Last-Event-ID.
+	streamBuffer = 64
+)
+
+// StreamFilter selects events by zone or center. An empty filter matches all.
+type StreamFilter struct {
+	zones   map[string]struct{}
+	centers map[string]struct{}
+}
+
+// NewStreamFilter parses comma-separated zone and center IDs, as given in the
+// zones and centers query parameters.
+func NewStreamFilter(zones, centers string) (StreamFilter, error) {
+	f := StreamFilter{zones: map[string]struct{}{}, centers: map[string]struct{}{}}
+	for _, raw := range splitList(zones) {
+		id, err := domain.ParseZoneID(raw)
+		if err != nil {
+			return StreamFilter{}, fmt.Errorf("invalid zone %q: %w", raw, err)
+		}
+		if id.IsCenterLevel() {
+			f.centers[id.Center()] = struct{}{}
+		} else {
+			f.zones[id.String()] = struct{}{}
+		}
+	}
+	for _, raw := range splitList(centers) {
+		id, err := domain.ParseZoneID(raw)
+		if err != nil || !id.IsCenterLevel() {
+			return StreamFilter{}, fmt.Errorf("invalid center %q", raw)
+		}
+		f.centers[id.Center()] = struct{}{}
+	}
+	return f, nil
+}
+
+// Matches reports whether ev is for a selected zone or center.
+func (f StreamFilter) Matches(ev models.ForecastEvent) bool {
+	if len(f.zones) == 0 && len(f.centers) == 0 {
+		return true
+	}
+	if _, ok := f.zones[ev.ZoneID]; ok {
+		return true
+	}
+	_, ok := f.centers[ev.CenterID]
+	return ok
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// StreamService fans out new entries of the forecast event log to connected
+// stream clients. A single poller reads the log so the number of clients does
+// not affect database load.
+type StreamService struct {
+	repo     *db.EventRepository
+	interval time.Duration
+
+	mu      sync.Mutex
+	lastSeq uint64
+	clients map[chan models.ForecastEvent]StreamFilter
+}
+
+// NewStreamService creates a stream reading repo every interval; zero uses
+// the default of two seconds.
+func NewStreamService(repo *db.EventRepository, interval time.Duration) *StreamService {
+	if interval <= 0 {
+		interval = defaultStreamPollInterval
+	}
+	return &StreamService{repo: repo, interval: interval, clients: map[chan models.ForecastEvent]StreamFilter{}}
+}
+
+// Run polls the event log until ctx is done. Events already in the log when
+// it starts are only available through Replay, so nothing is broadcast until
+// the log's position has been read; a failed read is retried every interval.
+func (s *StreamService) Run(ctx context.Context) {
+	positioned := s.position()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !positioned {
+				positioned = s.position()
+				continue
+			}
+			if err := s.poll(); err != nil {
+				log.Printf("[StreamService] failed to read event log: %v", err)
+			}
+		}
+	}
+}
+
+// position starts the stream at the end of the event log and reports whether
+// it could be read.
+func (s *StreamService) position() bool {
+	seq, err := s.repo.LatestSeq()
+	if err != nil {
+		log.Printf("[StreamService] failed to read event log position: %v", err)
+		return false
+	}
+	s.mu.Lock()
+	s.lastSeq = seq
+	s.mu.Unlock()
+	return true
+}
+
+func (s *StreamService) poll() error {
+	for {
+		s.mu.Lock()
+		after := s.lastSeq
+		s.mu.Unlock()
+
+		events, err := s.repo.ListAfter(after, streamPageSize)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		for _, ev := range events {
+			s.broadcast(ev)
+			s.lastSeq = ev.Seq
+		}
+		s.mu.Unlock()
+		if len(events) < streamPageSize {
+			return nil
+		}
+	}
+}
+
+// broadcast sends ev to matching clients. s.mu must be held.
+func (s *StreamService) broadcast(ev models.ForecastEvent) {
+	for ch, filter := range s.clients {
+		if !filter.Matches(ev) {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+			log.Printf("[StreamService] dropping slow stream client")
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a client for live events. The channel is closed when
+// the client falls too far behind; cancel must be called when it disconnects.
+func (s *StreamService) Subscribe(filter StreamFilter) (<-chan models.ForecastEvent, func()) {
+	ch := make(chan models.ForecastEvent, streamBuffer)
+	s.mu.Lock()
+	s.clients[ch] = filter
+	s.mu.Unlock()
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.clients[ch]; ok {
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Replay calls send, oldest first, for each logged event after seq that
+// matches filter. It returns the sequence number it read up to.
+func (s *StreamService) Replay(ctx context.Context, after uint64, filter StreamFilter, send func(models.ForecastEvent) error) (uint64, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return after, err
+		}
+		events, err := s.repo.ListAfter(after, streamPageSize)
+		if err != nil {
+			return after, fmt.Errorf("failed to read event log: %w", err)
+		}
+		for _, ev := range events {
+			if filter.Matches(ev) {
+				if err := send(ev); err != nil {
+					return after, err
+				}
+			}
+			after = ev.Seq
+		}
+		if len(events) < streamPageSize {
+			return after, nil
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestStreamService_RetriesLogPosition(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// The stream polls from another goroutine; keep it on the same in-memory database.
+	sqlDB, _ := gdb.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	// Without the events table the stream cannot read its starting position.
+	stream := services.NewStreamService(db.NewEventRepository(gdb), 10*time.Millisecond)
+	live, cancelClient := stream.Subscribe(services.StreamFilter{})
+	defer cancelClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+	time.Sleep(30 * time.Millisecond)
+
+	logEvent := func(tx *gorm.DB, id string) {
+		ev := models.ForecastEvent{EventID: id, Type: models.EventForecastIssued, ZoneID: "NWAC_10", CenterID: "NWAC", Data: "{}"}
+		if err := tx.Create(&ev).Error; err != nil {
+			t.Fatalf("log event: %v", err)
+		}
+	}
+	err = gdb.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&models.ForecastEvent{}); err != nil {
+			return err
+		}
+		logEvent(tx, "evt_1")
+		logEvent(tx, "evt_2")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	logEvent(gdb, "evt_3")
+
+	select {
+	case ev := <-live:
+		if ev.EventID != "evt_3" {
+			t.Errorf("expected only the new event, got %s", ev.EventID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no live event")
+	}
+}
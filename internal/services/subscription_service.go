@@ -18,6 +18,11 @@ type SubscriptionService struct {
 	centerRepo *db.CenterRepository
 	forecast   *ForecastService
 	emailer    notifier.EmailSender
+	channels   map[string]notifier.Channel
+	sms        notifier.SMSSender
+	phones     *db.PhoneVerificationRepository
+	replies    *notifier.ReplyAddresser
+	groups     *db.ZoneGroupRepository
 }
 
 // NewSubscriptionService creates a new subscription service with all required dependencies.
@@ -32,31 +37,122 @@ func NewSubscriptionService(
 		centerRepo: centerRepo,
 		forecast:   forecast,
 		emailer:    emailer,
+		channels:   map[string]notifier.Channel{},
 	}
 }
 
+// RegisterChannel enables welcome messages for subscriptions on a non-email channel.
+func (s *SubscriptionService) RegisterChannel(name string, ch notifier.Channel) {
+	s.channels[name] = ch
+}
+
+// SetReplyAddresser gives welcome emails a signed reply-to address so
+// subscribers can manage their subscription by replying.
+func (s *SubscriptionService) SetReplyAddresser(a *notifier.ReplyAddresser) {
+	s.replies = a
+}
+
+// SetZoneGroups enables subscriptions to zone groups.
+func (s *SubscriptionService) SetZoneGroups(groups *db.ZoneGroupRepository) {
+	s.groups = groups
+}
+
+// CreateSubscriptionRequest describes a new subscription. Email is required
+// for the email channel, Phone for SMS, SlackChannel for the Slack app, Push
+// for Web Push and WebhookURL for chat channels.
 type CreateSubscriptionRequest struct {
-	Email  *domain.Email
-	ZoneID *domain.ZoneID
+	Email        *domain.Email
+	ZoneID       *domain.ZoneID
+	Channel      string
+	WebhookURL   *domain.WebhookURL
+	Phone        *domain.PhoneNumber
+	SlackChannel string
+	Push         *domain.PushSubscription
 }
 
-// Create creates a new subscription and sends a welcome email asynchronously.
+// Create creates a new subscription and sends a welcome message asynchronously.
 // It returns the created subscription or an error if the operation fails.
 func (s *SubscriptionService) Create(ctx context.Context, req CreateSubscriptionRequest) (*models.Subscription, error) {
+	if req.ZoneID.IsGroup() {
+		if err := s.checkZoneGroup(req.ZoneID); err != nil {
+			return nil, err
+		}
+	}
 	sub := &models.Subscription{
-		Email:  req.Email.String(),
-		ZoneID: req.ZoneID.String(),
+		ZoneID:  req.ZoneID.String(),
+		Channel: req.Channel,
+	}
+	if sub.Channel == "" {
+		sub.Channel = models.ChannelEmail
+	}
+	switch {
+	case sub.Channel == models.ChannelEmail && req.Email != nil:
+		sub.Email = req.Email.String()
+	case sub.Channel == models.ChannelSMS && req.Phone != nil:
+		if s.phones == nil {
+			return nil, fmt.Errorf("sms subscriptions are not enabled")
+		}
+		sub.Target = req.Phone.String()
+		verified, err := s.phoneVerified(sub.Target)
+		if err != nil {
+			return nil, err
+		}
+		if !verified {
+			now := time.Now().UTC()
+			sub.PausedAt, sub.PauseReason = &now, models.PauseReasonUnverified
+		}
+	case sub.Channel == models.ChannelSlackApp && req.SlackChannel != "":
+		sub.Target = req.SlackChannel
+	case sub.Channel == models.ChannelWebPush && req.Push != nil:
+		sub.Target = req.Push.Endpoint.String()
+		sub.PushKeys = &models.PushKeys{P256DH: req.Push.P256DH, Auth: req.Push.Auth}
+		// Browsers re-send their subscription on every page load.
+		existing, err := s.subRepo.GetByTarget(sub.Target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load push subscriptions: %w", err)
+		}
+		for i := range existing {
+			if existing[i].ZoneID == sub.ZoneID {
+				return &existing[i], nil
+			}
+		}
+	case sub.Channel != models.ChannelEmail && sub.Channel != models.ChannelSMS && req.WebhookURL != nil:
+		sub.Target = req.WebhookURL.String()
+	default:
+		return nil, fmt.Errorf("missing target for %s subscription", sub.Channel)
 	}
 
 	if err := s.subRepo.Create(sub); err != nil {
 		return nil, fmt.Errorf("failed to create subscription: %w", err)
 	}
 
+	if sub.PauseReason == models.PauseReasonUnverified {
+		if err := s.sendVerificationCode(ctx, sub.Target); err != nil {
+			log.Printf("[SubscriptionService] %v", err)
+		}
+		return sub, nil
+	}
+
 	go s.sendWelcomeEmail(context.Background(), sub, req.ZoneID)
 
 	return sub, nil
 }
 
+// checkZoneGroup verifies that a subscribed zone group exists.
+func (s *SubscriptionService) checkZoneGroup(zoneID *domain.ZoneID) error {
+	if s.groups == nil {
+		return ErrZoneGroupNotFound
+	}
+	g, err := s.groups.Get(zoneID.Group())
+	if err != nil {
+		return fmt.Errorf("failed to load zone group: %w", err)
+	}
+	if g == nil {
+		return ErrZoneGroupNotFound
+	}
+	return nil
+}
+
 // Delete removes a subscription for the given email and zone ID.
 func (s *SubscriptionService) Delete(ctx context.Context, email *domain.Email, zoneID *domain.ZoneID) error {
 	if err := s.subRepo.Delete(email.String(), zoneID.String()); err != nil {
@@ -65,6 +161,37 @@ func (s *SubscriptionService) Delete(ctx context.Context, email *domain.Email, z
 	return nil
 }
 
+// DeletePush removes a browser's push subscription for zoneID, or for every
+// zone when zoneID is nil.
+func (s *SubscriptionService) DeletePush(ctx context.Context, endpoint *domain.WebhookURL, zoneID *domain.ZoneID) error {
+	var err error
+	if zoneID == nil {
+		err = s.subRepo.DeleteAllByTarget(endpoint.String())
+	} else {
+		err = s.subRepo.DeleteByTarget(endpoint.String(), zoneID.String())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteByTarget removes a non-email subscription for the given webhook URL and zone ID.
+func (s *SubscriptionService) DeleteByTarget(ctx context.Context, target *domain.WebhookURL, zoneID *domain.ZoneID) error {
+	if err := s.subRepo.DeleteByTarget(target.String(), zoneID.String()); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteByPhone removes an SMS subscription for the given phone number and zone ID.
+func (s *SubscriptionService) DeleteByPhone(ctx context.Context, phone *domain.PhoneNumber, zoneID *domain.ZoneID) error {
+	if err := s.subRepo.DeleteByTarget(phone.String(), zoneID.String()); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
+
 // GetByEmail retrieves all subscriptions for a given email address.
 func (s *SubscriptionService) GetByEmail(ctx context.Context, email *domain.Email) ([]models.Subscription, error) {
 	subs, err := s.subRepo.GetByEmail(email.String())
@@ -85,6 +212,12 @@ func (s *SubscriptionService) GetByZone(ctx context.Context, zoneID *domain.Zone
 
 // sendWelcomeEmail sends a welcome email with the latest forecast to a new subscriber.
 func (s *SubscriptionService) sendWelcomeEmail(ctx context.Context, sub *models.Subscription, zoneID *domain.ZoneID) {
+	if zoneID.IsGroup() {
+		// Group members can span centers; the first forecast email follows
+		// the next issued forecast instead.
+		log.Printf("[SubscriptionService] skipping welcome for zone group subscription %s", zoneID)
+		return
+	}
 	centerID := zoneID.Center()
 
 	forecasts, err := s.forecast.GetForecastsForCenters([]string{centerID}, time.Now().UTC())
@@ -108,13 +241,57 @@ func (s *SubscriptionService) sendWelcomeEmail(ctx context.Context, sub *models.
 		}
 	}
 
-	if zoneID.IsCenterLevel() {
+	switch {
+	case sub.ChannelOrDefault() != models.ChannelEmail:
+		s.sendZoneWelcomeChannel(ctx, sub, forecasts, center, zoneID)
+	case zoneID.IsCenterLevel():
 		s.sendCenterWelcomeEmail(ctx, sub, forecasts, center)
-	} else {
+	default:
 		s.sendZoneWelcomeEmail(ctx, sub, forecasts, center, zoneID)
 	}
 }
 
+// sendZoneWelcomeChannel posts the current forecast to a non-email channel so
+// subscribers can confirm their webhook works.
+func (s *SubscriptionService) sendZoneWelcomeChannel(
+	ctx context.Context,
+	sub *models.Subscription,
+	forecasts []models.ZoneForecast,
+	center *models.AvalancheCenter,
+	zoneID *domain.ZoneID,
+) {
+	ch, ok := s.channels[sub.Channel]
+	if !ok {
+		log.Printf("[SubscriptionService] no sender registered for channel %s", sub.Channel)
+		return
+	}
+	if zoneID.IsCenterLevel() {
+		log.Printf("[SubscriptionService] skipping %s welcome for center-level subscription %s", sub.Channel, zoneID)
+		return
+	}
+	for i := range forecasts {
+		if forecasts[i].ZoneID != zoneID.String() {
+			continue
+		}
+		n := notifier.Notification{
+			ZoneID:     forecasts[i].ZoneID,
+			ZoneName:   forecasts[i].ZoneName,
+			CenterID:   center.ID,
+			CenterName: center.Name,
+			CenterLink: center.URL,
+			IssuedAt:   time.Now().UTC(),
+			Forecast:   &forecasts[i],
+		}
+		if err := ch.Notify(ctx, *sub, n); err != nil {
+			log.Printf("[SubscriptionService] failed to send %s welcome (zone=%s): %v", sub.Channel, zoneID, err)
+		} else {
+			log.Printf("[SubscriptionService] sent %s welcome (zone=%s)", sub.Channel, zoneID)
+		}
+		return
+	}
+	log.Printf("[SubscriptionService] no forecast found for zone %s in %s welcome", zoneID, sub.Channel)
+}
+
 // sendZoneWelcomeEmail sends a welcome email for a specific zone subscription.
 func (s *SubscriptionService) sendZoneWelcomeEmail(
 	ctx context.Context,
@@ -139,6 +316,10 @@ func (s *SubscriptionService) sendZoneWelcomeEmail(
 				Today:      forecast.TodayDanger,
 				Tomorrow:   forecast.FutureDanger,
 				CenterLink: center.URL,
+				BottomLine: forecast.SafeBottomLine(),
+			}
+			if s.replies != nil {
+				emailData.ReplyTo = s.replies.Address(sub.ID)
 			}
 
 			if err := s.emailer.SendForecastEmail(ctx, sub.Email, emailData); err != nil {
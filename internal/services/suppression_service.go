@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+// SuppressionService maintains the email suppression list and keeps
+// subscriptions for suppressed addresses paused.
+type SuppressionService struct {
+	suppressions *db.SuppressionRepository
+	subRepo      *db.SubscriptionRepository
+}
+
+// NewSuppressionService creates a suppression service backed by the given repositories.
+func NewSuppressionService(suppressions *db.SuppressionRepository, subRepo *db.SubscriptionRepository) *SuppressionService {
+	return &SuppressionService{suppressions: suppressions, subRepo: subRepo}
+}
+
+// IngestSendGridEvents suppresses addresses named in bounce, dropped, spam
+// report and unsubscribe events and pauses their subscriptions. Other event
+// types are ignored. It returns the number of addresses suppressed.
+func (s *SuppressionService) IngestSendGridEvents(ctx context.Context, events []notifier.SendGridEvent) (int, error) {
+	suppressed := 0
+	for _, ev := range events {
+		reason, ok := ev.SuppressionReason()
+		email := strings.ToLower(strings.TrimSpace(ev.Email))
+		if !ok || email == "" {
+			continue
+		}
+		if err := s.Suppress(ctx, email, reason, ev.Reason, "sendgrid"); err != nil {
+			return suppressed, err
+		}
+		suppressed++
+	}
+	return suppressed, nil
+}
+
+// Suppress adds email to the suppression list and pauses its subscriptions.
+func (s *SuppressionService) Suppress(ctx context.Context, email, reason, detail, source string) error {
+	if err := s.suppressions.Upsert(&models.EmailSuppression{
+		Email:  email,
+		Reason: reason,
+		Detail: detail,
+		Source: source,
+	}); err != nil {
+		return fmt.Errorf("failed to store suppression: %w", err)
+	}
+	paused, err := s.subRepo.PauseByEmail(email, models.PauseReasonSuppressed, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to pause subscriptions: %w", err)
+	}
+	log.Printf("[SuppressionService] suppressed %s (%s), paused %d subscriptions", email, reason, paused)
+	return nil
+}
+
+// List returns every suppressed address, most recently updated first.
+func (s *SuppressionService) List(ctx context.Context) ([]models.EmailSuppression, error) {
+	out, err := s.suppressions.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppressions: %w", err)
+	}
+	return out, nil
+}
+
+// Clear removes email from the suppression list and resumes the subscriptions
+// that were paused because of it. It reports whether a suppression existed.
+func (s *SuppressionService) Clear(ctx context.Context, email string) (bool, error) {
+	found, err := s.suppressions.Delete(email)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete suppression: %w", err)
+	}
+	if !found {
+		return false, nil
+	}
+	resumed, err := s.subRepo.ResumeByEmail(email, models.PauseReasonSuppressed)
+	if err != nil {
+		return true, fmt.Errorf("failed to resume subscriptions: %w", err)
+	}
+	log.Printf("[SuppressionService] cleared suppression for %s, resumed %d subscriptions", email, resumed)
+	return true, nil
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+)
+
+var (
+	// ErrInvalidTripPlan is returned when a trip plan is rejected.
+	ErrInvalidTripPlan = errors.New("invalid trip plan")
+	// ErrTripPlanNotFound is returned for tokens that do not verify or whose
+	// plan was cancelled or has expired.
+	ErrTripPlanNotFound = errors.New("trip plan not found")
+	// ErrTripPlansDisabled is returned when no share link secret is configured.
+	ErrTripPlansDisabled = errors.New("trip plans are not configured")
+)
+
+const (
+	maxTripZones        = 10
+	maxTripParticipants = 20
+	maxTripNameLength   = 100
+	// maxTripLeadDays bounds how far ahead a trip may be planned; forecasts
+	// only look a day or two out.
+	maxTripLeadDays = 60
+	// defaultTripTimezone schedules plans created without a timezone.
+	defaultTripTimezone = "America/Denver"
+)
+
+// TripPlanService manages trip plans, shared between partners by signed
+// links. Participants are emailed a confirmation link before they join the
+// countdown, which the notifier sends.
+type TripPlanService struct {
+	repo   *db.TripPlanRepository
+	links  *notifier.TripLinks
+	mailer notifier.MessageSender
+	now    func() time.Time
+}
+
+// NewTripPlanService creates a trip plan service sending confirmations
+// through mailer. links may be nil, which disables trip plans.
+func NewTripPlanService(repo *db.TripPlanRepository, links *notifier.TripLinks, mailer notifier.MessageSender) *TripPlanService {
+	return &TripPlanService{repo: repo, links: links, mailer: mailer, now: time.Now}
+}
+
+// CreateTripPlanRequest describes a new trip. Timezone is an IANA name and
+// may be empty.
+type CreateTripPlanRequest struct {
+	Name         string
+	ZoneIDs      []*domain.ZoneID
+	Date         string
+	Timezone     string
+	Participants []*domain.Email
+}
+
+// SharedTripPlan is a trip plan with its share link and the time of its next
+// countdown email. OrganizerToken, which cancels the plan, is only returned
+// when the plan is created.
+type SharedTripPlan struct {
+	*models.TripPlan
+	ShareURL           string     `json:"share_url"`
+	OrganizerToken     string     `json:"organizer_token,omitempty"`
+	NextNotificationAt *time.Time `json:"next_notification_at,omitempty"`
+}
+
+// Create stores a plan and returns it with its share link and organizer
+// token. Participants are pending until they follow the emailed confirmation
+// link.
+func (s *TripPlanService) Create(ctx context.Context, req CreateTripPlanRequest) (*SharedTripPlan, error) {
+	if s.links == nil {
+		return nil, ErrTripPlansDisabled
+	}
+	plan := &models.TripPlan{
+		Name:     strings.TrimSpace(req.Name),
+		TripDate: req.Date,
+		Timezone: req.Timezone,
+	}
+	if plan.Timezone == "" {
+		plan.Timezone = defaultTripTimezone
+	}
+	if len(plan.Name) > maxTripNameLength {
+		return nil, fmt.Errorf("%w: name is longer than %d characters", ErrInvalidTripPlan, maxTripNameLength)
+	}
+	if _, err := time.LoadLocation(plan.Timezone); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidTripPlan, plan.Timezone)
+	}
+	day, err := plan.Day()
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidTripPlan)
+	}
+	plan.ExpiresAt = day.AddDate(0, 0, 1)
+	now := s.now()
+	if !plan.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: the trip date has passed", ErrInvalidTripPlan)
+	}
+	if day.After(now.AddDate(0, 0, maxTripLeadDays)) {
+		return nil, fmt.Errorf("%w: trips can be planned at most %d days ahead", ErrInvalidTripPlan, maxTripLeadDays)
+	}
+
+	for _, z := range req.ZoneIDs {
+		if !z.IsSpecificZone() {
+			return nil, fmt.Errorf("%w: %s is a center, not a zone", ErrInvalidTripPlan, z)
+		}
+		if !slices.Contains(plan.ZoneIDs, z.String()) {
+			plan.ZoneIDs = append(plan.ZoneIDs, z.String())
+		}
+	}
+	if len(plan.ZoneIDs) == 0 || len(plan.ZoneIDs) > maxTripZones {
+		return nil, fmt.Errorf("%w: plans need 1 to %d zones", ErrInvalidTripPlan, maxTripZones)
+	}
+	plan.Participants = []string{}
+	for _, e := range req.Participants {
+		if !slices.Contains(plan.Pending, e.String()) {
+			plan.Pending = append(plan.Pending, e.String())
+		}
+	}
+	if len(plan.Pending) == 0 || len(plan.Pending) > maxTripParticipants {
+		return nil, fmt.Errorf("%w: plans need 1 to %d participants", ErrInvalidTripPlan, maxTripParticipants)
+	}
+
+	if err := s.repo.Create(plan); err != nil {
+		return nil, fmt.Errorf("failed to create trip plan: %w", err)
+	}
+	log.Printf("[TripPlanService] plan %d for %s in %s with %d invited participants", plan.ID, plan.TripDate, strings.Join(plan.ZoneIDs, ","), len(plan.Pending))
+	for _, email := range plan.Pending {
+		s.sendConfirmation(ctx, plan, email)
+	}
+	out := s.shared(plan)
+	out.OrganizerToken = s.links.OrganizerToken(plan.ID)
+	return out, nil
+}
+
+// Get returns the plan a share token was issued for.
+func (s *TripPlanService) Get(ctx context.Context, token string) (*SharedTripPlan, error) {
+	plan, err := s.load(s.links.PlanID, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.shared(plan), nil
+}
+
+// Join emails a confirmation link to a new participant of a shared plan.
+// Asking again resends it; joining a plan one is part of is a no-op.
+func (s *TripPlanService) Join(ctx context.Context, token string, email *domain.Email) (*SharedTripPlan, error) {
+	plan, err := s.load(s.links.PlanID, token)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(plan.Participants, email.String()) {
+		return s.shared(plan), nil
+	}
+	if !slices.Contains(plan.Pending, email.String()) {
+		if len(plan.Participants)+len(plan.Pending) >= maxTripParticipants {
+			return nil, fmt.Errorf("%w: plans have at most %d participants", ErrInvalidTripPlan, maxTripParticipants)
+		}
+		plan.Pending = append(plan.Pending, email.String())
+		if err := s.repo.SetParticipants(plan.ID, plan.Participants, plan.Pending); err != nil {
+			return nil, fmt.Errorf("failed to join trip plan: %w", err)
+		}
+	}
+	s.sendConfirmation(ctx, plan, email.String())
+	return s.shared(plan), nil
+}
+
+// Confirm adds the pending participant a confirmation token was emailed to.
+// Confirming twice is a no-op.
+func (s *TripPlanService) Confirm(ctx context.Context, token string) (*SharedTripPlan, error) {
+	plan, email, err := s.loadRecipient(s.links.ConfirmRecipient, token, func(p *models.TripPlan) []string {
+		return append(slices.Clone(p.Participants), p.Pending...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if i := slices.Index(plan.Pending, email); i >= 0 {
+		plan.Pending = slices.Delete(plan.Pending, i, i+1)
+		plan.Participants = append(plan.Participants, email)
+		if err := s.repo.SetParticipants(plan.ID, plan.Participants, plan.Pending); err != nil {
+			return nil, fmt.Errorf("failed to confirm trip plan: %w", err)
+		}
+	}
+	return s.shared(plan), nil
+}
+
+// Leave removes the participant a leave token was issued to from the plan.
+func (s *TripPlanService) Leave(ctx context.Context, token string) (*SharedTripPlan, error) {
+	plan, email, err := s.loadRecipient(s.links.LeaveRecipient, token, func(p *models.TripPlan) []string {
+		return p.Participants
+	})
+	if err != nil {
+		return nil, err
+	}
+	i := slices.Index(plan.Participants, email)
+	plan.Participants = slices.Delete(plan.Participants, i, i+1)
+	if err := s.repo.SetParticipants(plan.ID, plan.Participants, plan.Pending); err != nil {
+		return nil, fmt.Errorf("failed to leave trip plan: %w", err)
+	}
+	return s.shared(plan), nil
+}
+
+// Cancel deletes a plan given its organizer token, stopping its countdown.
+func (s *TripPlanService) Cancel(ctx context.Context, token string) error {
+	plan, err := s.load(s.links.OrganizerPlanID, token)
+	if err != nil {
+		return err
+	}
+	if _, err := s.repo.Delete(plan.ID); err != nil {
+		return fmt.Errorf("failed to cancel trip plan: %w", err)
+	}
+	return nil
+}
+
+// sendConfirmation emails a confirmation link for plan to email. Failures are
+// logged; asking to join again resends the link.
+func (s *TripPlanService) sendConfirmation(ctx context.Context, plan *models.TripPlan, email string) {
+	if s.mailer == nil {
+		log.Printf("[TripPlanService] cannot send trip plan %d confirmation to %s: no email sender", plan.ID, email)
+		return
+	}
+	trip := "a trip"
+	if plan.Name != "" {
+		trip = fmt.Sprintf("the trip %q", plan.Name)
+	}
+	text := fmt.Sprintf("You were invited to %s on %s. To receive its avalanche forecast emails, confirm at %s\n\nIf you did not expect this, ignore this email and you will not hear from us again.",
+		trip, plan.TripDate, s.links.ConfirmURL(plan.ID, email))
+	msg := notifier.Message{To: email, Subject: "Confirm your trip plan for " + plan.TripDate, Text: text,
+		HTML: "<p>" + strings.ReplaceAll(html.EscapeString(text), "\n\n", "</p><p>") + "</p>"}
+	if err := s.mailer.SendMessage(ctx, msg); err != nil {
+		log.Printf("[TripPlanService] failed to send trip plan %d confirmation to %s: %v", plan.ID, email, err)
+	}
+}
+
+// loadRecipient verifies a per-participant token and returns its plan and the
+// one of emails(plan) it was issued to.
+func (s *TripPlanService) loadRecipient(verify func(string) (notifier.TripRecipient, error), token string, emails func(*models.TripPlan) []string) (*models.TripPlan, string, error) {
+	if s.links == nil {
+		return nil, "", ErrTripPlansDisabled
+	}
+	recipient, err := verify(token)
+	if err != nil {
+		return nil, "", ErrTripPlanNotFound
+	}
+	plan, err := s.get(recipient.PlanID)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, e := range emails(plan) {
+		if recipient.Is(e) {
+			return plan, e, nil
+		}
+	}
+	return nil, "", ErrTripPlanNotFound
+}
+
+// load verifies a plan token and returns its unexpired plan.
+func (s *TripPlanService) load(verify func(string) (uint, error), token string) (*models.TripPlan, error) {
+	if s.links == nil {
+		return nil, ErrTripPlansDisabled
+	}
+	id, err := verify(token)
+	if err != nil {
+		return nil, ErrTripPlanNotFound
+	}
+	return s.get(id)
+}
+
+func (s *TripPlanService) get(id uint) (*models.TripPlan, error) {
+	plan, err := s.repo.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trip plan: %w", err)
+	}
+	// Expired plans linger until the notifier's next pass.
+	if plan == nil || !plan.ExpiresAt.After(s.now()) {
+		return nil, ErrTripPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *TripPlanService) shared(plan *models.TripPlan) *SharedTripPlan {
+	out := &SharedTripPlan{TripPlan: plan, ShareURL: s.links.URL(plan.ID)}
+	now := s.now()
+	for i := plan.StepsSent; i < len(models.TripSchedule); i++ {
+		if at, err := plan.StepTime(i); err == nil && at.After(now) {
+			out.NextNotificationAt = &at
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/notifier"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTripPlanService(t *testing.T, links *notifier.TripLinks, mailer notifier.MessageSender) *services.TripPlanService {
+	t.Helper()
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.TripPlan{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return services.NewTripPlanService(db.NewTripPlanRepository(gdb), links, mailer)
+}
+
+func tripRequest(t *testing.T, date string, emails ...string) services.CreateTripPlanRequest {
+	t.Helper()
+	zone, _ := domain.ParseZoneID("NWAC_10")
+	req := services.CreateTripPlanRequest{Name: "Saturday tour", ZoneIDs: []*domain.ZoneID{zone, zone}, Date: date, Timezone: "UTC"}
+	for _, e := range emails {
+		email, err := domain.NewEmail(e)
+		if err != nil {
+			t.Fatalf("email %q: %v", e, err)
+		}
+		req.Participants = append(req.Participants, email)
+	}
+	return req
+}
+
+// linkToken returns the token of a confirmation or leave link.
+func linkToken(link string) string {
+	return strings.Split(strings.TrimPrefix(link, "https://avy.example/api/trips/"), "/")[0]
+}
+
+func TestTripPlanService_ShareJoinLeaveCancel(t *testing.T) {
+	links := notifier.NewTripLinks("secret", "https://avy.example")
+	mailer := &capturingEmailer{}
+	svc := newTripPlanService(t, links, mailer)
+	ctx := context.Background()
+	date := time.Now().UTC().AddDate(0, 0, 5).Format(time.DateOnly)
+
+	plan, err := svc.Create(ctx, tripRequest(t, date, "Lead@Example.com", "lead@example.com"))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(plan.ZoneIDs) != 1 || len(plan.Participants) != 0 || len(plan.Pending) != 1 || plan.Pending[0] != "lead@example.com" {
+		t.Errorf("zones and participants should be deduplicated and pending: %+v", plan.TripPlan)
+	}
+	if plan.ShareURL != links.URL(plan.ID) || plan.OrganizerToken != links.OrganizerToken(plan.ID) {
+		t.Errorf("share URL = %q, organizer token = %q", plan.ShareURL, plan.OrganizerToken)
+	}
+	if day, _ := plan.Day(); !plan.ExpiresAt.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("plan expires at %s, want the end of the trip day", plan.ExpiresAt)
+	}
+	first, _ := plan.StepTime(0)
+	if plan.NextNotificationAt == nil || !plan.NextNotificationAt.Equal(first) {
+		t.Errorf("next notification = %v, want %v", plan.NextNotificationAt, first)
+	}
+	leadConfirm := links.ConfirmURL(plan.ID, "lead@example.com")
+	if len(mailer.messages) != 1 || mailer.messages[0].To != "lead@example.com" || !strings.Contains(mailer.messages[0].Text, leadConfirm) {
+		t.Fatalf("expected a confirmation email, got %+v", mailer.messages)
+	}
+	if got, err := svc.Confirm(ctx, linkToken(leadConfirm)); err != nil || len(got.Participants) != 1 || len(got.Pending) != 0 {
+		t.Fatalf("confirm: %+v, %v", got, err)
+	}
+
+	token := links.Token(plan.ID)
+	partner, _ := domain.NewEmail("partner@example.com")
+	joined, err := svc.Join(ctx, token, partner)
+	if err != nil || len(joined.Participants) != 1 || len(joined.Pending) != 1 {
+		t.Fatalf("join: %+v, %v", joined, err)
+	}
+	if _, err := svc.Join(ctx, token, partner); err != nil || len(mailer.messages) != 3 {
+		t.Fatalf("joining twice should resend the confirmation: %v, %d emails", err, len(mailer.messages))
+	}
+	if _, err := svc.Confirm(ctx, linkToken(links.ConfirmURL(plan.ID, "stranger@example.com"))); !errors.Is(err, services.ErrTripPlanNotFound) {
+		t.Errorf("confirming an uninvited address: err = %v", err)
+	}
+	if _, err := svc.Confirm(ctx, linkToken(links.ConfirmURL(plan.ID, "partner@example.com"))); err != nil {
+		t.Fatalf("confirm partner: %v", err)
+	}
+	got, err := svc.Get(ctx, token)
+	if err != nil || len(got.Participants) != 2 {
+		t.Fatalf("get after join: %+v, %v", got, err)
+	}
+
+	if _, err := svc.Leave(ctx, token); !errors.Is(err, services.ErrTripPlanNotFound) {
+		t.Errorf("leaving with the share token: err = %v", err)
+	}
+	left, err := svc.Leave(ctx, linkToken(links.LeaveURL(plan.ID, "partner@example.com")))
+	if err != nil || len(left.Participants) != 1 || left.Participants[0] != "lead@example.com" {
+		t.Fatalf("leave: %+v, %v", left, err)
+	}
+
+	if _, err := svc.Get(ctx, token+"0"); !errors.Is(err, services.ErrTripPlanNotFound) {
+		t.Errorf("tampered token: err = %v", err)
+	}
+	if err := svc.Cancel(ctx, token); !errors.Is(err, services.ErrTripPlanNotFound) {
+		t.Errorf("cancelling with the share token: err = %v", err)
+	}
+	if err := svc.Cancel(ctx, plan.OrganizerToken); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := svc.Get(ctx, token); !errors.Is(err, services.ErrTripPlanNotFound) {
+		t.Errorf("cancelled plan: err = %v", err)
+	}
+}
+
+func TestTripPlanService_RejectsInvalidPlans(t *testing.T) {
+	svc := newTripPlanService(t, notifier.NewTripLinks("secret", ""), nil)
+	ctx := context.Background()
+	soon := time.Now().UTC().AddDate(0, 0, 2).Format(time.DateOnly)
+
+	center, _ := domain.ParseZoneID("NWAC")
+	centerReq := tripRequest(t, soon, "a@example.com")
+	centerReq.ZoneIDs = []*domain.ZoneID{center}
+	badZone := tripRequest(t, soon, "a@example.com")
+	badZone.Timezone = "Mars/Olympus"
+
+	for name, req := range map[string]services.CreateTripPlanRequest{
+		"past date":       tripRequest(t, "2020-01-04", "a@example.com"),
+		"far ahead":       tripRequest(t, time.Now().UTC().AddDate(0, 3, 0).Format(time.DateOnly), "a@example.com"),
+		"bad date":        tripRequest(t, "next saturday", "a@example.com"),
+		"no participants": tripRequest(t, soon),
+		"center":          centerReq,
+		"timezone":        badZone,
+	} {
+		if _, err := svc.Create(ctx, req); !errors.Is(err, services.ErrInvalidTripPlan) {
+			t.Errorf("%s: err = %v, want ErrInvalidTripPlan", name, err)
+		}
+	}
+
+	disabled := newTripPlanService(t, nil, nil)
+	if _, err := disabled.Create(ctx, tripRequest(t, soon, "a@example.com")); !errors.Is(err, services.ErrTripPlansDisabled) {
+		t.Errorf("without a share secret: err = %v", err)
+	}
+}
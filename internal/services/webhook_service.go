@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+// ErrInvalidWebhook is returned when a webhook registration is rejected.
+var ErrInvalidWebhook = errors.New("invalid webhook endpoint")
+
+// webhookDeliveryLimit caps the delivery log returned per endpoint.
+const webhookDeliveryLimit = 100
+
+// WebhookService manages partner webhook endpoints that receive forecast events.
+type WebhookService struct {
+	repo *db.WebhookRepository
+}
+
+// NewWebhookService creates a webhook service backed by repo.
+func NewWebhookService(repo *db.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+// CreateWebhookRequest describes a new endpoint. Events may be empty to
+// receive every event type.
+type CreateWebhookRequest struct {
+	URL    *domain.WebhookURL
+	ZoneID *domain.ZoneID
+	Events []string
+}
+
+// Create registers an endpoint with a freshly generated signing secret. The
+// secret is only returned here; it is never exposed again.
+func (s *WebhookService) Create(ctx context.Context, req CreateWebhookRequest) (*models.WebhookEndpoint, string, error) {
+	for _, e := range req.Events {
+		if !slices.Contains(models.WebhookEventTypes, e) {
+			return nil, "", fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, e)
+		}
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	ep := &models.WebhookEndpoint{
+		URL:    req.URL.String(),
+		Secret: secret,
+		ZoneID: req.ZoneID.String(),
+		Events: req.Events,
+		Active: true,
+	}
+	if err := s.repo.Create(ep); err != nil {
+		return nil, "", fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return ep, secret, nil
+}
+
+// List returns every registered endpoint.
+func (s *WebhookService) List(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	out, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return out, nil
+}
+
+// Delete removes an endpoint and reports whether it existed.
+func (s *WebhookService) Delete(ctx context.Context, id uint) (bool, error) {
+	found, err := s.repo.Delete(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	return found, nil
+}
+
+// Enable re-activates an endpoint disabled after repeated failures.
+func (s *WebhookService) Enable(ctx context.Context, id uint) (bool, error) {
+	found, err := s.repo.Enable(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to enable webhook endpoint: %w", err)
+	}
+	return found, nil
+}
+
+// Deliveries returns the recent delivery attempts for an endpoint, or nil when
+// the endpoint does not exist.
+func (s *WebhookService) Deliveries(ctx context.Context, id uint) ([]models.WebhookDelivery, error) {
+	ep, err := s.repo.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook endpoint: %w", err)
+	}
+	if ep == nil {
+		return nil, nil
+	}
+	out, err := s.repo.ListDeliveries(id, webhookDeliveryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	if out == nil {
+		out = []models.WebhookDelivery{}
+	}
+	return out, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/signing"
+)
+
+var (
+	// ErrInvalidZoneGroup is returned when a zone group is rejected.
+	ErrInvalidZoneGroup = errors.New("invalid zone group")
+	// ErrZoneGroupNotFound is returned for unknown slugs and for groups the
+	// caller does not own.
+	ErrZoneGroupNotFound = errors.New("zone group not found")
+	// ErrZoneGroupExists is returned when a slug is already taken.
+	ErrZoneGroupExists = errors.New("zone group slug is already taken")
+	// ErrZoneGroupManagementDisabled is returned when owners cannot change
+	// their groups because no signing secret is configured.
+	ErrZoneGroupManagementDisabled = errors.New("zone group management is not configured")
+)
+
+const (
+	maxZoneGroupZones = 20
+	maxZoneGroupName  = 100
+)
+
+// ZoneGroupService manages named zone groups, which may span centers, and
+// aggregates their forecasts into a worst-case rating.
+type ZoneGroupService struct {
+	repo     *db.ZoneGroupRepository
+	orgs     *db.OrganizationRepository
+	forecast *ForecastService
+	// secret signs the manage tokens owners change their groups with.
+	secret string
+}
+
+// NewZoneGroupService creates a zone group service. Without secret, owners
+// cannot change or delete their groups; administrators still can.
+func NewZoneGroupService(repo *db.ZoneGroupRepository, orgs *db.OrganizationRepository, forecast *ForecastService, secret string) *ZoneGroupService {
+	return &ZoneGroupService{repo: repo, orgs: orgs, forecast: forecast, secret: secret}
+}
+
+// ManagedZoneGroup is a subscriber's group with the token that authorizes
+// changes to it and the token that lists its owner's groups. The tokens are
+// only returned when the group is created.
+type ManagedZoneGroup struct {
+	*models.ZoneGroup
+	ManageToken string `json:"manage_token,omitempty"`
+	OwnerToken  string `json:"owner_token,omitempty"`
+}
+
+// CreateZoneGroupRequest describes a new group. Exactly one of Owner and
+// OrganizationID is set.
+type CreateZoneGroupRequest struct {
+	Slug           *domain.ZoneID
+	Name           string
+	ZoneIDs        []*domain.ZoneID
+	Owner          *domain.Email
+	OrganizationID *uint
+}
+
+// GroupBandDanger is the worst rating in one elevation band across a group,
+// with the zones that reach it.
+type GroupBandDanger struct {
+	Band        string             `json:"band"`
+	BandName    string             `json:"band_name"`
+	DangerLevel domain.DangerLevel `json:"danger_level"`
+	DangerName  string             `json:"danger_name"`
+	ZoneIDs     []string           `json:"zone_ids"`
+}
+
+// GroupDangerDay is the worst-case rating of a group for one forecast day.
+type GroupDangerDay struct {
+	Day   string            `json:"day"`
+	Bands []GroupBandDanger `json:"bands"`
+	// Max is the highest level across all bands.
+	Max domain.DangerLevel `json:"max_danger"`
+}
+
+// GroupForecast is the forecast of each zone in a group with the group's
+// worst-case rating per band for today and tomorrow. MissingZones lists
+// member zones without a forecast for the date.
+type GroupForecast struct {
+	Group        *models.ZoneGroup     `json:"group"`
+	Date         string                `json:"date"`
+	Zones        []models.ZoneForecast `json:"zones"`
+	WorstCase    []GroupDangerDay      `json:"worst_case"`
+	MissingZones []string              `json:"missing_zones,omitempty"`
+}
+
+// Centers returns the centers the group's zones belong to.
+func (f *GroupForecast) Centers() []string {
+	return groupCenters(f.Group)
+}
+
+// Create stores a group.
+func (s *ZoneGroupService) Create(ctx context.Context, req CreateZoneGroupRequest) (*models.ZoneGroup, error) {
+	if req.Slug == nil || !req.Slug.IsGroup() {
+		return nil, fmt.Errorf("%w: slug is required", ErrInvalidZoneGroup)
+	}
+	if (req.Owner == nil) == (req.OrganizationID == nil) {
+		return nil, fmt.Errorf("%w: a group is owned by either an email or an organization", ErrInvalidZoneGroup)
+	}
+	g := &models.ZoneGroup{Slug: req.Slug.Group(), OrganizationID: req.OrganizationID}
+	if req.Owner != nil {
+		g.OwnerEmail = req.Owner.String()
+	}
+	if err := setZoneGroup(g, req.Name, req.ZoneIDs); err != nil {
+		return nil, err
+	}
+	if req.OrganizationID != nil {
+		org, err := s.orgs.Get(*req.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load organization: %w", err)
+		}
+		if org == nil {
+			return nil, ErrOrganizationNotFound
+		}
+	}
+	existing, err := s.repo.Get(g.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load zone group: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrZoneGroupExists
+	}
+	if err := s.repo.Create(g); err != nil {
+		return nil, fmt.Errorf("failed to create zone group: %w", err)
+	}
+	log.Printf("[ZoneGroupService] created group %s with %s", g.Slug, strings.Join(g.ZoneIDs, ","))
+	return g, nil
+}
+
+// Get returns a group by slug.
+func (s *ZoneGroupService) Get(ctx context.Context, slug string) (*models.ZoneGroup, error) {
+	g, err := s.repo.Get(slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load zone group: %w", err)
+	}
+	if g == nil {
+		return nil, ErrZoneGroupNotFound
+	}
+	return g, nil
+}
+
+// ListByOwner returns the groups owned by an email address.
+func (s *ZoneGroupService) ListByOwner(ctx context.Context, owner *domain.Email) ([]models.ZoneGroup, error) {
+	groups, err := s.repo.ListByOwner(owner.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list zone groups: %w", err)
+	}
+	return groups, nil
+}
+
+// ListByOrganization returns the groups owned by an organization.
+func (s *ZoneGroupService) ListByOrganization(ctx context.Context, orgID uint) ([]models.ZoneGroup, error) {
+	groups, err := s.repo.ListByOrganization(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list zone groups: %w", err)
+	}
+	return groups, nil
+}
+
+// Update replaces the name and zones of a group. A nil owner skips the
+// ownership check, for admin callers.
+func (s *ZoneGroupService) Update(ctx context.Context, slug string, owner *domain.Email, name string, zoneIDs []*domain.ZoneID) (*models.ZoneGroup, error) {
+	g, err := s.owned(ctx, slug, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := setZoneGroup(g, name, zoneIDs); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(g); err != nil {
+		return nil, fmt.Errorf("failed to update zone group: %w", err)
+	}
+	return g, nil
+}
+
+// Delete removes a group and the subscriptions following it. A nil owner
+// skips the ownership check, for admin callers.
+func (s *ZoneGroupService) Delete(ctx context.Context, slug string, owner *domain.Email) error {
+	g, err := s.owned(ctx, slug, owner)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(g); err != nil {
+		return fmt.Errorf("failed to delete zone group: %w", err)
+	}
+	log.Printf("[ZoneGroupService] deleted group %s", g.Slug)
+	return nil
+}
+
+// Forecast returns the forecasts of a group's zones for date with the
+// group's worst-case rating.
+func (s *ZoneGroupService) Forecast(ctx context.Context, slug string, date time.Time) (*GroupForecast, error) {
+	g, err := s.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.forecast.GetForecastsForCenters(groupCenters(g), date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch forecasts: %w", err)
+	}
+	byZone := make(map[string]models.ZoneForecast, len(all))
+	for _, f := range all {
+		byZone[f.ZoneID] = f
+	}
+	out := &GroupForecast{Group: g, Date: date.Format(time.DateOnly), Zones: []models.ZoneForecast{}}
+	for _, id := range g.ZoneIDs {
+		if f, ok := byZone[id]; ok {
+			out.Zones = append(out.Zones, f)
+		} else {
+			out.MissingZones = append(out.MissingZones, id)
+		}
+	}
+	out.WorstCase = []GroupDangerDay{
+		worstCase("today", out.Zones, func(f models.ZoneForecast) *models.DangerRating { return f.TodayDanger }),
+		worstCase("tomorrow", out.Zones, func(f models.ZoneForecast) *models.DangerRating { return f.FutureDanger }),
+	}
+	return out, nil
+}
+
+// ManageToken returns the token that authorizes changes to a subscriber's
+// group, or "" for organization groups and when no secret is configured.
+func (s *ZoneGroupService) ManageToken(g *models.ZoneGroup) string {
+	if s.secret == "" || g.OwnerEmail == "" {
+		return ""
+	}
+	return signing.Token(s.secret, "g"+strconv.FormatUint(uint64(g.ID), 10))
+}
+
+// Authorize verifies a manage token for the group slug and returns the
+// group's owner. Invalid tokens are reported as ErrZoneGroupNotFound.
+func (s *ZoneGroupService) Authorize(ctx context.Context, slug, token string) (*domain.Email, error) {
+	if s.secret == "" {
+		return nil, ErrZoneGroupManagementDisabled
+	}
+	g, err := s.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := signing.ParseToken(s.secret, token)
+	if err != nil || g.OwnerEmail == "" || payload != "g"+strconv.FormatUint(uint64(g.ID), 10) {
+		return nil, ErrZoneGroupNotFound
+	}
+	return domain.NewEmail(g.OwnerEmail)
+}
+
+// OwnerToken returns the token that lists owner's groups, or "" when no
+// secret is configured.
+func (s *ZoneGroupService) OwnerToken(owner *domain.Email) string {
+	if s.secret == "" {
+		return ""
+	}
+	return signing.Token(s.secret, ownerTokenPayload(owner))
+}
+
+// AuthorizeOwner verifies an owner token for owner. Invalid tokens are
+// reported as ErrZoneGroupNotFound.
+func (s *ZoneGroupService) AuthorizeOwner(ctx context.Context, owner *domain.Email, token string) error {
+	if s.secret == "" {
+		return ErrZoneGroupManagementDisabled
+	}
+	payload, err := signing.ParseToken(s.secret, token)
+	if err != nil || payload != ownerTokenPayload(owner) {
+		return ErrZoneGroupNotFound
+	}
+	return nil
+}
+
+// ownerTokenPayload identifies owner in a token without exposing the address.
+func ownerTokenPayload(owner *domain.Email) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(owner.String())))
+	return "u" + hex.EncodeToString(sum[:8])
+}
+
+// owned loads a group, treating groups that owner does not own as missing.
+func (s *ZoneGroupService) owned(ctx context.Context, slug string, owner *domain.Email) (*models.ZoneGroup, error) {
+	g, err := s.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil && g.OwnerEmail != owner.String() {
+		return nil, ErrZoneGroupNotFound
+	}
+	return g, nil
+}
+
+// setZoneGroup validates and applies a group's name and zones.
+func setZoneGroup(g *models.ZoneGroup, name string, zoneIDs []*domain.ZoneID) error {
+	g.Name = strings.TrimSpace(name)
+	if g.Name == "" || len(g.Name) > maxZoneGroupName {
+		return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidZoneGroup, maxZoneGroupName)
+	}
+	g.ZoneIDs = nil
+	for _, z := range zoneIDs {
+		if !z.IsSpecificZone() {
+			return fmt.Errorf("%w: %s is not a zone", ErrInvalidZoneGroup, z)
+		}
+		if !slices.Contains(g.ZoneIDs, z.String()) {
+			g.ZoneIDs = append(g.ZoneIDs, z.String())
+		}
+	}
+	if len(g.ZoneIDs) == 0 || len(g.ZoneIDs) > maxZoneGroupZones {
+		return fmt.Errorf("%w: groups need 1 to %d zones", ErrInvalidZoneGroup, maxZoneGroupZones)
+	}
+	return nil
+}
+
+func groupCenters(g *models.ZoneGroup) []string {
+	var centers []string
+	for _, id := range g.ZoneIDs {
+		center, _, _ := strings.Cut(id, "_")
+		if !slices.Contains(centers, center) {
+			centers = append(centers, center)
+		}
+	}
+	return centers
+}
+
+// worstCase takes the highest level per band across zones. Zones without a
+// rating for the day are ignored.
+func worstCase(day string, zones []models.ZoneForecast, rating func(models.ZoneForecast) *models.DangerRating) GroupDangerDay {
+	out := GroupDangerDay{Day: day, Bands: make([]GroupBandDanger, len(exportBands))}
+	for i, b := range exportBands {
+		out.Bands[i] = GroupBandDanger{Band: b.band, BandName: b.name, ZoneIDs: []string{}}
+	}
+	for _, f := range zones {
+		r := rating(f)
+		if r == nil {
+			continue
+		}
+		for i, level := range []domain.DangerLevel{r.Upper, r.Middle, r.Lower} {
+			band := &out.Bands[i]
+			switch {
+			case !level.IsRated() || level < band.DangerLevel:
+				continue
+			case level > band.DangerLevel:
+				band.DangerLevel, band.ZoneIDs = level, []string{f.ZoneID}
+			default:
+				band.ZoneIDs = append(band.ZoneIDs, f.ZoneID)
+			}
+			out.Max = max(out.Max, level)
+		}
+	}
+	for i := range out.Bands {
+		out.Bands[i].DangerName = out.Bands[i].DangerLevel.Name()
+	}
+	return out
+}
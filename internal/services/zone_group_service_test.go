@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestZoneGroupService(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.Subscription{}, &models.Organization{}, &models.ZoneGroup{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	date := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	forecast := func(center string, id int, zone string, today, tomorrow models.DangerRating) models.Forecast {
+		today.ValidDay, tomorrow.ValidDay = "current", "tomorrow"
+		return models.Forecast{ID: id, Status: "published", AvalancheCenter: models.AvalancheCenter{ID: center},
+			PublishedTime: date, StartDate: date, EndDate: date.Add(24 * time.Hour),
+			ForecastZone: []models.Zone{{ZoneID: zone, Name: center + " " + zone}},
+			Danger:       []models.DangerRating{today, tomorrow}}
+	}
+	client := &mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": {
+			forecast("NWAC", 1, "10", models.DangerRating{Upper: 3, Middle: 2, Lower: 1}, models.DangerRating{Upper: 2, Middle: 2, Lower: 1}),
+			forecast("NWAC", 2, "11", models.DangerRating{Upper: 4, Middle: 2, Lower: 1}, models.DangerRating{Upper: 3, Middle: 2, Lower: 1}),
+		},
+		"IPAC": {
+			forecast("IPAC", 3, "1", models.DangerRating{Upper: 3, Middle: 3, Lower: 2}, models.DangerRating{Upper: 3, Middle: 3, Lower: 1}),
+		},
+	}}
+	repo := db.NewZoneGroupRepository(gdb)
+	svc := services.NewZoneGroupService(repo, db.NewOrganizationRepository(gdb), services.NewForecast(client), "group-secret")
+	ctx := context.Background()
+
+	slug, _ := domain.NewGroupZoneID("home-range")
+	owner, _ := domain.NewEmail("owner@example.com")
+	other, _ := domain.NewEmail("other@example.com")
+	zones := func(ids ...string) []*domain.ZoneID {
+		out := make([]*domain.ZoneID, len(ids))
+		for i, id := range ids {
+			out[i], _ = domain.ParseZoneID(id)
+		}
+		return out
+	}
+
+	for name, req := range map[string]services.CreateZoneGroupRequest{
+		"center":   {Slug: slug, Name: "Home", ZoneIDs: zones("NWAC"), Owner: owner},
+		"no zones": {Slug: slug, Name: "Home", Owner: owner},
+		"no name":  {Slug: slug, ZoneIDs: zones("NWAC_10"), Owner: owner},
+		"no owner": {Slug: slug, Name: "Home", ZoneIDs: zones("NWAC_10")},
+	} {
+		if _, err := svc.Create(ctx, req); !errors.Is(err, services.ErrInvalidZoneGroup) {
+			t.Errorf("%s: expected ErrInvalidZoneGroup, got %v", name, err)
+		}
+	}
+	orgID := uint(99)
+	if _, err := svc.Create(ctx, services.CreateZoneGroupRequest{Slug: slug, Name: "Home", ZoneIDs: zones("NWAC_10"), OrganizationID: &orgID}); !errors.Is(err, services.ErrOrganizationNotFound) {
+		t.Errorf("expected ErrOrganizationNotFound, got %v", err)
+	}
+
+	g, err := svc.Create(ctx, services.CreateZoneGroupRequest{Slug: slug, Name: " Home range ",
+		ZoneIDs: zones("NWAC_10", "NWAC_11", "IPAC_1", "IPAC_2", "NWAC_10"), Owner: owner})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if g.Name != "Home range" || len(g.ZoneIDs) != 4 || g.Ref() != "group:home-range" {
+		t.Errorf("unexpected group %+v", g)
+	}
+	if _, err := svc.Create(ctx, services.CreateZoneGroupRequest{Slug: slug, Name: "Again", ZoneIDs: zones("NWAC_10"), Owner: other}); !errors.Is(err, services.ErrZoneGroupExists) {
+		t.Errorf("expected ErrZoneGroupExists, got %v", err)
+	}
+
+	f, err := svc.Forecast(ctx, "home-range", date)
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	if f.Date != "2025-02-01" || len(f.Zones) != 3 || len(f.MissingZones) != 1 || f.MissingZones[0] != "IPAC_2" {
+		t.Fatalf("unexpected zones %+v missing %v", f.Zones, f.MissingZones)
+	}
+	if len(f.WorstCase) != 2 {
+		t.Fatalf("expected today and tomorrow, got %+v", f.WorstCase)
+	}
+	today, tomorrow := f.WorstCase[0], f.WorstCase[1]
+	if today.Day != "today" || today.Max != 4 ||
+		today.Bands[0].DangerLevel != 4 || today.Bands[0].DangerName != "High" || len(today.Bands[0].ZoneIDs) != 1 || today.Bands[0].ZoneIDs[0] != "NWAC_11" ||
+		today.Bands[1].DangerLevel != 3 || today.Bands[2].DangerLevel != 2 {
+		t.Errorf("unexpected worst case today %+v", today)
+	}
+	if tomorrow.Day != "tomorrow" || tomorrow.Max != 3 || len(tomorrow.Bands[0].ZoneIDs) != 2 || tomorrow.Bands[2].DangerLevel != 1 {
+		t.Errorf("unexpected worst case tomorrow %+v", tomorrow)
+	}
+
+	token := svc.ManageToken(g)
+	if token == "" {
+		t.Fatal("expected a manage token for a subscriber's group")
+	}
+	if got, err := svc.Authorize(ctx, "home-range", token); err != nil || got.String() != "owner@example.com" {
+		t.Errorf("authorize: %v %v", got, err)
+	}
+	forged := services.NewZoneGroupService(repo, db.NewOrganizationRepository(gdb), services.NewForecast(client), "other-secret").ManageToken(g)
+	for _, bad := range []string{"", "g1", forged} {
+		if _, err := svc.Authorize(ctx, "home-range", bad); !errors.Is(err, services.ErrZoneGroupNotFound) {
+			t.Errorf("token %q: expected ErrZoneGroupNotFound, got %v", bad, err)
+		}
+	}
+	if err := svc.AuthorizeOwner(ctx, owner, svc.OwnerToken(owner)); err != nil {
+		t.Errorf("authorize owner: %v", err)
+	}
+	if err := svc.AuthorizeOwner(ctx, other, svc.OwnerToken(owner)); !errors.Is(err, services.ErrZoneGroupNotFound) {
+		t.Errorf("expected another owner's token to be rejected, got %v", err)
+	}
+	unsigned := services.NewZoneGroupService(repo, db.NewOrganizationRepository(gdb), services.NewForecast(client), "")
+	if _, err := unsigned.Authorize(ctx, "home-range", token); !errors.Is(err, services.ErrZoneGroupManagementDisabled) {
+		t.Errorf("expected ErrZoneGroupManagementDisabled, got %v", err)
+	}
+
+	if _, err := svc.Update(ctx, "home-range", other, "Mine", zones("NWAC_10")); !errors.Is(err, services.ErrZoneGroupNotFound) {
+		t.Errorf("expected another owner's update to be rejected, got %v", err)
+	}
+	if g, err = svc.Update(ctx, "home-range", owner, "Home", zones("NWAC_10")); err != nil || len(g.ZoneIDs) != 1 {
+		t.Fatalf("update: %+v %v", g, err)
+	}
+	if groups, err := svc.ListByOwner(ctx, owner); err != nil || len(groups) != 1 || groups[0].ZoneIDs[0] != "NWAC_10" {
+		t.Errorf("unexpected groups %+v %v", groups, err)
+	}
+
+	if err := gdb.Create(&models.Subscription{Email: "me@example.com", ZoneID: "group:home-range"}).Error; err != nil {
+		t.Fatalf("seed subscription: %v", err)
+	}
+	if err := svc.Delete(ctx, "home-range", nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	var subs int64
+	gdb.Model(&models.Subscription{}).Count(&subs)
+	if subs != 0 {
+		t.Errorf("expected the group's subscriptions to be deleted, %d left", subs)
+	}
+	if _, err := svc.Forecast(ctx, "home-range", date); !errors.Is(err, services.ErrZoneGroupNotFound) {
+		t.Errorf("expected ErrZoneGroupNotFound, got %v", err)
+	}
+}
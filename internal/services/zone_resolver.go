@@ -0,0 +1,460 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+)
+
+// Zone resolution errors.
+var (
+	ErrUnknownZone       = errors.New("unknown zone")
+	ErrInvalidZoneAlias  = errors.New("invalid zone alias")
+	ErrZoneAliasNotFound = errors.New("zone alias not found")
+	errZoneAliasLoop     = errors.New("zone aliases form a loop")
+)
+
+// DefaultZoneCatalogTTL is how long the zone catalog is cached. Zones are
+// added or renumbered at most a few times a season.
+const DefaultZoneCatalogTTL = 6 * time.Hour
+
+// zoneCatalogRetry is how long a failed fetch, or a catalog missing centers
+// whose forecasts failed to load, is cached before fetching again.
+const zoneCatalogRetry = time.Minute
+
+const (
+	maxZoneAliasLength = 64
+	maxZoneAliasChain  = 5
+	maxZoneSuggestions = 5
+	// Fuzzy matches resolve at minZoneFuzzyMatch when the runner-up scores
+	// at least zoneFuzzyMatchMargin lower; weaker matches are suggestions.
+	minZoneFuzzyMatch    = 0.75
+	zoneFuzzyMatchMargin = 0.1
+	minZoneSuggestion    = 0.4
+)
+
+var (
+	zoneIDPattern = regexp.MustCompile(`^[A-Za-z]{2,12}(_[A-Za-z0-9-]+)?$`)
+	zoneKeyWords  = map[string]string{"mount": "mt", "mountain": "mtn", "mountains": "mtns", "saint": "st"}
+)
+
+// ZoneCatalogEntry is a zone known from the active centers' forecasts.
+type ZoneCatalogEntry struct {
+	ZoneID string `json:"zone_id"`
+	Name   string `json:"name"`
+	Center string `json:"center"`
+}
+
+// ZoneSuggestion is a catalog zone ranked against unresolved input; Score is
+// between 0 and 1.
+type ZoneSuggestion struct {
+	ZoneCatalogEntry
+	Score float64 `json:"score"`
+}
+
+// UnresolvedZoneError is returned when input matches no zone, or several
+// equally well. It wraps ErrUnknownZone.
+type UnresolvedZoneError struct {
+	Input       string
+	Suggestions []ZoneSuggestion
+}
+
+func (e *UnresolvedZoneError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("unknown zone %q", e.Input)
+	}
+	names := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		names[i] = fmt.Sprintf("%s (%s)", s.ZoneID, s.Name)
+	}
+	return fmt.Sprintf("unknown zone %q, did you mean %s?", e.Input, strings.Join(names, ", "))
+}
+
+func (e *UnresolvedZoneError) Unwrap() error { return ErrUnknownZone }
+
+// ZoneResolver turns user input into canonical zone IDs. Besides upstream IDs
+// ("NWAC_10") it accepts "center/name" ("NWAC/Mt Hood"), slugs
+// ("nwac-mt-hood"), zone names with typos and configured aliases, which also
+// carry the previous IDs of zones renumbered upstream.
+type ZoneResolver struct {
+	client  ForecastClient
+	centers *db.CenterRepository
+	aliases *db.ZoneAliasRepository
+	ttl     time.Duration
+
+	// fetching serializes catalog fetches; mu guards the cached state and is
+	// never held across upstream requests.
+	fetching sync.Mutex
+	mu       sync.Mutex
+	catalog  *zoneCatalog
+	err      error
+	expires  time.Time
+}
+
+// NewZoneResolver creates a resolver whose zone catalog is refetched after
+// ttl. A zero ttl uses DefaultZoneCatalogTTL.
+func NewZoneResolver(client ForecastClient, centers *db.CenterRepository, aliases *db.ZoneAliasRepository, ttl time.Duration) *ZoneResolver {
+	if ttl <= 0 {
+		ttl = DefaultZoneCatalogTTL
+	}
+	return &ZoneResolver{client: client, centers: centers, aliases: aliases, ttl: ttl}
+}
+
+// Catalog returns every zone of the active centers, sorted by zone ID.
+func (r *ZoneResolver) Catalog() ([]ZoneCatalogEntry, error) {
+	c, err := r.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return c.entries, nil
+}
+
+// Resolve returns the canonical ID of the zone or center raw refers to. When
+// raw matches nothing, or several zones equally well, the error is an
+// *UnresolvedZoneError listing the closest zones.
+func (r *ZoneResolver) Resolve(ctx context.Context, raw string) (*domain.ZoneID, error) {
+	raw = strings.TrimSpace(raw)
+	key := zoneKey(raw)
+	if key == "" {
+		return nil, &UnresolvedZoneError{Input: raw}
+	}
+	catalog, err := r.loadCatalog()
+	if err != nil {
+		log.Printf("[ZoneResolver] catalog unavailable, resolving %q without it: %v", raw, err)
+		catalog = &zoneCatalog{}
+	}
+
+	idLike := zoneIDPattern.MatchString(raw)
+	if idLike {
+		id, _ := domain.ParseZoneID(raw)
+		if id.IsCenterLevel() && catalog.centers[id.Center()] {
+			return id, nil
+		}
+		if e, ok := catalog.byID[strings.ToUpper(id.String())]; ok {
+			return domain.ParseZoneID(e.ZoneID)
+		}
+	}
+
+	target, err := r.followAlias(key)
+	if err != nil {
+		return nil, err
+	}
+	if target != "" {
+		return domain.ParseZoneID(target)
+	}
+	if id, _ := domain.ParseZoneID(raw); idLike && (len(catalog.entries) == 0 || catalog.missing[id.Center()]) {
+		// IDs of centers missing from the catalog are accepted as given.
+		return id, nil
+	}
+
+	var exact []ZoneSuggestion
+	for _, e := range catalog.entries {
+		if key == zoneKey(e.ZoneID) || key == zoneKey(e.Center+" "+e.Name) || key == zoneKey(e.Name) {
+			exact = append(exact, ZoneSuggestion{ZoneCatalogEntry: e, Score: 1})
+		}
+	}
+	if len(exact) == 1 {
+		return domain.ParseZoneID(exact[0].ZoneID)
+	}
+	if len(exact) > 1 {
+		return nil, &UnresolvedZoneError{Input: raw, Suggestions: exact}
+	}
+
+	ranked := catalog.rank(key)
+	if len(ranked) > 0 && ranked[0].Score >= minZoneFuzzyMatch &&
+		(len(ranked) == 1 || ranked[0].Score-ranked[1].Score >= zoneFuzzyMatchMargin) {
+		return domain.ParseZoneID(ranked[0].ZoneID)
+	}
+	suggestions := ranked[:min(len(ranked), maxZoneSuggestions)]
+	if id, _ := domain.ParseZoneID(raw); idLike && catalog.centers[id.Center()] {
+		// An unknown zone of a known center: offer the center's zones.
+		suggestions = nil
+		for _, e := range catalog.entries {
+			if e.Center == id.Center() && len(suggestions) < maxZoneSuggestions {
+				suggestions = append(suggestions, ZoneSuggestion{ZoneCatalogEntry: e})
+			}
+		}
+	}
+	return nil, &UnresolvedZoneError{Input: raw, Suggestions: suggestions}
+}
+
+// Suggest returns up to limit catalog zones ranked against raw.
+func (r *ZoneResolver) Suggest(raw string, limit int) ([]ZoneSuggestion, error) {
+	catalog, err := r.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	ranked := catalog.rank(zoneKey(raw))
+	return ranked[:min(len(ranked), limit)], nil
+}
+
+// followAlias returns the zone an alias key points to, following the chain
+// left by successive renumberings, or "" when key is not an alias.
+func (r *ZoneResolver) followAlias(key string) (string, error) {
+	if r.aliases == nil {
+		return "", nil
+	}
+	target := ""
+	for range maxZoneAliasChain {
+		a, err := r.aliases.Get(key)
+		if err != nil {
+			return "", fmt.Errorf("failed to load zone alias: %w", err)
+		}
+		if a == nil {
+			return target, nil
+		}
+		target, key = a.ZoneID, zoneKey(a.ZoneID)
+	}
+	return "", fmt.Errorf("%w at %q", errZoneAliasLoop, key)
+}
+
+// ListAliases returns every configured alias.
+func (r *ZoneResolver) ListAliases(ctx context.Context) ([]models.ZoneAlias, error) {
+	return r.aliases.List()
+}
+
+// SetAlias points alias, normalized like zone input, at a zone. Aliases may
+// not shadow a zone ID in the catalog.
+func (r *ZoneResolver) SetAlias(ctx context.Context, alias string, zoneID *domain.ZoneID) (*models.ZoneAlias, error) {
+	key := zoneKey(alias)
+	if key == "" || len(key) > maxZoneAliasLength {
+		return nil, fmt.Errorf("%w: alias must have 1 to %d letters or digits", ErrInvalidZoneAlias, maxZoneAliasLength)
+	}
+	if zoneID == nil || !zoneID.IsSpecificZone() {
+		return nil, fmt.Errorf("%w: alias must point to a zone", ErrInvalidZoneAlias)
+	}
+	if key == zoneKey(zoneID.String()) {
+		return nil, fmt.Errorf("%w: alias %q is the zone's own ID", ErrInvalidZoneAlias, key)
+	}
+	if catalog, err := r.loadCatalog(); err == nil {
+		for _, e := range catalog.entries {
+			if zoneKey(e.ZoneID) == key {
+				return nil, fmt.Errorf("%w: %q is the ID of %s", ErrInvalidZoneAlias, key, e.Name)
+			}
+		}
+	}
+	a := &models.ZoneAlias{Alias: key, ZoneID: zoneID.String()}
+	if err := r.aliases.Upsert(a); err != nil {
+		return nil, fmt.Errorf("failed to save zone alias: %w", err)
+	}
+	log.Printf("[ZoneResolver] alias %s -> %s", a.Alias, a.ZoneID)
+	return a, nil
+}
+
+// DeleteAlias removes an alias.
+func (r *ZoneResolver) DeleteAlias(ctx context.Context, alias string) error {
+	ok, err := r.aliases.Delete(zoneKey(alias))
+	if err != nil {
+		return fmt.Errorf("failed to delete zone alias: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrZoneAliasNotFound, alias)
+	}
+	return nil
+}
+
+// Renumber handles a zone renumbered upstream: the old ID becomes an alias of
+// the new one, and everything following the old ID moves to the new one. It
+// returns the number of subscriptions moved.
+func (r *ZoneResolver) Renumber(ctx context.Context, from, to *domain.ZoneID) (int64, error) {
+	if !from.IsSpecificZone() || !to.IsSpecificZone() {
+		return 0, fmt.Errorf("%w: renumbering needs two zone IDs", ErrInvalidZoneAlias)
+	}
+	if from.Equals(to) {
+		return 0, fmt.Errorf("%w: %s is renumbered to itself", ErrInvalidZoneAlias, from)
+	}
+	moved, err := r.aliases.Renumber(&models.ZoneAlias{Alias: zoneKey(from.String()), ZoneID: to.String(), Renumbered: true}, from.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to renumber %s: %w", from, err)
+	}
+	log.Printf("[ZoneResolver] renumbered %s to %s, moved %d subscriptions", from, to, moved)
+	return moved, nil
+}
+
+// zoneCatalog indexes the zones of the active centers.
+type zoneCatalog struct {
+	entries []ZoneCatalogEntry
+	byID    map[string]ZoneCatalogEntry
+	centers map[string]bool
+	// missing holds the active centers whose zones failed to load.
+	missing map[string]bool
+}
+
+// loadCatalog returns the cached catalog, refetching it after the TTL, or
+// sooner when centers were missing from it. While a refresh is in flight or
+// after it fails, the previous catalog is served instead.
+func (r *ZoneResolver) loadCatalog() (*zoneCatalog, error) {
+	if c, ok, err := r.cachedCatalog(); ok {
+		return c, err
+	}
+	if !r.fetching.TryLock() {
+		r.mu.Lock()
+		c := r.catalog
+		r.mu.Unlock()
+		if c != nil {
+			return c, nil
+		}
+		r.fetching.Lock()
+	}
+	defer r.fetching.Unlock()
+	// Another caller may have refreshed the catalog while this one waited.
+	if c, ok, err := r.cachedCatalog(); ok {
+		return c, err
+	}
+
+	c, err := r.fetchCatalog()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.expires = time.Now().Add(min(r.ttl, zoneCatalogRetry))
+		if r.catalog != nil {
+			log.Printf("[ZoneResolver] using cached zone catalog: %v", err)
+			return r.catalog, nil
+		}
+		r.err = err
+		return nil, err
+	}
+	r.catalog, r.err = c, nil
+	r.expires = time.Now().Add(r.ttl)
+	if len(c.missing) > 0 {
+		r.expires = time.Now().Add(min(r.ttl, zoneCatalogRetry))
+	}
+	return c, nil
+}
+
+// cachedCatalog returns the cached catalog or fetch error while it is fresh.
+func (r *ZoneResolver) cachedCatalog() (*zoneCatalog, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Now().After(r.expires) || (r.catalog == nil && r.err == nil) {
+		return nil, false, nil
+	}
+	if r.catalog != nil {
+		return r.catalog, true, nil
+	}
+	return nil, true, r.err
+}
+
+func (r *ZoneResolver) fetchCatalog() (*zoneCatalog, error) {
+	centers, err := r.centers.GetActiveCenters()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load centers: %w", err)
+	}
+	c := &zoneCatalog{byID: map[string]ZoneCatalogEntry{}, centers: map[string]bool{}, missing: map[string]bool{}}
+	var lastErr error
+	for _, center := range centers {
+		forecasts, err := r.client.FetchForecasts(center.ID)
+		if err != nil {
+			log.Printf("[ZoneResolver] failed to fetch zones of %s: %v", center.ID, err)
+			lastErr = err
+			c.missing[strings.ToUpper(center.ID)] = true
+			continue
+		}
+		c.centers[strings.ToUpper(center.ID)] = true
+		SortForecastsByPublishTime(forecasts)
+		for _, f := range forecasts {
+			for _, z := range f.ForecastZone {
+				e := ZoneCatalogEntry{ZoneID: center.ID + "_" + z.ZoneID, Name: z.Name, Center: center.ID}
+				if _, ok := c.byID[strings.ToUpper(e.ZoneID)]; !ok {
+					c.byID[strings.ToUpper(e.ZoneID)] = e
+					c.entries = append(c.entries, e)
+				}
+			}
+		}
+	}
+	if len(c.centers) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to fetch zones: %w", lastErr)
+	}
+	sort.Slice(c.entries, func(i, j int) bool { return c.entries[i].ZoneID < c.entries[j].ZoneID })
+	return c, nil
+}
+
+// rank scores every zone against key and returns those worth suggesting,
+// best first.
+func (c *zoneCatalog) rank(key string) []ZoneSuggestion {
+	var out []ZoneSuggestion
+	for _, e := range c.entries {
+		score := max(similarity(key, zoneKey(e.Name)), similarity(key, zoneKey(e.Center+" "+e.Name)))
+		if wordsPrefix(key, zoneKey(e.Name)) || wordsPrefix(key, zoneKey(e.Center+" "+e.Name)) {
+			score = max(score, 0.85)
+		}
+		if score >= minZoneSuggestion {
+			out = append(out, ZoneSuggestion{ZoneCatalogEntry: e, Score: float64(int(score*100)) / 100})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// zoneKey normalizes zone input for comparison: lowercase words of letters
+// and digits joined by dashes, with common words abbreviated, so that
+// "NWAC/Mount Hood" and "nwac-mt-hood" compare equal.
+func zoneKey(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		if short, ok := zoneKeyWords[w]; ok {
+			words[i] = short
+		}
+	}
+	return strings.Join(words, "-")
+}
+
+// wordsPrefix reports whether every word of key starts a word of name, in
+// order, e.g. "snoq" in "snoqualmie-pass".
+func wordsPrefix(key, name string) bool {
+	if key == "" {
+		return false
+	}
+	names := strings.Split(name, "-")
+	i := 0
+	for _, w := range strings.Split(key, "-") {
+		for i < len(names) && !strings.HasPrefix(names[i], w) {
+			i++
+		}
+		if i == len(names) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// similarity is 1 minus the edit distance of a and b relative to the longer.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
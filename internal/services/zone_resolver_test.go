@@ -0,0 +1,192 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"example.com/avalanche/internal/db"
+	"example.com/avalanche/internal/domain"
+	"example.com/avalanche/internal/models"
+	"example.com/avalanche/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// centerDownClient fails to fetch one center's forecasts.
+type centerDownClient struct {
+	*mockForecastClient
+	down  string
+	calls int
+}
+
+func (c *centerDownClient) FetchForecasts(centerID string) ([]models.Forecast, error) {
+	c.calls++
+	if centerID == c.down {
+		return nil, errors.New("upstream unavailable")
+	}
+	return c.mockForecastClient.FetchForecasts(centerID)
+}
+
+func newZoneResolver(t *testing.T) (*services.ZoneResolver, *gorm.DB) {
+	t.Helper()
+	r, gdb, _ := newZoneResolverWithClient(t, "")
+	return r, gdb
+}
+
+func newZoneResolverWithClient(t *testing.T, down string) (*services.ZoneResolver, *gorm.DB, *centerDownClient) {
+	t.Helper()
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := gdb.AutoMigrate(&models.AvalancheCenter{}, &models.ZoneAlias{}, &models.Subscription{},
+		&models.WebhookEndpoint{}, &models.ZoneGroup{}, &models.Organization{}, &models.TripPlan{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, id := range []string{"NWAC", "IPAC"} {
+		if err := gdb.Create(&models.AvalancheCenter{ID: id, Name: id, Active: true}).Error; err != nil {
+			t.Fatalf("seed center: %v", err)
+		}
+	}
+	zones := func(zs ...models.Zone) []models.Forecast {
+		return []models.Forecast{{ID: 1, ForecastZone: zs}}
+	}
+	client := &centerDownClient{down: down, mockForecastClient: &mockForecastClient{data: map[string][]models.Forecast{
+		"NWAC": zones(models.Zone{ZoneID: "10", Name: "Snoqualmie Pass"}, models.Zone{ZoneID: "11", Name: "Mt Hood"}),
+		"IPAC": zones(models.Zone{ZoneID: "1", Name: "East Cabinet Mountains"}, models.Zone{ZoneID: "3", Name: "Mt Hood"}),
+	}}}
+	return services.NewZoneResolver(client, db.NewCenterRepository(gdb), db.NewZoneAliasRepository(gdb), 0), gdb, client
+}
+
+func TestZoneResolver_Resolve(t *testing.T) {
+	r, _ := newZoneResolver(t)
+	ctx := context.Background()
+
+	for raw, want := range map[string]string{
+		"nwac_10":                "NWAC_10",
+		"NWAC":                   "NWAC",
+		"NWAC/Mount Hood":        "NWAC_11",
+		"nwac-mt-hood":           "NWAC_11",
+		"IPAC 1":                 "IPAC_1",
+		"snoqualmi pass":         "NWAC_10",
+		"snoq":                   "NWAC_10",
+		"east cabinet mountains": "IPAC_1",
+	} {
+		got, err := r.Resolve(ctx, raw)
+		if err != nil || got.String() != want {
+			t.Errorf("Resolve(%q) = %v, %v; want %s", raw, got, err, want)
+		}
+	}
+
+	var unresolved *services.UnresolvedZoneError
+	if _, err := r.Resolve(ctx, "Mt Hood"); !errors.As(err, &unresolved) || len(unresolved.Suggestions) != 2 {
+		t.Errorf("expected both Mt Hood zones as suggestions, got %v", err)
+	}
+	if _, err := r.Resolve(ctx, "NWAC_99"); !errors.As(err, &unresolved) || len(unresolved.Suggestions) != 2 ||
+		unresolved.Suggestions[0].Center != "NWAC" {
+		t.Errorf("expected the center's zones as suggestions, got %v", err)
+	}
+	if _, err := r.Resolve(ctx, "zzzz qqqq"); !errors.Is(err, services.ErrUnknownZone) {
+		t.Errorf("expected ErrUnknownZone, got %v", err)
+	}
+}
+
+func TestZoneResolver_CenterDown(t *testing.T) {
+	r, _, client := newZoneResolverWithClient(t, "IPAC")
+	ctx := context.Background()
+
+	for raw, want := range map[string]string{
+		"IPAC_1":         "IPAC_1",
+		"ipac_7":         "IPAC_7",
+		"snoqualmi pass": "NWAC_10",
+	} {
+		got, err := r.Resolve(ctx, raw)
+		if err != nil || got.String() != want {
+			t.Errorf("Resolve(%q) = %v, %v; want %s", raw, got, err, want)
+		}
+	}
+	var unresolved *services.UnresolvedZoneError
+	if _, err := r.Resolve(ctx, "NWAC_99"); !errors.As(err, &unresolved) {
+		t.Errorf("expected unknown zones of loaded centers to stay unresolved, got %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected the partial catalog to be cached briefly, fetched %d times", client.calls)
+	}
+
+	r, _, client = newZoneResolverWithClient(t, "")
+	client.err = errors.New("upstream unavailable")
+	for range 3 {
+		if _, err := r.Catalog(); err == nil {
+			t.Fatal("expected the catalog to be unavailable")
+		}
+	}
+	if client.calls != 2 {
+		t.Errorf("expected the failed fetch to be cached briefly, fetched %d times", client.calls)
+	}
+}
+
+func TestZoneResolver_AliasesAndRenumbering(t *testing.T) {
+	r, gdb := newZoneResolver(t)
+	ctx := context.Background()
+	zone := func(raw string) *domain.ZoneID {
+		id, _ := domain.ParseZoneID(raw)
+		return id
+	}
+
+	if _, err := r.SetAlias(ctx, "NWAC_11", zone("NWAC_10")); !errors.Is(err, services.ErrInvalidZoneAlias) {
+		t.Errorf("expected an alias shadowing a zone to be rejected, got %v", err)
+	}
+	if _, err := r.SetAlias(ctx, "Home", zone("NWAC")); !errors.Is(err, services.ErrInvalidZoneAlias) {
+		t.Errorf("expected an alias to a center to be rejected, got %v", err)
+	}
+	a, err := r.SetAlias(ctx, "The Pass", zone("NWAC_9"))
+	if err != nil || a.Alias != "the-pass" {
+		t.Fatalf("set alias: %+v %v", a, err)
+	}
+	if got, err := r.Resolve(ctx, "the pass"); err != nil || got.String() != "NWAC_9" {
+		t.Errorf("expected the alias to resolve, got %v %v", got, err)
+	}
+
+	for _, v := range []any{
+		&models.Subscription{Email: "a@example.com", ZoneID: "NWAC_9"},
+		&models.Subscription{Email: "b@example.com", ZoneID: "NWAC_11"},
+		&models.ZoneGroup{Slug: "home", Name: "Home", ZoneIDs: []string{"NWAC_9", "NWAC_10"}},
+		&models.Organization{Name: "Patrol", ZoneIDs: []string{"NWAC_9"}},
+	} {
+		if err := gdb.Create(v).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	if _, err := r.Renumber(ctx, zone("NWAC_9"), zone("NWAC_9")); !errors.Is(err, services.ErrInvalidZoneAlias) {
+		t.Errorf("expected renumbering to itself to be rejected, got %v", err)
+	}
+	moved, err := r.Renumber(ctx, zone("NWAC_9"), zone("NWAC_10"))
+	if err != nil || moved != 1 {
+		t.Fatalf("renumber: moved %d, %v", moved, err)
+	}
+	for _, raw := range []string{"NWAC_9", "the pass"} {
+		if got, err := r.Resolve(ctx, raw); err != nil || got.String() != "NWAC_10" {
+			t.Errorf("expected %q to follow the renumbering, got %v %v", raw, got, err)
+		}
+	}
+	var g models.ZoneGroup
+	gdb.First(&g)
+	var o models.Organization
+	gdb.First(&o)
+	if !slices.Equal(g.ZoneIDs, []string{"NWAC_10"}) || !slices.Equal(o.ZoneIDs, []string{"NWAC_10"}) {
+		t.Errorf("expected zone lists to be renumbered, got %v and %v", g.ZoneIDs, o.ZoneIDs)
+	}
+
+	aliases, err := r.ListAliases(ctx)
+	if err != nil || len(aliases) != 2 || !aliases[0].Renumbered || aliases[0].Alias != "nwac-9" {
+		t.Errorf("unexpected aliases %+v %v", aliases, err)
+	}
+	if err := r.DeleteAlias(ctx, "The Pass"); err != nil {
+		t.Errorf("delete alias: %v", err)
+	}
+	if err := r.DeleteAlias(ctx, "The Pass"); !errors.Is(err, services.ErrZoneAliasNotFound) {
+		t.Errorf("expected ErrZoneAliasNotFound, got %v", err)
+	}
+}
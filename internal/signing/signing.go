@@ -0,0 +1,77 @@
+// Package signing signs and verifies outbound webhook payloads with
+// HMAC-SHA256 over "<timestamp>.<body>".
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers attached to every signed webhook request.
+const (
+	HeaderID        = "Avy-Webhook-Id"
+	HeaderTimestamp = "Avy-Webhook-Timestamp"
+	HeaderSignature = "Avy-Webhook-Signature"
+)
+
+// DefaultTolerance is the maximum accepted age of a signed request.
+const DefaultTolerance = 5 * time.Minute
+
+const signatureVersion = "v1"
+
+var (
+	// ErrInvalidSignature is returned when no signature matches the payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrTimestampExpired is returned when the timestamp is outside the tolerance.
+	ErrTimestampExpired = errors.New("webhook timestamp outside tolerance")
+)
+
+// Sign returns the signature header value for body sent at ts.
+func Sign(secret string, ts time.Time, body []byte) string {
+	return signatureVersion + "=" + hex.EncodeToString(mac(secret, strconv.FormatInt(ts.Unix(), 10), body))
+}
+
+// Verify checks a signature header against body and rejects timestamps older
+// or newer than tolerance relative to now. The header may carry several
+// space-separated signatures, for example while a secret is being rotated.
+func Verify(secret, signatureHeader, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	if err := checkTimestamp(timestamp, tolerance, now); err != nil {
+		return err
+	}
+	expected := mac(secret, timestamp, body)
+	for _, part := range strings.Fields(signatureHeader) {
+		version, sig, ok := strings.Cut(part, "=")
+		if !ok || version != signatureVersion {
+			continue
+		}
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func checkTimestamp(timestamp string, tolerance time.Duration, now time.Time) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+		return ErrTimestampExpired
+	}
+	return nil
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
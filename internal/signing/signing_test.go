@@ -0,0 +1,72 @@
+package signing_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/avalanche/internal/signing"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1764600000, 0)
+	body := []byte(`{"type":"forecast.issued"}`)
+	sig := signing.Sign("whsec_test", now, body)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	if err := signing.Verify("whsec_test", sig, ts, body, signing.DefaultTolerance, now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if err := signing.Verify("whsec_other", sig, ts, body, signing.DefaultTolerance, now); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Fatalf("wrong secret: got %v", err)
+	}
+	if err := signing.Verify("whsec_test", sig, ts, []byte(`{}`), signing.DefaultTolerance, now); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Fatalf("tampered body: got %v", err)
+	}
+	if err := signing.Verify("whsec_test", sig, ts, body, signing.DefaultTolerance, now.Add(10*time.Minute)); !errors.Is(err, signing.ErrTimestampExpired) {
+		t.Fatalf("replayed request: got %v", err)
+	}
+	if err := signing.Verify("whsec_test", "v1=00 "+sig, ts, body, signing.DefaultTolerance, now); err != nil {
+		t.Fatalf("rotated signatures: got %v", err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	tok := signing.Token("secret", "s42")
+	got, err := signing.ParseToken("secret", strings.ToUpper(tok))
+	if err != nil || got != "s42" {
+		t.Fatalf("ParseToken = %q, %v", got, err)
+	}
+	for _, bad := range []string{"s43" + tok[3:], tok + "0", "s42", ""} {
+		if _, err := signing.ParseToken("secret", bad); !errors.Is(err, signing.ErrInvalidToken) {
+			t.Fatalf("expected %q to be rejected, got %v", bad, err)
+		}
+	}
+	if _, err := signing.ParseToken("other", tok); !errors.Is(err, signing.ErrInvalidToken) {
+		t.Fatalf("expected wrong secret to be rejected, got %v", err)
+	}
+}
+
+func TestVerifySlack(t *testing.T) {
+	secret := "slack-signing-secret"
+	now := time.Unix(1531420618, 0)
+	body := []byte("a=b")
+	// HMAC-SHA256 of "v0:1531420618:a=b" with key "secret".
+	if got := signing.SignSlack("secret", now, body); got != "v0=6b73b1a0b367ae0d9b094b009d88c7d648abb1fab59329f0b1117378b1c1e090" {
+		t.Fatalf("SignSlack = %s", got)
+	}
+
+	body = []byte("command=%2Favy&text=NWAC_10&channel_id=C123")
+	sig := signing.SignSlack(secret, now, body)
+	if err := signing.VerifySlack(secret, sig, "1531420618", body, signing.DefaultTolerance, now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if err := signing.VerifySlack(secret, sig, "1531420618", append(body, 'x'), signing.DefaultTolerance, now); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Fatalf("tampered body: got %v", err)
+	}
+	if err := signing.VerifySlack(secret, sig, "1531420618", body, signing.DefaultTolerance, now.Add(time.Hour)); !errors.Is(err, signing.ErrTimestampExpired) {
+		t.Fatalf("replayed request: got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers Slack attaches to slash command and interactivity requests.
+const (
+	SlackHeaderTimestamp = "X-Slack-Request-Timestamp"
+	SlackHeaderSignature = "X-Slack-Signature"
+)
+
+const slackSignatureVersion = "v0"
+
+// SignSlack returns the X-Slack-Signature value Slack would send for body at ts.
+func SignSlack(secret string, ts time.Time, body []byte) string {
+	return slackSignatureVersion + "=" + hex.EncodeToString(slackMAC(secret, strconv.FormatInt(ts.Unix(), 10), body))
+}
+
+// VerifySlack checks a Slack request signature, computed with the app's
+// signing secret over "v0:<timestamp>:<body>", and rejects timestamps outside
+// tolerance to prevent replays.
+func VerifySlack(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	if err := checkTimestamp(timestamp, tolerance, now); err != nil {
+		return err
+	}
+	sig, ok := strings.CutPrefix(signature, slackSignatureVersion+"=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, slackMAC(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func slackMAC(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(slackSignatureVersion + ":" + timestamp + ":"))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,45 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// tokenMACBytes is the truncated MAC length; 80 bits is ample for tokens that
+// are only accepted by our own endpoints.
+const tokenMACBytes = 10
+
+// ErrInvalidToken is returned when a token is malformed or its MAC does not match.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Token binds payload to secret as "<payload>-<mac>". The MAC is lowercase hex
+// so tokens survive case-folding, e.g. in email local parts. payload must not
+// contain "-" or characters that are unsafe where the token is used.
+func Token(secret, payload string) string {
+	return payload + "-" + hex.EncodeToString(tokenMAC(secret, payload))
+}
+
+// ParseToken verifies a token produced by Token and returns its payload.
+func ParseToken(secret, token string) (string, error) {
+	token = strings.ToLower(token)
+	i := strings.LastIndexByte(token, '-')
+	if i <= 0 {
+		return "", ErrInvalidToken
+	}
+	payload, sig := token[:i], token[i+1:]
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, tokenMAC(secret, payload)) {
+		return "", ErrInvalidToken
+	}
+	return payload, nil
+}
+
+func tokenMAC(secret, payload string) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("token."))
+	h.Write([]byte(payload))
+	return h.Sum(nil)[:tokenMACBytes]
+}
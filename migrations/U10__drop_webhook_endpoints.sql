@@ -0,0 +1,4 @@
+-- Undo V10__create_webhook_endpoints
+ALTER TABLE forecast_cache DROP COLUMN IF EXISTS product_id;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,2 @@
+-- Undo V11__create_phone_verifications
+DROP TABLE IF EXISTS phone_verifications;
@@ -0,0 +1,4 @@
+-- Undo V12__add_reply_commands_to_subscriptions
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS digest,
+    DROP COLUMN IF EXISTS paused_until;
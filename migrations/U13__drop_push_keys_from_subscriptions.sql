@@ -0,0 +1,3 @@
+-- Undo V13__add_push_keys_to_subscriptions
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS push_keys;
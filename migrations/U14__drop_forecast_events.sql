@@ -0,0 +1,2 @@
+-- Undo V14__create_forecast_events
+DROP TABLE IF EXISTS forecast_events;
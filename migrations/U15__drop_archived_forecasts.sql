@@ -0,0 +1,2 @@
+-- Undo V15__create_archived_forecasts
+DROP TABLE IF EXISTS archived_forecasts;
@@ -0,0 +1,9 @@
+-- Undo V16__add_danger_to_archived_forecasts
+DROP INDEX IF EXISTS idx_archived_forecasts_valid_date;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS valid_date;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS valid_from;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS valid_until;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS danger_upper;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS danger_middle;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS danger_lower;
+ALTER TABLE archived_forecasts DROP COLUMN IF EXISTS bottom_line;
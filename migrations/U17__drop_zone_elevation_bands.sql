@@ -0,0 +1,2 @@
+-- Undo V17__create_zone_elevation_bands
+DROP TABLE IF EXISTS zone_elevation_bands;
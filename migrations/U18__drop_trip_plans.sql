@@ -0,0 +1,2 @@
+-- Undo V18__create_trip_plans
+DROP TABLE IF EXISTS trip_plans;
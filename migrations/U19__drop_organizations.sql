@@ -0,0 +1,7 @@
+-- Undo V19__create_organizations
+DELETE FROM subscriptions WHERE organization_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_subscriptions_organization_id;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_audit_log;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
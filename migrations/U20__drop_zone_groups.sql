@@ -0,0 +1,3 @@
+-- Undo V20__create_zone_groups
+DELETE FROM subscriptions WHERE zone_id LIKE 'group:%';
+DROP TABLE IF EXISTS zone_groups;
@@ -0,0 +1,2 @@
+-- Undo V21__create_zone_aliases
+DROP TABLE IF EXISTS zone_aliases;
@@ -0,0 +1,3 @@
+-- Undo V22__add_pending_to_trip_plans
+ALTER TABLE trip_plans
+    DROP COLUMN IF EXISTS pending;
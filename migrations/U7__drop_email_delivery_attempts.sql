@@ -0,0 +1,2 @@
+-- Undo V7__create_email_delivery_attempts
+DROP TABLE IF EXISTS email_delivery_attempts;
@@ -0,0 +1,5 @@
+-- Undo V8__create_email_suppressions
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS pause_reason,
+    DROP COLUMN IF EXISTS paused_at;
+DROP TABLE IF EXISTS email_suppressions;
@@ -0,0 +1,7 @@
+-- Undo V9__add_channel_to_subscriptions (non-email subscriptions are removed)
+DELETE FROM subscriptions WHERE channel <> 'email' OR email IS NULL OR email = '';
+DROP INDEX IF EXISTS idx_subscriptions_target;
+ALTER TABLE subscriptions
+    ALTER COLUMN email SET NOT NULL,
+    DROP COLUMN IF EXISTS target,
+    DROP COLUMN IF EXISTS channel;
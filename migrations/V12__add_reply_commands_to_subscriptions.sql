@@ -0,0 +1,4 @@
+-- Timed pauses and digest delivery requested by replying to forecast emails
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS paused_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS digest BOOLEAN NOT NULL DEFAULT FALSE;
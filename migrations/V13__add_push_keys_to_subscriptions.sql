@@ -0,0 +1,3 @@
+-- Encryption keys for Web Push subscriptions; the push endpoint is stored in target
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS push_keys TEXT;
@@ -0,0 +1,14 @@
+-- Log of forecast events written by the notifier and replayed by the SSE stream
+CREATE TABLE IF NOT EXISTS forecast_events (
+    seq BIGSERIAL PRIMARY KEY,
+    event_id TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    zone_id TEXT NOT NULL,
+    center_id TEXT NOT NULL,
+    data TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_forecast_events_zone_id ON forecast_events (zone_id);
+CREATE INDEX IF NOT EXISTS idx_forecast_events_center_id ON forecast_events (center_id);
+CREATE INDEX IF NOT EXISTS idx_forecast_events_created_at ON forecast_events (created_at);
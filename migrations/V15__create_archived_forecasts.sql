@@ -0,0 +1,20 @@
+-- Forecast issuances archived by the notifier for the Atom feeds
+CREATE TABLE IF NOT EXISTS archived_forecasts (
+    id BIGSERIAL PRIMARY KEY,
+    zone_id TEXT NOT NULL,
+    product_id INTEGER NOT NULL,
+    center_id TEXT NOT NULL,
+    product_type TEXT NOT NULL,
+    zone_name TEXT,
+    center_name TEXT,
+    title TEXT NOT NULL,
+    link TEXT,
+    content TEXT NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL,
+    amended_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_archived_forecasts_zone_product ON archived_forecasts (zone_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_archived_forecasts_center_id ON archived_forecasts (center_id);
+CREATE INDEX IF NOT EXISTS idx_archived_forecasts_amended_at ON archived_forecasts (amended_at);
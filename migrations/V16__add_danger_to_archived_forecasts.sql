@@ -0,0 +1,10 @@
+-- Structured danger ratings on archived forecasts for the bulk export
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS valid_date TEXT;
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS valid_from TIMESTAMPTZ;
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS valid_until TIMESTAMPTZ;
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS danger_upper INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS danger_middle INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS danger_lower INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE archived_forecasts ADD COLUMN IF NOT EXISTS bottom_line TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_archived_forecasts_valid_date ON archived_forecasts (valid_date);
@@ -0,0 +1,8 @@
+-- Elevations separating each zone's danger rating bands, for route assessment
+CREATE TABLE IF NOT EXISTS zone_elevation_bands (
+    zone_id TEXT PRIMARY KEY,
+    near_treeline_m DOUBLE PRECISION NOT NULL,
+    above_treeline_m DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (near_treeline_m < above_treeline_m)
+);
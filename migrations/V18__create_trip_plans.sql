@@ -0,0 +1,15 @@
+-- Trip plans whose participants get a countdown of forecast emails
+CREATE TABLE IF NOT EXISTS trip_plans (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    zone_ids TEXT NOT NULL,
+    trip_date TEXT NOT NULL,
+    timezone TEXT NOT NULL,
+    participants TEXT NOT NULL,
+    steps_sent INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_plans_expires_at ON trip_plans (expires_at);
@@ -0,0 +1,37 @@
+-- Organizations whose members are subscribed to a shared zone set, with an
+-- audit trail of membership changes
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    zone_ids TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_organization_members_org_email UNIQUE (organization_id, email)
+);
+
+CREATE TABLE IF NOT EXISTS organization_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    subject TEXT,
+    detail TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_audit_log_organization_id ON organization_audit_log (organization_id);
+CREATE INDEX IF NOT EXISTS idx_organization_audit_log_created_at ON organization_audit_log (created_at);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS organization_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_organization_id ON subscriptions (organization_id);
@@ -0,0 +1,15 @@
+-- Named zone groups that can be queried and subscribed to like a zone
+CREATE TABLE IF NOT EXISTS zone_groups (
+    id BIGSERIAL PRIMARY KEY,
+    slug TEXT NOT NULL,
+    name TEXT NOT NULL,
+    zone_ids TEXT NOT NULL,
+    owner_email TEXT,
+    organization_id BIGINT REFERENCES organizations (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_zone_groups_slug UNIQUE (slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_zone_groups_owner_email ON zone_groups (owner_email);
+CREATE INDEX IF NOT EXISTS idx_zone_groups_organization_id ON zone_groups (organization_id);
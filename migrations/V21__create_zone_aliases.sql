@@ -0,0 +1,12 @@
+-- Alternative names for zones and the previous IDs of renumbered zones
+CREATE TABLE IF NOT EXISTS zone_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    alias TEXT NOT NULL,
+    zone_id TEXT NOT NULL,
+    renumbered BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_zone_aliases_alias UNIQUE (alias)
+);
+
+CREATE INDEX IF NOT EXISTS idx_zone_aliases_zone_id ON zone_aliases (zone_id);
@@ -0,0 +1,3 @@
+-- Trip plan participants who have not yet confirmed the emailed invitation
+ALTER TABLE trip_plans
+    ADD COLUMN IF NOT EXISTS pending TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,15 @@
+-- Log of every provider attempt made while sending an email
+CREATE TABLE IF NOT EXISTS email_delivery_attempts (
+    id SERIAL PRIMARY KEY,
+    message_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    subject TEXT,
+    status TEXT NOT NULL,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_delivery_attempts_message_id ON email_delivery_attempts (message_id);
+CREATE INDEX IF NOT EXISTS idx_email_delivery_attempts_recipient ON email_delivery_attempts (recipient);
@@ -0,0 +1,14 @@
+-- Addresses that must not be emailed (hard bounces, spam reports, unsubscribes)
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    email TEXT PRIMARY KEY,
+    reason TEXT NOT NULL,
+    detail TEXT,
+    source TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Subscriptions for suppressed addresses are paused rather than deleted
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS paused_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS pause_reason TEXT;
@@ -0,0 +1,7 @@
+-- Allow subscriptions to target non-email channels such as chat webhooks
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS channel TEXT NOT NULL DEFAULT 'email',
+    ADD COLUMN IF NOT EXISTS target TEXT,
+    ALTER COLUMN email DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_target ON subscriptions (target);